@@ -0,0 +1,77 @@
+package beam
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ActionKind describes how a client should follow up on a response.
+type ActionKind string
+
+// Action kinds understood by clients of async and retryable endpoints.
+const (
+	ActionPoll     ActionKind = "poll"     // Fetch Href every Interval until the operation completes
+	ActionRetry    ActionKind = "retry"    // Repeat the request at Href after Interval
+	ActionRedirect ActionKind = "redirect" // Continue at Href
+)
+
+// Names of the actions built by PollAction, RetryAction, and RedirectAction.
+const (
+	ActionNamePoll     = "poll"
+	ActionNameRetry    = "retry"
+	ActionNameRedirect = "redirect"
+)
+
+// PollAction returns an action telling the client to GET statusURL every
+// interval until the operation it tracks completes.
+func PollAction(statusURL string, interval time.Duration) Action {
+	return Action{
+		Name:     ActionNamePoll,
+		Method:   http.MethodGet,
+		Href:     statusURL,
+		Kind:     ActionPoll,
+		Interval: intervalSeconds(interval),
+	}
+}
+
+// RetryAction returns an action telling the client to repeat the request with
+// method at href after interval, at most maxAttempts times (zero for no limit).
+func RetryAction(method, href string, interval time.Duration, maxAttempts int) Action {
+	return Action{
+		Name:        ActionNameRetry,
+		Method:      method,
+		Href:        href,
+		Kind:        ActionRetry,
+		Interval:    intervalSeconds(interval),
+		MaxAttempts: maxAttempts,
+	}
+}
+
+// RedirectAction returns an action telling the client to continue at href.
+func RedirectAction(href string) Action {
+	return Action{Name: ActionNameRedirect, Method: http.MethodGet, Href: href, Kind: ActionRedirect}
+}
+
+// WithPollAction adds a PollAction for statusURL and the matching Location
+// and Retry-After headers, for async endpoints answering 202 Accepted.
+// Returns a new Renderer with the action and headers added.
+func (r *Renderer) WithPollAction(statusURL string, interval time.Duration) *Renderer {
+	action := PollAction(statusURL, interval)
+	nr := r.WithAction(action)
+	nr.header.Set("Location", statusURL)
+	if action.Interval > 0 {
+		nr.header.Set("Retry-After", strconv.Itoa(action.Interval))
+	}
+	return nr
+}
+
+// intervalSeconds rounds d up to whole seconds, the unit of Action.Interval
+// and Retry-After.
+func intervalSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,69 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestActionKinds(t *testing.T) {
+	t.Run("Constructors", func(t *testing.T) {
+		poll := PollAction("/jobs/42", 1500*time.Millisecond)
+		if poll.Kind != ActionPoll || poll.Method != http.MethodGet || poll.Interval != 2 {
+			t.Errorf("Unexpected poll action %+v", poll)
+		}
+		retry := RetryAction(http.MethodPost, "/orders", 30*time.Second, 3)
+		if retry.Kind != ActionRetry || retry.Interval != 30 || retry.MaxAttempts != 3 {
+			t.Errorf("Unexpected retry action %+v", retry)
+		}
+		if redirect := RedirectAction("/orders/7"); redirect.Kind != ActionRedirect || redirect.Interval != 0 {
+			t.Errorf("Unexpected redirect action %+v", redirect)
+		}
+	})
+
+	t.Run("WithPollAction", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).
+			WithPollAction("/jobs/42", 5*time.Second).
+			WithStatus(http.StatusAccepted).
+			WithWriter(w).
+			Push(nil, Response{Status: StatusPending, Message: "queued"})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if w.Code != http.StatusAccepted {
+			t.Errorf("Expected 202, got %d", w.Code)
+		}
+		if w.Header().Get("Location") != "/jobs/42" || w.Header().Get("Retry-After") != "5" {
+			t.Errorf("Expected Location and Retry-After, got %v", w.Header())
+		}
+		var resp struct {
+			Actions []map[string]interface{} `json:"actions"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Actions) != 1 {
+			t.Fatalf("Expected one action, got %v", resp.Actions)
+		}
+		a := resp.Actions[0]
+		if a["kind"] != "poll" || a["interval"] != float64(5) || a["href"] != "/jobs/42" {
+			t.Errorf("Unexpected action %v", a)
+		}
+		if _, ok := a["max_attempts"]; ok {
+			t.Error("Expected max_attempts omitted when unbounded")
+		}
+	})
+
+	t.Run("Plain", func(t *testing.T) {
+		b, err := json.Marshal(Action{Name: "self", Href: "/"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != `{"name":"self","href":"/"}` {
+			t.Errorf("Expected untyped actions unchanged, got %s", b)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package beam
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// metaAnnotations is the meta key carrying context annotations.
+const metaAnnotations = "annotations"
+
+// annotationsKey is the context key for the annotation collector.
+type annotationsKey struct{}
+
+// annotations collects key/value pairs attached through a context.
+// Keys keep their first insertion order; later values replace earlier ones.
+type annotations struct {
+	mu     sync.Mutex
+	keys   []string
+	values map[string]interface{}
+}
+
+// WithAnnotations returns a context carrying an annotation collector.
+// Returns ctx unchanged if it already carries one, so nested calls share it.
+// Handler installs a collector on every request; use AnnotationMiddleware
+// when serving without Handler.
+func WithAnnotations(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(annotationsKey{}).(*annotations); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, annotationsKey{}, &annotations{values: make(map[string]interface{})})
+}
+
+// Annotate attaches a diagnostic key/value pair to the response being built for ctx.
+// Code far below the handler, such as repositories or clients, can use it
+// without access to the Renderer. Renderers add the annotations to fatal log
+// entries, and to meta "annotations" when enabled with WithAnnotationMeta.
+// Safe for concurrent use. Returns false if ctx carries no collector.
+func Annotate(ctx context.Context, key string, value interface{}) bool {
+	a, ok := ctx.Value(annotationsKey{}).(*annotations)
+	if !ok {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, exists := a.values[key]; !exists {
+		a.keys = append(a.keys, key)
+	}
+	a.values[key] = value
+	return true
+}
+
+// Annotations returns a copy of the annotations attached to ctx, or nil if there are none.
+func Annotations(ctx context.Context) map[string]interface{} {
+	a, ok := ctx.Value(annotationsKey{}).(*annotations)
+	if !ok {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.values) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(a.values))
+	for k, v := range a.values {
+		out[k] = v
+	}
+	return out
+}
+
+// AnnotationMiddleware installs an annotation collector on each request's context.
+// Returns a middleware wrapping next.
+func AnnotationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(w, req.WithContext(WithAnnotations(req.Context())))
+	})
+}
+
+// WithAnnotationMeta enables or disables copying context annotations into
+// meta "annotations". Annotations often hold internals such as table names or
+// upstream hosts, so they stay in logs unless this is enabled.
+// Returns a new Renderer with the updated setting.
+func (r *Renderer) WithAnnotationMeta(enabled State) *Renderer {
+	nr := r.clone()
+	nr.annotationMeta = enabled
+	return nr
+}
+
+// annotate adds the context's annotations to resp's meta when enabled.
+func (r *Renderer) annotate(resp *Response) {
+	if !r.annotationMeta.Enabled() {
+		return
+	}
+	anns := Annotations(r.Context())
+	if anns == nil {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaAnnotations] = anns
+}
+
+// annotationFields returns the context's annotations as key/value log fields in insertion order.
+func (r *Renderer) annotationFields() []interface{} {
+	a, ok := r.Context().Value(annotationsKey{}).(*annotations)
+	if !ok {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fields := make([]interface{}, 0, 2*len(a.keys))
+	for _, k := range a.keys {
+		fields = append(fields, k, a.values[k])
+	}
+	return fields
+}
@@ -0,0 +1,83 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// loadUser stands in for repository code that only sees a context.
+func loadUser(ctx context.Context) error {
+	Annotate(ctx, "db.table", "users")
+	Annotate(ctx, "db.rows", 0)
+	return errors.New("user not found")
+}
+
+func TestAnnotate(t *testing.T) {
+	serve := func(r *Renderer) map[string]map[string]interface{} {
+		h := r.Handler(func(r *Renderer) error {
+			if err := loadUser(r.Context()); err != nil {
+				return r.Fatal(err)
+			}
+			return nil
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+		var resp struct {
+			Meta map[string]map[string]interface{} `json:"meta"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Meta
+	}
+
+	logger := &TestLogger{}
+	if meta := serve(NewRenderer(settings).WithLogger(logger)); meta["annotations"] != nil {
+		t.Errorf("Expected annotations kept out of meta by default, got %v", meta)
+	}
+	meta := serve(NewRenderer(settings).WithAnnotationMeta(Yes))
+	if anns := meta["annotations"]; anns["db.table"] != "users" || anns["db.rows"] != float64(0) {
+		t.Errorf("Expected annotations in meta, got %v", meta)
+	}
+
+	entry := logger.LastEntry()
+	if entry == nil {
+		t.Fatal("Expected fatal log entry")
+	}
+	found := false
+	for i := 0; i+1 < len(entry.Fields); i += 2 {
+		if entry.Fields[i] == "db.table" && entry.Fields[i+1] == "users" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected annotation in log fields, got %v", entry.Fields)
+	}
+}
+
+func TestAnnotateWithoutCollector(t *testing.T) {
+	if Annotate(context.Background(), "k", "v") {
+		t.Error("Expected Annotate to report no collector")
+	}
+	ctx := WithAnnotations(context.Background())
+	if WithAnnotations(ctx) != ctx {
+		t.Error("Expected nested WithAnnotations to reuse the collector")
+	}
+	Annotate(ctx, "k", "v1")
+	Annotate(ctx, "k", "v2")
+	if got := Annotations(ctx); len(got) != 1 || got["k"] != "v2" {
+		t.Errorf("Expected latest value, got %v", got)
+	}
+
+	var seen bool
+	AnnotationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		seen = Annotate(req.Context(), "k", "v")
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !seen {
+		t.Error("Expected middleware to install a collector")
+	}
+}
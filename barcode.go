@@ -0,0 +1,117 @@
+package beam
+
+import (
+	"errors"
+	"image"
+	"image/color"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Defaults of QR and Code128.
+const (
+	DefaultQRSize        = 256  // QR code width and height in pixels
+	DefaultBarcodeHeight = 80   // Code128 bar height in pixels
+	DefaultBarcodeModule = 2    // Code128 narrowest bar width in pixels when no width is given
+	MaxBarcodeSize       = 4096 // Largest width or height QR and Code128 draw, in pixels
+)
+
+// ErrBarcodeTooLarge is returned by QR and Code128 when the requested image
+// exceeds MaxBarcodeSize in either dimension. Sizes often come from query
+// parameters, so they are bounded like other untrusted input.
+var ErrBarcodeTooLarge = errors.New("barcode too large")
+
+// Quiet zones, in modules, kept blank around codes so scanners find their edges.
+const (
+	qrQuietZone      = 4
+	code128QuietZone = 10
+)
+
+// QR generates a QR code of data, with medium error correction, and sends it
+// as a size x size PNG through Image. Modules are scaled by a whole number of
+// pixels, so a size too small for data grows to fit; zero uses DefaultQRSize.
+// Returns ErrBarcodeTooLarge above MaxBarcodeSize, or an error if data cannot
+// be encoded or sending fails.
+func (r *Renderer) QR(data string, size int) error {
+	if size <= 0 {
+		size = DefaultQRSize
+	}
+	if size > MaxBarcodeSize {
+		return r.barcodeFailed(ErrBarcodeTooLarge)
+	}
+	code, err := qr.Encode(data, qr.M, qr.Auto)
+	if err != nil {
+		return r.barcodeFailed(errors.Join(errors.New("QR encoding failed"), err))
+	}
+	return r.Image(ContentTypePNG, drawBarcode(code, size, size, qrQuietZone, qrQuietZone))
+}
+
+// Code128 generates a Code 128 barcode of data and sends it as a PNG through
+// Image. A zero width uses DefaultBarcodeModule pixels per module, and a zero
+// height DefaultBarcodeHeight; a width too small for data grows to fit.
+// Returns ErrBarcodeTooLarge above MaxBarcodeSize, or an error if data cannot
+// be encoded or sending fails.
+func (r *Renderer) Code128(data string, width, height int) error {
+	if height <= 0 {
+		height = DefaultBarcodeHeight
+	}
+	if width > MaxBarcodeSize || height > MaxBarcodeSize {
+		return r.barcodeFailed(ErrBarcodeTooLarge)
+	}
+	code, err := code128.Encode(data)
+	if err != nil {
+		return r.barcodeFailed(errors.Join(errors.New("Code128 encoding failed"), err))
+	}
+	if width <= 0 {
+		width = (code.Bounds().Dx() + 2*code128QuietZone) * DefaultBarcodeModule
+	}
+	return r.Image(ContentTypePNG, drawBarcode(code, width, height, code128QuietZone, 0))
+}
+
+// barcodeFailed reports data that cannot be encoded like Image reports an
+// unsupported image, and returns err.
+func (r *Renderer) barcodeFailed(err error) error {
+	if err := r.checkOutput(r.writer); err != nil {
+		return err
+	}
+	r.triggerCallbacks(r.id, StatusError, err.Error(), err)
+	if r.finalizer != nil {
+		r.finalizer(r.writer, err)
+	}
+	return err
+}
+
+// drawBarcode scales code, one pixel per module, by whole pixels to fit
+// width x height with quiet zones of qx and qy modules, centering it on a
+// white canvas. The canvas grows when the code does not fit.
+// Callers bound width and height; codes themselves stay well below
+// MaxBarcodeSize (QR version 40 is 177 modules, Code128 at most 80 runes).
+func drawBarcode(code barcode.Barcode, width, height, qx, qy int) *image.Gray {
+	b := code.Bounds()
+	sx := max(width/(b.Dx()+2*qx), 1)
+	sy := max(height/(b.Dy()+2*qy), 1)
+	width = max(width, (b.Dx()+2*qx)*sx)
+	height = max(height, (b.Dy()+2*qy)*sy)
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	ox, oy := (width-b.Dx()*sx)/2, (height-b.Dy()*sy)/2
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			if color.GrayModel.Convert(code.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y >= 0x80 {
+				continue
+			}
+			for py := oy + y*sy; py < oy+(y+1)*sy; py++ {
+				row := img.Pix[py*img.Stride:]
+				for px := ox + x*sx; px < ox+(x+1)*sx; px++ {
+					row[px] = 0
+				}
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,108 @@
+package beam
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+func TestBarcode(t *testing.T) {
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) image.Image {
+		t.Helper()
+		if ct := w.Header().Get(HeaderContentType); ct != ContentTypePNG {
+			t.Fatalf("Expected %s, got %q", ContentTypePNG, ct)
+		}
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Invalid PNG: %v", err)
+		}
+		return img
+	}
+	dark := func(img image.Image, x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r < 0x8000
+	}
+
+	t.Run("QR", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).QR("https://example.com/ticket/42", 0); err != nil {
+			t.Fatalf("QR failed: %v", err)
+		}
+		img := decode(t, w)
+		if b := img.Bounds(); b.Dx() != DefaultQRSize || b.Dy() != DefaultQRSize {
+			t.Fatalf("Expected %dx%d, got %v", DefaultQRSize, DefaultQRSize, b)
+		}
+		if dark(img, 0, 0) {
+			t.Error("Expected a blank quiet zone")
+		}
+
+		code, _ := qr.Encode("https://example.com/ticket/42", qr.M, qr.Auto)
+		n := code.Bounds().Dx()
+		scale := DefaultQRSize / (n + 2*qrQuietZone)
+		offset := (DefaultQRSize - n*scale) / 2
+		for y := 0; y < n; y++ {
+			for x := 0; x < n; x++ {
+				want := code.At(x, y) == code.At(0, 0)
+				if got := dark(img, offset+x*scale+scale/2, offset+y*scale+scale/2); got != want {
+					t.Fatalf("Module %d,%d: expected dark=%v", x, y, want)
+				}
+			}
+		}
+	})
+
+	t.Run("QRTooSmall", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).QR("hello", 10); err != nil {
+			t.Fatalf("QR failed: %v", err)
+		}
+		if b := decode(t, w).Bounds(); b.Dx() < 21+2*qrQuietZone || b.Dx() != b.Dy() {
+			t.Errorf("Expected the code to grow to fit, got %v", b)
+		}
+	})
+
+	t.Run("Code128", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).Code128("SKU-12345", 0, 0); err != nil {
+			t.Fatalf("Code128 failed: %v", err)
+		}
+		img := decode(t, w)
+		b := img.Bounds()
+		if b.Dy() != DefaultBarcodeHeight || b.Dx()%DefaultBarcodeModule != 0 {
+			t.Errorf("Unexpected size %v", b)
+		}
+		quiet := code128QuietZone * DefaultBarcodeModule
+		if dark(img, quiet-1, 0) || !dark(img, quiet, 0) || !dark(img, quiet, b.Dy()-1) {
+			t.Error("Expected full-height bars after the quiet zone")
+		}
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		r := NewRenderer(settings).WithWriter(httptest.NewRecorder())
+		for name, err := range map[string]error{
+			"QR":            r.QR("hello", MaxBarcodeSize+1),
+			"Code128Width":  r.Code128("SKU", MaxBarcodeSize+1, 0),
+			"Code128Height": r.Code128("SKU", 0, 1<<30),
+		} {
+			if !errors.Is(err, ErrBarcodeTooLarge) {
+				t.Errorf("%s: expected ErrBarcodeTooLarge, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("InvalidData", func(t *testing.T) {
+		var got []CallbackData
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithCallback(func(d CallbackData) { got = append(got, d) })
+		if err := r.Code128("café ☕", 200, 50); err == nil {
+			t.Fatal("Expected an encoding error")
+		}
+		if len(got) != 1 || got[0].Status != StatusError {
+			t.Errorf("Expected one error callback, got %v", got)
+		}
+	})
+}
@@ -16,12 +16,14 @@ type EmptyStruct struct{}
 // Status constants define standardized response states for Renderer responses.
 // They are used in the Response struct to indicate the outcome of an operation.
 const (
-	StatusError      = "-error"   // Indicates a non-fatal error
-	StatusPending    = "?pending" // Indicates an operation is in progress
-	StatusSuccessful = "+ok"      // Indicates a successful operation
-	StatusFatal      = "*fatal"   // Indicates a critical error
-	StatusWarning    = "*warning" // Indicates a non-critical warning
-	StatusUnknown    = "*unknown" // Indicates an undefined or unknown state
+	StatusError      = "-error"    // Indicates a non-fatal error
+	StatusPending    = "?pending"  // Indicates an operation is in progress
+	StatusSuccessful = "+ok"       // Indicates a successful operation
+	StatusFatal      = "*fatal"    // Indicates a critical error
+	StatusWarning    = "*warning"  // Indicates a non-critical warning
+	StatusUnknown    = "*unknown"  // Indicates an undefined or unknown state
+	StatusSlow       = "*slow"     // Indicates a response exceeded the slow threshold
+	StatusProgress   = "?progress" // Indicates a long-lived stream is still making progress
 )
 
 // Header constants define standard HTTP header names and prefixes for metadata.
@@ -37,6 +39,7 @@ const (
 	HeaderNameVersion   = "Version"   // Application version
 	HeaderNameBuild     = "Build"     // Build identifier
 	HeaderNamePlay      = "Play"      // Play mode or context
+	HeaderNameEnvelope  = "Envelope"  // Envelope layout ("compact" when minimized)
 )
 
 // Operation status constants indicate the success or failure of operations.
@@ -87,14 +90,15 @@ var (
 // Predefined errors for common failure cases in Beam.
 // These reusable error instances reduce fmt.Errorf allocations and ensure consistency.
 var (
-	errNoWriter          = errors.New("no writer set; use WithWriter to set a default writer")
-	errEncodingFailed    = errors.New("encoding failed")
-	errWriteFailed       = errors.New("write failed")
-	errHeaderWriteFailed = errors.New("header write failed")
-	errUnsupportedImage  = errors.New("unsupported image content type")
-	errNilWriter         = errors.New("writer cannot be nil")
-	errNilProtocol       = errors.New("protocol cannot be nil")
-	errNoEncoder         = errors.New("no encoder for content type")
+	errNoWriter            = errors.New("no writer set; use WithWriter to set a default writer")
+	errEncodingFailed      = errors.New("encoding failed")
+	errWriteFailed         = errors.New("write failed")
+	errHeaderWriteFailed   = errors.New("header write failed")
+	errUnsupportedImage    = errors.New("unsupported image content type")
+	errNilWriter           = errors.New("writer cannot be nil")
+	errNilProtocol         = errors.New("protocol cannot be nil")
+	errNoEncoder           = errors.New("no encoder for content type")
+	errUnsupportedEncoding = errors.New("unsupported content encoding")
 )
 
 // Predefined errors for special handling in Renderer.
@@ -194,6 +198,8 @@ func putResponse(r *Response) {
 	r.Message = ""
 	r.Info = EmptyStruct{}
 	r.Data = make([]any, 0)
+	r.InfoType = ""
+	r.DataType = ""
 	for k := range r.Meta {
 		delete(r.Meta, k)
 	}
@@ -0,0 +1,25 @@
+package beamtest
+
+import (
+	"time"
+
+	"github.com/olekukonko/beam"
+)
+
+// Epoch is the instant reported by the clock of a Deterministic renderer.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// EpochID is the request ID generated by a Deterministic renderer.
+const EpochID = "00000000-0000-4000-8000-000000000000"
+
+// Deterministic returns a copy of r whose output is stable across runs.
+// Pins the clock to Epoch (so Timestamp headers never change and durations
+// are always zero), generates EpochID for every request ID, and relies on
+// beam's sorted meta and header emission, so golden files and snapshot diffs
+// stop flaking.
+func Deterministic(r *beam.Renderer) *beam.Renderer {
+	return r.
+		WithClock(beam.FixedClock(Epoch)).
+		WithIDGenerator(func() string { return EpochID }).
+		WithTimestampFormat(beam.TimestampRFC3339)
+}
@@ -0,0 +1,43 @@
+package beamtest
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olekukonko/beam"
+)
+
+func TestDeterministic(t *testing.T) {
+	render := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := Deterministic(beam.NewRenderer(beam.Setting{Name: "test", EnableHeaders: true})).
+			WithContentType(beam.ContentTypeXML).
+			WithIDGeneration(beam.Yes).
+			WithSystem(beam.SystemShowBoth, beam.System{App: "app"})
+		for _, k := range []string{"zeta", "alpha", "mid", "beta", "omega", "gamma"} {
+			r = r.WithMeta(k, k)
+		}
+		if err := r.Push(w, beam.Response{Message: "ok"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return w
+	}
+
+	first := render()
+	for i := 0; i < 20; i++ {
+		next := render()
+		if !bytes.Equal(first.Body.Bytes(), next.Body.Bytes()) {
+			t.Fatalf("Expected identical bodies, got\n%s\n%s", first.Body, next.Body)
+		}
+	}
+	if got := first.Header().Get("X-test-Timestamp"); got != "2000-01-01T00:00:00Z" {
+		t.Errorf("Expected pinned timestamp, got %q", got)
+	}
+	if got := first.Header().Get("X-test-Duration"); got != "0s" {
+		t.Errorf("Expected zero duration, got %q", got)
+	}
+	if !bytes.Contains(first.Body.Bytes(), []byte("<alpha>alpha</alpha><beta>beta</beta>")) {
+		t.Errorf("Expected sorted meta, got %s", first.Body)
+	}
+}
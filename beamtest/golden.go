@@ -0,0 +1,250 @@
+// Package beamtest provides helpers for testing handlers built with beam.
+package beamtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that switches Golden into record mode.
+// Set it to a non-empty value (e.g. BEAM_UPDATE_GOLDEN=1 go test ./...) to rewrite golden files.
+const UpdateEnv = "BEAM_UPDATE_GOLDEN"
+
+// redacted replaces volatile values in recorded snapshots.
+const redacted = "<redacted>"
+
+// DefaultRedactions lists JSON keys whose values change between runs.
+// Matched case-insensitively at any depth of the body.
+var DefaultRedactions = []string{"id", "request_id", "duration", "nonce", "timestamp"}
+
+// Golden records responses for named scenarios and diffs later runs against them.
+// JSON bodies are compared semantically (key order and whitespace are ignored)
+// after volatile fields are redacted; other bodies are compared byte for byte.
+type Golden struct {
+	Dir     string   // Directory holding golden files (default "testdata/golden")
+	Redact  []string // JSON keys to redact; defaults to DefaultRedactions
+	Headers []string // Response headers to include in the snapshot besides Content-Type
+	Update  bool     // Rewrite golden files instead of comparing; also enabled by UpdateEnv
+}
+
+// Snapshot is the recorded form of a response.
+type Snapshot struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body"`
+}
+
+// NewGolden creates a Golden rooted at dir.
+// Returns a Golden using DefaultRedactions.
+func NewGolden(dir string) *Golden {
+	return &Golden{Dir: dir}
+}
+
+// Record serves req with h and asserts the response against the named golden file.
+// Convenience wrapper around httptest for the common handler case.
+func (g *Golden) Record(t testing.TB, name string, h http.Handler, req *http.Request) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	g.AssertResponse(t, name, rec.Result())
+}
+
+// AssertResponse compares a response with the named golden file.
+// In update mode the snapshot is written instead and the assertion always passes.
+func (g *Golden) AssertResponse(t testing.TB, name string, resp *http.Response) {
+	t.Helper()
+	var body bytes.Buffer
+	if resp.Body != nil {
+		if _, err := body.ReadFrom(resp.Body); err != nil {
+			t.Fatalf("beamtest: reading response body: %v", err)
+		}
+		resp.Body.Close()
+	}
+	headers := map[string]string{}
+	for _, h := range append([]string{"Content-Type"}, g.Headers...) {
+		if v := resp.Header.Get(h); v != "" {
+			headers[http.CanonicalHeaderKey(h)] = v
+		}
+	}
+	g.assert(t, name, Snapshot{Status: resp.StatusCode, Headers: headers, Body: g.normalize(body.Bytes())})
+}
+
+// Assert compares a raw body with the named golden file.
+func (g *Golden) Assert(t testing.TB, name string, body []byte) {
+	t.Helper()
+	g.assert(t, name, Snapshot{Body: g.normalize(body)})
+}
+
+// assert writes or compares a snapshot.
+func (g *Golden) assert(t testing.TB, name string, got Snapshot) {
+	t.Helper()
+	path := g.path(name)
+	if g.Update || os.Getenv(UpdateEnv) != "" {
+		data, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatalf("beamtest: encoding snapshot %s: %v", name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("beamtest: %v", err)
+		}
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			t.Fatalf("beamtest: writing golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("beamtest: golden file %s missing (run with %s=1 to record): %v", path, UpdateEnv, err)
+	}
+	var want Snapshot
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatalf("beamtest: decoding golden file %s: %v", path, err)
+	}
+	// Round-trip the new snapshot so both sides share JSON types.
+	var normalized Snapshot
+	encoded, _ := json.Marshal(got)
+	_ = json.Unmarshal(encoded, &normalized)
+
+	if diffs := Diff(want, normalized); len(diffs) > 0 {
+		t.Errorf("beamtest: %s does not match golden file %s:\n  %s", name, path, strings.Join(diffs, "\n  "))
+	}
+}
+
+// path returns the golden file location for a scenario name.
+func (g *Golden) path(name string) string {
+	dir := g.Dir
+	if dir == "" {
+		dir = filepath.Join("testdata", "golden")
+	}
+	safe := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == ':' {
+			return '_'
+		}
+		return r
+	}, name)
+	return filepath.Join(dir, safe+".golden.json")
+}
+
+// normalize decodes JSON bodies and redacts volatile fields.
+// Non-JSON bodies are returned as strings.
+func (g *Golden) normalize(body []byte) interface{} {
+	var doc interface{}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return string(body)
+	}
+	keys := g.Redact
+	if keys == nil {
+		keys = DefaultRedactions
+	}
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = true
+	}
+	return redact(doc, set)
+}
+
+// redact replaces the values of matching keys throughout doc.
+func redact(doc interface{}, keys map[string]bool) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if keys[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = redact(val, keys)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i], keys)
+		}
+	}
+	return doc
+}
+
+// Diff compares two snapshots semantically.
+// Returns one line per differing path, empty when they match.
+func Diff(want, got Snapshot) []string {
+	var diffs []string
+	if want.Status != got.Status {
+		diffs = append(diffs, fmt.Sprintf("status: want %d, got %d", want.Status, got.Status))
+	}
+	diffs = append(diffs, diffValues("headers", toAny(want.Headers), toAny(got.Headers))...)
+	return append(diffs, diffValues("body", want.Body, got.Body)...)
+}
+
+// toAny converts a header map into the generic form used by diffValues.
+func toAny(m map[string]string) interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// diffValues walks two decoded JSON values and reports differences by path.
+func diffValues(path string, want, got interface{}) []string {
+	wm, wok := want.(map[string]interface{})
+	gm, gok := got.(map[string]interface{})
+	if wok && gok {
+		keys := make([]string, 0, len(wm)+len(gm))
+		for k := range wm {
+			keys = append(keys, k)
+		}
+		for k := range gm {
+			if _, ok := wm[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var diffs []string
+		for _, k := range keys {
+			w, inWant := wm[k]
+			g, inGot := gm[k]
+			switch {
+			case !inWant:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: unexpected %s", path, k, compact(g)))
+			case !inGot:
+				diffs = append(diffs, fmt.Sprintf("%s.%s: missing, want %s", path, k, compact(w)))
+			default:
+				diffs = append(diffs, diffValues(path+"."+k, w, g)...)
+			}
+		}
+		return diffs
+	}
+	wa, wok := want.([]interface{})
+	ga, gok := got.([]interface{})
+	if wok && gok && len(wa) == len(ga) {
+		var diffs []string
+		for i := range wa {
+			diffs = append(diffs, diffValues(fmt.Sprintf("%s[%d]", path, i), wa[i], ga[i])...)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(want, got) {
+		return []string{fmt.Sprintf("%s: want %s, got %s", path, compact(want), compact(got))}
+	}
+	return nil
+}
+
+// compact renders a value as single-line JSON for diff output.
+func compact(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,62 @@
+package beamtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/olekukonko/beam"
+)
+
+// fakeT captures failures without aborting the enclosing test.
+type fakeT struct {
+	testing.TB
+	failed []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.failed = append(f.failed, format)
+}
+
+func TestGolden(t *testing.T) {
+	dir := t.TempDir()
+	message := "hello"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		beam.NewRenderer(beam.Setting{Name: "test"}).
+			WithIDGeneration(beam.Yes).
+			WithWriter(w).
+			Info(message, map[string]interface{}{"count": 2})
+	})
+
+	g := &Golden{Dir: dir, Update: true}
+	g.Record(t, "users/list", handler, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	t.Run("Matches", func(t *testing.T) {
+		g := &Golden{Dir: dir}
+		g.Record(t, "users/list", handler, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		message = "changed"
+		defer func() { message = "hello" }()
+		ft := &fakeT{TB: t}
+		g := &Golden{Dir: dir}
+		g.Record(ft, "users/list", handler, httptest.NewRequest(http.MethodGet, "/", nil))
+		if len(ft.failed) != 1 {
+			t.Fatalf("Expected one failure, got %d", len(ft.failed))
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		diffs := Diff(
+			Snapshot{Status: 200, Body: map[string]interface{}{"a": 1.0, "b": "x"}},
+			Snapshot{Status: 200, Body: map[string]interface{}{"b": "y", "a": 1.0}},
+		)
+		if len(diffs) != 1 || !strings.HasPrefix(diffs[0], "body.b:") {
+			t.Errorf("Expected a single body.b difference, got %v", diffs)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package beamtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/olekukonko/beam"
+)
+
+// RaceGoroutines and RaceIterations size the workload run by Race.
+var (
+	RaceGoroutines = 16
+	RaceIterations = 50
+)
+
+// raceToken prefixes the values each Race worker writes, so leaks between workers are detectable.
+const raceToken = "beamrace-"
+
+// raceHeader carries the worker token in response headers.
+const raceHeader = "X-Beam-Race"
+
+// Race stresses the concurrency contract of beam.Renderer on a shared base.
+// Many goroutines derive renderers from base with With* options and Push
+// through them at the same time; every response must carry only its own
+// worker's meta, tag, and header, and base must render identically before and
+// after. Run it under go test -race to also catch unsynchronized access.
+// Returns an error describing the first violation, or nil.
+func Race(base *beam.Renderer) error {
+	base = Deterministic(base)
+	before, err := racePush(base)
+	if err != nil {
+		return fmt.Errorf("base push: %w", err)
+	}
+	if bytes.Contains(before, []byte(raceToken)) {
+		return errors.New("base renderer already contains race tokens")
+	}
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+	fail := func(err error) { once.Do(func() { first = err }) }
+	for g := 0; g < RaceGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < RaceIterations; i++ {
+				if err := raceRound(base, fmt.Sprintf("%s%d-%d.", raceToken, g, i)); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if first != nil {
+		return first
+	}
+
+	after, err := racePush(base)
+	if err != nil {
+		return fmt.Errorf("base push: %w", err)
+	}
+	if !bytes.Equal(before, after) {
+		return fmt.Errorf("base renderer changed:\nbefore: %s\nafter:  %s", before, after)
+	}
+	return nil
+}
+
+// raceRound derives a renderer tagged with token, pushes through it, and checks isolation.
+func raceRound(base *beam.Renderer, token string) error {
+	r := base.
+		WithMeta("race", token).
+		WithTag(token).
+		WithHeader(raceHeader, token).
+		WithStatus(http.StatusAccepted)
+	rec := httptest.NewRecorder()
+	if err := r.Push(rec, beam.Response{Message: "race"}); err != nil {
+		return fmt.Errorf("%s: %w", token, err)
+	}
+	if rec.Code != http.StatusAccepted {
+		return fmt.Errorf("%s: status %d, want %d", token, rec.Code, http.StatusAccepted)
+	}
+	if got := rec.Header().Values(raceHeader); len(got) != 1 || got[0] != token {
+		return fmt.Errorf("%s: header %s = %q", token, raceHeader, got)
+	}
+	body := rec.Body.Bytes()
+	if all, own := bytes.Count(body, []byte(raceToken)), bytes.Count(body, []byte(token)); all != own || own < 2 {
+		return fmt.Errorf("%s: body leaked state from other renderers: %s", token, body)
+	}
+	return nil
+}
+
+// racePush renders a fixed response through r and returns the body.
+func racePush(r *beam.Renderer) ([]byte, error) {
+	rec := httptest.NewRecorder()
+	if err := r.Push(rec, beam.Response{Message: "race"}); err != nil {
+		return nil, err
+	}
+	return rec.Body.Bytes(), nil
+}
@@ -0,0 +1,23 @@
+package beamtest
+
+import (
+	"testing"
+
+	"github.com/olekukonko/beam"
+)
+
+func TestRace(t *testing.T) {
+	settings := beam.Setting{Name: "test", EnableHeaders: true}
+	for name, base := range map[string]*beam.Renderer{
+		"JSON": beam.NewRenderer(settings).WithMeta("region", "eu").WithTag("api"),
+		"XML":  beam.NewRenderer(settings).WithContentType(beam.ContentTypeXML).WithIDGeneration(beam.Yes),
+		"MsgPack": beam.NewRenderer(settings).WithContentType(beam.ContentTypeMsgPack).
+			WithSystem(beam.SystemShowBoth, beam.System{App: "app"}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := Race(base); err != nil {
+				t.Errorf("Expected no race violations, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Meta keys describing partial responses.
+const (
+	metaPartial = "partial" // true when at least one part is missing
+	metaMissing = "missing" // map of missing part names to "timeout" or "error"
+)
+
+// PartFunc produces one named fragment of a response.
+// Build PartFuncs with Part so each carries its own timeout.
+type PartFunc func(ctx context.Context) (name string, value interface{}, err error)
+
+// Part creates a PartFunc that runs fetch with its own timeout.
+// The part gives up when the timeout (if positive) or the parent context expires,
+// even if fetch ignores cancellation.
+// A panicking fetch fails the part with a *PanicError.
+// Returns a PartFunc usable with Renderer.Best.
+func Part(name string, timeout time.Duration, fetch func(ctx context.Context) (interface{}, error)) PartFunc {
+	return func(ctx context.Context) (string, interface{}, error) {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		type result struct {
+			value interface{}
+			err   error
+		}
+		done := make(chan result, 1)
+		go func() {
+			defer func() {
+				if v := recover(); v != nil {
+					done <- result{err: &PanicError{Value: v, Stack: debug.Stack()}}
+				}
+			}()
+			v, err := fetch(ctx)
+			done <- result{v, err}
+		}()
+		select {
+		case res := <-done:
+			return name, res.value, res.err
+		case <-ctx.Done():
+			return name, nil, ctx.Err()
+		}
+	}
+}
+
+// partResult is the outcome of one PartFunc.
+type partResult struct {
+	name  string
+	value interface{}
+	err   error
+}
+
+// runParts runs all parts concurrently and waits for every one to finish.
+// Returns results in the order the parts were given.
+func runParts(ctx context.Context, parts []PartFunc) []partResult {
+	results := make([]partResult, len(parts))
+	var wg sync.WaitGroup
+	for i, p := range parts {
+		wg.Add(1)
+		go func(i int, p PartFunc) {
+			defer wg.Done()
+			defer func() {
+				if v := recover(); v != nil {
+					results[i].err = &PanicError{Value: v}
+				}
+			}()
+			name, value, err := p(ctx)
+			results[i] = partResult{name: name, value: value, err: err}
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// Best gathers independent Data fragments concurrently and renders whatever completed.
+// Completed parts become keys of Response.Data; parts that failed or timed out are
+// listed in the "missing" meta with the reason, and "partial" is set to true.
+// When no part completed, responds 504 if every part timed out, 500 if one
+// panicked, and 502 otherwise.
+// Returns a *ConfigError if the writer or other configuration is missing, or an
+// error if sending the response fails.
+func (r *Renderer) Best(ctx context.Context, parts ...PartFunc) error {
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	data := make(map[string]interface{}, len(parts))
+	missing := make(map[string]string)
+	code := http.StatusGatewayTimeout // Status when no part completed
+	for _, res := range runParts(ctx, parts) {
+		if res.err == nil {
+			data[res.name] = res.value
+			continue
+		}
+		if errors.Is(res.err, context.DeadlineExceeded) || errors.Is(res.err, context.Canceled) {
+			missing[res.name] = "timeout"
+			continue
+		}
+		missing[res.name] = "error"
+		r.Log(res.err)
+		var pe *PanicError
+		if errors.As(res.err, &pe) {
+			code = http.StatusInternalServerError
+		} else if code != http.StatusInternalServerError {
+			code = http.StatusBadGateway
+		}
+	}
+
+	nr := r
+	if len(missing) > 0 {
+		nr = nr.WithMeta(metaPartial, true).WithMeta(metaMissing, missing)
+	}
+	if len(data) == 0 && len(parts) > 0 {
+		return nr.WithStatus(code).Push(nr.writer, Response{
+			Status:  StatusError,
+			Message: "no parts completed",
+		})
+	}
+	return nr.WithStatus(http.StatusOK).Push(nr.writer, Response{
+		Status: StatusSuccessful,
+		Data:   data,
+	})
+}
@@ -0,0 +1,82 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRenderer_Best(t *testing.T) {
+	fast := Part("profile", time.Second, func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"name": "ada"}, nil
+	})
+	slow := Part("recommendations", 10*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		time.Sleep(200 * time.Millisecond)
+		return []string{"late"}, nil
+	})
+	failing := Part("billing", time.Second, func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("billing down")
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		start := time.Now()
+		if err := NewRenderer(settings).WithWriter(rec).Best(context.Background(), fast, slow, failing); err != nil {
+			t.Fatalf("Best failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Errorf("Expected slow part to be abandoned, took %v", elapsed)
+		}
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		data, _ := resp.Data.(map[string]interface{})
+		if _, ok := data["profile"]; !ok || len(data) != 1 {
+			t.Errorf("Expected only profile in data, got %+v", resp.Data)
+		}
+		missing, _ := resp.Meta[metaMissing].(map[string]interface{})
+		if missing["recommendations"] != "timeout" || missing["billing"] != "error" {
+			t.Errorf("Expected missing parts in meta, got %+v", resp.Meta)
+		}
+		if resp.Meta[metaPartial] != true {
+			t.Errorf("Expected partial flag, got %+v", resp.Meta)
+		}
+	})
+
+	t.Run("NothingCompleted", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(rec).Best(context.Background(), slow); err != nil {
+			t.Fatalf("Best failed: %v", err)
+		}
+		if rec.Code != http.StatusGatewayTimeout {
+			t.Errorf("Expected status 504, got %d", rec.Code)
+		}
+	})
+
+	t.Run("NothingCompletedFailed", func(t *testing.T) {
+		panicking := Part("search", time.Second, func(ctx context.Context) (interface{}, error) {
+			panic("index missing")
+		})
+		for _, tc := range []struct {
+			name  string
+			parts []PartFunc
+			code  int
+		}{
+			{"Error", []PartFunc{slow, failing}, http.StatusBadGateway},
+			{"Panic", []PartFunc{failing, panicking}, http.StatusInternalServerError},
+		} {
+			rec := httptest.NewRecorder()
+			if err := NewRenderer(settings).WithWriter(rec).Best(context.Background(), tc.parts...); err != nil {
+				t.Fatalf("%s: Best failed: %v", tc.name, err)
+			}
+			if rec.Code != tc.code {
+				t.Errorf("%s: expected status %d, got %d", tc.name, tc.code, rec.Code)
+			}
+		}
+	})
+}
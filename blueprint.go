@@ -0,0 +1,95 @@
+package beam
+
+import (
+	"errors"
+	"maps"
+)
+
+// errBlueprintUnknown is returned when rendering a blueprint that was never registered.
+var errBlueprintUnknown = errors.New("unknown blueprint")
+
+// Blueprint is a reusable Response skeleton referenced by name.
+// Registered once on a base Renderer with WithBlueprint and stamped out per
+// request with Renderer.Blueprint, so identically shaped responses stay consistent.
+type Blueprint struct {
+	Status  string                 // Response status; defaults to StatusSuccessful
+	Code    int                    // HTTP status code; defaults from Status when zero
+	Title   string                 // Response title
+	Message string                 // Default message
+	Tags    []string               // Tags added to the response
+	Actions []Action               // Actions added to the response
+	Meta    map[string]interface{} // Meta merged into the response
+}
+
+// WithBlueprint registers a named Response skeleton.
+// Renderers derived from the result can render it with Blueprint(name).
+// Returns a new Renderer with the blueprint registered.
+func (r *Renderer) WithBlueprint(name string, bp Blueprint) *Renderer {
+	nr := r.clone()
+	// Copy on write so renderers sharing the previous map are unaffected.
+	nr.blueprints = maps.Clone(r.blueprints)
+	if nr.blueprints == nil {
+		nr.blueprints = make(map[string]Blueprint)
+	}
+	nr.blueprints[name] = bp
+	return nr
+}
+
+// Blueprint starts a response from the named skeleton.
+// Unknown names are reported when the response is sent.
+// Returns a Draft whose terminal methods render the response.
+func (r *Renderer) Blueprint(name string) *Draft {
+	bp, ok := r.blueprints[name]
+	if !ok {
+		return &Draft{r: r, err: errors.Join(errBlueprintUnknown, errors.New(name))}
+	}
+	nr := r.WithTag(bp.Tags...).WithAction(bp.Actions...)
+	for k, v := range bp.Meta {
+		nr = nr.WithMeta(k, v)
+	}
+	if bp.Code != 0 {
+		nr = nr.WithStatus(bp.Code)
+	}
+	return &Draft{r: nr, resp: Response{Status: bp.Status, Title: bp.Title, Message: bp.Message}}
+}
+
+// Draft is a blueprint response waiting for its payload.
+type Draft struct {
+	r    *Renderer
+	resp Response
+	err  error
+}
+
+// Message overrides the blueprint's default message.
+// Returns the Draft for chaining.
+func (d *Draft) Message(msg string) *Draft {
+	d.resp.Message = msg
+	return d
+}
+
+// Data renders the blueprint with v as Response.Data.
+// Returns an error if the blueprint is unknown, no writer is bound, or sending fails.
+func (d *Draft) Data(v interface{}) error {
+	d.resp.Data = v
+	return d.Send()
+}
+
+// Info renders the blueprint with v as Response.Info.
+// Returns an error if the blueprint is unknown, no writer is bound, or sending fails.
+func (d *Draft) Info(v interface{}) error {
+	d.resp.Info = v
+	return d.Send()
+}
+
+// Send renders the blueprint as is.
+// Returns an error if the blueprint is unknown, a *ConfigError if no writer is
+// bound, or an error if sending fails.
+func (d *Draft) Send() error {
+	if d.err != nil {
+		return d.err
+	}
+	if err := d.r.checkConfig(d.r.writer); err != nil {
+		return err
+	}
+	return d.r.Push(d.r.writer, d.resp)
+}
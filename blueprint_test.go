@@ -0,0 +1,47 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBlueprint(t *testing.T) {
+	base := NewRenderer(settings).WithBlueprint("user.created", Blueprint{
+		Code:    http.StatusCreated,
+		Title:   "User created",
+		Message: "welcome aboard",
+		Tags:    []string{"users"},
+		Actions: []Action{{Name: "view", Method: http.MethodGet, Href: "/users/{id}"}},
+		Meta:    map[string]interface{}{"version": 2},
+	})
+
+	t.Run("Data", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := base.WithWriter(rec).Blueprint("user.created").Data(map[string]string{"name": "ada"}); err != nil {
+			t.Fatalf("Blueprint failed: %v", err)
+		}
+		if rec.Code != http.StatusCreated {
+			t.Errorf("Expected status 201, got %d", rec.Code)
+		}
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if resp.Title != "User created" || resp.Message != "welcome aboard" {
+			t.Errorf("Expected blueprint title and message, got %+v", resp)
+		}
+		if len(resp.Tags) != 1 || len(resp.Actions) != 1 || resp.Meta["version"] != float64(2) {
+			t.Errorf("Expected blueprint tags, actions, and meta, got %+v", resp)
+		}
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		err := base.WithWriter(httptest.NewRecorder()).Blueprint("missing").Data(nil)
+		if !errors.Is(err, errBlueprintUnknown) {
+			t.Errorf("Expected errBlueprintUnknown, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package beam
+
+import (
+	"slices"
+)
+
+// bodyLimit is one WithMaxBodySize setting, replayed onto the Hauler used by Request.
+type bodyLimit struct {
+	limit        int64
+	contentTypes []string
+}
+
+// WithMaxBodySize limits request bodies read by Request to limit bytes.
+// Without contentTypes the limit applies to every content type; otherwise to
+// those media types only, overriding the default. Later calls take precedence.
+// Oversized bodies fail with hauler.ErrBodyTooLarge, which Error and Handler
+// answer with 413 Request Entity Too Large.
+// Returns a new Renderer with the added limit.
+func (r *Renderer) WithMaxBodySize(limit int64, contentTypes ...string) *Renderer {
+	nr := r.clone()
+	nr.bodyLimits = append(slices.Clip(r.bodyLimits), bodyLimit{limit: limit, contentTypes: slices.Clone(contentTypes)})
+	return nr
+}
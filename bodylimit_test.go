@@ -0,0 +1,42 @@
+package beam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+func TestWithMaxBodySize(t *testing.T) {
+	base := NewRenderer(settings)
+	h := base.WithMaxBodySize(16).Handler(func(r *Renderer) error {
+		var data map[string]string
+		if err := r.Request(r.request, &data); err != nil {
+			return err
+		}
+		return r.Msg("ok")
+	})
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", hauler.ContentTypeJSON)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"a":"b"}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for small body, got %d", rec.Code)
+	}
+	rec := post(`{"name":"` + strings.Repeat("x", 64) + `"}`)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for large body, got %d: %s", rec.Code, rec.Body)
+	}
+
+	// Limits set on a derived renderer do not leak into the base renderer.
+	if len(base.bodyLimits) != 0 {
+		t.Error("Expected base renderer without body limits")
+	}
+}
@@ -0,0 +1,75 @@
+package beam
+
+import "sync/atomic"
+
+// EventPublisher receives CallbackData from a CallbackBridge.
+// Publish must not block; it returns false when the event could not be
+// accepted, such as when the bus is full, and the bridge counts it as dropped.
+type EventPublisher interface {
+	Publish(data CallbackData) bool
+}
+
+// EventPublisherFunc adapts a function to the EventPublisher interface.
+type EventPublisherFunc func(data CallbackData) bool
+
+// Publish calls f(data).
+func (f EventPublisherFunc) Publish(data CallbackData) bool { return f(data) }
+
+// BridgeStats reports a CallbackBridge's delivery counters.
+type BridgeStats struct {
+	Published uint64 // Events accepted by the publisher
+	Dropped   uint64 // Events the publisher could not accept
+}
+
+// CallbackBridge forwards CallbackData to a channel or event bus without
+// blocking the response path, so decoupled consumers such as metrics or audit
+// pipelines can observe responses through a single callback.
+// Safe for concurrent use by any number of Renderers.
+type CallbackBridge struct {
+	pub       EventPublisher
+	published atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// NewCallbackBridge creates a bridge publishing to pub.
+func NewCallbackBridge(pub EventPublisher) *CallbackBridge {
+	return &CallbackBridge{pub: pub}
+}
+
+// NewChannelBridge creates a bridge sending to ch.
+// Events are dropped instead of waiting when ch is full.
+func NewChannelBridge(ch chan<- CallbackData) *CallbackBridge {
+	return NewCallbackBridge(EventPublisherFunc(func(data CallbackData) bool {
+		select {
+		case ch <- data:
+			return true
+		default:
+			return false
+		}
+	}))
+}
+
+// Callback returns the function to register with WithCallback.
+func (b *CallbackBridge) Callback() func(data CallbackData) {
+	return b.publish
+}
+
+// publish hands data to the publisher and counts the outcome.
+func (b *CallbackBridge) publish(data CallbackData) {
+	if b.pub.Publish(data) {
+		b.published.Add(1)
+	} else {
+		b.dropped.Add(1)
+	}
+}
+
+// Stats returns a snapshot of the bridge's counters.
+func (b *CallbackBridge) Stats() BridgeStats {
+	return BridgeStats{Published: b.published.Load(), Dropped: b.dropped.Load()}
+}
+
+// WithEventBridge registers b's callback, forwarding every callback event to its publisher.
+// Returns a new Renderer with the bridge attached.
+func (r *Renderer) WithEventBridge(b *CallbackBridge) *Renderer {
+	return r.WithCallback(b.Callback())
+}
@@ -0,0 +1,32 @@
+package beam
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallbackBridge(t *testing.T) {
+	ch := make(chan CallbackData, 1)
+	bridge := NewChannelBridge(ch)
+	r := NewRenderer(settings).WithEventBridge(bridge)
+
+	_ = r.WithWriter(httptest.NewRecorder()).Msg("first")
+	_ = r.WithWriter(httptest.NewRecorder()).Msg("second") // Channel full: dropped, not blocked
+
+	if got := <-ch; got.Status != StatusSuccessful || got.Message != "first" {
+		t.Errorf("Unexpected event %+v", got)
+	}
+	if stats := bridge.Stats(); stats.Published != 1 || stats.Dropped != 1 {
+		t.Errorf("Expected 1 published and 1 dropped, got %+v", stats)
+	}
+
+	var seen []string
+	bus := NewCallbackBridge(EventPublisherFunc(func(d CallbackData) bool {
+		seen = append(seen, d.Message)
+		return true
+	}))
+	_ = NewRenderer(settings).WithEventBridge(bus).WithWriter(httptest.NewRecorder()).Msg("hello")
+	if len(seen) != 1 || seen[0] != "hello" || bus.Stats().Published != 1 {
+		t.Errorf("Expected event published to bus, got %v", seen)
+	}
+}
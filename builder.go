@@ -0,0 +1,109 @@
+package beam
+
+// Builder constructs a Response fluently and pushes it with the bound writer.
+// Obtain one with Renderer.New; each setter returns the Builder for chaining.
+// A Builder is not safe for concurrent use.
+type Builder struct {
+	r       *Renderer
+	resp    Response
+	code    int
+	tags    []string
+	actions []Action
+	meta    map[string]interface{}
+}
+
+// New starts a fluent Response builder on the Renderer.
+// Returns a Builder that renders via Send.
+func (r *Renderer) New() *Builder {
+	return &Builder{r: r}
+}
+
+// Status sets the response status (e.g., StatusSuccessful, StatusError).
+func (b *Builder) Status(status string) *Builder {
+	b.resp.Status = status
+	return b
+}
+
+// Code sets the HTTP status code; when unset it is derived from the status.
+func (b *Builder) Code(code int) *Builder {
+	b.code = code
+	return b
+}
+
+// Title sets the response title.
+func (b *Builder) Title(title string) *Builder {
+	b.resp.Title = title
+	return b
+}
+
+// Message sets the response message.
+func (b *Builder) Message(msg string) *Builder {
+	b.resp.Message = msg
+	return b
+}
+
+// Info sets Response.Info.
+func (b *Builder) Info(info interface{}) *Builder {
+	b.resp.Info = info
+	return b
+}
+
+// Data sets Response.Data.
+func (b *Builder) Data(data interface{}) *Builder {
+	b.resp.Data = data
+	return b
+}
+
+// Error appends errors to the response, skipping nils.
+func (b *Builder) Error(errs ...error) *Builder {
+	for _, err := range errs {
+		if err != nil {
+			b.resp.Errors = append(b.resp.Errors, err)
+		}
+	}
+	return b
+}
+
+// Tag appends tags to the response.
+func (b *Builder) Tag(tags ...string) *Builder {
+	b.tags = append(b.tags, tags...)
+	return b
+}
+
+// Action appends actions to the response.
+func (b *Builder) Action(actions ...Action) *Builder {
+	b.actions = append(b.actions, actions...)
+	return b
+}
+
+// Meta sets a metadata key on the response.
+func (b *Builder) Meta(key string, value interface{}) *Builder {
+	if b.meta == nil {
+		b.meta = make(map[string]interface{})
+	}
+	b.meta[key] = value
+	return b
+}
+
+// Send pushes the built response using the Renderer's bound writer.
+// Returns a *ConfigError if no writer is bound or configuration is missing, or
+// an error if pushing fails.
+func (b *Builder) Send() error {
+	nr := b.r
+	if err := nr.checkConfig(nr.writer); err != nil {
+		return err
+	}
+	if len(b.tags) > 0 {
+		nr = nr.WithTag(b.tags...)
+	}
+	if len(b.actions) > 0 {
+		nr = nr.WithAction(b.actions...)
+	}
+	for k, v := range b.meta {
+		nr = nr.WithMeta(k, v)
+	}
+	if b.code != 0 {
+		nr = nr.WithStatus(b.code)
+	}
+	return nr.Push(nr.writer, b.resp)
+}
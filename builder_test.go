@@ -0,0 +1,46 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := NewRenderer(settings).WithWriter(rec).New().
+		Status(StatusError).
+		Code(http.StatusConflict).
+		Message("already exists").
+		Data(map[string]int{"id": 7}).
+		Action(Action{Name: "view", Href: "/items/7"}).
+		Tag("items").
+		Meta("retry", false).
+		Error(errors.New("duplicate key"), nil).
+		Send()
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", rec.Code)
+	}
+	var resp struct {
+		Response
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if resp.Status != StatusError || resp.Message != "already exists" {
+		t.Errorf("Unexpected status or message: %+v", resp.Response)
+	}
+	if len(resp.Actions) != 1 || len(resp.Tags) != 1 || resp.Meta["retry"] != false || len(resp.Errors) != 1 {
+		t.Errorf("Expected actions, tags, meta, and errors, got %s", rec.Body.String())
+	}
+
+	if err := NewRenderer(settings).New().Send(); !errors.Is(err, errNoWriter) {
+		t.Errorf("Expected errNoWriter without a writer, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package beam
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCallbackPanicRecovery(t *testing.T) {
+	logger := &TestLogger{}
+	panics, calls := 0, 0
+	r := NewRenderer(settings).
+		WithLogger(logger).
+		WithCallback(func(CallbackData) {
+			panics++
+			panic("bad callback")
+		}).
+		WithCallback(func(CallbackData) { calls++ })
+
+	rec := httptest.NewRecorder()
+	if err := r.WithWriter(rec).Msg("first"); err != nil {
+		t.Fatalf("Expected response despite panicking callback, got %v", err)
+	}
+	if calls != 1 || panics != 1 {
+		t.Fatalf("Expected both callbacks to run once, got calls=%d panics=%d", calls, panics)
+	}
+	var pe *CallbackPanicError
+	if entry := logger.LastEntry(); entry == nil || !errors.As(entry.Err, &pe) || pe.Value != "bad callback" {
+		t.Fatalf("Expected logged CallbackPanicError, got %+v", logger.Entries)
+	}
+
+	// A panic is reported, not fatal: the callback still runs for later responses.
+	_ = r.WithWriter(httptest.NewRecorder()).Msg("second")
+	if calls != 2 || panics != 2 {
+		t.Errorf("Expected both callbacks to run again, got calls=%d panics=%d", calls, panics)
+	}
+	if n := r.callbacks.Len(); n != 2 {
+		t.Errorf("Expected 2 callbacks, got %d", n)
+	}
+}
+
+func TestWithoutCallback(t *testing.T) {
+	var got []string
+	base, h := NewRenderer(settings).WithCallbackHandle(func(d CallbackData) { got = append(got, "a") })
+	base = base.WithCallback(func(d CallbackData) { got = append(got, "b") })
+
+	r := base.WithoutCallback(h)
+	_ = r.callbacks.Trigger("id", StatusSuccessful, "", nil)
+	if len(got) != 1 || got[0] != "b" {
+		t.Errorf("Expected only b after removal, got %v", got)
+	}
+
+	got = nil
+	_ = base.callbacks.Trigger("id", StatusSuccessful, "", nil)
+	if len(got) != 2 {
+		t.Errorf("Expected base to keep both callbacks, got %v", got)
+	}
+	if r.callbacks.Remove(h) {
+		t.Error("Expected second removal to report nothing removed")
+	}
+}
@@ -0,0 +1,92 @@
+package beam
+
+import (
+	"slices"
+	"strings"
+)
+
+// HeaderClientFeatures is the request header clients use to advertise capabilities.
+// Holds a comma-separated list of feature names (e.g., "tags, actions, meta.slow").
+const HeaderClientFeatures = "X-Client-Features"
+
+// Optional envelope fields that can be gated on client capabilities.
+// Meta keys are gated individually as "meta.<key>".
+const (
+	FieldTitle   = "title"
+	FieldTags    = "tags"
+	FieldInfo    = "info"
+	FieldMeta    = "meta"
+	FieldActions = "actions"
+	FieldTypes   = "types" // info_type and data_type
+)
+
+// WithOptionalFields marks envelope fields as optional.
+// Optional fields are sent only to clients that list them in X-Client-Features,
+// so older clients are not broken by new additions and constrained devices get smaller payloads.
+// Returns a new Renderer with the fields added.
+func (r *Renderer) WithOptionalFields(fields ...string) *Renderer {
+	nr := r.clone()
+	nr.optional = append(slices.Clone(r.optional), fields...)
+	return nr
+}
+
+// Features returns the capabilities advertised by the bound request.
+// Returns nil when no request is bound or the header is absent.
+func (r *Renderer) Features() []string {
+	if r.request == nil {
+		return nil
+	}
+	var features []string
+	for _, value := range r.request.Header.Values(HeaderClientFeatures) {
+		for _, f := range strings.Split(value, ",") {
+			if f = strings.ToLower(strings.TrimSpace(f)); f != Empty {
+				features = append(features, f)
+			}
+		}
+	}
+	return features
+}
+
+// Supports reports whether the client advertised the given feature.
+func (r *Renderer) Supports(feature string) bool {
+	return slices.Contains(r.Features(), strings.ToLower(feature))
+}
+
+// negotiateFields strips optional fields and feature-gated actions the client did not advertise.
+func (r *Renderer) negotiateFields(resp *Response) {
+	hasGated := slices.ContainsFunc(resp.Actions, func(a Action) bool { return a.Feature != Empty })
+	if len(r.optional) == 0 && !hasGated {
+		return
+	}
+	features := r.Features()
+	supported := func(f string) bool { return slices.Contains(features, strings.ToLower(f)) }
+
+	for _, field := range r.optional {
+		if supported(field) {
+			continue
+		}
+		switch field {
+		case FieldTitle:
+			resp.Title = Empty
+		case FieldTags:
+			resp.Tags = nil
+		case FieldInfo:
+			resp.Info = nil
+		case FieldMeta:
+			resp.Meta = nil
+		case FieldActions:
+			resp.Actions = nil
+		case FieldTypes:
+			resp.InfoType, resp.DataType = Empty, Empty
+		default:
+			if key, ok := strings.CutPrefix(field, FieldMeta+"."); ok {
+				delete(resp.Meta, key)
+			}
+		}
+	}
+	if hasGated {
+		resp.Actions = slices.DeleteFunc(resp.Actions, func(a Action) bool {
+			return a.Feature != Empty && !supported(a.Feature)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientCapabilities(t *testing.T) {
+	base := NewRenderer(settings).
+		WithTag("beta").
+		WithMeta("slow", true).
+		WithMeta("page", 1).
+		WithAction(Action{Name: "self"}, Action{Name: "share", Feature: "share"}).
+		WithOptionalFields(FieldTags, "meta.slow")
+
+	push := func(t *testing.T, features string) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/", nil)
+		if features != "" {
+			req.Header.Set(HeaderClientFeatures, features)
+		}
+		w := httptest.NewRecorder()
+		if err := base.WithRequest(req).Push(w, Response{Data: "ok"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		return body
+	}
+
+	t.Run("LegacyClient", func(t *testing.T) {
+		body := push(t, "")
+		if _, ok := body["tags"]; ok {
+			t.Errorf("Expected tags to be stripped, got %v", body["tags"])
+		}
+		meta := body["meta"].(map[string]interface{})
+		if _, ok := meta["slow"]; ok || meta["page"] == nil {
+			t.Errorf("Expected only meta.slow stripped, got %v", meta)
+		}
+		if actions := body["actions"].([]interface{}); len(actions) != 1 {
+			t.Errorf("Expected gated action to be stripped, got %v", actions)
+		}
+	})
+
+	t.Run("CapableClient", func(t *testing.T) {
+		body := push(t, "Tags, meta.slow,share")
+		if _, ok := body["tags"]; !ok {
+			t.Error("Expected tags to be included")
+		}
+		if meta := body["meta"].(map[string]interface{}); meta["slow"] != true {
+			t.Errorf("Expected meta.slow to be included, got %v", meta)
+		}
+		if actions := body["actions"].([]interface{}); len(actions) != 2 {
+			t.Errorf("Expected both actions, got %v", actions)
+		}
+	})
+
+	t.Run("Supports", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Add(HeaderClientFeatures, "a, b")
+		req.Header.Add(HeaderClientFeatures, "C")
+		r := NewRenderer(settings).WithRequest(req)
+		if !r.Supports("c") || !r.Supports("B") || r.Supports("d") {
+			t.Errorf("Expected features [a b c], got %v", r.Features())
+		}
+	})
+}
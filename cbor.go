@@ -0,0 +1,57 @@
+package beam
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// ContentTypeCBOR is the content type for Concise Binary Object Representation (RFC 8949).
+const ContentTypeCBOR = "application/cbor"
+
+// CBOREncoder encodes data as CBOR.
+// Struct fields use `cbor` tags and fall back to `json` tags, so Response
+// encodes with the same field names as JSON.
+type CBOREncoder struct{}
+
+// Marshal encodes v as CBOR.
+// Returns the encoded bytes or an error if encoding fails.
+func (e *CBOREncoder) Marshal(v interface{}) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+// Unmarshal decodes CBOR data into the provided pointer.
+// Returns an error if decoding fails.
+func (e *CBOREncoder) Unmarshal(data []byte, v interface{}) error {
+	return cbor.Unmarshal(data, v)
+}
+
+// ContentType returns the CBOR content type.
+// Returns the constant "application/cbor".
+func (e *CBOREncoder) ContentType() string {
+	return ContentTypeCBOR
+}
+
+// MarshalCBOR encodes an ErrorList as an array of {code, message} maps.
+// Implements cbor.Marshaler so errors are not lost as empty maps.
+func (el ErrorList) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(el.items())
+}
+
+// UnmarshalCBOR decodes an ErrorList from {code, message} maps or plain strings.
+// Implements cbor.Unmarshaler.
+func (el *ErrorList) UnmarshalCBOR(data []byte) error {
+	var raw []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*el = make(ErrorList, len(raw))
+	for i, r := range raw {
+		var it ErrorItem
+		if err := cbor.Unmarshal(r, &it.Message); err != nil {
+			if err := cbor.Unmarshal(r, &it); err != nil {
+				return err
+			}
+		}
+		(*el)[i] = it.err()
+	}
+	return nil
+}
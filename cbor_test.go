@@ -0,0 +1,29 @@
+package beam
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCBOREncoder(t *testing.T) {
+	e := &CBOREncoder{}
+	out, err := e.Marshal(Response{
+		Status: StatusError,
+		Data:   map[string]int{"n": 1},
+		Errors: ErrorList{errors.New("plain"), &codedError{code: "E1", msg: "coded"}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var back Response
+	if err := e.Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if back.Status != StatusError || len(back.Errors) != 2 {
+		t.Fatalf("Expected status and 2 errors, got %+v", back)
+	}
+	var coder ErrorCoder
+	if back.Errors[0].Error() != "plain" || !errors.As(back.Errors[1], &coder) || coder.ErrorCode() != "E1" {
+		t.Errorf("Expected plain and coded E1 errors, got %v", back.Errors)
+	}
+}
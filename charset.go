@@ -0,0 +1,172 @@
+package beam
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// Charsets supported by WithCharsets.
+const (
+	CharsetUTF8     = "utf-8"
+	CharsetLatin1   = "iso-8859-1"
+	CharsetShiftJIS = "shift_jis"
+)
+
+// errUnsupportedCharset reports a charset WithCharsets cannot produce.
+var errUnsupportedCharset = errors.New("unsupported charset")
+
+// XML declaration parts rewritten when transcoding XML.
+var (
+	xmlDeclEncoding = regexp.MustCompile(`encoding\s*=\s*("[^"]*"|'[^']*')`)
+	xmlDeclVersion  = regexp.MustCompile(`version\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// charsetEncodings maps the legacy charsets to their encoders.
+var charsetEncodings = map[string]encoding.Encoding{
+	CharsetLatin1:   charmap.ISO8859_1,
+	CharsetShiftJIS: japanese.ShiftJIS,
+}
+
+// WithCharsets offers legacy charsets besides UTF-8 for clients that cannot
+// consume it, e.g. CharsetLatin1 or CharsetShiftJIS. Textual Push and Raw
+// bodies are transcoded after encoding and before compression when the
+// request's Accept-Charset prefers one of them; Content-Type then carries the
+// charset parameter, and XML declarations are rewritten to name the charset.
+// Requires the request bound with WithRequest.
+// Returns a new Renderer with the charsets offered; none disables transcoding.
+func (r *Renderer) WithCharsets(charsets ...string) *Renderer {
+	nr := r.clone()
+	nr.charsets = nil
+	for _, c := range charsets {
+		c = charsetAlias(strings.ToLower(c))
+		if _, ok := charsetEncodings[c]; !ok {
+			if r.logger != nil {
+				warnTo(r.logger, errUnsupportedCharset, "charset", c)
+			}
+			continue
+		}
+		nr.charsets = append(nr.charsets, c)
+	}
+	return nr
+}
+
+// transcode converts a UTF-8 body of contentType to the negotiated charset.
+// Leaves the body untouched if no legacy charset is preferred, the content
+// type is binary, or the body holds characters the charset cannot represent.
+// Returns the body to write.
+func (r *Renderer) transcode(body []byte, contentType string) []byte {
+	if len(r.charsets) == 0 || !textual(contentType) {
+		return body
+	}
+	addVary(r.header, "Accept-Charset")
+	if r.request == nil {
+		return body
+	}
+	charset := negotiateCharset(r.request.Header.Values("Accept-Charset"), r.charsets)
+	if charset == Empty || charset == CharsetUTF8 {
+		return body
+	}
+	src := body
+	if strings.Contains(contentType, "xml") {
+		src = xmlDeclare(body, charset)
+	}
+	out, err := charsetEncodings[charset].NewEncoder().Bytes(src)
+	if err != nil {
+		if r.logger != nil {
+			warnTo(r.logger, err, "id", r.id, "charset", charset)
+		}
+		return body
+	}
+	r.charset = charset
+	return out
+}
+
+// xmlDeclare returns body with its XML declaration naming charset, adding a
+// declaration if there is none: parsers trust the declaration over the
+// Content-Type header once it is saved to a file.
+func xmlDeclare(body []byte, charset string) []byte {
+	encoding := []byte(`encoding="` + charset + `"`)
+	end := bytes.Index(body, []byte("?>"))
+	if !bytes.HasPrefix(body, []byte("<?xml")) || end < 0 {
+		decl := append([]byte(`<?xml version="1.0" `), encoding...)
+		return append(append(decl, "?>\n"...), body...)
+	}
+	decl := body[:end]
+	if loc := xmlDeclEncoding.FindIndex(decl); loc != nil {
+		decl = slices.Concat(decl[:loc[0]], encoding, decl[loc[1]:])
+	} else if loc := xmlDeclVersion.FindIndex(decl); loc != nil {
+		decl = slices.Concat(decl[:loc[1]], []byte(" "), encoding, decl[loc[1]:])
+	}
+	return slices.Concat(decl, body[end:])
+}
+
+// charsetType appends the transcoded charset to contentType.
+func (r *Renderer) charsetType(contentType string) string {
+	if r.charset == Empty {
+		return contentType
+	}
+	return contentType + "; charset=" + r.charset
+}
+
+// negotiateCharset picks the charset best matching the Accept-Charset values
+// among UTF-8 and offers, honoring q weights and preferring UTF-8 on ties.
+// A missing header or "*" accepts UTF-8.
+// Returns Empty if nothing is acceptable.
+func negotiateCharset(accept []string, offers []string) string {
+	if len(accept) == 0 {
+		return CharsetUTF8
+	}
+	q := make(map[string]float64)
+	for _, v := range accept {
+		for _, item := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == Empty {
+				continue
+			}
+			weight := 1.0
+			if k, val, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					weight = f
+				}
+			}
+			q[charsetAlias(name)] = weight
+		}
+	}
+
+	weight := func(charset string) float64 {
+		if w, ok := q[charset]; ok {
+			return w
+		}
+		return q["*"]
+	}
+	candidates := append([]string{CharsetUTF8}, offers...)
+	// MaxFunc keeps the first of equal candidates, so UTF-8 wins ties.
+	best := slices.MaxFunc(candidates, func(a, b string) int { return cmp.Compare(weight(a), weight(b)) })
+	if weight(best) <= 0 {
+		return Empty
+	}
+	return best
+}
+
+// charsetAlias returns the canonical name of a charset label.
+func charsetAlias(name string) string {
+	switch name {
+	case "utf8":
+		return CharsetUTF8
+	case "latin1", "latin-1", "iso_8859-1", "iso8859-1":
+		return CharsetLatin1
+	case "shift-jis", "sjis", "ms_kanji", "csshiftjis":
+		return CharsetShiftJIS
+	}
+	return name
+}
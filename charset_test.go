@@ -0,0 +1,129 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestNegotiateCharset(t *testing.T) {
+	offers := []string{CharsetLatin1, CharsetShiftJIS}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", CharsetUTF8},
+		{"iso-8859-1", CharsetLatin1},
+		{"Shift_JIS, utf-8;q=0.5", CharsetShiftJIS},
+		{"latin1, utf-8", CharsetUTF8},
+		{"*", CharsetUTF8},
+		{"utf-8;q=0, *;q=0.3", CharsetLatin1},
+		{"koi8-r", ""},
+	}
+	for _, tt := range tests {
+		var accept []string
+		if tt.accept != Empty {
+			accept = []string{tt.accept}
+		}
+		if got := negotiateCharset(accept, offers); got != tt.want {
+			t.Errorf("negotiateCharset(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestCharsets(t *testing.T) {
+	r := NewRenderer(settings).WithCharsets("latin1", "sjis")
+	serve := func(r *Renderer, accept string, data interface{}) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != Empty {
+			req.Header.Set("Accept-Charset", accept)
+		}
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).WithRequest(req).Raw(data); err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("Latin1", func(t *testing.T) {
+		w := serve(r, "iso-8859-1", map[string]string{"city": "Zürich"})
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeJSON+"; charset=iso-8859-1" {
+			t.Errorf("Expected the charset parameter, got %q", got)
+		}
+		if got := w.Header().Get("Vary"); got != "Accept-Charset" {
+			t.Errorf("Expected Vary: Accept-Charset, got %q", got)
+		}
+		if want := "{\"city\":\"Z\xfcrich\"}"; w.Body.String() != want {
+			t.Errorf("Expected Latin-1 bytes %q, got %q", want, w.Body.String())
+		}
+	})
+
+	t.Run("ShiftJIS", func(t *testing.T) {
+		w := serve(r, "shift_jis", map[string]string{"city": "東京"})
+		body, err := japanese.ShiftJIS.NewDecoder().Bytes(w.Body.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != "{\"city\":\"東京\"}" {
+			t.Errorf("Expected the Shift-JIS body to decode, got %q", body)
+		}
+	})
+
+	t.Run("Unrepresentable", func(t *testing.T) {
+		w := serve(r, "iso-8859-1", map[string]string{"city": "東京"})
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeJSON {
+			t.Errorf("Expected UTF-8 output, got %q", got)
+		}
+	})
+
+	t.Run("UTF8", func(t *testing.T) {
+		w := serve(r, Empty, "Zürich")
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeJSON {
+			t.Errorf("Expected no transcoding, got %q", got)
+		}
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		w := serve(r.WithContentType(ContentTypeXML), "iso-8859-1", "Zürich")
+		if want := "<?xml version=\"1.0\" encoding=\"iso-8859-1\"?>"; !strings.HasPrefix(w.Body.String(), want) {
+			t.Errorf("Expected the declaration to name the charset, got %q", w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "Z\xfcrich") {
+			t.Errorf("Expected Latin-1 bytes, got %q", w.Body.String())
+		}
+		for in, want := range map[string]string{
+			"<a/>":                      "<?xml version=\"1.0\" encoding=\"shift_jis\"?>\n<a/>",
+			"<?xml version='1.0'?><a/>": "<?xml version='1.0' encoding=\"shift_jis\"?><a/>",
+			"<?xml version=\"1.0\" encoding='utf-8'?><a/>": "<?xml version=\"1.0\" encoding=\"shift_jis\"?><a/>",
+		} {
+			if got := string(xmlDeclare([]byte(in), CharsetShiftJIS)); got != want {
+				t.Errorf("xmlDeclare(%q) = %q, want %q", in, got, want)
+			}
+		}
+	})
+
+	t.Run("ProblemType", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Charset", "iso-8859-1")
+		w := httptest.NewRecorder()
+		_ = r.WithContentType(ContentTypeXML).WithProblemDetails(Yes).WithWriter(w).WithRequest(req).
+			WithStatus(http.StatusNotFound).Error(errors.New("Zürich not found"))
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeProblemXML+"; charset=iso-8859-1" {
+			t.Errorf("Expected the problem type with the charset, got %q", got)
+		}
+		if !strings.Contains(w.Body.String(), `encoding="iso-8859-1"`) {
+			t.Errorf("Expected the declaration to name the charset, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		w := serve(r.WithContentType(ContentTypeMsgPack), "iso-8859-1", "Zürich")
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeMsgPack || w.Header().Get("Vary") != Empty {
+			t.Errorf("Expected binary output untouched, got %v", w.Header())
+		}
+	})
+}
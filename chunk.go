@@ -0,0 +1,75 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errChunkType reports a Chunk whose content type the stream's format cannot carry.
+var errChunkType = errors.New("chunk content type not supported by stream")
+
+// Chunk is a stream item that carries its own content type.
+// Returned from Stream callbacks so a single stream can interleave formats,
+// e.g. JSON status updates alongside binary frames.
+// Data of type []byte or string is written as-is; other values are encoded
+// with the encoder registered for ContentType. Streaming encoders that frame
+// items themselves (NDJSON, CSV, event streams) only accept Chunks of the
+// stream's own content type; multipart/x-mixed-replace sends each as a part.
+type Chunk struct {
+	ContentType string
+	Data        interface{}
+}
+
+// NewChunk creates a Chunk with the given content type and data.
+// Returns the Chunk value for use as a Stream callback result.
+func NewChunk(contentType string, data interface{}) Chunk {
+	return Chunk{ContentType: contentType, Data: data}
+}
+
+// encodeStreamItem encodes a single item produced by a Stream callback.
+// Chunks are encoded with their own content type (or written raw for bytes and strings);
+// any other value uses the Renderer's content type.
+// Returns the encoded bytes or an error if no encoder matches or encoding fails.
+func (r *Renderer) encodeStreamItem(data interface{}) ([]byte, error) {
+	c, ok := data.(Chunk)
+	if !ok {
+		return r.encoders.Encode(r.contentType, data)
+	}
+	switch v := c.Data.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	}
+	ct := c.ContentType
+	if ct == Empty {
+		ct = r.contentType
+	}
+	return r.encoders.Encode(ct, c.Data)
+}
+
+// ChunkAccepter is an optional interface for Streamer encoders that frame Chunks
+// themselves, such as MixedReplaceEncoder with its per-part content types.
+// Encoder middleware wrapping such an encoder should delegate AcceptsChunks to it.
+type ChunkAccepter interface {
+	AcceptsChunks() bool
+}
+
+// streamerItem prepares a Stream callback result for an encoder implementing
+// Streamer. Such encoders frame every item in their own format, so a Chunk is
+// unwrapped when its content type is empty or contentType, and rejected
+// otherwise; a ChunkAccepter that accepts Chunks receives them as is.
+// Returns the item to encode or an error wrapping errChunkType.
+func streamerItem(encoder Encoder, contentType string, data interface{}) (interface{}, error) {
+	c, ok := data.(Chunk)
+	if !ok {
+		return data, nil
+	}
+	if ca, ok := encoder.(ChunkAccepter); ok && ca.AcceptsChunks() {
+		return c, nil
+	}
+	if c.ContentType != Empty && c.ContentType != contentType {
+		return nil, fmt.Errorf("%w: %s in %s", errChunkType, c.ContentType, contentType)
+	}
+	return c.Data, nil
+}
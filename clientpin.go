@@ -0,0 +1,123 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// EnvelopeStandard is the envelope version of the full Response layout.
+const EnvelopeStandard = "standard"
+
+// HeaderAPIKey is the request header commonly carrying a client's API key.
+const HeaderAPIKey = "X-API-Key"
+
+// errClientPin reports an invalid client pin.
+var errClientPin = errors.New("invalid client pin")
+
+// ClientPin fixes the output format of one client regardless of what the
+// Renderer would otherwise pick. Empty fields keep the Renderer's choice.
+type ClientPin struct {
+	ContentType string // Output content type (e.g., ContentTypeXML)
+	Envelope    string // EnvelopeStandard or EnvelopeCompact
+}
+
+// ClientRegistry maps client identities, such as API keys or JWT subjects,
+// to the formats they are locked to. Share one registry between renderers via
+// WithClientPinning; pins may change while the server runs. Safe for concurrent use.
+type ClientRegistry struct {
+	mu   sync.RWMutex
+	pins map[string]ClientPin
+}
+
+// NewClientRegistry creates an empty ClientRegistry.
+func NewClientRegistry() *ClientRegistry {
+	return &ClientRegistry{pins: make(map[string]ClientPin)}
+}
+
+// Pin locks the client identified by id to pin, replacing any previous pin.
+// Returns an error if id is empty or the envelope version is unknown.
+func (c *ClientRegistry) Pin(id string, pin ClientPin) error {
+	if id == Empty {
+		return fmt.Errorf("%w: empty client id", errClientPin)
+	}
+	switch pin.Envelope {
+	case Empty, EnvelopeStandard, EnvelopeCompact:
+	default:
+		return fmt.Errorf("%w: unknown envelope %q", errClientPin, pin.Envelope)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pins[id] = pin
+	return nil
+}
+
+// Unpin removes the pin of the client identified by id.
+func (c *ClientRegistry) Unpin(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pins, id)
+}
+
+// Lookup returns the pin of the client identified by id.
+func (c *ClientRegistry) Lookup(id string) (ClientPin, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	pin, ok := c.pins[id]
+	return pin, ok
+}
+
+// APIKeyIdentity returns an identity hook reading the client's API key from header.
+// An empty header uses HeaderAPIKey.
+func APIKeyIdentity(header string) func(*http.Request) string {
+	if header == Empty {
+		header = HeaderAPIKey
+	}
+	return func(req *http.Request) string {
+		return req.Header.Get(header)
+	}
+}
+
+// WithClientPinning resolves the bound request's client with identify and
+// applies its pin from reg, overriding the configured content type and envelope.
+// identify returns the client identity, e.g. an API key or the subject of a
+// verified JWT, or Empty for anonymous clients. Pins are applied when a request
+// is bound, so later WithContentType or WithCompact calls still take precedence.
+// Returns a new Renderer with client pinning configured.
+func (r *Renderer) WithClientPinning(identify func(*http.Request) string, reg *ClientRegistry) *Renderer {
+	nr := r.clone()
+	nr.identify = identify
+	nr.clients = reg
+	nr.pinClient()
+	return nr
+}
+
+// pinClient applies the pin of the bound request's client, if any.
+// Pins naming a content type without a registered encoder are ignored with a warning.
+func (r *Renderer) pinClient() {
+	if r.request == nil || r.identify == nil || r.clients == nil {
+		return
+	}
+	id := r.identify(r.request)
+	if id == Empty {
+		return
+	}
+	pin, ok := r.clients.Lookup(id)
+	if !ok {
+		return
+	}
+	if pin.ContentType != Empty {
+		if _, ok := r.encoders.Get(pin.ContentType); ok {
+			r.contentType = pin.ContentType
+		} else if r.logger != nil {
+			warnTo(r.logger, fmt.Errorf("%w: no encoder for %q", errClientPin, pin.ContentType))
+		}
+	}
+	switch pin.Envelope {
+	case EnvelopeStandard:
+		r.compact = No
+	case EnvelopeCompact:
+		r.compact = Yes
+	}
+}
@@ -0,0 +1,72 @@
+package beam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientPinning(t *testing.T) {
+	reg := NewClientRegistry()
+	if err := reg.Pin("legacy-key", ClientPin{ContentType: ContentTypeXML, Envelope: EnvelopeStandard}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Pin("m2m-key", ClientPin{Envelope: EnvelopeCompact}); err != nil {
+		t.Fatal(err)
+	}
+	log := &TestLogger{}
+	r := NewRenderer(settings).WithLogger(log).WithClientPinning(APIKeyIdentity(Empty), reg)
+	serve := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if key != Empty {
+			req.Header.Set(HeaderAPIKey, key)
+		}
+		w := httptest.NewRecorder()
+		r.Handler(func(r *Renderer) error { return r.Data("ok", nil) })(w, req)
+		return w
+	}
+
+	t.Run("Pinned", func(t *testing.T) {
+		w := serve("legacy-key")
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ContentTypeXML) {
+			t.Errorf("Expected XML for the pinned client, got %q", ct)
+		}
+		w = serve("m2m-key")
+		if got := w.Header().Get(r.headerName(HeaderNameEnvelope)); got != EnvelopeCompact {
+			t.Errorf("Expected compact envelope, got %q", got)
+		}
+		if !strings.Contains(w.Body.String(), `"s":"+ok"`) {
+			t.Errorf("Expected compact body, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Unpinned", func(t *testing.T) {
+		for _, key := range []string{Empty, "unknown-key"} {
+			w := serve(key)
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ContentTypeJSON) {
+				t.Errorf("Expected default JSON for key %q, got %q", key, ct)
+			}
+		}
+		reg.Unpin("legacy-key")
+		if ct := serve("legacy-key").Header().Get("Content-Type"); !strings.HasPrefix(ct, ContentTypeJSON) {
+			t.Errorf("Expected JSON after unpinning, got %q", ct)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if err := reg.Pin("bad", ClientPin{Envelope: "v9"}); err == nil {
+			t.Error("Expected an unknown envelope to be rejected")
+		}
+		if err := reg.Pin(Empty, ClientPin{}); err == nil {
+			t.Error("Expected an empty client id to be rejected")
+		}
+		_ = reg.Pin("odd-key", ClientPin{ContentType: "application/x-unknown"})
+		if ct := serve("odd-key").Header().Get("Content-Type"); !strings.HasPrefix(ct, ContentTypeJSON) {
+			t.Errorf("Expected JSON when the pinned type has no encoder, got %q", ct)
+		}
+		if len(log.Entries) == 0 {
+			t.Error("Expected a warning for the unusable pin")
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package beam
+
+import (
+	"strconv"
+	"time"
+)
+
+// Clock supplies the current time.
+// Used for start times, Timestamp headers, durations, and ID generation,
+// so tests can freeze or advance time deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now calls f().
+func (f ClockFunc) Now() time.Time { return f() }
+
+// FixedClock returns a Clock that always reports t.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// TimestampFormat selects how the Timestamp header is rendered.
+type TimestampFormat int
+
+// Timestamp header formats.
+const (
+	TimestampUnix      TimestampFormat = iota // Seconds since the epoch (default)
+	TimestampUnixMilli                        // Milliseconds since the epoch
+	TimestampRFC3339                          // RFC 3339 in UTC with nanoseconds
+)
+
+// WithClock sets the time source used by the Renderer.
+// Also resets the start time so durations are measured on the new clock.
+// Returns a new Renderer using c; nil restores the system clock.
+func (r *Renderer) WithClock(c Clock) *Renderer {
+	nr := r.clone()
+	nr.clock = c
+	nr.start = nr.now()
+	return nr
+}
+
+// WithTimestampFormat sets the format of the Timestamp header.
+// Returns a new Renderer with the updated format.
+func (r *Renderer) WithTimestampFormat(f TimestampFormat) *Renderer {
+	nr := r.clone()
+	nr.stampFormat = f
+	return nr
+}
+
+// now returns the current time from the Renderer's clock.
+func (r *Renderer) now() time.Time {
+	if r.clock != nil {
+		return r.clock.Now()
+	}
+	return time.Now()
+}
+
+// timestamp formats the current time for the Timestamp header.
+func (r *Renderer) timestamp() string {
+	now := r.now()
+	switch r.stampFormat {
+	case TimestampUnixMilli:
+		return strconv.FormatInt(now.UnixMilli(), 10)
+	case TimestampRFC3339:
+		return now.UTC().Format(time.RFC3339Nano)
+	default:
+		return strconv.FormatInt(now.Unix(), 10)
+	}
+}
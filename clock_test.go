@@ -0,0 +1,50 @@
+package beam
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderer_Clock(t *testing.T) {
+	frozen := time.Date(2024, 5, 6, 7, 8, 9, 500_000_000, time.UTC)
+	base := NewRenderer(settings).
+		WithClock(FixedClock(frozen)).
+		WithIDGeneration(Yes).
+		WithSystem(SystemShowHeaders, System{App: "app"})
+
+	cases := []struct {
+		format TimestampFormat
+		want   string
+	}{
+		{TimestampUnix, "1714979289"},
+		{TimestampUnixMilli, "1714979289500"},
+		{TimestampRFC3339, "2024-05-06T07:08:09.5Z"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.want, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := base.WithTimestampFormat(tc.format).Push(w, Response{Message: "ok"}); err != nil {
+				t.Fatalf("Push failed: %v", err)
+			}
+			if got := w.Header().Get("X-test-Timestamp"); got != tc.want {
+				t.Errorf("Expected timestamp %q, got %q", tc.want, got)
+			}
+			if got := w.Header().Get("X-test-Duration"); got != "0s" {
+				t.Errorf("Expected frozen duration 0s, got %q", got)
+			}
+		})
+	}
+
+	t.Run("ID", func(t *testing.T) {
+		var id string
+		r := base.WithCallback(func(d CallbackData) { id = d.ID })
+		if err := r.Push(httptest.NewRecorder(), Response{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if id == Empty || strings.Contains(id, "1714979289") {
+			t.Errorf("Expected an ID independent of the clock, got %q", id)
+		}
+	})
+}
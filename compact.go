@@ -0,0 +1,84 @@
+package beam
+
+import "encoding/xml"
+
+// EnvelopeCompact is the X-<name>-Envelope value announcing a compact response.
+const EnvelopeCompact = "compact"
+
+// CompactResponse is the minimal form of Response for machine-to-machine calls.
+// JSON and XML use single-letter keys; MessagePack encodes it as a positional array
+// in field order, so both sides must agree on the layout.
+type CompactResponse struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	XMLName  xml.Name `json:"-" xml:"r" msgpack:"-"`
+
+	Status   string                 `json:"s" xml:"s" msgpack:"s"`
+	Title    string                 `json:"t,omitempty" xml:"t,omitempty" msgpack:"t"`
+	Message  string                 `json:"m,omitempty" xml:"m,omitempty" msgpack:"m"`
+	Tags     []string               `json:"g,omitempty" xml:"g,omitempty" msgpack:"g"`
+	Info     interface{}            `json:"i,omitempty" xml:"i,omitempty" msgpack:"i"`
+	Data     interface{}            `json:"d,omitempty" xml:"d,omitempty" msgpack:"d"`
+	InfoType string                 `json:"it,omitempty" xml:"it,omitempty" msgpack:"it"`
+	DataType string                 `json:"dt,omitempty" xml:"dt,omitempty" msgpack:"dt"`
+	Meta     map[string]interface{} `json:"x,omitempty" xml:"-" msgpack:"x"`
+	Errors   ErrorList              `json:"e,omitempty" xml:"e,omitempty" msgpack:"e"`
+	Actions  []Action               `json:"a,omitempty" xml:"a,omitempty" msgpack:"a"`
+}
+
+// Compact converts the response to its compact form.
+func (r Response) Compact() CompactResponse {
+	return CompactResponse{
+		Status:   r.Status,
+		Title:    r.Title,
+		Message:  r.Message,
+		Tags:     r.Tags,
+		Info:     r.Info,
+		Data:     r.Data,
+		InfoType: r.InfoType,
+		DataType: r.DataType,
+		Meta:     r.Meta,
+		Errors:   r.Errors,
+		Actions:  r.Actions,
+	}
+}
+
+// Expand converts a compact response back to the full envelope.
+func (c CompactResponse) Expand() Response {
+	return Response{
+		Status:   c.Status,
+		Title:    c.Title,
+		Message:  c.Message,
+		Tags:     c.Tags,
+		Info:     c.Info,
+		Data:     c.Data,
+		InfoType: c.InfoType,
+		DataType: c.DataType,
+		Meta:     c.Meta,
+		Errors:   c.Errors,
+		Actions:  c.Actions,
+	}
+}
+
+// WithCompact enables or disables the compact envelope for Push.
+// Compact responses are announced with an X-<name>-Envelope: compact header
+// so callers can pick the matching decoder.
+// Returns a new Renderer with the updated setting.
+func (r *Renderer) WithCompact(enabled State) *Renderer {
+	nr := r.clone()
+	nr.compact = enabled
+	return nr
+}
+
+// envelope returns the value Push should encode for resp.
+// Sets the envelope header when the compact form is used; error responses
+// become ProblemDetails when WithProblemDetails is enabled.
+func (r *Renderer) envelope(resp *Response) interface{} {
+	if r.isProblem(resp) {
+		return r.problemDetails(resp)
+	}
+	if !r.compact.Enabled() {
+		return *resp
+	}
+	r.header.Set(r.headerName(HeaderNameEnvelope), EnvelopeCompact)
+	return resp.Compact()
+}
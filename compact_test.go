@@ -0,0 +1,57 @@
+package beam
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestCompactEnvelope(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithCompact(Yes)
+		if err := r.Push(w, Response{Message: "hi", Data: 1}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := strings.TrimSpace(w.Body.String()); got != `{"s":"+ok","m":"hi","d":1}` {
+			t.Errorf("Expected compact JSON, got %s", got)
+		}
+		if got := w.Header().Get("X-test-Envelope"); got != EnvelopeCompact {
+			t.Errorf("Expected envelope header %q, got %q", EnvelopeCompact, got)
+		}
+	})
+
+	t.Run("MsgPackPositional", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithContentType(ContentTypeMsgPack).WithCompact(Yes)
+		if err := r.Push(w, Response{Message: "hi", Data: "payload"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		var raw []interface{}
+		if err := msgpack.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+			t.Fatalf("Expected a positional array: %v", err)
+		}
+		if raw[0] != StatusSuccessful || raw[2] != "hi" {
+			t.Errorf("Expected status and message by position, got %v", raw)
+		}
+		var c CompactResponse
+		if err := msgpack.Unmarshal(w.Body.Bytes(), &c); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if resp := c.Expand(); resp.Message != "hi" || resp.Data != "payload" {
+			t.Errorf("Expected round-trip, got %+v", resp)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).Push(w, Response{Message: "hi"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if !strings.Contains(w.Body.String(), `"status"`) || w.Header().Get("X-test-Envelope") != "" {
+			t.Errorf("Expected verbose envelope, got %s", w.Body.String())
+		}
+	})
+}
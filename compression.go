@@ -0,0 +1,203 @@
+package beam
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings supported by WithCompression.
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+	EncodingZstd   = "zstd"
+)
+
+// DefaultCompressionMinSize is the body size below which responses are sent uncompressed.
+const DefaultCompressionMinSize = 1024
+
+// Compression configures transparent response compression.
+// The zero value compresses bodies of at least DefaultCompressionMinSize bytes
+// with the first of zstd, br, and gzip the client accepts.
+type Compression struct {
+	Encodings []string       // Codings in server preference order; defaults to zstd, br, gzip
+	MinSize   int            // Minimum body size to compress; zero uses DefaultCompressionMinSize
+	MinSizes  map[string]int // Per content type minimum sizes; a negative value disables compression for the type
+	Level     int            // Codec level; zero uses each codec's default
+}
+
+// WithCompression compresses Push, Raw, and Binary bodies when the request's
+// Accept-Encoding allows it, setting Content-Encoding and Vary headers.
+// Images, audio, and video are left alone unless listed in MinSizes, since
+// they are already compressed. Requires the request bound with WithRequest.
+// Returns a new Renderer with compression enabled.
+func (r *Renderer) WithCompression(opts Compression) *Renderer {
+	nr := r.clone()
+	if len(opts.Encodings) == 0 {
+		opts.Encodings = []string{EncodingZstd, EncodingBrotli, EncodingGzip}
+	}
+	if opts.MinSize == 0 {
+		opts.MinSize = DefaultCompressionMinSize
+	}
+	nr.compression = &opts
+	return nr
+}
+
+// compress applies the negotiated content coding to an encoded body.
+// Leaves the body untouched if compression is disabled, another coding was
+// already applied, the body is below the threshold, or the client accepts
+// none of the configured codings.
+// Returns the body to write.
+func (r *Renderer) compress(body []byte, contentType string) []byte {
+	c := r.compression
+	if c == nil || r.header.Get("Content-Encoding") != Empty {
+		return body
+	}
+	threshold, ok := c.MinSizes[contentType]
+	if !ok {
+		if precompressed(contentType) {
+			return body
+		}
+		threshold = c.MinSize
+	}
+	if threshold < 0 {
+		return body
+	}
+	addVary(r.header, "Accept-Encoding")
+	if len(body) < threshold || r.request == nil {
+		return body
+	}
+	coding := negotiateEncoding(r.request.Header.Values("Accept-Encoding"), c.Encodings)
+	if coding == Empty {
+		return body
+	}
+	out, err := encodeBody(coding, c.Level, body)
+	if err != nil || len(out) >= len(body) {
+		return body
+	}
+	r.header.Set("Content-Encoding", coding)
+	return out
+}
+
+// precompressed reports whether bodies of the content type are already compressed.
+func precompressed(contentType string) bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", ContentTypeZstd, "application/gzip", "application/zip"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding picks the first of offers accepted by the Accept-Encoding values.
+// Codings with q=0 are refused; "*" accepts any coding not listed explicitly.
+// Returns an empty string if none is acceptable.
+func negotiateEncoding(accept []string, offers []string) string {
+	q := make(map[string]float64)
+	for _, v := range accept {
+		for _, item := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == Empty {
+				continue
+			}
+			weight := 1.0
+			if k, val, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					weight = f
+				}
+			}
+			q[name] = weight
+		}
+	}
+	for _, offer := range offers {
+		weight, ok := q[offer]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > 0 {
+			return offer
+		}
+	}
+	return Empty
+}
+
+// addVary adds token to the Vary header unless it is already listed.
+func addVary(h http.Header, token string) {
+	for _, v := range h.Values("Vary") {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", token)
+}
+
+// zstdEncoders caches one stateless zstd encoder per level; EncodeAll is safe for concurrent use.
+var zstdEncoders sync.Map
+
+// encodeBody compresses body with the named coding at level (zero for the default).
+// Returns the compressed bytes or an error if the codec fails.
+func encodeBody(coding string, level int, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	switch coding {
+	case EncodingGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		zw, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(body); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	case EncodingBrotli:
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		bw := brotli.NewWriterLevel(&buf, level)
+		if _, err := bw.Write(body); err != nil {
+			return nil, err
+		}
+		if err := bw.Close(); err != nil {
+			return nil, err
+		}
+	case EncodingZstd:
+		enc, err := zstdEncoder(level)
+		if err != nil {
+			return nil, err
+		}
+		return enc.EncodeAll(body, nil), nil
+	default:
+		return nil, errUnsupportedEncoding
+	}
+	return buf.Bytes(), nil
+}
+
+// zstdEncoder returns the shared encoder for level, creating it on first use.
+func zstdEncoder(level int) (*zstd.Encoder, error) {
+	if enc, ok := zstdEncoders.Load(level); ok {
+		return enc.(*zstd.Encoder), nil
+	}
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+	if level != 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+	enc, err := zstd.NewWriter(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := zstdEncoders.LoadOrStore(level, enc)
+	return actual.(*zstd.Encoder), nil
+}
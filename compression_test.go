@@ -0,0 +1,103 @@
+package beam
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompression(t *testing.T) {
+	payload := strings.Repeat("beam compresses repetitive payloads ", 100)
+	push := func(r *Renderer, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		if accept != "" {
+			req.Header.Set("Accept-Encoding", accept)
+		}
+		w := httptest.NewRecorder()
+		if err := r.WithRequest(req).Push(w, Response{Message: payload}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		return w
+	}
+	decode := func(coding string, body []byte) string {
+		var rd io.Reader
+		switch coding {
+		case EncodingGzip:
+			zr, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("gzip: %v", err)
+			}
+			rd = zr
+		case EncodingBrotli:
+			rd = brotli.NewReader(bytes.NewReader(body))
+		case EncodingZstd:
+			zr, err := zstd.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("zstd: %v", err)
+			}
+			defer zr.Close()
+			rd = zr
+		default:
+			return string(body)
+		}
+		out, err := io.ReadAll(rd)
+		if err != nil {
+			t.Fatalf("%s: %v", coding, err)
+		}
+		return string(out)
+	}
+	r := NewRenderer(settings).WithCompression(Compression{})
+
+	t.Run("Negotiation", func(t *testing.T) {
+		for accept, want := range map[string]string{
+			"gzip":                       EncodingGzip,
+			"gzip, br":                   EncodingBrotli,
+			"gzip, br;q=0, zstd":         EncodingZstd,
+			"br;q=0, *":                  EncodingZstd,
+			"zstd;q=0, br;q=0, gzip;q=0": "",
+			"":                           "",
+		} {
+			w := push(r, accept)
+			if got := w.Header().Get("Content-Encoding"); got != want {
+				t.Errorf("Accept-Encoding %q: expected %q, got %q", accept, want, got)
+			}
+			if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+				t.Errorf("Accept-Encoding %q: expected Vary header, got %q", accept, w.Header().Get("Vary"))
+			}
+			if !strings.Contains(decode(want, w.Body.Bytes()), payload) {
+				t.Errorf("Accept-Encoding %q: expected payload after decoding", accept)
+			}
+		}
+	})
+
+	t.Run("Thresholds", func(t *testing.T) {
+		small := NewRenderer(settings).WithCompression(Compression{MinSize: 1 << 20})
+		if got := push(small, "gzip").Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected small body uncompressed, got %q", got)
+		}
+		off := NewRenderer(settings).WithCompression(Compression{MinSizes: map[string]int{ContentTypeJSON: -1}})
+		if got := push(off, "gzip").Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected JSON compression disabled, got %q", got)
+		}
+	})
+
+	t.Run("BinarySkipsImages", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := &TestWriter{Headers: http.Header{}}
+		img := NewRenderer(settings).WithCompression(Compression{}).WithRequest(req).WithWriter(w)
+		if err := img.Binary(ContentTypePNG, []byte(payload)); err != nil {
+			t.Fatalf("Binary failed: %v", err)
+		}
+		if got := w.Headers.Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected PNG uncompressed, got %q", got)
+		}
+	})
+}
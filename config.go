@@ -0,0 +1,79 @@
+package beam
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrMisconfigured matches any *ConfigError via errors.Is.
+var ErrMisconfigured = errors.New("renderer misconfigured")
+
+// ConfigError reports which Renderer configuration was missing for an operation.
+// Every sender returns one when it cannot write, such as without a writer.
+// Missing holds human-readable items such as "writer (use WithWriter)".
+type ConfigError struct {
+	Missing []string
+}
+
+// Error lists the missing configuration.
+func (e *ConfigError) Error() string {
+	return ErrMisconfigured.Error() + ": missing " + strings.Join(e.Missing, ", ")
+}
+
+// Is reports whether target is ErrMisconfigured, or errNoWriter when the writer is missing.
+func (e *ConfigError) Is(target error) bool {
+	if target == ErrMisconfigured {
+		return true
+	}
+	if target == errNoWriter {
+		for _, m := range e.Missing {
+			if strings.HasPrefix(m, "writer") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkConfig verifies the Renderer can encode and write a response to w.
+// Returns a *ConfigError naming every missing piece, or nil.
+func (r *Renderer) checkConfig(w Writer) error {
+	return r.configError(w, true)
+}
+
+// checkOutput verifies the Renderer can write bytes to w without encoding
+// them, for senders such as Binary, Media, and Relay.
+// Returns a *ConfigError naming every missing piece, or nil.
+func (r *Renderer) checkOutput(w Writer) error {
+	return r.configError(w, false)
+}
+
+// configError collects the configuration missing to write to w, including
+// the encoder for the content type when encode is set.
+func (r *Renderer) configError(w Writer, encode bool) error {
+	var missing []string
+	if w == nil {
+		missing = append(missing, "writer (use WithWriter or pass one to Push)")
+	}
+	if r.protocol == nil {
+		missing = append(missing, "protocol (use WithProtocol)")
+	}
+	if encode {
+		if r.encoders == nil {
+			missing = append(missing, "encoder registry")
+		} else if _, ok := r.encoders.Get(r.contentType); !ok {
+			missing = append(missing, "encoder for "+r.contentType+" (use UseEncoder)")
+		}
+	}
+	if len(missing) > 0 {
+		return &ConfigError{Missing: missing}
+	}
+	return nil
+}
+
+// Reply pushes resp using the writer bound with WithWriter.
+// Equivalent to Push(nil, resp), the supported writer-less form of Push.
+// Returns a *ConfigError if the writer or other configuration is missing.
+func (r *Renderer) Reply(resp Response) error {
+	return r.Push(nil, resp)
+}
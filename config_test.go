@@ -0,0 +1,90 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"image"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReply(t *testing.T) {
+	t.Run("BoundWriter", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(rec).Reply(Response{Message: "hi"}); err != nil {
+			t.Fatalf("Reply failed: %v", err)
+		}
+		if !strings.Contains(rec.Body.String(), `"message":"hi"`) {
+			t.Errorf("Expected response body, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("MissingConfiguration", func(t *testing.T) {
+		err := NewRenderer(settings).WithContentType("application/unknown").Push(nil, Response{})
+		var ce *ConfigError
+		if !errors.As(err, &ce) {
+			t.Fatalf("Expected ConfigError, got %v", err)
+		}
+		if len(ce.Missing) != 2 {
+			t.Errorf("Expected writer and encoder to be missing, got %v", ce.Missing)
+		}
+		if !errors.Is(err, ErrMisconfigured) || !errors.Is(err, errNoWriter) {
+			t.Errorf("Expected error to match ErrMisconfigured and errNoWriter, got %v", err)
+		}
+	})
+
+	t.Run("Senders", func(t *testing.T) {
+		r := NewRenderer(settings)
+		img := image.NewGray(image.Rect(0, 0, 1, 1))
+		stream := func(*Renderer) (interface{}, error) { return nil, io.EOF }
+		senders := []struct {
+			name string
+			send func() error
+		}{
+			{"Msg", func() error { return r.Msg("hi") }},
+			{"Msgf", func() error { return r.Msgf("hi %d", 1) }},
+			{"Send", func() error { return r.Send("hi", nil) }},
+			{"Info", func() error { return r.Info("hi", nil) }},
+			{"Data", func() error { return r.Data("hi", nil) }},
+			{"Response", func() error { return r.Response("hi", nil, nil) }},
+			{"Pending", func() error { return r.Pending("hi", nil) }},
+			{"Titled", func() error { return r.Titled("t", "hi", nil) }},
+			{"Error", func() error { return r.Error(errors.New("boom")) }},
+			{"Errorf", func() error { return r.Errorf("boom %d", 1) }},
+			{"Fatal", func() error { return r.Fatal(errors.New("boom")) }},
+			{"Warning", func() error { return r.Warning(errors.New("careful")) }},
+			{"Warningf", func() error { return r.Warningf("careful %d", 1) }},
+			{"Reply", func() error { return r.Reply(Response{}) }},
+			{"Raw", func() error { return r.Raw("data") }},
+			{"Rest", func() error { return r.Rest("data") }},
+			{"Stream", func() error { return r.Stream(stream) }},
+			{"Relay", func() error { return r.Relay("data") }},
+			{"Binary", func() error { return r.Binary(ContentTypeBinary, []byte("data")) }},
+			{"Pusher", func() error { return r.Pusher(ContentTypeBinary, strings.NewReader("data")) }},
+			{"Image", func() error { return r.Image(ContentTypePNG, img) }},
+			{"Media", func() error { return r.Media(strings.NewReader("data"), ContentTypeBinary) }},
+			{"Delta", func() error { return r.Delta(Delta{Format: DeltaBSDiff}) }},
+			{"QR", func() error { return r.QR("data", 0) }},
+			{"PushEvent", func() error { return r.PushEvent(Response{}) }},
+			{"SSESession", func() error { return r.SSESession(SSEOptions{}).Run() }},
+			{"Job", func() error { return r.Job("1", "/jobs/1") }},
+			{"JobStatus", func() error { return r.JobStatus(Job{ID: "1"}) }},
+			{"Paginate", func() error { return r.Paginate("hi", nil, Pagination{}) }},
+			{"Builder", func() error { return r.New().Send() }},
+			{"Blueprint", func() error { return r.WithBlueprint("ok", Blueprint{}).Blueprint("ok").Send() }},
+			{"Best", func() error { return r.Best(context.Background()) }},
+			{"Gather", func() error { return Gather(context.Background(), r, nil) }},
+		}
+		for _, tc := range senders {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.send()
+				var ce *ConfigError
+				if !errors.As(err, &ce) || !errors.Is(err, ErrMisconfigured) || !errors.Is(err, errNoWriter) {
+					t.Errorf("Expected ConfigError matching errNoWriter, got %v", err)
+				}
+			})
+		}
+	})
+}
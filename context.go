@@ -0,0 +1,33 @@
+package beam
+
+import "context"
+
+// PushContext sends a structured Response bound to ctx for this call only.
+// Prefer it over WithContext on shared renderers so a stale context is never reused.
+// Returns ErrContextCanceled if ctx is already done, or any error from Push.
+func (r *Renderer) PushContext(ctx context.Context, w Writer, d Response) error {
+	return r.WithContext(ctx).Push(w, d)
+}
+
+// StreamContext streams callback output bound to ctx for this call only.
+// The callback sees ctx through Renderer.Context and Renderer.Done.
+// Returns ErrContextCanceled if ctx is already done, or any error from Stream.
+func (r *Renderer) StreamContext(ctx context.Context, callback func(*Renderer) (interface{}, error)) error {
+	return r.WithContext(ctx).Stream(callback)
+}
+
+// BinaryContext sends binary data bound to ctx for this call only.
+// Returns ErrContextCanceled if ctx is already done, or any error from Binary.
+func (r *Renderer) BinaryContext(ctx context.Context, contentType string, data []byte) error {
+	return r.WithContext(ctx).Binary(contentType, data)
+}
+
+// canceled reports ErrContextCanceled once the Renderer's context has ended.
+// Triggers callbacks so the skipped response is still observable.
+func (r *Renderer) canceled() error {
+	if r.ctx == nil || r.ctx.Err() == nil {
+		return nil
+	}
+	r.triggerCallbacks(r.id, StatusError, "operation canceled", ErrContextCanceled)
+	return ErrContextCanceled
+}
@@ -0,0 +1,54 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestContextVariants(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("PushContext", func(t *testing.T) {
+		base := NewRenderer(settings)
+		w := &TestWriter{Headers: http.Header{}}
+		if err := base.PushContext(canceled, w, Response{Message: "x"}); !errors.Is(err, ErrContextCanceled) {
+			t.Errorf("Expected ErrContextCanceled, got %v", err)
+		}
+		if w.Buffer.Len() != 0 {
+			t.Errorf("Expected nothing written, got %s", w.Buffer.String())
+		}
+		if err := base.PushContext(context.Background(), w, Response{Message: "x"}); err != nil {
+			t.Errorf("Expected base renderer to be unaffected, got %v", err)
+		}
+	})
+
+	t.Run("StreamContext", func(t *testing.T) {
+		w := &TestWriter{Headers: http.Header{}}
+		r := NewRenderer(settings).WithWriter(w)
+		called := false
+		err := r.StreamContext(canceled, func(*Renderer) (interface{}, error) {
+			called = true
+			return nil, nil
+		})
+		if !errors.Is(err, ErrContextCanceled) || called {
+			t.Errorf("Expected ErrContextCanceled without calling back, got %v (called %v)", err, called)
+		}
+	})
+
+	t.Run("BinaryContext", func(t *testing.T) {
+		w := &TestWriter{Headers: http.Header{}}
+		r := NewRenderer(settings).WithWriter(w)
+		if err := r.BinaryContext(canceled, "application/octet-stream", []byte{1}); !errors.Is(err, ErrContextCanceled) {
+			t.Errorf("Expected ErrContextCanceled, got %v", err)
+		}
+		if err := r.BinaryContext(context.Background(), "application/octet-stream", []byte{1}); err != nil {
+			t.Errorf("Expected success, got %v", err)
+		}
+		if w.Buffer.Len() != 1 {
+			t.Errorf("Expected 1 byte written, got %d", w.Buffer.Len())
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentTypeCSV is the content type for comma-separated values.
+const ContentTypeCSV = "text/csv"
+
+// errCSVUnsupported is returned for values the CSV encoder cannot tabulate.
+var errCSVUnsupported = errors.New("csv: unsupported value")
+
+// CSVEncoder encodes tabular data as CSV.
+// Accepts [][]string, []string (a single row), structs and slices of structs
+// (a header row from `csv` tags or field names), and maps and slices of maps
+// (a header row of sorted keys). A Response is encoded as its Data.
+// Implements Streamer so Renderer.Stream emits rows incrementally with flushes.
+type CSVEncoder struct {
+	Comma    rune // Field delimiter; zero uses ','
+	NoHeader bool // Omit the header row for structs and maps
+}
+
+// Marshal encodes v as CSV.
+// Returns the encoded rows or an error if v is not tabular.
+func (e *CSVEncoder) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	cw := e.writer(&buf)
+	var header []string
+	if err := e.writeValue(cw, &header, unwrapResponse(v)); err != nil {
+		return nil, err
+	}
+	cw.Flush()
+	return buf.Bytes(), cw.Error()
+}
+
+// Unmarshal decodes CSV data into v.
+// Takes a *[][]string, or a pointer to a slice of structs filled by matching
+// header names against `csv` tags or field names.
+// Returns an error if v is unsupported or a field fails to parse.
+func (e *CSVEncoder) Unmarshal(data []byte, v interface{}) error {
+	cr := csv.NewReader(bytes.NewReader(data))
+	if e.Comma != 0 {
+		cr.Comma = e.Comma
+	}
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	if rows, ok := v.(*[][]string); ok {
+		*rows = records
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: decode target %T", errCSVUnsupported, v)
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: decode target %T", errCSVUnsupported, v)
+	}
+	slice.SetLen(0)
+	if len(records) == 0 {
+		return nil
+	}
+	fields := csvFields(structType)
+	index := make([]int, len(records[0]))
+	for i, name := range records[0] {
+		index[i] = -1
+		for j, f := range fields {
+			if f.name == name {
+				index[i] = j
+			}
+		}
+	}
+	for line, record := range records[1:] {
+		item := reflect.New(structType).Elem()
+		for i, cell := range record {
+			if i >= len(index) || index[i] < 0 {
+				continue
+			}
+			f := fields[index[i]]
+			if err := setCSVField(item.FieldByIndex(f.index), cell); err != nil {
+				return fmt.Errorf("csv line %d, column %q: %w", line+2, f.name, err)
+			}
+		}
+		if elemType.Kind() == reflect.Ptr {
+			item = item.Addr()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+	return nil
+}
+
+// ContentType returns the CSV content type.
+// Returns the constant "text/csv".
+func (e *CSVEncoder) ContentType() string {
+	return ContentTypeCSV
+}
+
+// Stream writes the rows of each callback result until io.EOF.
+// The header row is written once, before the first struct or map row.
+// Flushes after every callback result if the writer supports it.
+// Returns an error if the callback, encoding, or writing fails.
+func (e *CSVEncoder) Stream(w Writer, callback func() (interface{}, error)) error {
+	cw := e.writer(w)
+	var header []string
+	for {
+		data, err := callback()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("stream callback failed: %w", err)
+		}
+		if err := e.writeValue(cw, &header, data); err != nil {
+			return fmt.Errorf("encoding failed: %w", err)
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("write failed: %w", err)
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+// writer returns a csv.Writer using the configured delimiter.
+func (e *CSVEncoder) writer(w io.Writer) *csv.Writer {
+	cw := csv.NewWriter(w)
+	if e.Comma != 0 {
+		cw.Comma = e.Comma
+	}
+	return cw
+}
+
+// writeValue writes the rows of v, emitting the header on the first keyed row.
+// header holds the column names once written so later rows keep the same order.
+func (e *CSVEncoder) writeValue(cw *csv.Writer, header *[]string, v interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case [][]string:
+		return cw.WriteAll(t)
+	case []string:
+		return cw.Write(t)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := e.writeValue(cw, header, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		fields := csvFields(rv.Type())
+		if *header == nil {
+			*header = make([]string, len(fields))
+			for i, f := range fields {
+				(*header)[i] = f.name
+			}
+			if err := e.writeHeader(cw, *header); err != nil {
+				return err
+			}
+		}
+		row := make([]string, len(*header))
+		for i, name := range *header {
+			for _, f := range fields {
+				if f.name == name {
+					row[i] = formatCSV(rv.FieldByIndex(f.index))
+				}
+			}
+		}
+		return cw.Write(row)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if *header == nil {
+			keys := make([]string, 0, rv.Len())
+			for _, k := range rv.MapKeys() {
+				keys = append(keys, k.String())
+			}
+			slices.Sort(keys)
+			*header = keys
+			if err := e.writeHeader(cw, *header); err != nil {
+				return err
+			}
+		}
+		row := make([]string, len(*header))
+		for i, name := range *header {
+			if cell := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key())); cell.IsValid() {
+				row[i] = formatCSV(cell)
+			}
+		}
+		return cw.Write(row)
+	}
+	return fmt.Errorf("%w: %T", errCSVUnsupported, v)
+}
+
+// writeHeader writes the header row unless NoHeader is set.
+func (e *CSVEncoder) writeHeader(cw *csv.Writer, header []string) error {
+	if e.NoHeader {
+		return nil
+	}
+	return cw.Write(header)
+}
+
+// csvField is an exported struct field mapped to a CSV column.
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvFields lists the columns of struct type t in declaration order.
+// Uses the `csv` tag name when present and skips fields tagged "-".
+func csvFields(t reflect.Type) []csvField {
+	var fields []csvField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("csv"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, csvField{name: name, index: f.Index})
+	}
+	return fields
+}
+
+// formatCSV renders a single cell value.
+// Times use RFC 3339, nil pointers and interfaces are empty, and other values use fmt.
+func formatCSV(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch t := v.Interface().(type) {
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case fmt.Stringer:
+		return t.String()
+	case string:
+		return t
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// setCSVField parses cell into the field f.
+// Supports strings, booleans, integers, floats, durations, and RFC 3339 times.
+func setCSVField(f reflect.Value, cell string) error {
+	if f.Kind() == reflect.Ptr {
+		if cell == "" {
+			return nil
+		}
+		f.Set(reflect.New(f.Type().Elem()))
+		f = f.Elem()
+	}
+	switch f.Interface().(type) {
+	case time.Time:
+		t, err := time.Parse(time.RFC3339, cell)
+		if err == nil {
+			f.Set(reflect.ValueOf(t))
+		}
+		return err
+	case time.Duration:
+		d, err := time.ParseDuration(cell)
+		if err == nil {
+			f.SetInt(int64(d))
+		}
+		return err
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(cell, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("%w: field type %s", errCSVUnsupported, f.Type())
+	}
+	return nil
+}
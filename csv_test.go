@@ -0,0 +1,65 @@
+package beam
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type csvRow struct {
+	Name    string    `csv:"name"`
+	Age     int       `csv:"age"`
+	Joined  time.Time `csv:"joined"`
+	Secret  string    `csv:"-"`
+	Comment *string
+}
+
+func TestCSVEncoder(t *testing.T) {
+	joined := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []csvRow{{Name: "alice", Age: 30, Joined: joined, Secret: "x"}, {Name: "bob, jr", Age: 4, Joined: joined}}
+	want := "name,age,joined,Comment\nalice,30,2024-01-02T03:04:05Z,\n\"bob, jr\",4,2024-01-02T03:04:05Z,\n"
+	e := &CSVEncoder{}
+
+	t.Run("Structs", func(t *testing.T) {
+		out, err := e.Marshal(rows)
+		if err != nil || string(out) != want {
+			t.Errorf("Expected %q, got %q (%v)", want, out, err)
+		}
+		var back []csvRow
+		if err := e.Unmarshal(out, &back); err != nil || len(back) != 2 || back[1].Name != "bob, jr" || !back[0].Joined.Equal(joined) {
+			t.Errorf("Expected round trip, got %+v (%v)", back, err)
+		}
+	})
+
+	t.Run("Records", func(t *testing.T) {
+		out, err := e.Marshal(Response{Data: [][]string{{"a", "b"}, {"1", "2"}}})
+		if err != nil || string(out) != "a,b\n1,2\n" {
+			t.Errorf("Expected records, got %q (%v)", out, err)
+		}
+		out, err = (&CSVEncoder{Comma: ';'}).Marshal([]map[string]int{{"b": 2, "a": 1}})
+		if err != nil || string(out) != "a;b\n1;2\n" {
+			t.Errorf("Expected sorted map columns, got %q (%v)", out, err)
+		}
+		if _, err := e.Marshal(42); !errors.Is(err, errCSVUnsupported) {
+			t.Errorf("Expected errCSVUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		w := &TestWriter{Headers: http.Header{}}
+		r := NewRenderer(settings).WithWriter(w).WithContentType(ContentTypeCSV)
+		i := 0
+		err := r.Stream(func(*Renderer) (interface{}, error) {
+			if i == len(rows) {
+				return nil, io.EOF
+			}
+			i++
+			return rows[i-1], nil
+		})
+		if err != nil || w.Buffer.String() != want {
+			t.Errorf("Expected %q, got %q (%v)", want, w.Buffer.String(), err)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errDeadLetterFull is returned by QueueSink when its buffer is full.
+var errDeadLetterFull = errors.New("dead-letter queue full")
+
+// DeadLetter is a response the client never received.
+// Carries the encoded body plus enough context to inspect or replay it.
+type DeadLetter struct {
+	ID          string      `json:"id,omitempty"`
+	Time        time.Time   `json:"time"`
+	Code        int         `json:"code"`
+	ContentType string      `json:"content_type"`
+	Header      http.Header `json:"header,omitempty"`
+	Method      string      `json:"method,omitempty"`
+	Path        string      `json:"path,omitempty"`
+	Body        []byte      `json:"body"`
+	Written     int         `json:"written"` // Bytes the writer accepted before failing
+	Error       string      `json:"error"`
+}
+
+// DeadLetterSink persists failed responses.
+// Implementations must be safe for concurrent use.
+type DeadLetterSink interface {
+	Capture(dl DeadLetter) error
+}
+
+// DeadLetterFunc adapts a function to the DeadLetterSink interface.
+type DeadLetterFunc func(dl DeadLetter) error
+
+// Capture calls f(dl).
+func (f DeadLetterFunc) Capture(dl DeadLetter) error { return f(dl) }
+
+// FileSink writes each dead letter as a JSON file in a directory.
+// Dead letters hold full response bodies, so the directory is created
+// owner-only (0700) and files are readable by the owner only (0600).
+type FileSink struct {
+	Dir string
+}
+
+// Capture writes dl to <Dir>/<time>-<id>.json.
+// Returns an error if the directory or file cannot be written.
+func (s FileSink) Capture(dl DeadLetter) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(dl, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.json", dl.Time.UnixNano(), strings.NewReplacer("/", "_", "\\", "_").Replace(dl.ID))
+	return os.WriteFile(filepath.Join(s.Dir, name), b, 0o600)
+}
+
+// QueueSink buffers dead letters in a channel for an external consumer.
+// Captures fail rather than block when the buffer is full.
+type QueueSink struct {
+	ch chan DeadLetter
+}
+
+// NewQueueSink creates a QueueSink holding up to size dead letters.
+func NewQueueSink(size int) *QueueSink {
+	return &QueueSink{ch: make(chan DeadLetter, size)}
+}
+
+// Capture enqueues dl.
+// Returns an error if the queue is full.
+func (q *QueueSink) Capture(dl DeadLetter) error {
+	select {
+	case q.ch <- dl:
+		return nil
+	default:
+		return errDeadLetterFull
+	}
+}
+
+// C returns the channel consumers read dead letters from.
+func (q *QueueSink) C() <-chan DeadLetter {
+	return q.ch
+}
+
+// WithDeadLetter captures responses whose writes ultimately fail.
+// The encoded body and its context go to sink so operators can inspect or replay
+// what the client never received; sink errors are logged.
+// Returns a new Renderer with the sink set; nil disables capture.
+func (r *Renderer) WithDeadLetter(sink DeadLetterSink) *Renderer {
+	nr := r.clone()
+	nr.deadLetter = sink
+	return nr
+}
+
+// captureDeadLetter sends a failed write to the dead-letter sink, if configured.
+func (r *Renderer) captureDeadLetter(body []byte, written int, err error) {
+	if r.deadLetter == nil {
+		return
+	}
+	dl := DeadLetter{
+		ID:          r.id,
+		Time:        r.now(),
+		Code:        r.code,
+		ContentType: r.contentType,
+		Header:      cloneHeader(r.header),
+		Body:        append([]byte(nil), body...),
+		Written:     written,
+		Error:       err.Error(),
+	}
+	if r.request != nil {
+		dl.Method, dl.Path = r.request.Method, r.request.URL.Path
+	}
+	if sinkErr := r.deadLetter.Capture(dl); sinkErr != nil && r.logger != nil {
+		r.logger.Error(errors.Join(errors.New("dead-letter capture failed"), sinkErr), "id", r.id)
+	}
+}
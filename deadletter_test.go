@@ -0,0 +1,76 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDeadLetter(t *testing.T) {
+	broken := errors.New("connection reset")
+
+	t.Run("Queue", func(t *testing.T) {
+		q := NewQueueSink(1)
+		w := &TestWriter{Headers: make(http.Header), WriteError: broken}
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		err := NewRenderer(settings).WithID("req-1").WithRequest(req).WithDeadLetter(q).Push(w, Response{Message: "created"})
+		if !errors.Is(err, broken) {
+			t.Fatalf("Expected write error, got %v", err)
+		}
+		select {
+		case dl := <-q.C():
+			if dl.ID != "req-1" || dl.Method != http.MethodPost || dl.Path != "/orders" || dl.Code != http.StatusOK {
+				t.Errorf("Unexpected dead letter context %+v", dl)
+			}
+			if !strings.Contains(string(dl.Body), `"message":"created"`) || dl.Error != broken.Error() {
+				t.Errorf("Unexpected dead letter body %s (%s)", dl.Body, dl.Error)
+			}
+		default:
+			t.Fatal("Expected a dead letter")
+		}
+		if err := q.Capture(DeadLetter{}); err != nil {
+			t.Fatalf("Capture failed: %v", err)
+		}
+		if err := q.Capture(DeadLetter{}); err == nil {
+			t.Error("Expected full queue error")
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "dead")
+		w := &TestWriter{Headers: make(http.Header), WriteError: broken}
+		_ = NewRenderer(settings).WithID("req-2").WithDeadLetter(FileSink{Dir: dir}).Push(w, Response{Message: "x"})
+		files, _ := filepath.Glob(filepath.Join(dir, "*-req-2.json"))
+		if len(files) != 1 {
+			t.Fatalf("Expected one dead-letter file, got %v", files)
+		}
+		raw, _ := os.ReadFile(files[0])
+		var dl DeadLetter
+		if err := json.Unmarshal(raw, &dl); err != nil || dl.ID != "req-2" {
+			t.Errorf("Expected readable dead letter, got %+v (%v)", dl, err)
+		}
+		if runtime.GOOS != "windows" {
+			di, _ := os.Stat(dir)
+			fi, _ := os.Stat(files[0])
+			if di.Mode().Perm() != 0o700 || fi.Mode().Perm() != 0o600 {
+				t.Errorf("Expected owner-only permissions, got %v and %v", di.Mode().Perm(), fi.Mode().Perm())
+			}
+		}
+	})
+
+	t.Run("SuccessNotCaptured", func(t *testing.T) {
+		q := NewQueueSink(1)
+		if err := NewRenderer(settings).WithDeadLetter(q).Push(httptest.NewRecorder(), Response{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if len(q.C()) != 0 {
+			t.Error("Expected no dead letters")
+		}
+	})
+}
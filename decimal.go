@@ -0,0 +1,125 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// errInvalidDecimal reports a decimal whose text could not be parsed.
+var errInvalidDecimal = errors.New("invalid decimal")
+
+// maxDecimalPlaces bounds the digits emitted for an exact decimal.
+const maxDecimalPlaces = 1000
+
+// DecimalRounding selects how DecimalFormat rounds to a fixed number of places.
+type DecimalRounding int
+
+// Rounding modes for DecimalFormat.
+const (
+	RoundHalfEven DecimalRounding = iota // Ties go to the even digit (banker's rounding, default)
+	RoundHalfUp                          // Ties go away from zero
+	RoundDown                            // Truncate toward zero
+)
+
+// DecimalFormat configures how decimal values are serialized.
+// Decimals are emitted as strings by default so no encoder or client ever
+// passes them through a float.
+type DecimalFormat struct {
+	Places   int             // Digits after the decimal point when Fixed is set
+	Fixed    bool            // Round and zero-pad to Places; otherwise keep the exact value
+	Rounding DecimalRounding // Rounding mode used with Fixed
+	Number   bool            // Emit JSON numbers instead of strings; other encoders still get strings
+}
+
+// RegisterDecimal registers a type marshaler serializing T, a decimal type
+// such as shopspring's decimal.Decimal or *apd.Decimal, in every encoder.
+// Values are formatted from their String form; values that cannot be parsed
+// are emitted unchanged as strings.
+func RegisterDecimal[T fmt.Stringer](df DecimalFormat) {
+	RegisterTypeMarshaler(func(v T) any {
+		if rv := reflect.ValueOf(v); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+			return nil
+		}
+		s := v.String()
+		if out, err := df.Format(s); err == nil {
+			s = out
+		}
+		if df.Number {
+			return json.Number(s)
+		}
+		return s
+	})
+}
+
+// Format rewrites the decimal text s (e.g., "-12.345" or "1.5E+3") in plain
+// notation, rounded to Places when Fixed is set.
+// Returns an error if s is not a decimal number.
+func (df DecimalFormat) Format(s string) (string, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.Contains(s, "/") {
+		return Empty, fmt.Errorf("%w: %q", errInvalidDecimal, s)
+	}
+	places := df.Places
+	if !df.Fixed {
+		if places, ok = exactPlaces(r.Denom()); !ok {
+			return Empty, fmt.Errorf("%w: %q has no finite decimal form", errInvalidDecimal, s)
+		}
+	}
+	places = max(places, 0)
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	num := new(big.Int).Mul(r.Num(), scale)
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		half := new(big.Int).Abs(m)
+		half.Lsh(half, 1)
+		away := false
+		switch cmp := half.Cmp(r.Denom()); {
+		case df.Rounding == RoundDown:
+		case cmp > 0:
+			away = true
+		case cmp == 0:
+			away = df.Rounding == RoundHalfUp || q.Bit(0) == 1
+		}
+		if away {
+			q.Add(q, big.NewInt(int64(r.Sign())))
+		}
+	}
+	return formatScaled(q, places), nil
+}
+
+// exactPlaces returns the digits after the decimal point needed to write a
+// number with denominator denom exactly.
+func exactPlaces(denom *big.Int) (int, bool) {
+	ten := big.NewInt(10)
+	pow := big.NewInt(1)
+	m := new(big.Int)
+	for places := 0; places <= maxDecimalPlaces; places++ {
+		if m.Mod(pow, denom).Sign() == 0 {
+			return places, true
+		}
+		pow.Mul(pow, ten)
+	}
+	return 0, false
+}
+
+// formatScaled writes q / 10^places in plain decimal notation.
+func formatScaled(q *big.Int, places int) string {
+	digits := new(big.Int).Abs(q).String()
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	sign := Empty
+	if q.Sign() < 0 {
+		sign = "-"
+	}
+	if places == 0 {
+		return sign + digits
+	}
+	point := len(digits) - places
+	return sign + digits[:point] + "." + digits[point:]
+}
@@ -0,0 +1,82 @@
+package beam
+
+import (
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// testDecimal mimics a decimal library type exposing its exact value via String.
+type testDecimal string
+
+func (d testDecimal) String() string { return string(d) }
+
+// testPtrDecimal mimics decimal types whose String has a pointer receiver, like apd.
+type testPtrDecimal struct{ text string }
+
+func (d *testPtrDecimal) String() string { return d.text }
+
+func TestDecimalFormat(t *testing.T) {
+	tests := []struct {
+		in   string
+		df   DecimalFormat
+		want string
+	}{
+		{"12.345", DecimalFormat{}, "12.345"},
+		{"1.5E+3", DecimalFormat{}, "1500"},
+		{"-2.50e-2", DecimalFormat{}, "-0.025"},
+		{"2.345", DecimalFormat{Places: 2, Fixed: true}, "2.34"},
+		{"2.355", DecimalFormat{Places: 2, Fixed: true}, "2.36"},
+		{"-2.345", DecimalFormat{Places: 2, Fixed: true}, "-2.34"},
+		{"2.345", DecimalFormat{Places: 2, Fixed: true, Rounding: RoundHalfUp}, "2.35"},
+		{"-2.345", DecimalFormat{Places: 2, Fixed: true, Rounding: RoundHalfUp}, "-2.35"},
+		{"2.349", DecimalFormat{Places: 2, Fixed: true, Rounding: RoundDown}, "2.34"},
+		{"7", DecimalFormat{Places: 2, Fixed: true}, "7.00"},
+		{"0.004", DecimalFormat{Places: 2, Fixed: true}, "0.00"},
+		{"2.5", DecimalFormat{Fixed: true}, "2"},
+		{"3.5", DecimalFormat{Fixed: true}, "4"},
+	}
+	for _, tt := range tests {
+		got, err := tt.df.Format(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("Format(%q) with %+v = %q, %v; want %q", tt.in, tt.df, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"abc", "1/3", ""} {
+		if _, err := (DecimalFormat{}).Format(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestRegisterDecimal(t *testing.T) {
+	RegisterDecimal[testDecimal](DecimalFormat{Places: 2, Fixed: true})
+	defer RegisterTypeMarshaler[testDecimal](nil)
+	RegisterDecimal[*testPtrDecimal](DecimalFormat{Number: true})
+	defer RegisterTypeMarshaler[*testPtrDecimal](nil)
+
+	type invoice struct {
+		Total testDecimal     `json:"total"`
+		Tax   *testPtrDecimal `json:"tax"`
+		Fee   *testPtrDecimal `json:"fee"`
+	}
+	v := invoice{Total: "10.125", Tax: &testPtrDecimal{"0.8100"}}
+
+	r := NewRenderer(settings)
+	out, err := r.encoders.Encode(ContentTypeJSON, v)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if want := `{"total":"10.12","tax":0.81,"fee":null}`; string(out) != want {
+		t.Errorf("Expected %s, got %s", want, out)
+	}
+
+	out, err = r.encoders.Encode(ContentTypeMsgPack, map[string]testDecimal{"total": "99.995"})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := msgpack.Unmarshal(out, &decoded); err != nil || decoded["total"] != "100.00" {
+		t.Errorf("Expected the rounded decimal string in MessagePack, got %v (%v)", decoded, err)
+	}
+}
@@ -0,0 +1,64 @@
+package beam
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Delta(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/update", nil)
+	req.Header.Set("A-IM", "zstd, bsdiff;q=0.5")
+	if !AcceptsDelta(req, DeltaBSDiff) || AcceptsDelta(req, "vcdiff") {
+		t.Fatal("Unexpected A-IM negotiation result")
+	}
+
+	w := httptest.NewRecorder()
+	err := NewRenderer(settings).WithWriter(w).WithHeader("Vary", "Available-Dictionary").Delta(Delta{
+		Format:     DeltaZstd,
+		Base:       "v1",
+		ETag:       `"v2"`,
+		Dictionary: "dict-7",
+		Data:       []byte{0x28, 0xb5},
+	})
+	if err != nil {
+		t.Fatalf("Delta failed: %v", err)
+	}
+	if w.Code != StatusIMUsed {
+		t.Errorf("Expected status 226, got %d", w.Code)
+	}
+	h := w.Header()
+	if h.Get("IM") != DeltaZstd || h.Get("Delta-Base") != `"v1"` || h.Get("ETag") != `"v2"` {
+		t.Errorf("Unexpected delta headers: %v", h)
+	}
+	if h.Get(HeaderContentType) != ContentTypeZstd {
+		t.Errorf("Expected content type %s, got %s", ContentTypeZstd, h.Get(HeaderContentType))
+	}
+	if h.Get("Dictionary-ID") != `"dict-7"` {
+		t.Errorf("Expected Dictionary-ID header, got %q", h.Get("Dictionary-ID"))
+	}
+	if got := strings.Join(h.Values("Vary"), ", "); got != "Available-Dictionary, A-IM" {
+		t.Errorf("Expected each Vary token once, got %q", got)
+	}
+
+	t.Run("NotCompressed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/update", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		patch := bytes.Repeat([]byte("patch"), 1000)
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).WithWriter(w).WithRequest(req).
+			WithCompression(Compression{MinSize: 1}).
+			Delta(Delta{Format: DeltaBSDiff, Data: patch})
+		if err != nil {
+			t.Fatalf("Delta failed: %v", err)
+		}
+		if ce := w.Header().Get("Content-Encoding"); ce != Empty {
+			t.Errorf("Expected no Content-Encoding, got %q", ce)
+		}
+		if !bytes.Equal(w.Body.Bytes(), patch) {
+			t.Errorf("Expected the patch bytes unchanged, got %d bytes", w.Body.Len())
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package beam
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// metaDeprecations is the meta key listing deprecated fields present in a response.
+const metaDeprecations = "deprecations"
+
+// warnDeprecated is the RFC 7234 warn-code for miscellaneous persistent warnings.
+const warnDeprecated = 299
+
+// Deprecation describes a deprecated Data field found in a response.
+type Deprecation struct {
+	Field string `json:"field" xml:"field" msgpack:"field"`
+	Note  string `json:"note,omitempty" xml:"note,omitempty" msgpack:"note,omitempty"`
+}
+
+// WithFieldDeprecation marks a Data field as deprecated.
+// Path is dot-separated (e.g., "user.email") and matches inside arrays as well;
+// responses carrying the field get a meta.deprecations entry and a Warning header.
+// Returns a new Renderer with the deprecation registered.
+func (r *Renderer) WithFieldDeprecation(path, note string) *Renderer {
+	nr := r.clone()
+	// Copy on write so renderers sharing the previous map are unaffected.
+	nr.deprecations = maps.Clone(r.deprecations)
+	if nr.deprecations == nil {
+		nr.deprecations = make(map[string]string)
+	}
+	nr.deprecations[path] = note
+	return nr
+}
+
+// deprecate annotates resp with every deprecated field present in its Data.
+// Adds meta.deprecations and one Warning header per field.
+func (r *Renderer) deprecate(resp *Response) {
+	if len(r.deprecations) == 0 || resp.Data == nil {
+		return
+	}
+	raw, err := json.Marshal(applyTypeMarshalers(resp.Data))
+	if err != nil {
+		return
+	}
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return
+	}
+
+	var found []Deprecation
+	for _, path := range slices.Sorted(maps.Keys(r.deprecations)) {
+		if !hasPath(data, strings.Split(path, ".")) {
+			continue
+		}
+		note := r.deprecations[path]
+		found = append(found, Deprecation{Field: path, Note: note})
+		r.header.Add("Warning", warningValue(path, note))
+	}
+	if len(found) == 0 {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaDeprecations] = found
+}
+
+// hasPath reports whether the path exists in v.
+// Arrays match when any element contains the remaining path.
+func hasPath(v interface{}, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		next, ok := t[path[0]]
+		return ok && hasPath(next, path[1:])
+	case []interface{}:
+		for _, item := range t {
+			if hasPath(item, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// warningValue formats an RFC 7234 Warning header value for a deprecated field.
+func warningValue(path, note string) string {
+	text := fmt.Sprintf("Deprecated field %q", path)
+	if note != Empty {
+		text += ": " + note
+	}
+	return fmt.Sprintf(`%d - "%s"`, warnDeprecated, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text))
+}
@@ -0,0 +1,54 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFieldDeprecation(t *testing.T) {
+	type user struct {
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty"`
+	}
+	r := NewRenderer(settings).
+		WithFieldDeprecation("users.email", "use contact.email").
+		WithFieldDeprecation("legacy", "")
+
+	t.Run("Present", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		data := map[string]interface{}{"users": []user{{Name: "a"}, {Name: "b", Email: "b@x"}}}
+		if err := r.Push(w, Response{Data: data}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		want := `299 - "Deprecated field \"users.email\": use contact.email"`
+		if got := w.Header().Values("Warning"); len(got) != 1 || got[0] != want {
+			t.Errorf("Expected Warning %q, got %q", want, got)
+		}
+		var body struct {
+			Meta struct {
+				Deprecations []Deprecation `json:"deprecations"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if d := body.Meta.Deprecations; len(d) != 1 || d[0].Field != "users.email" || d[0].Note != "use contact.email" {
+			t.Errorf("Expected users.email deprecation, got %+v", d)
+		}
+	})
+
+	t.Run("Absent", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := r.Push(w, Response{Data: map[string]interface{}{"users": []user{{Name: "a"}}}}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := w.Header().Get("Warning"); got != "" {
+			t.Errorf("Expected no Warning header, got %q", got)
+		}
+		if strings.Contains(w.Body.String(), "deprecations") {
+			t.Errorf("Expected no deprecations meta, got %s", w.Body.String())
+		}
+	})
+}
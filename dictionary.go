@@ -0,0 +1,184 @@
+package beam
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+)
+
+// EncodingDCZ is the Content-Encoding for dictionary-compressed zstd responses
+// as defined by Compression Dictionary Transport.
+const EncodingDCZ = "dcz"
+
+// dczMagic prefixes every dcz body, followed by the SHA-256 of the dictionary.
+var dczMagic = []byte{0x5e, 0x2a, 0x4d, 0x18, 0x20, 0x00, 0x00, 0x00}
+
+// zstdDictMagic marks dictionaries in zstd's trained dictionary format.
+var zstdDictMagic = []byte{0x37, 0xa4, 0x30, 0xec}
+
+// defaultDictionarySize bounds trained dictionaries when no size is given.
+const defaultDictionarySize = 110 << 10
+
+var (
+	errDictionaryEmpty    = errors.New("dictionary content required")
+	errDictionaryMismatch = errors.New("dcz body does not match dictionary")
+)
+
+// Dictionary is a shared zstd dictionary for an endpoint's typical payloads.
+// Responses are compressed against it when the client advertises the same dictionary,
+// which cuts bytes sharply for small, repetitive responses.
+// Safe for concurrent use.
+type Dictionary struct {
+	ID   string
+	raw  []byte
+	hash [sha256.Size]byte
+	enc  *zstd.Encoder
+	dec  *zstd.Decoder
+}
+
+// NewDictionary creates a Dictionary from existing content.
+// Takes either a trained zstd dictionary or arbitrary raw bytes used as shared history.
+// Returns an error if the content is empty or cannot be loaded.
+func NewDictionary(id string, content []byte) (*Dictionary, error) {
+	if len(content) == 0 {
+		return nil, errDictionaryEmpty
+	}
+	d := &Dictionary{ID: id, raw: bytes.Clone(content), hash: sha256.Sum256(content)}
+	var eopt zstd.EOption
+	var dopt zstd.DOption
+	if bytes.HasPrefix(content, zstdDictMagic) {
+		eopt, dopt = zstd.WithEncoderDict(d.raw), zstd.WithDecoderDicts(d.raw)
+	} else {
+		eopt, dopt = zstd.WithEncoderDictRaw(0, d.raw), zstd.WithDecoderDictRaw(0, d.raw)
+	}
+	var err error
+	if d.enc, err = zstd.NewWriter(nil, eopt); err != nil {
+		return nil, err
+	}
+	if d.dec, err = zstd.NewReader(nil, dopt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// TrainDictionary builds a Dictionary from sample payloads.
+// Takes representative responses for the endpoint and a size limit (0 uses ~110KB).
+// Returns an error if there are no samples or training fails.
+func TrainDictionary(id string, samples [][]byte, size int) (*Dictionary, error) {
+	if len(samples) == 0 {
+		return nil, errDictionaryEmpty
+	}
+	if size <= 0 {
+		size = defaultDictionarySize
+	}
+	content, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: size,
+		HashBytes:   6,
+		ZstdDictID:  dictionaryID(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewDictionary(id, content)
+}
+
+// dictionaryID derives a stable numeric zstd dictionary ID from a name.
+func dictionaryID(id string) uint32 {
+	sum := sha256.Sum256([]byte(id))
+	// IDs below 32768 are reserved by the zstd format.
+	return 1<<15 + (uint32(sum[0])<<16|uint32(sum[1])<<8|uint32(sum[2]))%(1<<31-1<<15)
+}
+
+// Bytes returns the dictionary content clients must download.
+func (d *Dictionary) Bytes() []byte {
+	return d.raw
+}
+
+// Hash returns the dictionary's SHA-256 as a structured-field byte sequence.
+// Matches the value clients send in the Available-Dictionary header.
+func (d *Dictionary) Hash() string {
+	return ":" + base64.StdEncoding.EncodeToString(d.hash[:]) + ":"
+}
+
+// Compress encodes data as a dcz body against the dictionary.
+// Returns the magic header, dictionary hash, and zstd frame.
+func (d *Dictionary) Compress(data []byte) []byte {
+	out := make([]byte, 0, len(dczMagic)+sha256.Size+len(data)/2)
+	out = append(out, dczMagic...)
+	out = append(out, d.hash[:]...)
+	return d.enc.EncodeAll(data, out)
+}
+
+// Decompress decodes a dcz body produced with this dictionary.
+// Returns an error if the header does not match or the frame is invalid.
+func (d *Dictionary) Decompress(data []byte) ([]byte, error) {
+	prefix := len(dczMagic) + sha256.Size
+	if len(data) < prefix || !bytes.Equal(data[:len(dczMagic)], dczMagic) || !bytes.Equal(data[len(dczMagic):prefix], d.hash[:]) {
+		return nil, errDictionaryMismatch
+	}
+	return d.dec.DecodeAll(data[prefix:], nil)
+}
+
+// Handler serves the dictionary so clients can fetch and cache it.
+// Sets Use-As-Dictionary with the URL match pattern and the dictionary ID.
+// Returns an http.Handler suitable for mounting on a dictionary URL.
+func (d *Dictionary) Handler(match string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		value := "match=" + strconv.Quote(match)
+		if d.ID != Empty {
+			value += ", id=" + strconv.Quote(d.ID)
+		}
+		w.Header().Set("Use-As-Dictionary", value)
+		w.Header().Set(HeaderContentType, ContentTypeBinary)
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		_, _ = w.Write(d.raw)
+	})
+}
+
+// WithCompressionDictionary compresses Push responses against d for capable clients.
+// Clients opt in with Accept-Encoding: dcz and an Available-Dictionary header
+// naming d's hash; everyone else receives the uncompressed body.
+// Returns a new Renderer with the dictionary set.
+func (r *Renderer) WithCompressionDictionary(d *Dictionary) *Renderer {
+	nr := r.clone()
+	nr.dictionary = d
+	return nr
+}
+
+// acceptsDictionary reports whether the bound request negotiated d.
+func acceptsDictionary(req *http.Request, d *Dictionary) bool {
+	if req == nil || AvailableDictionary(req) != strings.Trim(d.Hash(), ":") {
+		return false
+	}
+	for _, v := range req.Header.Values("Accept-Encoding") {
+		for _, item := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			if strings.EqualFold(strings.TrimSpace(name), EncodingDCZ) && strings.TrimSpace(params) != "q=0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compressDictionary applies dictionary compression to an encoded body when negotiated.
+// Returns the body to write, setting Content-Encoding and Vary headers as needed.
+func (r *Renderer) compressDictionary(encoded []byte) []byte {
+	if r.dictionary == nil {
+		return encoded
+	}
+	addVary(r.header, "Accept-Encoding")
+	addVary(r.header, "Available-Dictionary")
+	if !acceptsDictionary(r.request, r.dictionary) {
+		return encoded
+	}
+	r.header.Set("Content-Encoding", EncodingDCZ)
+	return r.dictionary.Compress(encoded)
+}
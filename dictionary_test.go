@@ -0,0 +1,86 @@
+package beam
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressionDictionary(t *testing.T) {
+	var samples [][]byte
+	for i := 0; i < 50; i++ {
+		samples = append(samples, []byte(fmt.Sprintf(`{"status":"+ok","data":{"id":%d,"name":"product-%d","currency":"EUR","in_stock":true}}`, i, i)))
+	}
+	dict, err := TrainDictionary("products-v1", samples, 4096)
+	if err != nil {
+		t.Fatalf("TrainDictionary failed: %v", err)
+	}
+	r := NewRenderer(settings).WithCompressionDictionary(dict)
+	data := map[string]interface{}{"id": 99, "name": "product-99", "currency": "EUR", "in_stock": true}
+
+	t.Run("Negotiated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/products/99", nil)
+		req.Header.Set("Accept-Encoding", "gzip, br, zstd, dcz")
+		req.Header.Set("Available-Dictionary", dict.Hash())
+		w := httptest.NewRecorder()
+		if err := r.WithRequest(req).Push(w, Response{Data: data}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != EncodingDCZ {
+			t.Fatalf("Expected Content-Encoding dcz, got %q", got)
+		}
+		plain, err := dict.Decompress(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Decompress failed: %v", err)
+		}
+		if !bytes.Contains(plain, []byte(`"name":"product-99"`)) {
+			t.Errorf("Expected original body, got %s", plain)
+		}
+		if w.Body.Len()-len(dczMagic)-32 >= len(plain)*3/4 {
+			t.Errorf("Expected compression, got %d bytes for %d", w.Body.Len(), len(plain))
+		}
+	})
+
+	t.Run("UnknownDictionary", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/products/99", nil)
+		req.Header.Set("Accept-Encoding", "dcz")
+		req.Header.Set("Available-Dictionary", ":AAAA:")
+		w := httptest.NewRecorder()
+		if err := r.WithHeader("Vary", "Accept-Encoding").WithRequest(req).Push(w, Response{Data: data}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != "" {
+			t.Errorf("Expected no Content-Encoding, got %q", got)
+		}
+		if got := strings.Join(w.Header().Values("Vary"), ", "); got != "Accept-Encoding, Available-Dictionary" {
+			t.Errorf("Expected each Vary token once, got %q", got)
+		}
+	})
+
+	t.Run("RawContent", func(t *testing.T) {
+		raw, err := NewDictionary("raw", bytes.Join(samples, nil))
+		if err != nil {
+			t.Fatalf("NewDictionary failed: %v", err)
+		}
+		out, err := raw.Decompress(raw.Compress(samples[3]))
+		if err != nil || !bytes.Equal(out, samples[3]) {
+			t.Errorf("Expected round-trip, got %s (%v)", out, err)
+		}
+		if _, err := raw.Decompress([]byte("nope")); err == nil {
+			t.Error("Expected error for foreign body")
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		dict.Handler("/products/*").ServeHTTP(w, httptest.NewRequest("GET", "/dict", nil))
+		if got := w.Header().Get("Use-As-Dictionary"); got != `match="/products/*", id="products-v1"` {
+			t.Errorf("Unexpected Use-As-Dictionary %q", got)
+		}
+		if !bytes.Equal(w.Body.Bytes(), dict.Bytes()) {
+			t.Error("Expected dictionary bytes")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package beam
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientGone is returned when the client disconnected before or during a response.
+var ErrClientGone = errors.New("client gone")
+
+// Done returns a channel closed when the client disconnects or the Renderer's context ends.
+// Wired from the bound request's context; returns nil (never closes) when neither is set.
+// Handlers can select on it to stop expensive work once nobody is listening.
+func (r *Renderer) Done() <-chan struct{} {
+	if r.ctx == nil && r.request == nil {
+		return nil
+	}
+	return r.Context().Done()
+}
+
+// clientGone reports ErrClientGone once the bound request's context has ended.
+// Triggers callbacks so the abandoned response is still observable.
+func (r *Renderer) clientGone() error {
+	if r.request == nil || r.request.Context().Err() == nil {
+		return nil
+	}
+	r.emit(newCallbackData(r.id, StatusError, "client gone", ErrClientGone))
+	return ErrClientGone
+}
+
+// watchClient wraps a Stream callback so it ends with ErrClientGone once the client disconnects.
+func (r *Renderer) watchClient(callback func(*Renderer) (interface{}, error)) func(*Renderer) (interface{}, error) {
+	if r.request == nil {
+		return callback
+	}
+	ctx := r.request.Context()
+	return func(nr *Renderer) (interface{}, error) {
+		if ctx.Err() != nil {
+			return nil, ErrClientGone
+		}
+		data, err := callback(nr)
+		if err != nil && ctx.Err() != nil && errors.Is(err, context.Canceled) {
+			return nil, ErrClientGone
+		}
+		return data, err
+	}
+}
@@ -0,0 +1,61 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientDisconnect(t *testing.T) {
+	t.Run("DoneNilWithoutRequest", func(t *testing.T) {
+		if NewRenderer(settings).Done() != nil {
+			t.Error("Expected nil Done channel without request or context")
+		}
+	})
+
+	t.Run("PushShortCircuits", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+		r := NewRenderer(settings).WithRequest(req)
+		cancel()
+		select {
+		case <-r.Done():
+		case <-time.After(time.Second):
+			t.Fatal("Expected Done to close after disconnect")
+		}
+		w := httptest.NewRecorder()
+		if err := r.Push(w, Response{Message: "late"}); !errors.Is(err, ErrClientGone) {
+			t.Errorf("Expected ErrClientGone, got %v", err)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected nothing written, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("StreamStops", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithRequest(req).WithContentType(ContentTypeNDJSON)
+		n := 0
+		err := r.Stream(func(r *Renderer) (interface{}, error) {
+			if n++; n == 3 {
+				cancel()
+			}
+			select {
+			case <-r.Done():
+				return nil, r.Context().Err()
+			default:
+				return n, nil
+			}
+		})
+		if !errors.Is(err, ErrClientGone) {
+			t.Errorf("Expected ErrClientGone, got %v", err)
+		}
+		if n != 3 {
+			t.Errorf("Expected stream to stop at item 3, got %d", n)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package beam
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
@@ -12,6 +13,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/olekukonko/beam/hauler"
 	"github.com/vmihailenco/msgpack/v5"
 )
 
@@ -48,6 +50,7 @@ func putBuffer(buf *bytes.Buffer) {
 
 const (
 	ContentTypeJSON           = "application/json"
+	ContentTypeNDJSON         = "application/x-ndjson"
 	ContentTypeMsgPack        = "application/msgpack"
 	ContentTypeXML            = "application/xml"
 	ContentTypeText           = "text/plain"
@@ -76,6 +79,13 @@ type Streamer interface {
 	Stream(w Writer, callback func() (interface{}, error)) error
 }
 
+// ContextStreamer is an optional interface for Streamers that wait between
+// items, such as throttled encoders. Renderer.Stream prefers StreamContext and
+// passes the Renderer's context so waits end when the stream is canceled.
+type ContextStreamer interface {
+	StreamContext(ctx context.Context, w Writer, callback func() (interface{}, error)) error
+}
+
 // EncoderRegistry manages content-type to encoder mappings.
 type EncoderRegistry struct {
 	mu       sync.RWMutex
@@ -84,7 +94,7 @@ type EncoderRegistry struct {
 
 // NewEncoderRegistry initializes an EncoderRegistry with default encoders.
 // Creates a new registry with thread-safe encoder mappings.
-// Registers JSON, MsgPack, XML, Text, FormURLEncoded, and EventStream encoders.
+// Registers JSON, NDJSON, MsgPack, XML, Text, FormURLEncoded, EventStream, MixedReplace, Protobuf, CSV, CBOR, JSON:API, HAL, and GeoJSON encoders.
 // Returns a pointer to the initialized EncoderRegistry.
 func NewEncoderRegistry() *EncoderRegistry {
 	er := &EncoderRegistry{
@@ -92,11 +102,19 @@ func NewEncoderRegistry() *EncoderRegistry {
 	}
 	// Register default encoders
 	er.Register(&JSONEncoder{})
+	er.Register(&NDJSONEncoder{})
 	er.Register(&MsgPackEncoder{})
 	er.Register(&XMLEncoder{})
 	er.Register(&TextEncoder{})
 	er.Register(&FormURLEncodedEncoder{})
 	er.Register(&EventStreamEncoder{})
+	er.Register(&MixedReplaceEncoder{})
+	er.Register(&ProtobufEncoder{})
+	er.Register(&CSVEncoder{})
+	er.Register(&CBOREncoder{})
+	er.Register(&JSONAPIEncoder{})
+	er.Register(&HALEncoder{})
+	er.Register(&GeoJSONEncoder{})
 	return er
 }
 
@@ -121,16 +139,60 @@ func (er *EncoderRegistry) Get(contentType string) (Encoder, bool) {
 	return e, ok
 }
 
+// Clone creates an independent copy of the registry.
+// The copy shares encoder instances but not the mapping, so registrations
+// on the copy do not affect the original.
+// Returns a new *EncoderRegistry.
+func (er *EncoderRegistry) Clone() *EncoderRegistry {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	nr := &EncoderRegistry{encoders: make(map[string]Encoder, len(er.encoders))}
+	for ct, e := range er.encoders {
+		nr.encoders[ct] = e
+	}
+	return nr
+}
+
+// All returns a snapshot of the registered encoders.
+// Order is unspecified.
+func (er *EncoderRegistry) All() []Encoder {
+	er.mu.RLock()
+	defer er.mu.RUnlock()
+	out := make([]Encoder, 0, len(er.encoders))
+	for _, e := range er.encoders {
+		out = append(out, e)
+	}
+	return out
+}
+
+// EncoderMiddleware wraps an Encoder to add behavior around Marshal or Unmarshal.
+// Implementations typically embed next and override the methods they decorate.
+type EncoderMiddleware func(next Encoder) Encoder
+
+// Wrap replaces the encoder for a content type with mw applied to it.
+// The wrapped encoder is registered under the original content type.
+// Returns false if no encoder is registered for the content type.
+func (er *EncoderRegistry) Wrap(contentType string, mw EncoderMiddleware) bool {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	e, ok := er.encoders[contentType]
+	if !ok || mw == nil {
+		return false
+	}
+	er.encoders[contentType] = mw(e)
+	return true
+}
+
 // Encode marshals data using the encoder for the given content type.
 // Takes a content type and data to encode.
 // Returns the encoded bytes or an error if the encoder is not found.
-// Delegates to the appropriate encoder's Marshal method.
+// Applies registered type marshalers, then delegates to the encoder's Marshal method.
 func (er *EncoderRegistry) Encode(contentType string, v interface{}) ([]byte, error) {
 	e, ok := er.Get(contentType)
 	if !ok {
 		return nil, fmt.Errorf("no encoder for content type %s", contentType)
 	}
-	return e.Marshal(v)
+	return e.Marshal(applyTypeMarshalers(v))
 }
 
 // EncodeWithFallback marshals data with fallback on error.
@@ -143,7 +205,7 @@ func (er *EncoderRegistry) EncodeWithFallback(contentType string, v interface{})
 		return nil, fmt.Errorf("no encoder for content type %s", contentType)
 	}
 
-	data, err := e.Marshal(v)
+	data, err := e.Marshal(applyTypeMarshalers(v))
 	if err == nil {
 		return data, nil
 	}
@@ -275,36 +337,70 @@ func (e *EncoderError) GenerateFallback() []byte {
 // SSE Event Type
 // -----------------------------------------------------------------------------
 
+// EventTypeError is the SSE event type used for enveloped error responses.
+const EventTypeError = "error"
+
 // Event represents a Server-Sent Events (SSE) event.
+// When Envelope is true, Data is wrapped in the standard Response envelope
+// so stream consumers see the same status, errors, and meta as regular clients.
 type Event struct {
-	ID    string      `json:"id,omitempty"`
-	Type  string      `type:"type,omitempty"`
-	Data  interface{} `json:"data"`
-	Retry int         `json:"retry,omitempty"`
+	ID       string      `json:"id,omitempty"`
+	Type     string      `type:"type,omitempty"`
+	Data     interface{} `json:"data"`
+	Retry    int         `json:"retry,omitempty"`
+	Comment  string      `json:"-"` // Optional comment lines emitted before the fields
+	Envelope bool        `json:"-"`
+}
+
+// payload returns the value to encode in the event's data field.
+// Wraps Data in a Response when Envelope is set, reusing an existing Response as-is.
+// Returns the payload and the event type, defaulting to EventTypeError for error envelopes.
+func (e Event) payload() (interface{}, string) {
+	if !e.Envelope {
+		return e.Data, e.Type
+	}
+	var resp Response
+	switch d := e.Data.(type) {
+	case Response:
+		resp = d
+	case *Response:
+		if d != nil {
+			resp = *d
+		}
+	default:
+		resp = Response{Data: d}
+	}
+	if resp.Status == Empty {
+		resp.Status = StatusSuccessful
+	}
+	typ := e.Type
+	if typ == Empty && (resp.Status == StatusError || resp.Status == StatusFatal) {
+		typ = EventTypeError
+	}
+	return resp, typ
 }
 
 // -----------------------------------------------------------------------------
 // Default Encoder Implementations
 // -----------------------------------------------------------------------------
 
-type JSONEncoder struct{}
+// JSONEncoder encodes JSON using a pooled buffer.
+// Numbers optionally controls integer and float formatting.
+type JSONEncoder struct {
+	Numbers NumberFormat
+}
 
 // Marshal encodes data to JSON format using a pooled buffer.
 // Takes any JSON-serializable data as input.
 // Returns the encoded JSON bytes without trailing newline or an error if encoding fails.
 // Uses a pooled buffer to reduce memory allocations.
 func (e *JSONEncoder) Marshal(v interface{}) ([]byte, error) {
-	buf := getBuffer()
-	defer putBuffer(buf)
-	enc := json.NewEncoder(buf)
-	if err := enc.Encode(v); err != nil {
-		return nil, err
-	}
-	// Trim trailing newline added by json.Encoder
-	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
-	result := make([]byte, len(data))
-	copy(result, data)
-	return result, nil
+	return marshalJSON(v, e.Numbers, Empty)
+}
+
+// withNumbers returns a copy of the encoder using the given NumberFormat.
+func (e *JSONEncoder) withNumbers(nf NumberFormat) Encoder {
+	return &JSONEncoder{Numbers: nf}
 }
 
 // Unmarshal decodes JSON data into the provided pointer.
@@ -343,9 +439,12 @@ func (e *MsgPackEncoder) Marshal(v interface{}) ([]byte, error) {
 
 // Unmarshal decodes MsgPack data into the provided pointer.
 // Takes a byte slice and a pointer to the target variable.
-// Returns an error if decoding fails.
-// Uses standard msgpack.Unmarshal without buffer pooling.
+// Returns an error if decoding fails, or hauler.ErrMalformedMsgPack when
+// declared lengths exceed the input, before anything is allocated for them.
 func (e *MsgPackEncoder) Unmarshal(data []byte, v interface{}) error {
+	if err := hauler.CheckMsgPack(data); err != nil {
+		return err
+	}
 	return msgpack.Unmarshal(data, v)
 }
 
@@ -395,11 +494,11 @@ func (e *XMLEncoder) mapToXMLBytes(m map[string]interface{}) ([]byte, error) {
 		Value   interface{} `xml:",innerxml"`
 	}
 
-	var entries []Entry
-	for k, v := range m {
+	entries := make([]Entry, 0, len(m))
+	for _, k := range sortedKeys(m) {
 		entries = append(entries, Entry{
 			XMLName: xml.Name{Local: k},
-			Value:   v,
+			Value:   m[k],
 		})
 	}
 
@@ -431,15 +530,17 @@ func (e *XMLEncoder) marshalResponse(resp Response) ([]byte, error) {
 	}
 
 	type Alias struct {
-		XMLName xml.Name     `xml:"response"` // root element
-		Status  string       `xml:"status"`
-		Title   string       `xml:"title,omitempty"`
-		Message string       `xml:"message,omitempty"`
-		Tags    []string     `xml:"tags,omitempty"`
-		Info    interface{}  `xml:"info,omitempty"`
-		Data    interface{}  `xml:"data,omitempty"`
-		Meta    *MetaWrapper `xml:"meta,omitempty"`
-		Errors  ErrorList    `xml:"errors,omitempty"`
+		XMLName  xml.Name     `xml:"response"` // root element
+		Status   string       `xml:"status"`
+		Title    string       `xml:"title,omitempty"`
+		Message  string       `xml:"message,omitempty"`
+		Tags     []string     `xml:"tags,omitempty"`
+		Info     interface{}  `xml:"info,omitempty"`
+		Data     interface{}  `xml:"data,omitempty"`
+		InfoType string       `xml:"info_type,omitempty"`
+		DataType string       `xml:"data_type,omitempty"`
+		Meta     *MetaWrapper `xml:"meta,omitempty"`
+		Errors   ErrorList    `xml:"errors,omitempty"`
 	}
 
 	// Build the MetaWrapper if there is meta information
@@ -468,8 +569,9 @@ func (e *XMLEncoder) marshalResponse(resp Response) ([]byte, error) {
 			delete(resp.Meta, "system")
 		}
 
-		// Process any additional meta fields
-		for key, value := range resp.Meta {
+		// Process any additional meta fields in sorted order for stable output.
+		for _, key := range sortedKeys(resp.Meta) {
+			value := resp.Meta[key]
 			if nestedMap, ok := value.(map[string]interface{}); ok {
 				nested := e.mapToXML(nestedMap)
 				mw.OtherMeta = append(mw.OtherMeta, xmlMeta{
@@ -487,14 +589,16 @@ func (e *XMLEncoder) marshalResponse(resp Response) ([]byte, error) {
 	}
 
 	aux := Alias{
-		Status:  resp.Status,
-		Title:   resp.Title,
-		Message: resp.Message,
-		Tags:    resp.Tags,
-		Info:    resp.Info,
-		Data:    resp.Data,
-		Meta:    metaWrapper,
-		Errors:  resp.Errors,
+		Status:   resp.Status,
+		Title:    resp.Title,
+		Message:  resp.Message,
+		Tags:     resp.Tags,
+		Info:     resp.Info,
+		Data:     resp.Data,
+		InfoType: resp.InfoType,
+		DataType: resp.DataType,
+		Meta:     metaWrapper,
+		Errors:   resp.Errors,
 	}
 
 	buf := getBuffer()
@@ -521,7 +625,8 @@ func (e *XMLEncoder) mapToXML(m map[string]interface{}) interface{} {
 	}
 
 	elements := make([]xmlElement, 0, len(m))
-	for key, value := range m {
+	for _, key := range sortedKeys(m) {
+		value := m[key]
 		if nestedMap, ok := value.(map[string]interface{}); ok {
 			elements = append(elements, xmlElement{XMLName: xml.Name{Local: key}, Value: e.mapToXML(nestedMap)})
 		} else {
@@ -617,38 +722,44 @@ func (e *FormURLEncodedEncoder) ContentType() string {
 }
 
 // EventStreamEncoder encodes Server-Sent Events (SSE).
-type EventStreamEncoder struct{}
+// Indent enables pretty-printed JSON data; multi-line payloads are split
+// into one "data:" line per line so frames stay well-formed.
+type EventStreamEncoder struct {
+	Indent  string
+	Numbers NumberFormat
+}
+
+// withNumbers returns a copy of the encoder using the given NumberFormat.
+func (e *EventStreamEncoder) withNumbers(nf NumberFormat) Encoder {
+	return &EventStreamEncoder{Indent: e.Indent, Numbers: nf}
+}
 
 // Marshal encodes an SSE event to its string representation.
-// Takes an Event struct with ID, Type, Data, and Retry fields.
+// Takes an Event struct with ID, Type, Data, Comment, and Retry fields, or an *EventBuilder.
 // Returns the encoded SSE bytes without extra newlines or an error if encoding fails.
 // Uses pooled buffers for both the event and its JSON data field to minimize allocations.
 func (e *EventStreamEncoder) Marshal(v interface{}) ([]byte, error) {
+	if b, ok := v.(*EventBuilder); ok {
+		return b.Build()
+	}
 	if evt, ok := v.(Event); ok {
+		payload, typ := evt.payload()
 		buf := getBuffer()
 		defer putBuffer(buf)
+		if evt.Comment != "" {
+			writeSSEField(buf, "", evt.Comment)
+		}
 		if evt.ID != "" {
-			buf.WriteString("id: ")
-			buf.WriteString(evt.ID)
-			buf.WriteByte('\n')
+			writeSSEField(buf, "id", sseSingleLine(evt.ID))
 		}
-		if evt.Type != "" {
-			buf.WriteString("event: ")
-			buf.WriteString(evt.Type)
-			buf.WriteByte('\n')
+		if typ != "" {
+			writeSSEField(buf, "event", sseSingleLine(typ))
 		}
-		dataBuf := getBuffer()
-		enc := json.NewEncoder(dataBuf)
-		if err := enc.Encode(evt.Data); err != nil {
-			putBuffer(dataBuf)
+		data, err := marshalJSON(payload, e.Numbers, e.Indent)
+		if err != nil {
 			return nil, err
 		}
-		// Trim trailing newline from JSON data
-		data := bytes.TrimSuffix(dataBuf.Bytes(), []byte("\n"))
-		buf.WriteString("data: ")
-		buf.Write(data)
-		buf.WriteByte('\n')
-		putBuffer(dataBuf)
+		writeSSEField(buf, "data", string(data))
 		if evt.Retry > 0 {
 			buf.WriteString("retry: ")
 			// Convert int to string efficiently
@@ -0,0 +1,79 @@
+package beam
+
+import (
+	"encoding/xml"
+	"errors"
+)
+
+// ErrorCoder is implemented by errors that carry a machine-readable code.
+// The code is preserved when an ErrorList is encoded as XML or MessagePack.
+type ErrorCoder interface {
+	ErrorCode() string
+}
+
+// ErrorItem is the structured form of one ErrorList entry.
+type ErrorItem struct {
+	Code    string `json:"code,omitempty" xml:"code,attr,omitempty" msgpack:"code,omitempty"`
+	Message string `json:"message" xml:",chardata" msgpack:"message"`
+}
+
+// codedError is an error decoded from a structured item with a code.
+type codedError struct {
+	code string
+	msg  string
+}
+
+// Error returns the error message.
+func (e *codedError) Error() string { return e.msg }
+
+// ErrorCode returns the error's code.
+func (e *codedError) ErrorCode() string { return e.code }
+
+// err converts an item back into an error, keeping its code if present.
+func (it ErrorItem) err() error {
+	if it.Code == Empty {
+		return errors.New(it.Message)
+	}
+	return &codedError{code: it.Code, msg: it.Message}
+}
+
+// items converts the list into structured items.
+func (el ErrorList) items() []ErrorItem {
+	items := make([]ErrorItem, len(el))
+	for i, err := range el {
+		if err == nil {
+			continue
+		}
+		items[i].Message = err.Error()
+		var coder ErrorCoder
+		if errors.As(err, &coder) {
+			items[i].Code = coder.ErrorCode()
+		}
+	}
+	return items
+}
+
+// MarshalXML encodes the list as <error code="...">message</error> children.
+// Returns an error if XML encoding fails.
+func (el ErrorList) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	wrapper := struct {
+		Items []ErrorItem `xml:"error"`
+	}{Items: el.items()}
+	return e.EncodeElement(wrapper, start)
+}
+
+// UnmarshalXML decodes <error> children back into the list.
+// Returns an error if XML decoding fails.
+func (el *ErrorList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var wrapper struct {
+		Items []ErrorItem `xml:"error"`
+	}
+	if err := d.DecodeElement(&wrapper, &start); err != nil {
+		return err
+	}
+	*el = make(ErrorList, len(wrapper.Items))
+	for i, it := range wrapper.Items {
+		(*el)[i] = it.err()
+	}
+	return nil
+}
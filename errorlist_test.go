@@ -0,0 +1,84 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testCodedError struct{ code, msg string }
+
+func (e testCodedError) Error() string     { return e.msg }
+func (e testCodedError) ErrorCode() string { return e.code }
+
+func TestErrorListRoundTrip(t *testing.T) {
+	in := ErrorList{
+		testCodedError{code: "E_INPUT", msg: "bad input"},
+		fmt.Errorf("wrapped: %w", testCodedError{code: "E_LEN", msg: "too long"}),
+		errors.New("plain"),
+	}
+	codecs := map[string]Encoder{
+		"JSON":    &JSONEncoder{},
+		"XML":     &XMLEncoder{},
+		"MsgPack": &MsgPackEncoder{},
+	}
+	for name, enc := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := enc.Marshal(Response{Status: StatusError, Errors: in})
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			var resp Response
+			if err := enc.Unmarshal(data, &resp); err != nil {
+				t.Fatalf("Unmarshal failed: %v (%s)", err, data)
+			}
+			if len(resp.Errors) != len(in) {
+				t.Fatalf("Expected %d errors, got %v", len(in), resp.Errors)
+			}
+			for i, err := range resp.Errors {
+				if err.Error() != in[i].Error() {
+					t.Errorf("Expected message %q, got %q", in[i].Error(), err.Error())
+				}
+			}
+			var coder ErrorCoder
+			if !errors.As(resp.Errors[1], &coder) || coder.ErrorCode() != "E_LEN" {
+				t.Errorf("Expected code E_LEN to survive, got %v", resp.Errors[1])
+			}
+			if errors.As(resp.Errors[2], &coder) {
+				t.Errorf("Expected no code on plain error, got %q", coder.ErrorCode())
+			}
+		})
+	}
+
+	t.Run("XMLShape", func(t *testing.T) {
+		data, err := (&XMLEncoder{}).Marshal(Response{Status: StatusError, Errors: in[:1]})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if !strings.Contains(string(data), `<errors><error code="E_INPUT">bad input</error></errors>`) {
+			t.Errorf("Expected structured error element, got %s", data)
+		}
+	})
+
+	t.Run("JSONShape", func(t *testing.T) {
+		data, err := ErrorList{in[0], in[2]}.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON failed: %v", err)
+		}
+		if want := `[{"code":"E_INPUT","message":"bad input"},"plain"]`; string(data) != want {
+			t.Errorf("Expected %s, got %s", want, data)
+		}
+	})
+
+	t.Run("JSONObjects", func(t *testing.T) {
+		var el ErrorList
+		if err := el.UnmarshalJSON([]byte(`["plain",{"code":"E_X","message":"coded"}]`)); err != nil {
+			t.Fatalf("UnmarshalJSON failed: %v", err)
+		}
+		var coder ErrorCoder
+		if len(el) != 2 || !errors.As(el[1], &coder) || coder.ErrorCode() != "E_X" {
+			t.Errorf("Expected mixed items to decode, got %v", el)
+		}
+	})
+}
@@ -2,8 +2,10 @@ package beam
 
 import (
 	"errors"
+	"maps"
 	"net/http"
 	"runtime"
+	"slices"
 	"strings"
 )
 
@@ -44,6 +46,20 @@ func cloneHeader(h http.Header) http.Header {
 	return newHeader
 }
 
+// cloneStatusHooks creates a copy of a status hook map.
+// Hook slices are duplicated so appends on the copy do not affect the original.
+// Returns nil if the input map is nil.
+func cloneStatusHooks(m map[int][]StatusHook) map[int][]StatusHook {
+	if m == nil {
+		return nil
+	}
+	newMap := make(map[int][]StatusHook, len(m))
+	for code, hooks := range m {
+		newMap[code] = slices.Clone(hooks)
+	}
+	return newMap
+}
+
 // cloneMap creates a shallow copy of a string-to-interface map.
 // It duplicates key-value pairs from the input map into a new map with pre-allocated capacity.
 // Returns a new map or nil if the input map is nil.
@@ -132,3 +148,21 @@ func getCallerInfo() (file string, line int, funcName string) {
 	}
 	return "unknown", 0, "unknown"
 }
+
+// sortedKeys returns the keys of m in sorted order.
+// Used wherever map iteration affects output, so encodings are byte-stable.
+func sortedKeys[M ~map[string]V, V any](m M) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// unwrapResponse returns the Data of a Response, or v unchanged.
+// Used by encoders whose wire format carries only the payload.
+func unwrapResponse(v interface{}) interface{} {
+	switch t := v.(type) {
+	case Response:
+		return t.Data
+	case *Response:
+		return t.Data
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package beam
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Compression flags of FramedProtocol frames with a flag byte.
+const (
+	FrameUncompressed byte = 0 // Payload is sent as encoded
+	FrameCompressed   byte = 1 // Payload is gzip-compressed
+)
+
+// errFrameFlag reports a frame with an unknown compression flag.
+var errFrameFlag = errors.New("unknown frame compression flag")
+
+// FramedProtocol prefixes every message with its length so binary clients can
+// find message boundaries: an optional 1-byte compression flag followed by a
+// 4-byte big-endian length and the payload. With Flag set the layout matches
+// gRPC-web message frames. Use Renderer.Framed to write through it.
+type FramedProtocol struct {
+	Flag     bool // Precede the length with the compression flag byte
+	Compress bool // Gzip payloads of at least MinSize bytes; requires Flag
+	MinSize  int  // Smallest payload compressed; zero compresses every payload
+}
+
+// ApplyHeaders writes the status code when framing an HTTP response, such as
+// a gRPC-web stream, and is a no-op on raw connections.
+// Returns nil.
+func (p *FramedProtocol) ApplyHeaders(w Writer, code int) error {
+	if fw, ok := w.(*framedWriter); ok {
+		w = fw.w
+	}
+	if hw, ok := w.(http.ResponseWriter); ok {
+		hw.WriteHeader(code)
+	}
+	return nil
+}
+
+// WriteMessage writes payload to w as one frame.
+// Returns an error if compression or writing fails.
+func (p *FramedProtocol) WriteMessage(w io.Writer, payload []byte) error {
+	_, err := p.writeMessage(w, payload)
+	return err
+}
+
+// writeMessage writes payload to w as one frame and returns the bytes written.
+func (p *FramedProtocol) writeMessage(w io.Writer, payload []byte) (int, error) {
+	flag := FrameUncompressed
+	if p.Flag && p.Compress && len(payload) >= p.MinSize {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		payload, flag = buf.Bytes(), FrameCompressed
+	}
+	header := []byte{flag, 0, 0, 0, 0}
+	if !p.Flag {
+		header = header[1:]
+	}
+	return writeLengthPrefixed(w, header, payload)
+}
+
+// ReadMessage reads the next frame from r and returns its decompressed payload.
+// Returns io.EOF at a clean end of stream or an error if the frame is malformed.
+func (p *FramedProtocol) ReadMessage(r io.Reader) ([]byte, error) {
+	header := make([]byte, 4, 5)
+	if p.Flag {
+		header = header[:5]
+	}
+	payload, err := readLengthPrefixed(r, header)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Flag || header[0] == FrameUncompressed {
+		return payload, nil
+	}
+	if header[0] != FrameCompressed {
+		return nil, fmt.Errorf("%w: %d", errFrameFlag, header[0])
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(io.LimitReader(zr, maxFramePayload))
+}
+
+// Framed makes the Renderer write every encoded response or stream chunk as
+// one length-prefixed frame of p on its current writer, so Push and Stream
+// output can be consumed by gRPC-web style or custom TCP clients.
+// Headers are still applied when the writer is an http.ResponseWriter.
+// Returns a new Renderer writing frames with p.
+func (r *Renderer) Framed(p *FramedProtocol) *Renderer {
+	nr := r.WithWriter(&framedWriter{w: r.writer, p: p})
+	nr.httpWriter = r.httpWriter
+	nr.protocol = NewProtocolHandler(p)
+	return nr
+}
+
+// framedWriter adapts a Writer to FramedProtocol, one frame per Write.
+// A frame is all or nothing: a failed Write reports 0 bytes, and a frame cut
+// short fails with an error that is never retried.
+type framedWriter struct {
+	w Writer
+	p *FramedProtocol
+}
+
+func (w *framedWriter) Write(b []byte) (int, error) {
+	if w.w == nil {
+		return 0, errNilWriter
+	}
+	if n, err := w.p.writeMessage(w.w, b); err != nil {
+		return 0, frameWriteError(n, err)
+	}
+	return len(b), nil
+}
+
+// Flush flushes the underlying writer so each frame reaches the client promptly.
+func (w *framedWriter) Flush() {
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,77 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFramedProtocol(t *testing.T) {
+	t.Run("Stream", func(t *testing.T) {
+		var buf bytes.Buffer
+		p := &FramedProtocol{}
+		r := NewRenderer(settings).WithWriter(&buf).WithContentType(ContentTypeNDJSON).Framed(p)
+		i := 0
+		err := r.Stream(func(*Renderer) (interface{}, error) {
+			if i == 3 {
+				return nil, io.EOF
+			}
+			i++
+			return map[string]int{"n": i}, nil
+		})
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		for want := 1; want <= 3; want++ {
+			msg, err := p.ReadMessage(&buf)
+			if err != nil {
+				t.Fatalf("ReadMessage %d failed: %v", want, err)
+			}
+			var got map[string]int
+			if err := json.Unmarshal(msg, &got); err != nil || got["n"] != want {
+				t.Errorf("Expected record %d in its own frame, got %q", want, msg)
+			}
+		}
+		if _, err := p.ReadMessage(&buf); err != io.EOF {
+			t.Errorf("Expected io.EOF after the last frame, got %v", err)
+		}
+	})
+
+	t.Run("CompressedHTTP", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		p := &FramedProtocol{Flag: true, Compress: true, MinSize: 16}
+		r := NewRenderer(settings).WithWriter(w).Framed(p)
+		err := r.WithStatus(http.StatusCreated).Push(nil, Response{Status: StatusSuccessful, Message: "created", Data: strings.Repeat("x", 64)})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if w.Code != http.StatusCreated || w.Header().Get(HeaderContentType) != ContentTypeJSON {
+			t.Errorf("Expected status and headers on the HTTP response, got %d %v", w.Code, w.Header())
+		}
+		if w.Body.Bytes()[0] != FrameCompressed {
+			t.Fatalf("Expected the compressed flag, got %d", w.Body.Bytes()[0])
+		}
+		msg, err := p.ReadMessage(w.Body)
+		if err != nil {
+			t.Fatalf("ReadMessage failed: %v", err)
+		}
+		var resp Response
+		if err := json.Unmarshal(msg, &resp); err != nil || resp.Message != "created" {
+			t.Errorf("Expected the decompressed envelope, got %q (%v)", msg, err)
+		}
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		p := &FramedProtocol{Flag: true}
+		if _, err := p.ReadMessage(bytes.NewReader([]byte{7, 0, 0, 0, 1, 'x'})); err == nil {
+			t.Error("Expected an unknown flag to fail")
+		}
+		if _, err := p.ReadMessage(bytes.NewReader([]byte{0, 0, 0, 0, 5, 'x'})); err != io.ErrUnexpectedEOF {
+			t.Errorf("Expected a truncated frame to fail, got %v", err)
+		}
+	})
+}
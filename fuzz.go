@@ -0,0 +1,46 @@
+package beam
+
+import (
+	"fmt"
+)
+
+// FuzzEncoder is a fuzzing entry point for the default encoder registered for contentType.
+// Decodes data with the encoder's Unmarshal and, when that succeeds, re-encodes the
+// result with Marshal, panicking if an accepted input cannot be re-encoded.
+// Returns 1 when data decoded successfully, 0 when it was rejected, and -1 for
+// unknown content types, following the go-fuzz convention.
+func FuzzEncoder(contentType string, data []byte) int {
+	e, ok := NewEncoderRegistry().Get(contentType)
+	if !ok {
+		return -1
+	}
+	var v interface{}
+	if err := e.Unmarshal(data, &v); err != nil {
+		return 0
+	}
+	if v == nil {
+		// Decoders that do not populate generic targets have nothing to re-encode.
+		return 1
+	}
+	if _, err := e.Marshal(v); err != nil {
+		panic(fmt.Sprintf("beam: %s accepted input it cannot re-encode: %v", contentType, err))
+	}
+	return 1
+}
+
+// FuzzMarshal is a fuzzing entry point for encoding arbitrary strings.
+// Marshals s as a Response message and as raw data with the encoder for contentType.
+// Returns 1 when encoding succeeded, 0 on an encoding error, and -1 for unknown content types.
+func FuzzMarshal(contentType string, s string) int {
+	e, ok := NewEncoderRegistry().Get(contentType)
+	if !ok {
+		return -1
+	}
+	if _, err := e.Marshal(Response{Status: StatusSuccessful, Message: s, Tags: []string{s}}); err != nil {
+		return 0
+	}
+	if _, err := e.Marshal(s); err != nil {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,46 @@
+package beam
+
+import "testing"
+
+// fuzzContentTypes lists the encoders exercised by the native fuzz targets.
+var fuzzContentTypes = []string{
+	ContentTypeJSON,
+	ContentTypeNDJSON,
+	ContentTypeMsgPack,
+	ContentTypeXML,
+	ContentTypeText,
+	ContentTypeFormURLEncoded,
+	ContentTypeEventStream,
+}
+
+func FuzzEncoderUnmarshal(f *testing.F) {
+	seeds := [][]byte{
+		[]byte(`{"status":"successful","data":[1,2,3]}`),
+		[]byte("{\"a\":1}\n{\"b\":2}\n"),
+		[]byte("<response><status>ok</status></response>"),
+		[]byte("a=1&b=2"),
+		[]byte("id: 1\nevent: message\ndata: {}\n\n"),
+		{0x81, 0xa1, 0x61, 0x01},
+		[]byte("\xdd\xdd\xdd\xdd\xdd\xdd\xdd\xdd0\xc60"), // array32 declaring billions of items
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, ct := range fuzzContentTypes {
+			FuzzEncoder(ct, data)
+		}
+	})
+}
+
+func FuzzEncoderMarshal(f *testing.F) {
+	f.Add("hello")
+	f.Add("line1\nline2\r\n")
+	f.Add("<&>\"'")
+	f.Add("\xff\xfe")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, ct := range fuzzContentTypes {
+			FuzzMarshal(ct, s)
+		}
+	})
+}
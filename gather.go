@@ -0,0 +1,73 @@
+package beam
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Fetcher retrieves one named source for Gather.
+type Fetcher func(ctx context.Context) (interface{}, error)
+
+// Gather runs fetchers concurrently and renders their merged results once.
+// Each result is stored in Response.Data under its fetcher's name. Failures are
+// prefixed with the source name and run through the Renderer's error filters;
+// any that survive produce an error response (502, or 500 for fatal errors)
+// that still carries the data from the sources that succeeded.
+// Returns a *ConfigError if the writer or other configuration is missing, or an
+// error if sending the response fails.
+func Gather(ctx context.Context, r *Renderer, fetchers map[string]Fetcher) error {
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	parts := make([]PartFunc, 0, len(fetchers))
+	for name, fetch := range fetchers {
+		parts = append(parts, Part(name, 0, fetch))
+	}
+
+	data := make(map[string]interface{}, len(fetchers))
+	var errs, finalErrors []error
+	fatal, hidden := false, false
+	for _, res := range runParts(ctx, parts) {
+		if res.err == nil {
+			data[res.name] = res.value
+			continue
+		}
+		errs = append(errs, res.err)
+		// Filter each source separately so the name survives error unwrapping.
+		responseErrors, fatalErrors, hasHidden := r.processErrors(false, res.err)
+		for _, err := range append(responseErrors, fatalErrors...) {
+			finalErrors = append(finalErrors, fmt.Errorf("%s: %w", res.name, err))
+		}
+		fatal = fatal || len(fatalErrors) > 0
+		hidden = hidden || hasHidden
+	}
+
+	if len(finalErrors) == 0 && !hidden {
+		return r.WithStatus(http.StatusOK).Push(r.writer, Response{
+			Status: StatusSuccessful,
+			Data:   data,
+		})
+	}
+
+	resp := Response{
+		Status:  StatusError,
+		Message: "one or more sources failed",
+		Data:    data,
+	}
+	code := http.StatusBadGateway
+	if fatal {
+		resp.Status = StatusFatal
+		code = http.StatusInternalServerError
+	}
+	if r.showsErrors() {
+		resp.Errors = finalErrors
+	}
+	for _, err := range r.filterErrorsForLogging(errs) {
+		r.Log(err)
+	}
+	return r.WithStatus(code).Push(r.writer, resp)
+}
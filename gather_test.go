@@ -0,0 +1,72 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGather(t *testing.T) {
+	ok := func(v interface{}) Fetcher {
+		return func(ctx context.Context) (interface{}, error) { return v, nil }
+	}
+
+	t.Run("AllSucceed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := Gather(context.Background(), NewRenderer(settings).WithWriter(rec), map[string]Fetcher{
+			"user":   ok("ada"),
+			"orders": ok([]int{1, 2}),
+		})
+		if err != nil {
+			t.Fatalf("Gather failed: %v", err)
+		}
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		data, _ := resp.Data.(map[string]interface{})
+		if resp.Status != StatusSuccessful || data["user"] != "ada" || data["orders"] == nil {
+			t.Errorf("Unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("SourceFails", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		err := Gather(context.Background(), NewRenderer(settings).WithWriter(rec), map[string]Fetcher{
+			"user": ok("ada"),
+			"billing": func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("upstream unavailable")
+			},
+		})
+		if err != nil {
+			t.Fatalf("Gather failed: %v", err)
+		}
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status 502, got %d", rec.Code)
+		}
+		body := rec.Body.String()
+		if !strings.Contains(body, "billing: upstream unavailable") || !strings.Contains(body, `"user":"ada"`) {
+			t.Errorf("Expected named error and partial data, got %s", body)
+		}
+	})
+
+	t.Run("SkippedErrors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		skip := errors.New("not found")
+		r := NewRenderer(settings).WithSkipFilter(func(err error) bool { return errors.Is(err, skip) }).WithWriter(rec)
+		err := Gather(context.Background(), r, map[string]Fetcher{
+			"user":  ok("ada"),
+			"extra": func(ctx context.Context) (interface{}, error) { return nil, skip },
+		})
+		if err != nil {
+			t.Fatalf("Gather failed: %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected skipped errors to yield 200, got %d", rec.Code)
+		}
+	})
+}
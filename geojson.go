@@ -0,0 +1,134 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+// ContentTypeGeoJSON is the content type for RFC 7946 GeoJSON.
+const ContentTypeGeoJSON = "application/geo+json"
+
+// GeoJSON object models, shared with the hauler GeoJSON parser.
+type (
+	FeatureCollection = hauler.FeatureCollection
+	Feature           = hauler.Feature
+	Geometry          = hauler.Geometry
+)
+
+// GeoJSONEncoder encodes responses as GeoJSON FeatureCollections.
+// Data may be a FeatureCollection, a Feature, a []Feature, a geometry, or any
+// value marshaling to one; single features and geometries are wrapped in a
+// collection. Status, message, errors, and meta travel as foreign members.
+// Output is validated against RFC 7946 so malformed geometry never ships.
+type GeoJSONEncoder struct{}
+
+// geoJSONDocument is a Response laid out as a FeatureCollection.
+type geoJSONDocument struct {
+	FeatureCollection
+	Status  string                 `json:"status,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Errors  ErrorList              `json:"errors,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Marshal encodes v as a GeoJSON FeatureCollection.
+// Returns hauler.ErrInvalidGeoJSON if the data is not valid GeoJSON.
+func (e *GeoJSONEncoder) Marshal(v interface{}) ([]byte, error) {
+	switch resp := v.(type) {
+	case Response:
+		return e.document(resp)
+	case *Response:
+		return e.document(*resp)
+	}
+	fc, err := geoCollection(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fc)
+}
+
+// document encodes resp with its Data as the collection.
+func (e *GeoJSONEncoder) document(resp Response) ([]byte, error) {
+	fc, err := geoCollection(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(geoJSONDocument{
+		FeatureCollection: fc,
+		Status:            resp.Status,
+		Message:           resp.Message,
+		Errors:            resp.Errors,
+		Meta:              resp.Meta,
+	})
+}
+
+// Unmarshal validates GeoJSON data and decodes it into the provided pointer.
+// Returns hauler.ErrInvalidGeoJSON if the data is not valid GeoJSON.
+func (e *GeoJSONEncoder) Unmarshal(data []byte, v interface{}) error {
+	if err := hauler.ValidateGeoJSON(data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ContentType returns the GeoJSON content type.
+// Returns the constant "application/geo+json".
+func (e *GeoJSONEncoder) ContentType() string {
+	return ContentTypeGeoJSON
+}
+
+// geoCollection validates data and returns it as a FeatureCollection.
+// A nil value yields an empty collection.
+func geoCollection(data interface{}) (FeatureCollection, error) {
+	switch d := data.(type) {
+	case nil:
+		return hauler.NewFeatureCollection(), nil
+	case Feature:
+		data = hauler.NewFeatureCollection(d)
+	case *Feature:
+		if d != nil {
+			data = hauler.NewFeatureCollection(*d)
+		}
+	case []Feature:
+		data = hauler.NewFeatureCollection(d...)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return FeatureCollection{}, err
+	}
+	if err := hauler.ValidateGeoJSON(raw); err != nil {
+		return FeatureCollection{}, err
+	}
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return FeatureCollection{}, err
+	}
+	switch probe.Type {
+	case hauler.GeoFeatureCollection:
+		var fc FeatureCollection
+		err := decodeGeo(raw, &fc)
+		return fc, err
+	case hauler.GeoFeature:
+		var f Feature
+		err := decodeGeo(raw, &f)
+		return hauler.NewFeatureCollection(f), err
+	}
+	var g Geometry
+	if err := decodeGeo(raw, &g); err != nil {
+		return FeatureCollection{}, fmt.Errorf("%w: %v", hauler.ErrInvalidGeoJSON, err)
+	}
+	return hauler.NewFeatureCollection(Feature{Geometry: &g}), nil
+}
+
+// decodeGeo decodes raw into v keeping numbers as json.Number, so IDs and
+// properties such as int64 keys survive re-encoding without float64 rounding.
+func decodeGeo(raw []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
@@ -0,0 +1,110 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+func TestGeoJSONEncoder(t *testing.T) {
+	depot := Feature{
+		ID:         "depot-1",
+		Geometry:   &Geometry{Type: hauler.GeoPoint, Coordinates: []float64{13.4, 52.5}},
+		Properties: map[string]interface{}{"name": "Depot"},
+	}
+
+	t.Run("Response", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithContentType(ContentTypeGeoJSON)
+		if err := r.Data("depots", []Feature{depot}); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if ct := w.Header().Get(HeaderContentType); ct != ContentTypeGeoJSON {
+			t.Errorf("Expected GeoJSON content type, got %q", ct)
+		}
+		if err := hauler.ValidateGeoJSON(w.Body.Bytes()); err != nil {
+			t.Fatalf("Expected valid GeoJSON, got %v: %s", err, w.Body.String())
+		}
+		var doc struct {
+			FeatureCollection
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Type != hauler.GeoFeatureCollection || len(doc.Features) != 1 || doc.Features[0].ID != "depot-1" {
+			t.Errorf("Unexpected collection %s", w.Body.String())
+		}
+		if doc.Status != StatusSuccessful || doc.Message != "depots" {
+			t.Errorf("Expected envelope foreign members, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("Wrapping", func(t *testing.T) {
+		e := &GeoJSONEncoder{}
+		for name, v := range map[string]interface{}{
+			"feature":  depot,
+			"geometry": depot.Geometry,
+			"map":      map[string]interface{}{"type": "Point", "coordinates": []int{1, 2}},
+			"nil":      nil,
+		} {
+			out, err := e.Marshal(v)
+			if err != nil {
+				t.Errorf("%s: Marshal failed: %v", name, err)
+				continue
+			}
+			if !bytes.HasPrefix(out, []byte(`{"type":"FeatureCollection"`)) {
+				t.Errorf("%s: expected a FeatureCollection, got %s", name, out)
+			}
+		}
+	})
+
+	t.Run("Precision", func(t *testing.T) {
+		const big = int64(1<<53 + 1) // Not representable as float64
+		f := Feature{
+			ID:         big,
+			Geometry:   &Geometry{Type: hauler.GeoPoint, Coordinates: []float64{13.4, 52.5}},
+			Properties: map[string]interface{}{"osm_id": big},
+		}
+		for name, v := range map[string]interface{}{"feature": f, "map": map[string]interface{}{
+			"type": "Feature", "id": big, "geometry": f.Geometry, "properties": f.Properties,
+		}} {
+			out, err := (&GeoJSONEncoder{}).Marshal(v)
+			if err != nil {
+				t.Fatalf("%s: Marshal failed: %v", name, err)
+			}
+			if !bytes.Contains(out, []byte(`"id":9007199254740993`)) || !bytes.Contains(out, []byte(`"osm_id":9007199254740993`)) {
+				t.Errorf("%s: expected int64 values kept exactly, got %s", name, out)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		bad := Feature{Geometry: &Geometry{Type: hauler.GeoLineString, Coordinates: [][]float64{{0, 0}}}}
+		if _, err := (&GeoJSONEncoder{}).Marshal(bad); !errors.Is(err, hauler.ErrInvalidGeoJSON) {
+			t.Errorf("Expected ErrInvalidGeoJSON, got %v", err)
+		}
+		if _, err := (&GeoJSONEncoder{}).Marshal(map[string]int{"n": 1}); !errors.Is(err, hauler.ErrInvalidGeoJSON) {
+			t.Errorf("Expected ErrInvalidGeoJSON for non-GeoJSON data, got %v", err)
+		}
+	})
+
+	t.Run("HandlerRejectsInvalidBody", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"type":"Point","coordinates":[1]}`))
+		req.Header.Set(HeaderContentType, ContentTypeGeoJSON)
+		w := httptest.NewRecorder()
+		NewRenderer(settings).Handler(func(r *Renderer) error {
+			var fc FeatureCollection
+			return r.Request(r.HTTPRequest(), &fc)
+		})(w, req)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected 422 for invalid GeoJSON, got %d", w.Code)
+		}
+	})
+}
@@ -4,10 +4,18 @@ go 1.23.0
 
 require (
 	github.com/HugoSmits86/nativewebp v1.2.0
+	github.com/andybalholm/brotli v1.2.0
+	github.com/boombuler/barcode v1.1.0
+	github.com/fxamacker/cbor/v2 v2.9.0
+	github.com/klauspost/compress v1.18.0
 	github.com/vmihailenco/msgpack/v5 v5.4.1
+	golang.org/x/image v0.28.0
+	golang.org/x/text v0.28.0
+	google.golang.org/protobuf v1.36.6
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
-	golang.org/x/image v0.28.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 )
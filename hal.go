@@ -0,0 +1,137 @@
+package beam
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ContentTypeHAL is the content type for HAL (Hypertext Application Language) JSON.
+const ContentTypeHAL = "application/hal+json"
+
+// halEmbeddedData is the _embedded key for Response.Data when DataType is not set.
+const halEmbeddedData = "data"
+
+// HALLink is a HAL link object.
+// Method is an extension telling clients which HTTP method the link expects.
+type HALLink struct {
+	Href      string `json:"href"`
+	Title     string `json:"title,omitempty"`
+	Method    string `json:"method,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// HALEncoder encodes responses as HAL resources.
+// Actions become _links keyed by action name, with several actions of the
+// same name rendered as an array; Data moves to _embedded under its DataType
+// (or "data"); the remaining envelope fields stay top-level properties.
+type HALEncoder struct{}
+
+// Marshal encodes v as HAL JSON.
+// Values other than Response are encoded as plain JSON.
+// Returns the encoded bytes or an error if encoding fails.
+func (e *HALEncoder) Marshal(v interface{}) ([]byte, error) {
+	switch resp := v.(type) {
+	case Response:
+		return json.Marshal(halResource(resp))
+	case *Response:
+		return json.Marshal(halResource(*resp))
+	}
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes HAL JSON into the provided pointer.
+// A *Response receives its _links back as Actions and its embedded data as Data.
+// Returns an error if decoding fails.
+func (e *HALEncoder) Unmarshal(data []byte, v interface{}) error {
+	resp, ok := v.(*Response)
+	if !ok {
+		return json.Unmarshal(data, v)
+	}
+	var hal struct {
+		Links    map[string]json.RawMessage `json:"_links"`
+		Embedded map[string]interface{}     `json:"_embedded"`
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &hal); err != nil {
+		return err
+	}
+	for _, rel := range sortedKeys(hal.Links) {
+		var links []HALLink
+		if err := json.Unmarshal(hal.Links[rel], &links); err != nil {
+			var link HALLink
+			if err := json.Unmarshal(hal.Links[rel], &link); err != nil {
+				return err
+			}
+			links = []HALLink{link}
+		}
+		for _, l := range links {
+			resp.Actions = append(resp.Actions, Action{Name: rel, Href: l.Href, Method: l.Method, Description: l.Title})
+		}
+	}
+	key := resp.DataType
+	if key == Empty {
+		key = halEmbeddedData
+	}
+	resp.Data = hal.Embedded[key]
+	return nil
+}
+
+// ContentType returns the HAL content type.
+// Returns the constant "application/hal+json".
+func (e *HALEncoder) ContentType() string {
+	return ContentTypeHAL
+}
+
+// halDocument is a Response laid out as a HAL resource.
+type halDocument struct {
+	Response
+	Data     interface{}            `json:"data,omitempty"`    // Shadows Response.Data; always nil
+	Actions  []Action               `json:"actions,omitempty"` // Shadows Response.Actions; always nil
+	Links    map[string]interface{} `json:"_links,omitempty"`
+	Embedded map[string]interface{} `json:"_embedded,omitempty"`
+}
+
+// halResource converts resp into its HAL layout.
+func halResource(resp Response) halDocument {
+	doc := halDocument{Response: resp}
+	if len(resp.Actions) > 0 {
+		rels := make(map[string][]HALLink)
+		var order []string
+		for _, a := range resp.Actions {
+			if _, ok := rels[a.Name]; !ok {
+				order = append(order, a.Name)
+			}
+			rels[a.Name] = append(rels[a.Name], HALLink{
+				Href:      a.Href,
+				Title:     a.Description,
+				Method:    a.Method,
+				Templated: strings.Contains(a.Href, "{"),
+			})
+		}
+		doc.Links = make(map[string]interface{}, len(order))
+		for _, rel := range order {
+			if links := rels[rel]; len(links) == 1 {
+				doc.Links[rel] = links[0]
+			} else {
+				doc.Links[rel] = links
+			}
+		}
+	}
+	if resp.Data != nil {
+		key := resp.DataType
+		if key == Empty {
+			key = halEmbeddedData
+		}
+		doc.Embedded = map[string]interface{}{key: resp.Data}
+	}
+	return doc
+}
+
+// WithLink adds a hypermedia link as an Action named rel.
+// HAL responses render it under _links; other formats list it in actions.
+// Returns a new Renderer with the link added.
+func (r *Renderer) WithLink(rel, href, method string) *Renderer {
+	return r.WithAction(Action{Name: rel, Href: href, Method: method})
+}
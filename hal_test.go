@@ -0,0 +1,62 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHALEncoder(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := NewRenderer(settings).
+		WithContentType(ContentTypeHAL).
+		WithWriter(rec).
+		WithLink("self", "/orders/523", http.MethodGet).
+		WithLink("item", "/orders/523/items/1", http.MethodGet).
+		WithLink("item", "/orders/523/items/2", http.MethodGet).
+		WithAction(Action{Name: "find", Href: "/orders{?id}", Description: "Find an order"})
+	if err := r.Data("order", map[string]int{"total": 30}); err != nil {
+		t.Fatal(err)
+	}
+	if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeHAL {
+		t.Errorf("Expected %s, got %s", ContentTypeHAL, ct)
+	}
+
+	var doc struct {
+		Status   string                     `json:"status"`
+		Actions  []Action                   `json:"actions"`
+		Data     interface{}                `json:"data"`
+		Links    map[string]json.RawMessage `json:"_links"`
+		Embedded map[string]map[string]int  `json:"_embedded"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid HAL: %v\n%s", err, rec.Body)
+	}
+	if doc.Status != StatusSuccessful || doc.Actions != nil || doc.Data != nil {
+		t.Errorf("Expected envelope without actions and data, got %s", rec.Body)
+	}
+	var self HALLink
+	if err := json.Unmarshal(doc.Links["self"], &self); err != nil || self.Href != "/orders/523" || self.Method != http.MethodGet {
+		t.Errorf("Unexpected self link %s", doc.Links["self"])
+	}
+	var items []HALLink
+	if err := json.Unmarshal(doc.Links["item"], &items); err != nil || len(items) != 2 {
+		t.Errorf("Expected two item links, got %s", doc.Links["item"])
+	}
+	var find HALLink
+	if _ = json.Unmarshal(doc.Links["find"], &find); !find.Templated || find.Title != "Find an order" {
+		t.Errorf("Expected templated find link, got %s", doc.Links["find"])
+	}
+	if doc.Embedded["data"]["total"] != 30 {
+		t.Errorf("Expected data embedded, got %v", doc.Embedded)
+	}
+
+	var resp Response
+	if err := (&HALEncoder{}).Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Actions) != 4 || resp.Data == nil {
+		t.Errorf("Expected links and data restored, got %+v", resp)
+	}
+}
@@ -0,0 +1,38 @@
+package beam
+
+import (
+	"net/http"
+	"strings"
+)
+
+// NotFoundHandler returns a handler that answers with a standard 404 envelope.
+// Plugs into http.ServeMux, chi, or any router accepting an http.Handler.
+// Returns an http.HandlerFunc bound to a clone of r per request.
+func NotFoundHandler(r *Renderer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		nr := r.WithWriter(w).WithRequest(req).WithStatus(http.StatusNotFound)
+		_ = nr.Push(w, Response{
+			Status:  StatusError,
+			Title:   http.StatusText(http.StatusNotFound),
+			Message: "no resource found at " + req.URL.Path,
+		})
+	}
+}
+
+// MethodNotAllowedHandler returns a handler that answers with a standard 405 envelope.
+// Lists the allowed methods in the Allow header and the response meta.
+// Returns an http.HandlerFunc bound to a clone of r per request.
+func MethodNotAllowedHandler(r *Renderer, allowed ...string) http.HandlerFunc {
+	allow := strings.Join(allowed, ", ")
+	return func(w http.ResponseWriter, req *http.Request) {
+		nr := r.WithWriter(w).WithRequest(req).WithStatus(http.StatusMethodNotAllowed)
+		if allow != Empty {
+			nr = nr.WithHeader("Allow", allow).WithMeta("allowed", allowed)
+		}
+		_ = nr.Push(w, Response{
+			Status:  StatusError,
+			Title:   http.StatusText(http.StatusMethodNotAllowed),
+			Message: "method " + req.Method + " not allowed for " + req.URL.Path,
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStandardHandlers(t *testing.T) {
+	r := NewRenderer(settings)
+
+	t.Run("NotFound", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NotFoundHandler(r).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if resp.Status != StatusError || resp.Title != "Not Found" {
+			t.Errorf("Unexpected envelope: %+v", resp)
+		}
+	})
+
+	t.Run("MethodNotAllowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h := MethodNotAllowedHandler(r, http.MethodGet, http.MethodPost)
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/items", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405, got %d", rec.Code)
+		}
+		if allow := rec.Header().Get("Allow"); allow != "GET, POST" {
+			t.Errorf("Expected Allow header %q, got %q", "GET, POST", allow)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package hauler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+var (
+	ErrBodyTooLarge = errors.New("request body exceeds size limit")
+	ErrNoFilePart   = errors.New("multipart body has no file part")
+)
+
+// Limits configures Copy.
+type Limits struct {
+	MaxBytes int64            // Maximum bytes copied; zero means unlimited
+	Field    string           // Multipart form field holding the file; empty uses the first file part
+	Hash     func() hash.Hash // Checksum algorithm; nil uses SHA-256
+}
+
+// CopyResult describes a body streamed by Copy.
+type CopyResult struct {
+	Bytes       int64  // Number of bytes written to the destination
+	Filename    string // File name from the multipart part, if any
+	ContentType string // Content type of the copied data
+	Checksum    string // Hex-encoded checksum of the copied data
+}
+
+// Copy streams an upload straight into dst without intermediate buffering.
+// Octet-stream and other non-multipart bodies are copied as is; for
+// multipart/form-data the selected file part is copied and other parts skipped.
+// The checksum is computed while copying.
+// Returns ErrBodyTooLarge when the body exceeds MaxBytes; dst then holds the
+// first MaxBytes bytes, which the caller should discard.
+func Copy(req *http.Request, dst io.Writer, limits Limits) (CopyResult, error) {
+	if req == nil || req.Body == nil {
+		return CopyResult{}, ErrNilRequest
+	}
+	if limits.MaxBytes > 0 && req.ContentLength > limits.MaxBytes {
+		return CopyResult{}, fmt.Errorf("%w: %d bytes", ErrBodyTooLarge, req.ContentLength)
+	}
+
+	mt, err := ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		mt = MediaType{Type: ContentTypeBinary}
+	}
+	res := CopyResult{ContentType: mt.Type}
+	src := io.Reader(req.Body)
+	if mt.Type == ContentTypeMultipartForm {
+		part, err := filePart(mt, req.Body, limits.Field)
+		if err != nil {
+			return res, err
+		}
+		defer part.Close()
+		res.Filename = part.FileName()
+		res.ContentType = part.Header.Get("Content-Type")
+		src = part
+	}
+
+	newHash := limits.Hash
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	h := newHash()
+	limited := src
+	if limits.MaxBytes > 0 {
+		limited = io.LimitReader(src, limits.MaxBytes)
+	}
+	res.Bytes, err = io.Copy(io.MultiWriter(dst, h), limited)
+	if err != nil {
+		return res, fmt.Errorf("failed to copy request body: %w", err)
+	}
+	if limits.MaxBytes > 0 && res.Bytes == limits.MaxBytes {
+		// Probe one byte past the limit, without writing it, to tell an
+		// exact fit from an overflow.
+		var probe [1]byte
+		if n, _ := io.ReadFull(src, probe[:]); n > 0 {
+			return res, ErrBodyTooLarge
+		}
+	}
+	res.Checksum = hex.EncodeToString(h.Sum(nil))
+	return res, nil
+}
+
+// filePart advances a multipart body to the requested file part.
+// Returns ErrNoFilePart if no matching part exists.
+func filePart(mt MediaType, body io.Reader, field string) (*multipart.Part, error) {
+	boundary := mt.Params["boundary"]
+	if boundary == "" {
+		return nil, ErrMissingBoundary
+	}
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoFilePart
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart data: %w", err)
+		}
+		if part.FileName() != "" && (field == "" || part.FormName() == field) {
+			return part, nil
+		}
+		_ = part.Close()
+	}
+}
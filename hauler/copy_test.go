@@ -0,0 +1,80 @@
+package hauler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	payload := []byte("binary payload")
+	sum := sha256.Sum256(payload)
+	checksum := hex.EncodeToString(sum[:])
+
+	t.Run("octet-stream", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", ContentTypeBinary)
+		var dst bytes.Buffer
+		res, err := Copy(req, &dst, Limits{MaxBytes: int64(len(payload))})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(dst.Bytes(), payload) || res.Bytes != int64(len(payload)) {
+			t.Errorf("Expected %q, got %q (%d bytes)", payload, dst.Bytes(), res.Bytes)
+		}
+		if res.Checksum != checksum {
+			t.Errorf("Expected checksum %s, got %s", checksum, res.Checksum)
+		}
+	})
+
+	t.Run("multipart field", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		_ = mw.WriteField("title", "report")
+		other, _ := mw.CreateFormFile("thumb", "t.png")
+		_, _ = other.Write([]byte("thumb"))
+		fw, _ := mw.CreateFormFile("file", "report.bin")
+		_, _ = fw.Write(payload)
+		_ = mw.Close()
+
+		req := httptest.NewRequest("POST", "/", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		var dst bytes.Buffer
+		res, err := Copy(req, &dst, Limits{Field: "file"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(dst.Bytes(), payload) || res.Filename != "report.bin" || res.Checksum != checksum {
+			t.Errorf("Expected report.bin payload, got %q %+v", dst.Bytes(), res)
+		}
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/", strings.NewReader("0123456789"))
+		req.ContentLength = -1
+		var dst bytes.Buffer
+		if _, err := Copy(req, &dst, Limits{MaxBytes: 4}); !errors.Is(err, ErrBodyTooLarge) {
+			t.Errorf("Expected ErrBodyTooLarge, got %v", err)
+		}
+		if dst.String() != "0123" {
+			t.Errorf("Expected only MaxBytes written, got %q", dst.String())
+		}
+	})
+
+	t.Run("no file part", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		_ = mw.WriteField("title", "report")
+		_ = mw.Close()
+		req := httptest.NewRequest("POST", "/", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		if _, err := Copy(req, &bytes.Buffer{}, Limits{}); !errors.Is(err, ErrNoFilePart) {
+			t.Errorf("Expected ErrNoFilePart, got %v", err)
+		}
+	})
+}
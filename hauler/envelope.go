@@ -0,0 +1,180 @@
+package hauler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Response statuses that mark a failed envelope; they mirror beam's StatusError and StatusFatal.
+const (
+	statusError = "-error"
+	statusFatal = "*fatal"
+)
+
+// ErrEnvelopeStatus is returned by Envelope.Err for error envelopes that carry no error details.
+var ErrEnvelopeStatus = errors.New("envelope reports failure")
+
+// Envelope is a beam Response envelope with its Data decoded into T.
+// Lets services that receive beam-shaped payloads from other services
+// decode them with a typed payload and Go errors.
+// Info, Meta, and Actions are decoded from JSON and MsgPack only.
+type Envelope[T any] struct {
+	Status   string                   `json:"status" xml:"status" msgpack:"status"`
+	Title    string                   `json:"title,omitempty" xml:"title,omitempty" msgpack:"title"`
+	Message  string                   `json:"message,omitempty" xml:"message,omitempty" msgpack:"message"`
+	Tags     []string                 `json:"tags,omitempty" xml:"tags,omitempty" msgpack:"tags"`
+	Info     interface{}              `json:"info,omitempty" xml:"-" msgpack:"info"`
+	Data     T                        `json:"data,omitempty" xml:"data,omitempty" msgpack:"data"`
+	InfoType string                   `json:"info_type,omitempty" xml:"info_type,omitempty" msgpack:"info_type,omitempty"`
+	DataType string                   `json:"data_type,omitempty" xml:"data_type,omitempty" msgpack:"data_type,omitempty"`
+	Meta     map[string]interface{}   `json:"meta,omitempty" xml:"-" msgpack:"meta"`
+	Errors   ErrorList                `json:"errors,omitempty" xml:"errors,omitempty" msgpack:"errors"`
+	Actions  []map[string]interface{} `json:"actions,omitempty" xml:"-" msgpack:"actions"`
+}
+
+// ReadEnvelope parses a beam Response envelope from the request body with the default reader.
+// Takes the request; the body's Content-Type selects the parser.
+// Returns the decoded envelope or an error if the request is nil or parsing fails.
+func ReadEnvelope[T any](req *http.Request) (*Envelope[T], error) {
+	env := new(Envelope[T])
+	if err := Read(req, env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// Failed reports whether the envelope's status is an error or fatal status.
+func (e *Envelope[T]) Failed() bool {
+	return e.Status == statusError || e.Status == statusFatal
+}
+
+// Err returns the envelope's errors joined into one error.
+// Error envelopes without error details yield ErrEnvelopeStatus with the message.
+// Returns nil for successful envelopes without errors.
+func (e *Envelope[T]) Err() error {
+	if len(e.Errors) > 0 {
+		return errors.Join(e.Errors...)
+	}
+	if e.Failed() {
+		if e.Message != "" {
+			return fmt.Errorf("%w: %s", ErrEnvelopeStatus, e.Message)
+		}
+		return fmt.Errorf("%w: %s", ErrEnvelopeStatus, e.Status)
+	}
+	return nil
+}
+
+// CodedError is an envelope error that carried a machine-readable code.
+// Its ErrorCode method matches beam.ErrorCoder, so the code survives re-rendering.
+type CodedError struct {
+	Code    string
+	Message string
+}
+
+// Error returns the error message.
+func (e *CodedError) Error() string { return e.Message }
+
+// ErrorCode returns the error code.
+func (e *CodedError) ErrorCode() string { return e.Code }
+
+// ErrorList holds envelope errors decoded from plain messages or {code, message} items.
+type ErrorList []error
+
+// errorItem is the wire form of a coded envelope error.
+type errorItem struct {
+	Code    string `json:"code" xml:"code,attr" msgpack:"code"`
+	Message string `json:"message" xml:",chardata" msgpack:"message"`
+}
+
+// err converts the item to a Go error, keeping the code when present.
+func (it errorItem) err() error {
+	if it.Code == "" {
+		return errors.New(it.Message)
+	}
+	return &CodedError{Code: it.Code, Message: it.Message}
+}
+
+// UnmarshalJSON decodes an array of strings or {code, message} objects.
+func (el *ErrorList) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*el = make(ErrorList, len(raw))
+	for i, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			(*el)[i] = errors.New(s)
+			continue
+		}
+		var it errorItem
+		if err := json.Unmarshal(r, &it); err != nil {
+			return err
+		}
+		(*el)[i] = it.err()
+	}
+	return nil
+}
+
+// UnmarshalXML decodes <error code="...">message</error> elements.
+func (el *ErrorList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var aux struct {
+		Items []errorItem `xml:"error"`
+	}
+	if err := d.DecodeElement(&aux, &start); err != nil {
+		return err
+	}
+	for _, it := range aux.Items {
+		*el = append(*el, it.err())
+	}
+	return nil
+}
+
+// DecodeMsgpack decodes an array of strings or {code, message} maps.
+// Implements msgpack.CustomDecoder.
+func (el *ErrorList) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var raw []interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*el = make(ErrorList, len(raw))
+	for i, r := range raw {
+		switch v := r.(type) {
+		case string:
+			(*el)[i] = errors.New(v)
+		case map[string]interface{}:
+			code, _ := v["code"].(string)
+			msg, _ := v["message"].(string)
+			(*el)[i] = errorItem{Code: code, Message: msg}.err()
+		default:
+			(*el)[i] = fmt.Errorf("%v", v)
+		}
+	}
+	return nil
+}
+
+// UnmarshalCBOR decodes an array of strings or {code, message} maps.
+// Implements cbor.Unmarshaler.
+func (el *ErrorList) UnmarshalCBOR(data []byte) error {
+	var raw []cbor.RawMessage
+	if err := cbor.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*el = make(ErrorList, len(raw))
+	for i, r := range raw {
+		var it errorItem
+		if err := cbor.Unmarshal(r, &it.Message); err != nil {
+			if err := cbor.Unmarshal(r, &it); err != nil {
+				return err
+			}
+		}
+		(*el)[i] = it.err()
+	}
+	return nil
+}
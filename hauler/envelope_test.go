@@ -0,0 +1,66 @@
+package hauler_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olekukonko/beam"
+	"github.com/olekukonko/beam/hauler"
+)
+
+type user struct {
+	Name string `json:"name" xml:"name" msgpack:"name"`
+}
+
+type codeErr struct{}
+
+func (codeErr) Error() string     { return "not found" }
+func (codeErr) ErrorCode() string { return "E404" }
+
+func TestReadEnvelope(t *testing.T) {
+	for _, ct := range []string{beam.ContentTypeJSON, beam.ContentTypeXML, beam.ContentTypeMsgPack, beam.ContentTypeCBOR} {
+		t.Run(ct, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r := beam.NewRenderer(beam.Setting{Name: "test"}).WithContentType(ct)
+			err := r.Push(rec, beam.Response{
+				Status:  beam.StatusError,
+				Message: "partial",
+				Data:    user{Name: "alice"},
+				Errors:  beam.ErrorList{errors.New("boom"), codeErr{}},
+			})
+			if err != nil {
+				t.Fatalf("Push failed: %v", err)
+			}
+
+			req := httptest.NewRequest("POST", "/", rec.Body)
+			req.Header.Set("Content-Type", ct)
+			env, err := hauler.ReadEnvelope[user](req)
+			if err != nil {
+				t.Fatalf("ReadEnvelope failed: %v", err)
+			}
+			if env.Data.Name != "alice" || env.Message != "partial" || !env.Failed() {
+				t.Errorf("Expected decoded envelope, got %+v", env)
+			}
+			if len(env.Errors) != 2 || env.Errors[0].Error() != "boom" {
+				t.Fatalf("Expected 2 errors, got %v", env.Errors)
+			}
+			// beam's JSON errors are plain strings; the other formats keep codes.
+			var coded *hauler.CodedError
+			if ct != beam.ContentTypeJSON && (!errors.As(env.Err(), &coded) || coded.ErrorCode() != "E404") {
+				t.Errorf("Expected coded error E404, got %v", env.Err())
+			}
+		})
+	}
+
+	t.Run("StatusOnly", func(t *testing.T) {
+		env := &hauler.Envelope[user]{Status: beam.StatusFatal, Message: "down"}
+		if err := env.Err(); !errors.Is(err, hauler.ErrEnvelopeStatus) {
+			t.Errorf("Expected ErrEnvelopeStatus, got %v", err)
+		}
+		env.Status = beam.StatusSuccessful
+		if err := env.Err(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package hauler
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+)
+
+// Fuzz is a fuzzing entry point for the default parsers.
+// Parses data as a request body of the given content type into the generic
+// targets each parser supports.
+// Returns 1 when data parsed successfully, 0 when it was rejected, and -1 for
+// unsupported content types, following the go-fuzz convention.
+func Fuzz(contentType string, data []byte) int {
+	h := New()
+	if _, ok := h.registry[contentType]; !ok {
+		return -1
+	}
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	if err != nil {
+		return -1
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	var target interface{}
+	switch contentType {
+	case ContentTypeFormURLEncoded:
+		target = &url.Values{}
+	case ContentTypeText:
+		target = new(string)
+	default:
+		target = new(interface{})
+	}
+	if err := h.Read(req, target); err != nil {
+		return 0
+	}
+	return 1
+}
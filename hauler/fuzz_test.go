@@ -0,0 +1,22 @@
+package hauler
+
+import "testing"
+
+func FuzzRead(f *testing.F) {
+	f.Add([]byte(`{"name":"beam"}`))
+	f.Add([]byte("<item><name>beam</name></item>"))
+	f.Add([]byte("a=1&b=2&a=3"))
+	f.Add([]byte{0x81, 0xa1, 0x61, 0x01})
+	f.Add([]byte("\xdd\xdd\xdd\xdd\xdd\xdd\xdd\xdd0\xc60")) // array32 declaring billions of items
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, ct := range []string{
+			ContentTypeJSON,
+			ContentTypeXML,
+			ContentTypeMsgPack,
+			ContentTypeFormURLEncoded,
+			ContentTypeText,
+		} {
+			Fuzz(ct, data)
+		}
+	})
+}
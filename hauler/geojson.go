@@ -0,0 +1,250 @@
+package hauler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ContentTypeGeoJSON is the RFC 7946 GeoJSON content type.
+const ContentTypeGeoJSON = "application/geo+json"
+
+// ErrInvalidGeoJSON is returned for documents that are not valid RFC 7946 GeoJSON.
+var ErrInvalidGeoJSON = errors.New("invalid geojson")
+
+// GeoJSON object types.
+const (
+	GeoPoint              = "Point"
+	GeoMultiPoint         = "MultiPoint"
+	GeoLineString         = "LineString"
+	GeoMultiLineString    = "MultiLineString"
+	GeoPolygon            = "Polygon"
+	GeoMultiPolygon       = "MultiPolygon"
+	GeoGeometryCollection = "GeometryCollection"
+	GeoFeature            = "Feature"
+	GeoFeatureCollection  = "FeatureCollection"
+)
+
+// Geometry is a GeoJSON geometry object.
+// Coordinates nest positions ([longitude, latitude] or with altitude) to the
+// depth required by Type, e.g. []float64 for a Point or [][][]float64 for a Polygon.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates,omitempty"`
+	Geometries  []Geometry  `json:"geometries,omitempty"` // GeometryCollection members
+	BBox        []float64   `json:"bbox,omitempty"`
+}
+
+// Feature is a GeoJSON feature: a geometry with properties.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+	BBox       []float64              `json:"bbox,omitempty"`
+}
+
+// FeatureCollection is a GeoJSON feature collection.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+	BBox     []float64 `json:"bbox,omitempty"`
+}
+
+// NewFeatureCollection returns a FeatureCollection of features.
+// Features without a Type are typed as "Feature".
+func NewFeatureCollection(features ...Feature) FeatureCollection {
+	fc := FeatureCollection{Type: GeoFeatureCollection, Features: make([]Feature, len(features))}
+	for i, f := range features {
+		if f.Type == "" {
+			f.Type = GeoFeature
+		}
+		fc.Features[i] = f
+	}
+	return fc
+}
+
+// ValidateGeoJSON checks that data is a valid RFC 7946 GeoJSON object:
+// known types, required members, and coordinates of the right shape.
+// Returns ErrInvalidGeoJSON wrapping the first problem found.
+func ValidateGeoJSON(data []byte) error {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+	if err := validateGeoObject(doc, "$"); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+	return nil
+}
+
+// validateGeoObject validates any GeoJSON object at path.
+func validateGeoObject(v interface{}, path string) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected an object", path)
+	}
+	typ, _ := obj["type"].(string)
+	switch typ {
+	case GeoFeatureCollection:
+		features, ok := obj["features"].([]interface{})
+		if !ok {
+			return fmt.Errorf("%s.features: expected an array", path)
+		}
+		for i, f := range features {
+			p := fmt.Sprintf("%s.features[%d]", path, i)
+			if fo, ok := f.(map[string]interface{}); !ok || fo["type"] != GeoFeature {
+				return fmt.Errorf("%s: expected a Feature", p)
+			}
+			if err := validateGeoObject(f, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	case GeoFeature:
+		geometry, ok := obj["geometry"]
+		if !ok {
+			return fmt.Errorf("%s.geometry: missing", path)
+		}
+		if geometry != nil {
+			if err := validateGeometry(geometry, path+".geometry"); err != nil {
+				return err
+			}
+		}
+		properties, ok := obj["properties"]
+		if !ok {
+			return fmt.Errorf("%s.properties: missing", path)
+		}
+		if _, isObj := properties.(map[string]interface{}); properties != nil && !isObj {
+			return fmt.Errorf("%s.properties: expected an object or null", path)
+		}
+		return nil
+	}
+	return validateGeometry(obj, path)
+}
+
+// validateGeometry validates a geometry object at path.
+func validateGeometry(v interface{}, path string) error {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected a geometry object", path)
+	}
+	typ, _ := obj["type"].(string)
+	if typ == GeoGeometryCollection {
+		members, ok := obj["geometries"].([]interface{})
+		if !ok {
+			return fmt.Errorf("%s.geometries: expected an array", path)
+		}
+		for i, g := range members {
+			if err := validateGeometry(g, fmt.Sprintf("%s.geometries[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	check, ok := geoCoordinateChecks[typ]
+	if !ok {
+		return fmt.Errorf("%s.type: unknown type %q", path, typ)
+	}
+	coords, ok := obj["coordinates"]
+	if !ok {
+		return fmt.Errorf("%s.coordinates: missing", path)
+	}
+	if err := check(coords); err != nil {
+		return fmt.Errorf("%s.coordinates: %v", path, err)
+	}
+	return nil
+}
+
+// geoCoordinateChecks validates the coordinates of each geometry type.
+var geoCoordinateChecks = map[string]func(interface{}) error{
+	GeoPoint:           geoPosition,
+	GeoMultiPoint:      geoArrayOf(0, geoPosition),
+	GeoLineString:      geoLine,
+	GeoMultiLineString: geoArrayOf(0, geoLine),
+	GeoPolygon:         geoPolygon,
+	GeoMultiPolygon:    geoArrayOf(0, geoPolygon),
+}
+
+// geoArrayOf returns a check for arrays of at least minLen items passing item.
+func geoArrayOf(minLen int, item func(interface{}) error) func(interface{}) error {
+	return func(v interface{}) error {
+		items, ok := v.([]interface{})
+		if !ok {
+			return errors.New("expected an array")
+		}
+		if len(items) < minLen {
+			return fmt.Errorf("expected at least %d items, got %d", minLen, len(items))
+		}
+		for _, it := range items {
+			if err := item(it); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// geoPosition checks a position: two or three numbers.
+func geoPosition(v interface{}) error {
+	items, ok := v.([]interface{})
+	if !ok || len(items) < 2 || len(items) > 3 {
+		return errors.New("expected a position of 2 or 3 numbers")
+	}
+	for _, it := range items {
+		if _, ok := it.(json.Number); !ok {
+			return errors.New("expected a position of 2 or 3 numbers")
+		}
+	}
+	return nil
+}
+
+// geoLine checks a LineString: two or more positions.
+var geoLine = geoArrayOf(2, geoPosition)
+
+// geoRing checks a closed linear ring: four or more positions, first equal to last.
+func geoRing(v interface{}) error {
+	if err := geoArrayOf(4, geoPosition)(v); err != nil {
+		return err
+	}
+	ring := v.([]interface{})
+	if !slices.Equal(ring[0].([]interface{}), ring[len(ring)-1].([]interface{})) {
+		return errors.New("linear ring is not closed")
+	}
+	return nil
+}
+
+// geoPolygon checks a Polygon: an array of linear rings.
+var geoPolygon = geoArrayOf(0, geoRing)
+
+// geoJSONParser handles GeoJSON request bodies.
+// Implements BodyParser; bodies are validated against RFC 7946 before
+// decoding, so targets such as *FeatureCollection receive well-formed data.
+// Supports "application/geo+json".
+type geoJSONParser struct{}
+
+func (p *geoJSONParser) CanParse(contentType string) bool {
+	return contentType == ContentTypeGeoJSON
+}
+
+func (p *geoJSONParser) Parse(body io.Reader, v interface{}) error {
+	if v == nil {
+		return ErrInvalidPointer
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return io.EOF
+	}
+	if err := ValidateGeoJSON(data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,63 @@
+package hauler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestValidateGeoJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr bool
+	}{
+		{"point", `{"type":"Point","coordinates":[13.4,52.5]}`, false},
+		{"point with altitude", `{"type":"Point","coordinates":[13.4,52.5,34]}`, false},
+		{"polygon", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,0]]]}`, false},
+		{"collection", `{"type":"FeatureCollection","features":[
+			{"type":"Feature","geometry":{"type":"LineString","coordinates":[[0,0],[1,1]]},"properties":{"name":"a"}},
+			{"type":"Feature","geometry":null,"properties":null}]}`, false},
+		{"geometry collection", `{"type":"GeometryCollection","geometries":[{"type":"MultiPoint","coordinates":[[0,0]]}]}`, false},
+		{"unknown type", `{"type":"Circle","coordinates":[0,0]}`, true},
+		{"short position", `{"type":"Point","coordinates":[13.4]}`, true},
+		{"string coordinate", `{"type":"Point","coordinates":["13.4","52.5"]}`, true},
+		{"short line", `{"type":"LineString","coordinates":[[0,0]]}`, true},
+		{"open ring", `{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1]]]}`, true},
+		{"feature without properties", `{"type":"Feature","geometry":null}`, true},
+		{"collection of geometries", `{"type":"FeatureCollection","features":[{"type":"Point","coordinates":[0,0]}]}`, true},
+		{"not json", `{"type":`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGeoJSON([]byte(tt.doc))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateGeoJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidGeoJSON) {
+				t.Errorf("Expected ErrInvalidGeoJSON, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRead_GeoJSON(t *testing.T) {
+	body := `{"type":"FeatureCollection","features":[{"type":"Feature","id":"depot-1",
+		"geometry":{"type":"Point","coordinates":[13.4,52.5]},"properties":{"name":"Depot"}}]}`
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", ContentTypeGeoJSON)
+	var fc FeatureCollection
+	if err := Read(req, &fc); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(fc.Features) != 1 || fc.Features[0].Properties["name"] != "Depot" || fc.Features[0].Geometry.Type != GeoPoint {
+		t.Errorf("Unexpected collection %+v", fc)
+	}
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader(`{"type":"Point","coordinates":[1]}`))
+	req.Header.Set("Content-Type", ContentTypeGeoJSON+"; charset=utf-8")
+	if err := Read(req, &fc); !errors.Is(err, ErrInvalidGeoJSON) {
+		t.Errorf("Expected ErrInvalidGeoJSON, got %v", err)
+	}
+}
@@ -1,18 +1,26 @@
 package hauler
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
 )
 
 // ContentType constants matching Beam's encoder types
@@ -24,12 +32,15 @@ const (
 	ContentTypeMultipartForm  = "multipart/form-data"
 	ContentTypeText           = "text/plain"
 	ContentTypeBinary         = "application/octet-stream"
+	ContentTypeProtobuf       = "application/x-protobuf"
+	ContentTypeCBOR           = "application/cbor"
 )
 
 var (
 	ErrUnsupportedContentType = errors.New("unsupported content type")
 	ErrNilRequest             = errors.New("request cannot be nil")
 	ErrInvalidPointer         = errors.New("must provide a non-nil pointer")
+	ErrUnsupportedCharset     = errors.New("unsupported charset")
 )
 
 // BodyParser defines the interface for content-type specific parsers.
@@ -40,17 +51,130 @@ type BodyParser interface {
 	Parse(body io.Reader, v interface{}) error
 }
 
+// MediaType is a parsed Content-Type value.
+// Type is the lowercased media type without parameters; Params holds
+// parameters such as the multipart boundary, charset, or JSON profile.
+type MediaType struct {
+	Type   string
+	Params map[string]string
+}
+
+// MediaParser is an optional BodyParser extension for parsers that need the
+// Content-Type parameters. Hauler calls ParseMedia instead of Parse when a
+// parser implements it.
+type MediaParser interface {
+	BodyParser
+	ParseMedia(body io.Reader, mt MediaType, v interface{}) error
+}
+
+// ParseMediaType parses a Content-Type header value with mime.ParseMediaType.
+// Returns ErrUnsupportedContentType wrapping the cause if the value is malformed.
+func ParseMediaType(contentType string) (MediaType, error) {
+	typ, params, err := mime.ParseMediaType(contentType)
+	if err != nil && !errors.Is(err, mime.ErrInvalidMediaParameter) {
+		return MediaType{}, fmt.Errorf("%w: %q: %v", ErrUnsupportedContentType, contentType, err)
+	}
+	return MediaType{Type: typ, Params: params}, nil
+}
+
+// parse runs parser on body, passing the media type to MediaParser implementations.
+// Multipart forms that keep uploaded files are attached to req so net/http
+// removes their temporary files when the request completes.
+func parse(req *http.Request, parser BodyParser, body io.Reader, mt MediaType, v interface{}) error {
+	if mp, ok := parser.(*multipartParser); ok && req != nil {
+		return mp.parseRequest(req, body, mt, v)
+	}
+	if mp, ok := parser.(MediaParser); ok {
+		return mp.ParseMedia(body, mt, v)
+	}
+	return parser.Parse(body, v)
+}
+
 // Hauler manages HTTP request body parsing.
 // Stores a registry of parsers and handles content-type based parsing.
 // Thread-safe using a read-write mutex for concurrent access.
 type Hauler struct {
-	parsers  []BodyParser
-	registry map[string]BodyParser
-	mu       sync.RWMutex
+	parsers    []BodyParser
+	registry   map[string]BodyParser
+	buffering  Buffering
+	limit      int64            // Capture cap for BufferTee
+	maxBody    int64            // Default body size limit; zero means unlimited
+	maxBodyFor map[string]int64 // Body size limits by media type
+	emptyBody  EmptyBody
+	metrics    Collector
+	slow       time.Duration
+	onSlow     func(ParseStats)
+	validator  func(interface{}) error // Hook run after decoding; see WithValidator
+	mu         sync.RWMutex
+}
+
+// Buffering controls how Read treats the request body.
+type Buffering int
+
+// Buffering modes for Hauler.WithBuffering.
+const (
+	BufferAll  Buffering = iota // Read the whole body into memory and restore req.Body (default)
+	BufferNone                  // Decode straight from req.Body; the body is consumed
+	BufferTee                   // Decode from req.Body while capturing up to a limit for replay
+)
+
+// EmptyBody controls how Read treats a request without body bytes.
+type EmptyBody int
+
+// EmptyBody modes for Hauler.WithEmptyBody.
+const (
+	EmptyParse  EmptyBody = iota // Let the parser decide; decoders that need input fail with ErrEmptyBody (default)
+	EmptyReject                  // Fail with ErrEmptyBody without parsing
+	EmptyZero                    // Reset the target to its zero value and succeed
+)
+
+// ErrEmptyBody is returned when a request has no body and the parser needs one.
+// Distinguishes a missing payload from a malformed one.
+var ErrEmptyBody = errors.New("request body is empty")
+
+// WithEmptyBody returns a copy of the Hauler using the given empty-body semantics.
+func (r *Hauler) WithEmptyBody(mode EmptyBody) *Hauler {
+	nr := r.clone()
+	nr.emptyBody = mode
+	return nr
+}
+
+// ErrBodyNotReplayable is returned when reading a body that was consumed by a
+// streaming Read or exceeded the BufferTee capture limit.
+var ErrBodyNotReplayable = errors.New("request body not replayable")
+
+// WithBuffering returns a copy of the Hauler using the given body buffering mode.
+// limit caps the bytes captured for replay in BufferTee mode and is ignored otherwise.
+// Large uploads should use BufferNone or BufferTee so parsers decode without
+// holding the whole body in memory.
+func (r *Hauler) WithBuffering(mode Buffering, limit int64) *Hauler {
+	nr := r.clone()
+	nr.buffering = mode
+	nr.limit = limit
+	return nr
+}
+
+// clone copies the Hauler so options can be changed without affecting r.
+func (r *Hauler) clone() *Hauler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &Hauler{
+		parsers:    slices.Clone(r.parsers),
+		registry:   maps.Clone(r.registry),
+		buffering:  r.buffering,
+		limit:      r.limit,
+		maxBody:    r.maxBody,
+		maxBodyFor: maps.Clone(r.maxBodyFor),
+		emptyBody:  r.emptyBody,
+		metrics:    r.metrics,
+		slow:       r.slow,
+		onSlow:     r.onSlow,
+		validator:  r.validator,
+	}
 }
 
 // New creates a new Hauler with default parsers.
-// Initializes a Hauler with JSON, XML, MsgPack, form, and text parsers.
+// Initializes a Hauler with JSON, XML, MsgPack, form, multipart, text, protobuf, CBOR, and GeoJSON parsers.
 // Returns a pointer to the initialized Hauler.
 func New() *Hauler {
 	r := &Hauler{
@@ -62,7 +186,11 @@ func New() *Hauler {
 	r.Register(&xmlParser{})
 	r.Register(&msgpackParser{})
 	r.Register(&formParser{})
+	r.Register(&multipartParser{maxMemory: MaxMultipartMemory})
 	r.Register(&textParser{})
+	r.Register(&protobufParser{})
+	r.Register(&cborParser{})
+	r.Register(&geoJSONParser{})
 
 	return r
 }
@@ -74,13 +202,7 @@ func (r *Hauler) Register(p BodyParser) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	for _, ct := range []string{
-		ContentTypeJSON,
-		ContentTypeXML,
-		ContentTypeMsgPack,
-		ContentTypeFormURLEncoded,
-		ContentTypeText,
-	} {
+	for _, ct := range canonicalTypes {
 		if p.CanParse(ct) {
 			r.registry[ct] = p
 		}
@@ -90,34 +212,104 @@ func (r *Hauler) Register(p BodyParser) {
 }
 
 // Read reads and parses the request body based on Content-Type.
-// Takes an HTTP request and a target interface to parse the body into.
-// Returns an error if the request is nil, content type is unsupported, or parsing fails.
+// Takes an HTTP request and a target interface to parse the body into, then
+// validates it if it implements Validator or a WithValidator hook is set.
+// Returns an error if the request is nil, content type is unsupported, or parsing or validation fails.
 func (r *Hauler) Read(req *http.Request, v interface{}) error {
 	if req == nil || req.Body == nil {
 		return ErrNilRequest
 	}
 
-	contentType := req.Header.Get("Content-Type")
-	// Remove charset if present
-	if idx := strings.Index(contentType, ";"); idx > 0 {
-		contentType = contentType[:idx]
+	return r.read(req, req.Header.Get("Content-Type"), v)
+}
+
+// ReadAs parses the request body as contentType, ignoring the Content-Type header.
+// Lets endpoints force a parser for mislabeled bodies, such as legacy clients
+// sending JSON as text/plain.
+// Returns an error if the request is nil, contentType is unsupported, or parsing fails.
+func (r *Hauler) ReadAs(req *http.Request, contentType string, v interface{}) error {
+	if req == nil || req.Body == nil {
+		return ErrNilRequest
 	}
+	return r.read(req, contentType, v)
+}
 
-	r.mu.RLock()
-	parser, ok := r.registry[contentType]
-	r.mu.RUnlock()
+// read parses the request body with the parser selected by contentType.
+func (r *Hauler) read(req *http.Request, contentType string, v interface{}) error {
+	mt, err := ParseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	parser, err := r.parserFor(mt.Type)
+	if err != nil {
+		r.count(MetricParseErrors, 1, labelContentType, contentTypeOther, labelReason, reasonUnsupported)
+		return err
+	}
+	if isEmpty(req) {
+		err = r.empty(req, parser, mt, v)
+	} else if r.metrics == nil && r.onSlow == nil {
+		err = r.decode(req, parser, mt, v)
+	} else {
+		err = r.instrument(req, parser, mt, v)
+	}
+	if err != nil {
+		return err
+	}
+	return r.validate(v)
+}
 
-	if !ok {
-		// Try to find a parser that can handle this content type
-		for _, p := range r.parsers {
-			if p.CanParse(contentType) {
-				parser = p
-				break
-			}
+// empty applies the configured empty-body semantics.
+func (r *Hauler) empty(req *http.Request, parser BodyParser, mt MediaType, v interface{}) error {
+	switch r.emptyBody {
+	case EmptyReject:
+		return ErrEmptyBody
+	case EmptyZero:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return ErrInvalidPointer
 		}
-		if parser == nil {
-			return fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+		return nil
+	}
+	if err := r.decode(req, parser, mt, v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrEmptyBody
 		}
+		return err
+	}
+	return nil
+}
+
+// isEmpty reports whether the request body has no bytes.
+// Peeks one byte and restores it, so the body stays fully readable.
+func isEmpty(req *http.Request) bool {
+	if req.ContentLength > 0 {
+		return false
+	}
+	br := bufio.NewReaderSize(req.Body, 16)
+	_, err := br.Peek(1)
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, req.Body}
+	return errors.Is(err, io.EOF)
+}
+
+// decode parses the body with parser according to the buffering mode,
+// enforcing the body size limit for mt.
+func (r *Hauler) decode(req *http.Request, parser BodyParser, mt MediaType, v interface{}) error {
+	return r.enforceLimit(req, mt, func() error { return r.buffer(req, parser, mt, v) })
+}
+
+// buffer parses the body with parser according to the buffering mode.
+func (r *Hauler) buffer(req *http.Request, parser BodyParser, mt MediaType, v interface{}) error {
+	switch r.buffering {
+	case BufferNone:
+		body := req.Body
+		req.Body = io.NopCloser(errReader{ErrBodyNotReplayable})
+		return parse(req, parser, body, mt, v)
+	case BufferTee:
+		return r.tee(req, parser, mt, v)
 	}
 
 	// For idempotency, we'll read the body once and then re-create it
@@ -128,7 +320,67 @@ func (r *Hauler) Read(req *http.Request, v interface{}) error {
 	}
 	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
-	return parser.Parse(bytes.NewReader(bodyBytes), v)
+	return parse(req, parser, bytes.NewReader(bodyBytes), mt, v)
+}
+
+// tee parses the body while capturing up to r.limit bytes.
+// When the capture fits, req.Body is restored to the captured bytes followed
+// by whatever the parser left unread; otherwise it reports ErrBodyNotReplayable.
+func (r *Hauler) tee(req *http.Request, parser BodyParser, mt MediaType, v interface{}) error {
+	body := req.Body
+	capture := &cappedBuffer{limit: r.limit}
+	err := parse(req, parser, io.TeeReader(body, capture), mt, v)
+	if capture.overflow {
+		req.Body = io.NopCloser(errReader{ErrBodyNotReplayable})
+	} else {
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(capture.Bytes()), body), body}
+	}
+	return err
+}
+
+// cappedBuffer collects writes until limit bytes, then drops the capture.
+// Writes always succeed so the TeeReader feeding the parser is never interrupted.
+type cappedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.overflow {
+		return len(p), nil
+	}
+	if int64(b.Len()+len(p)) > b.limit {
+		b.overflow = true
+		b.Reset()
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// errReader is a reader that always fails with err.
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+// parserFor returns the parser registered for the bare media type.
+// Falls back to the first parser whose CanParse accepts it.
+// Returns ErrUnsupportedContentType if no parser matches.
+func (r *Hauler) parserFor(mediaType string) (BodyParser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if parser, ok := r.registry[mediaType]; ok {
+		return parser, nil
+	}
+	for _, p := range r.parsers {
+		if p.CanParse(mediaType) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
 }
 
 // DefaultReader is the package-level default reader.
@@ -143,6 +395,13 @@ func Read(req *http.Request, v interface{}) error {
 	return DefaultReader.Read(req, v)
 }
 
+// ReadAs is a convenience function using the default reader.
+// Parses an HTTP request body as contentType regardless of its header.
+// Returns an error if parsing fails or the request is invalid.
+func ReadAs(req *http.Request, contentType string, v interface{}) error {
+	return DefaultReader.ReadAs(req, contentType, v)
+}
+
 // Parser implementations
 
 // jsonParser handles JSON content type parsing.
@@ -191,7 +450,14 @@ func (p *msgpackParser) Parse(body io.Reader, v interface{}) error {
 	if v == nil {
 		return ErrInvalidPointer
 	}
-	return msgpack.NewDecoder(body).Decode(v)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := CheckMsgPack(data); err != nil {
+		return err
+	}
+	return msgpack.Unmarshal(data, v)
 }
 
 // formParser handles form-urlencoded content type parsing.
@@ -253,10 +519,20 @@ func (p *textParser) CanParse(contentType string) bool {
 }
 
 func (p *textParser) Parse(body io.Reader, v interface{}) error {
+	return p.ParseMedia(body, MediaType{Type: ContentTypeText}, v)
+}
+
+// ParseMedia parses text honoring the charset parameter.
+// UTF-8 and US-ASCII are passed through and ISO-8859-1 is transcoded to UTF-8.
+// Returns ErrUnsupportedCharset for any other charset.
+func (p *textParser) ParseMedia(body io.Reader, mt MediaType, v interface{}) error {
 	data, err := io.ReadAll(body)
 	if err != nil {
 		return err
 	}
+	if data, err = decodeCharset(data, mt.Params["charset"]); err != nil {
+		return err
+	}
 
 	switch dest := v.(type) {
 	case *string:
@@ -269,3 +545,58 @@ func (p *textParser) Parse(body io.Reader, v interface{}) error {
 
 	return nil
 }
+
+// decodeCharset converts data in the named charset to UTF-8.
+// Returns ErrUnsupportedCharset for charsets other than UTF-8, US-ASCII, and ISO-8859-1.
+func decodeCharset(data []byte, charset string) ([]byte, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return data, nil
+	case "iso-8859-1", "latin1", "latin-1":
+		out := make([]rune, len(data))
+		for i, b := range data {
+			out[i] = rune(b)
+		}
+		return []byte(string(out)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCharset, charset)
+	}
+}
+
+// protobufParser handles Protocol Buffers request bodies.
+// Implements BodyParser for proto.Message targets.
+// Supports "application/x-protobuf" and "application/protobuf".
+type protobufParser struct{}
+
+func (p *protobufParser) CanParse(contentType string) bool {
+	return contentType == ContentTypeProtobuf || contentType == "application/protobuf"
+}
+
+func (p *protobufParser) Parse(body io.Reader, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("protobuf data can only be decoded into a proto.Message")
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// cborParser handles CBOR request bodies.
+// Implements BodyParser; targets may implement cbor.Unmarshaler or use
+// `cbor`/`json` struct tags.
+// Supports "application/cbor".
+type cborParser struct{}
+
+func (p *cborParser) CanParse(contentType string) bool {
+	return contentType == ContentTypeCBOR
+}
+
+func (p *cborParser) Parse(body io.Reader, v interface{}) error {
+	if v == nil {
+		return ErrInvalidPointer
+	}
+	return cbor.NewDecoder(body).Decode(v)
+}
@@ -4,9 +4,14 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestNew(t *testing.T) {
@@ -318,3 +323,187 @@ func TestDefaultReader(t *testing.T) {
 		t.Fatal("DefaultReader is nil")
 	}
 }
+
+func TestRead_MediaTypeParams(t *testing.T) {
+	t.Run("multipart boundary", func(t *testing.T) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		_ = mw.WriteField("name", "alice")
+		fw, _ := mw.CreateFormFile("avatar", "a.png")
+		_, _ = fw.Write([]byte("png"))
+		_ = mw.Close()
+
+		req := httptest.NewRequest("POST", "/", &buf)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		var form multipart.Form
+		if err := Read(req, &form); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer form.RemoveAll()
+		if got := form.Value["name"]; len(got) != 1 || got[0] != "alice" {
+			t.Errorf("Expected name alice, got %v", got)
+		}
+		if files := form.File["avatar"]; len(files) != 1 || files[0].Filename != "a.png" {
+			t.Errorf("Expected avatar file, got %v", files)
+		}
+	})
+
+	t.Run("multipart without boundary", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader("x"))
+		req.Header.Set("Content-Type", ContentTypeMultipartForm)
+		var data map[string]string
+		if err := Read(req, &data); !errors.Is(err, ErrMissingBoundary) {
+			t.Errorf("Expected ErrMissingBoundary, got %v", err)
+		}
+	})
+
+	t.Run("latin1 charset", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", bytes.NewReader([]byte{'c', 'a', 'f', 0xe9}))
+		req.Header.Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		var data string
+		if err := Read(req, &data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data != "café" {
+			t.Errorf("Expected %q, got %q", "café", data)
+		}
+	})
+
+	t.Run("unsupported charset", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader("x"))
+		req.Header.Set("Content-Type", "text/plain; charset=koi8-r")
+		var data string
+		if err := Read(req, &data); !errors.Is(err, ErrUnsupportedCharset) {
+			t.Errorf("Expected ErrUnsupportedCharset, got %v", err)
+		}
+	})
+
+	t.Run("case and profile", func(t *testing.T) {
+		mt, err := ParseMediaType(`Application/JSON; profile="https://example.com/v2"`)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if mt.Type != ContentTypeJSON || mt.Params["profile"] != "https://example.com/v2" {
+			t.Errorf("Expected json with profile, got %+v", mt)
+		}
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Content-Type", `Application/JSON; profile="https://example.com/v2"`)
+		var data map[string]int
+		if err := Read(req, &data); err != nil || data["a"] != 1 {
+			t.Errorf("Expected a=1, got %v (%v)", data, err)
+		}
+	})
+}
+
+func TestRead_Buffering(t *testing.T) {
+	newReq := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", ContentTypeJSON)
+		return req
+	}
+	body := `{"name":"alice"}`
+
+	t.Run("none", func(t *testing.T) {
+		req := newReq(body)
+		var data map[string]string
+		if err := New().WithBuffering(BufferNone, 0).Read(req, &data); err != nil || data["name"] != "alice" {
+			t.Fatalf("Expected alice, got %v (%v)", data, err)
+		}
+		if _, err := io.ReadAll(req.Body); !errors.Is(err, ErrBodyNotReplayable) {
+			t.Errorf("Expected ErrBodyNotReplayable, got %v", err)
+		}
+	})
+
+	t.Run("tee within limit", func(t *testing.T) {
+		req := newReq(body)
+		var data map[string]string
+		if err := New().WithBuffering(BufferTee, 1024).Read(req, &data); err != nil || data["name"] != "alice" {
+			t.Fatalf("Expected alice, got %v (%v)", data, err)
+		}
+		replay, err := io.ReadAll(req.Body)
+		if err != nil || string(replay) != body {
+			t.Errorf("Expected replayed body %q, got %q (%v)", body, replay, err)
+		}
+	})
+
+	t.Run("tee over limit", func(t *testing.T) {
+		req := newReq(body)
+		var data map[string]string
+		if err := New().WithBuffering(BufferTee, 4).Read(req, &data); err != nil || data["name"] != "alice" {
+			t.Fatalf("Expected alice, got %v (%v)", data, err)
+		}
+		if _, err := io.ReadAll(req.Body); !errors.Is(err, ErrBodyNotReplayable) {
+			t.Errorf("Expected ErrBodyNotReplayable, got %v", err)
+		}
+	})
+}
+
+func TestReadAs(t *testing.T) {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"alice"}`))
+	req.Header.Set("Content-Type", ContentTypeText)
+	var data map[string]string
+	if err := ReadAs(req, ContentTypeJSON, &data); err != nil || data["name"] != "alice" {
+		t.Errorf("Expected alice, got %v (%v)", data, err)
+	}
+	if err := ReadAs(req, "application/unknown", &data); !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("Expected ErrUnsupportedContentType, got %v", err)
+	}
+}
+
+func TestRead_Protobuf(t *testing.T) {
+	body, _ := proto.Marshal(wrapperspb.String("hello"))
+	req := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+	var got wrapperspb.StringValue
+	if err := Read(req, &got); err != nil || got.GetValue() != "hello" {
+		t.Errorf("Expected hello, got %q (%v)", got.GetValue(), err)
+	}
+
+	req = httptest.NewRequest("POST", "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", ContentTypeProtobuf)
+	var data map[string]string
+	if err := Read(req, &data); err == nil {
+		t.Error("Expected error for non-message target, got nil")
+	}
+}
+
+func TestRead_EmptyBody(t *testing.T) {
+	newReq := func(ct string) *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(""))
+		req.Header.Set("Content-Type", ct)
+		return req
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		var data map[string]string
+		if err := Read(newReq(ContentTypeJSON), &data); !errors.Is(err, ErrEmptyBody) {
+			t.Errorf("Expected ErrEmptyBody, got %v", err)
+		}
+		if err := Read(newReq(ContentTypeFormURLEncoded), &data); err != nil {
+			t.Errorf("Expected empty form to parse, got %v", err)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		var data string
+		if err := New().WithEmptyBody(EmptyReject).Read(newReq(ContentTypeText), &data); !errors.Is(err, ErrEmptyBody) {
+			t.Errorf("Expected ErrEmptyBody, got %v", err)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		data := map[string]string{"stale": "x"}
+		if err := New().WithEmptyBody(EmptyZero).Read(newReq(ContentTypeJSON), &data); err != nil || data != nil {
+			t.Errorf("Expected nil map and no error, got %v (%v)", data, err)
+		}
+	})
+
+	t.Run("malformed is not empty", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader("{"))
+		req.Header.Set("Content-Type", ContentTypeJSON)
+		var data map[string]string
+		if err := Read(req, &data); err == nil || errors.Is(err, ErrEmptyBody) {
+			t.Errorf("Expected a decode error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package hauler
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+)
+
+// BodyTooLargeError reports a request body over the configured size limit.
+// It matches ErrBodyTooLarge with errors.Is, so callers such as beam.Renderer
+// can answer 413 Request Entity Too Large.
+type BodyTooLargeError struct {
+	ContentType string // Media type the limit applied to
+	Limit       int64  // Maximum body size in bytes
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %s body over %d bytes", ErrBodyTooLarge, e.ContentType, e.Limit)
+}
+
+// Is reports whether target is ErrBodyTooLarge.
+func (e *BodyTooLargeError) Is(target error) bool {
+	return target == ErrBodyTooLarge
+}
+
+// WithMaxBodySize returns a copy of the Hauler that rejects bodies over limit bytes.
+// Without contentTypes the limit is the default for every content type;
+// otherwise it applies to those media types only and overrides the default.
+// A non-positive limit removes the limit.
+// Oversized bodies fail with a *BodyTooLargeError before or while parsing.
+func (r *Hauler) WithMaxBodySize(limit int64, contentTypes ...string) *Hauler {
+	nr := r.clone()
+	if len(contentTypes) == 0 {
+		nr.maxBody = limit
+		return nr
+	}
+	nr.maxBodyFor = maps.Clone(nr.maxBodyFor)
+	if nr.maxBodyFor == nil {
+		nr.maxBodyFor = make(map[string]int64, len(contentTypes))
+	}
+	for _, ct := range contentTypes {
+		nr.maxBodyFor[ct] = limit
+	}
+	return nr
+}
+
+// bodyLimit returns the size limit for mediaType; zero or less means unlimited.
+func (r *Hauler) bodyLimit(mediaType string) int64 {
+	if limit, ok := r.maxBodyFor[mediaType]; ok {
+		return limit
+	}
+	return r.maxBody
+}
+
+// enforceLimit runs decode under the body size limit for mt.
+// Declared lengths over the limit are rejected without reading the body.
+func (r *Hauler) enforceLimit(req *http.Request, mt MediaType, decode func() error) error {
+	limit := r.bodyLimit(mt.Type)
+	if limit <= 0 {
+		return decode()
+	}
+	tooLarge := &BodyTooLargeError{ContentType: mt.Type, Limit: limit}
+	if req.ContentLength > limit {
+		return tooLarge
+	}
+	body := &limitedBody{ReadCloser: req.Body, remaining: limit, err: tooLarge}
+	req.Body = body
+	err := decode()
+	// Parsers may hide the read error behind their own, so report the limit directly.
+	if body.exceeded {
+		return tooLarge
+	}
+	return err
+}
+
+// limitedBody fails reads with err once more than remaining bytes arrive.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, b.err
+	}
+	// Read one byte past the limit to tell an exact fit from an overflow.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		b.exceeded = true
+		return n, b.err
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
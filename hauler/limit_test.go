@@ -0,0 +1,69 @@
+package hauler
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHauler_WithMaxBodySize(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("a", 100) + `"}`
+	read := func(h *Hauler, contentType string, known bool) error {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		if !known {
+			// Hide the length so the limit is enforced while reading.
+			req.ContentLength = -1
+			req.Body = io.NopCloser(strings.NewReader(body))
+		}
+		req.Header.Set("Content-Type", contentType)
+		var data map[string]string
+		return h.Read(req, &data)
+	}
+
+	t.Run("DeclaredLength", func(t *testing.T) {
+		err := read(New().WithMaxBodySize(50), ContentTypeJSON, true)
+		var tooLarge *BodyTooLargeError
+		if !errors.As(err, &tooLarge) || tooLarge.Limit != 50 || tooLarge.ContentType != ContentTypeJSON {
+			t.Fatalf("Expected BodyTooLargeError, got %v", err)
+		}
+		if !errors.Is(err, ErrBodyTooLarge) {
+			t.Error("Expected error to match ErrBodyTooLarge")
+		}
+	})
+
+	t.Run("StreamedBody", func(t *testing.T) {
+		for _, mode := range []Buffering{BufferAll, BufferNone, BufferTee} {
+			h := New().WithBuffering(mode, 1024).WithMaxBodySize(50)
+			if err := read(h, ContentTypeJSON, false); !errors.Is(err, ErrBodyTooLarge) {
+				t.Errorf("Buffering %d: expected ErrBodyTooLarge, got %v", mode, err)
+			}
+		}
+	})
+
+	t.Run("ExactFit", func(t *testing.T) {
+		if err := read(New().WithMaxBodySize(int64(len(body))), ContentTypeJSON, false); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("PerContentType", func(t *testing.T) {
+		h := New().WithMaxBodySize(50).WithMaxBodySize(1<<20, ContentTypeJSON)
+		if err := read(h, ContentTypeJSON, false); err != nil {
+			t.Errorf("Expected JSON override to allow body, got %v", err)
+		}
+		h = New().WithMaxBodySize(50, ContentTypeMsgPack)
+		if err := read(h, ContentTypeJSON, true); err != nil {
+			t.Errorf("Expected JSON unlimited, got %v", err)
+		}
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		c := &testCollector{counts: map[string]float64{}, observed: map[string][]float64{}}
+		_ = read(New().WithMetrics(c).WithMaxBodySize(50), ContentTypeJSON, false)
+		if c.counts[MetricParseErrors+"|content_type,application/json,reason,too_large"] != 1 {
+			t.Errorf("Expected too_large parse error, got %v", c.counts)
+		}
+	})
+}
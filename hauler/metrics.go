@@ -0,0 +1,155 @@
+package hauler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Metric names emitted by Hauler.
+const (
+	MetricParses        = "hauler_parses_total"           // Parsed bodies by content type and result
+	MetricParseErrors   = "hauler_parse_errors_total"     // Failed parses by content type and reason
+	MetricParseDuration = "hauler_parse_duration_seconds" // Time spent reading and decoding a body
+	MetricBodyBytes     = "hauler_body_bytes"             // Size of parsed bodies
+	MetricSlowParses    = "hauler_slow_parses_total"      // Parses slower than the WithSlowParse threshold
+)
+
+// Metric label keys and values.
+const (
+	labelContentType  = "content_type"
+	labelResult       = "result"
+	labelReason       = "reason"
+	resultOK          = "ok"
+	resultError       = "error"
+	reasonUnsupported = "unsupported"
+	reasonDecode      = "decode"
+	reasonTooLarge    = "too_large"
+	contentTypeOther  = "other"
+)
+
+// canonicalTypes are the content types metrics are labeled with.
+// The Content-Type header is client-controlled, so labeling with it verbatim
+// would let clients create unbounded metric series.
+var canonicalTypes = []string{
+	ContentTypeJSON,
+	ContentTypeXML,
+	ContentTypeMsgPack,
+	ContentTypeFormURLEncoded,
+	ContentTypeMultipartForm,
+	ContentTypeText,
+	ContentTypeProtobuf,
+	ContentTypeCBOR,
+	ContentTypeGeoJSON,
+}
+
+// Collector receives parse metrics.
+// It has the same method set as beam.Collector, so a renderer's collector can
+// be passed to Hauler.WithMetrics directly.
+// Implementations must be safe for concurrent use.
+type Collector interface {
+	// Count adds delta to the named counter.
+	Count(name string, delta float64, labels ...string)
+
+	// Observe records a sample in the named histogram or summary.
+	Observe(name string, value float64, labels ...string)
+}
+
+// ParseStats describes one parsed request body.
+type ParseStats struct {
+	ContentType string        // Media type without parameters
+	Bytes       int64         // Body bytes read by the parser
+	Duration    time.Duration // Time spent reading and decoding
+	Err         error         // Parse error, if any
+}
+
+// WithMetrics returns a copy of the Hauler that reports parse duration, body
+// sizes, and content-type distribution to c. Content types are labeled with
+// the matched parser's canonical type, such as "application/json" for
+// "application/json-patch+json", or "other".
+func (r *Hauler) WithMetrics(c Collector) *Hauler {
+	nr := r.clone()
+	nr.metrics = c
+	return nr
+}
+
+// WithSlowParse returns a copy of the Hauler that calls fn for every parse
+// taking at least threshold, so ingestion hot spots can be logged.
+// Slow parses are also counted when a Collector is set.
+func (r *Hauler) WithSlowParse(threshold time.Duration, fn func(ParseStats)) *Hauler {
+	nr := r.clone()
+	nr.slow = threshold
+	nr.onSlow = fn
+	return nr
+}
+
+// instrument decodes the body while measuring its size and duration.
+func (r *Hauler) instrument(req *http.Request, parser BodyParser, mt MediaType, v interface{}) error {
+	body := &countingBody{ReadCloser: req.Body}
+	req.Body = body
+	start := time.Now()
+	err := r.decode(req, parser, mt, v)
+	stats := ParseStats{ContentType: mt.Type, Bytes: body.n, Duration: time.Since(start), Err: err}
+	label := r.metricType(parser, mt.Type)
+
+	result := resultOK
+	if err != nil {
+		result = resultError
+		reason := reasonDecode
+		if errors.Is(err, ErrBodyTooLarge) {
+			reason = reasonTooLarge
+		}
+		r.count(MetricParseErrors, 1, labelContentType, label, labelReason, reason)
+	}
+	r.count(MetricParses, 1, labelContentType, label, labelResult, result)
+	r.observe(MetricParseDuration, stats.Duration.Seconds(), labelContentType, label)
+	r.observe(MetricBodyBytes, float64(stats.Bytes), labelContentType, label)
+	if r.onSlow != nil && stats.Duration >= r.slow {
+		r.count(MetricSlowParses, 1, labelContentType, label)
+		r.onSlow(stats)
+	}
+	return err
+}
+
+// metricType returns the canonical content type parser was registered for,
+// preferring mediaType itself, or "other" for custom parsers.
+func (r *Hauler) metricType(parser BodyParser, mediaType string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.registry[mediaType] == parser {
+		return mediaType
+	}
+	for _, ct := range canonicalTypes {
+		if r.registry[ct] == parser {
+			return ct
+		}
+	}
+	return contentTypeOther
+}
+
+// count adds delta to a counter when a collector is configured.
+func (r *Hauler) count(name string, delta float64, labels ...string) {
+	if r.metrics != nil {
+		r.metrics.Count(name, delta, labels...)
+	}
+}
+
+// observe records a sample when a collector is configured.
+func (r *Hauler) observe(name string, value float64, labels ...string) {
+	if r.metrics != nil {
+		r.metrics.Observe(name, value, labels...)
+	}
+}
+
+// countingBody counts the bytes read from a request body.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,80 @@
+package hauler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testCollector struct {
+	mu       sync.Mutex
+	counts   map[string]float64
+	observed map[string][]float64
+}
+
+func (c *testCollector) Count(name string, delta float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[name+"|"+strings.Join(labels, ",")] += delta
+}
+
+func (c *testCollector) Observe(name string, value float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observed[name] = append(c.observed[name], value)
+}
+
+func TestMetrics(t *testing.T) {
+	c := &testCollector{counts: map[string]float64{}, observed: map[string][]float64{}}
+	var slow []ParseStats
+	h := New().WithMetrics(c).WithSlowParse(0, func(s ParseStats) { slow = append(slow, s) })
+
+	body := `{"name":"alice"}`
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	var data map[string]string
+	if err := h.Read(req, &data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req = httptest.NewRequest("POST", "/", strings.NewReader("{"))
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	_ = h.Read(req, &data)
+	req = httptest.NewRequest("POST", "/", strings.NewReader("x"))
+	req.Header.Set("Content-Type", "application/unknown")
+	_ = h.Read(req, &data)
+	// Client-chosen types are labeled with their parser's type, not the header.
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json-random-12345")
+	_ = h.Read(req, &data)
+
+	for key, want := range map[string]float64{
+		MetricParses + "|content_type,application/json,result,ok":          2,
+		MetricParses + "|content_type,application/json,result,error":       1,
+		MetricParseErrors + "|content_type,application/json,reason,decode": 1,
+		MetricParseErrors + "|content_type,other,reason,unsupported":       1,
+		MetricSlowParses + "|content_type,application/json":                3,
+	} {
+		if got := c.counts[key]; got != want {
+			t.Errorf("Expected %s = %v, got %v", key, want, got)
+		}
+	}
+	if sizes := c.observed[MetricBodyBytes]; len(sizes) != 3 || sizes[0] != float64(len(body)) {
+		t.Errorf("Expected body sizes [%d 1 %d], got %v", len(body), len(body), sizes)
+	}
+	if len(c.observed[MetricParseDuration]) != 3 {
+		t.Errorf("Expected 3 duration samples, got %v", c.observed[MetricParseDuration])
+	}
+	if len(slow) != 3 || slow[0].Bytes != int64(len(body)) || slow[1].Err == nil {
+		t.Errorf("Expected 3 slow parses with stats, got %+v", slow)
+	}
+	if slow[2].ContentType != "application/json-random-12345" {
+		t.Errorf("Expected slow parse stats to keep the media type, got %q", slow[2].ContentType)
+	}
+
+	quiet := New().WithSlowParse(time.Hour, func(ParseStats) { t.Error("Expected no slow parse callback") })
+	req = httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	_ = quiet.Read(req, &data)
+}
@@ -0,0 +1,124 @@
+package hauler
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// maxMsgPackDepth bounds the nesting of arrays and maps accepted by CheckMsgPack.
+const maxMsgPackDepth = 1000
+
+// ErrMalformedMsgPack reports MessagePack input whose declared lengths exceed
+// the input or whose nesting is too deep.
+var ErrMalformedMsgPack = errors.New("malformed msgpack")
+
+// CheckMsgPack walks the first MessagePack value in data without decoding it,
+// checking every array, map, string, binary, and extension length against the
+// remaining input. Decoders preallocate declared lengths, so an 11-byte body
+// declaring a 4-billion-element array would otherwise exhaust memory.
+// Returns ErrMalformedMsgPack if data is truncated, too deep, or inconsistent.
+func CheckMsgPack(data []byte) error {
+	pending := []uint64{1} // Values left to read at each nesting level
+	for len(pending) > 0 {
+		if pending[len(pending)-1] == 0 {
+			pending = pending[:len(pending)-1]
+			continue
+		}
+		pending[len(pending)-1]--
+		if len(data) == 0 {
+			return ErrMalformedMsgPack
+		}
+		skip, items, err := msgpackHeader(data)
+		if err != nil {
+			return err
+		}
+		if skip > uint64(len(data)) {
+			return ErrMalformedMsgPack
+		}
+		data = data[skip:]
+		if items == 0 {
+			continue
+		}
+		// Every item takes at least one byte, bounding allocations by the input.
+		if items > uint64(len(data)) {
+			return ErrMalformedMsgPack
+		}
+		if len(pending) >= maxMsgPackDepth {
+			return ErrMalformedMsgPack
+		}
+		pending = append(pending, items)
+	}
+	return nil
+}
+
+// msgpackHeader returns the bytes taken by the value starting data, excluding
+// the children of arrays and maps, and the number of those children.
+func msgpackHeader(data []byte) (skip, items uint64, err error) {
+	b := data[0]
+	size := func(n int) (uint64, error) {
+		if len(data) < 1+n {
+			return 0, ErrMalformedMsgPack
+		}
+		switch n {
+		case 1:
+			return uint64(data[1]), nil
+		case 2:
+			return uint64(binary.BigEndian.Uint16(data[1:])), nil
+		default:
+			return uint64(binary.BigEndian.Uint32(data[1:])), nil
+		}
+	}
+	switch {
+	case b <= 0x7f, b >= 0xe0, b == 0xc0, b == 0xc2, b == 0xc3:
+		return 1, 0, nil
+	case b <= 0x8f:
+		return 1, 2 * uint64(b&0x0f), nil
+	case b <= 0x9f:
+		return 1, uint64(b & 0x0f), nil
+	case b <= 0xbf:
+		return 1 + uint64(b&0x1f), 0, nil
+	}
+	switch b {
+	case 0xcc, 0xd0:
+		return 2, 0, nil
+	case 0xcd, 0xd1:
+		return 3, 0, nil
+	case 0xca, 0xce, 0xd2:
+		return 5, 0, nil
+	case 0xcb, 0xcf, 0xd3:
+		return 9, 0, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8: // fixext: type byte plus 1, 2, 4, 8, or 16 bytes
+		return 2 + 1<<(b-0xd4), 0, nil
+	case 0xc4, 0xd9: // bin8, str8
+		n, err := size(1)
+		return 2 + n, 0, err
+	case 0xc5, 0xda: // bin16, str16
+		n, err := size(2)
+		return 3 + n, 0, err
+	case 0xc6, 0xdb: // bin32, str32
+		n, err := size(4)
+		return 5 + n, 0, err
+	case 0xc7: // ext8
+		n, err := size(1)
+		return 3 + n, 0, err
+	case 0xc8: // ext16
+		n, err := size(2)
+		return 4 + n, 0, err
+	case 0xc9: // ext32
+		n, err := size(4)
+		return 6 + n, 0, err
+	case 0xdc: // array16
+		n, err := size(2)
+		return 3, n, err
+	case 0xdd: // array32
+		n, err := size(4)
+		return 5, n, err
+	case 0xde: // map16
+		n, err := size(2)
+		return 3, 2 * n, err
+	case 0xdf: // map32
+		n, err := size(4)
+		return 5, 2 * n, err
+	}
+	return 0, 0, ErrMalformedMsgPack // 0xc1 is never used
+}
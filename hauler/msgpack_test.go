@@ -0,0 +1,50 @@
+package hauler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestCheckMsgPack(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		v := map[string]interface{}{
+			"str":    strings.Repeat("x", 300),
+			"bin":    make([]byte, 70000),
+			"ints":   []interface{}{1, -1, 200, -200, 70000, -70000, int64(1) << 40, uint64(1) << 63},
+			"floats": []interface{}{float32(1.5), 2.5},
+			"nested": map[string]interface{}{"a": []interface{}{nil, true, false}},
+			"time":   time.Unix(1700000000, 5),
+			"big":    make([]interface{}, 70000),
+		}
+		data, err := msgpack.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := CheckMsgPack(data); err != nil {
+			t.Errorf("Expected valid input accepted, got %v", err)
+		}
+	})
+
+	for name, data := range map[string]string{
+		"HugeArray":  "\xdd\xdd\xdd\xdd\xdd\xdd\xdd\xdd0\xc60",
+		"HugeMap":    "\xdf\x7f\xff\xff\xff\x01",
+		"HugeString": "\xdb\x7f\xff\xff\xffabc",
+		"HugeExt":    "\xc9\x7f\xff\xff\xff\x01",
+		"Truncated":  "\x92\x01",
+		"Unused":     "\xc1",
+		"Deep":       strings.Repeat("\x91", maxMsgPackDepth+1) + "\x00",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := CheckMsgPack([]byte(data)); !errors.Is(err, ErrMalformedMsgPack) {
+				t.Errorf("Expected ErrMalformedMsgPack, got %v", err)
+			}
+			if Fuzz(ContentTypeMsgPack, []byte(data)) != 0 {
+				t.Error("Expected the parser to reject the body")
+			}
+		})
+	}
+}
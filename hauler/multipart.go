@@ -0,0 +1,215 @@
+package hauler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingBoundary is returned for multipart bodies whose Content-Type lacks a boundary.
+var ErrMissingBoundary = errors.New("multipart boundary missing")
+
+// MaxMultipartMemory is the default bound on multipart form data kept in memory;
+// larger file parts are streamed to temporary files by mime/multipart.
+// Use Hauler.WithMaxMemory to set it per Hauler.
+var MaxMultipartMemory int64 = 32 << 20
+
+// fileHeaderType and formType identify struct fields that receive uploads.
+var (
+	fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+	formType       = reflect.TypeOf((*multipart.Form)(nil))
+)
+
+// WithMaxMemory returns a copy of the Hauler whose multipart parser keeps at
+// most maxMemory bytes of form data in memory, streaming the rest to temporary files.
+func (r *Hauler) WithMaxMemory(maxMemory int64) *Hauler {
+	nr := r.clone()
+	nr.Register(&multipartParser{maxMemory: maxMemory})
+	return nr
+}
+
+// multipartParser handles multipart/form-data parsing.
+// Implements MediaParser since the boundary lives in the Content-Type parameters.
+// Supports the "multipart/form-data" content type.
+type multipartParser struct {
+	maxMemory int64
+}
+
+func (p *multipartParser) CanParse(contentType string) bool {
+	return contentType == ContentTypeMultipartForm
+}
+
+func (p *multipartParser) Parse(body io.Reader, v interface{}) error {
+	return ErrMissingBoundary
+}
+
+// ParseMedia parses multipart form data using the boundary parameter.
+// Decodes into *multipart.Form, a struct with `form` tags, or the value fields
+// into map[string]string, map[string][]string, or url.Values.
+// Temporary files are removed unless the target keeps file headers; the
+// caller then owns the form's cleanup.
+// Returns an error if the boundary is missing, the data is invalid, or the target type is unsupported.
+func (p *multipartParser) ParseMedia(body io.Reader, mt MediaType, v interface{}) error {
+	form, keep, err := p.parse(body, mt, v)
+	if err != nil || keep {
+		return err
+	}
+	return form.RemoveAll()
+}
+
+// parseRequest parses like ParseMedia, but hands forms that keep file headers
+// to req.MultipartForm, which net/http cleans up when the request completes.
+func (p *multipartParser) parseRequest(req *http.Request, body io.Reader, mt MediaType, v interface{}) error {
+	form, keep, err := p.parse(body, mt, v)
+	if err != nil {
+		return err
+	}
+	if keep {
+		req.MultipartForm = form
+		return nil
+	}
+	return form.RemoveAll()
+}
+
+// parse reads the form and binds it to v.
+// Returns the form and whether v still references its file headers.
+func (p *multipartParser) parse(body io.Reader, mt MediaType, v interface{}) (*multipart.Form, bool, error) {
+	boundary := mt.Params["boundary"]
+	if boundary == "" {
+		return nil, false, ErrMissingBoundary
+	}
+	maxMemory := p.maxMemory
+	if maxMemory <= 0 {
+		maxMemory = MaxMultipartMemory
+	}
+	form, err := multipart.NewReader(body, boundary).ReadForm(maxMemory)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid multipart data: %w", err)
+	}
+	keep, err := bindForm(form, v)
+	if err != nil {
+		_ = form.RemoveAll()
+		return nil, false, err
+	}
+	return form, keep, nil
+}
+
+// bindForm copies form into v.
+// Returns whether v references the form's file headers.
+func bindForm(form *multipart.Form, v interface{}) (bool, error) {
+	switch dest := v.(type) {
+	case *multipart.Form:
+		*dest = *form
+		return true, nil
+	case *map[string]string:
+		*dest = make(map[string]string)
+		for k, v := range form.Value {
+			if len(v) > 0 {
+				(*dest)[k] = v[0]
+			}
+		}
+		return false, nil
+	case *map[string][]string:
+		*dest = form.Value
+		return false, nil
+	case *url.Values:
+		*dest = form.Value
+		return false, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return false, fmt.Errorf("multipart data can only be decoded into *multipart.Form, a struct, map[string]string, map[string][]string, or url.Values")
+	}
+	return bindStruct(form, rv.Elem())
+}
+
+// bindStruct fills the exported fields of a struct from the form.
+// Fields are matched by their `form` tag or name; "-" skips a field.
+// *multipart.FileHeader and []*multipart.FileHeader fields receive uploads,
+// a *multipart.Form field receives the whole form, and string, []string,
+// bool, integer, and float fields receive values.
+func bindStruct(form *multipart.Form, sv reflect.Value) (bool, error) {
+	keep := false
+	for _, f := range reflect.VisibleFields(sv.Type()) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		field := sv.FieldByIndex(f.Index)
+		switch {
+		case f.Type == formType:
+			field.Set(reflect.ValueOf(form))
+			keep = true
+		case f.Type == fileHeaderType:
+			if files := form.File[name]; len(files) > 0 {
+				field.Set(reflect.ValueOf(files[0]))
+				keep = true
+			}
+		case f.Type == reflect.SliceOf(fileHeaderType):
+			if files := form.File[name]; len(files) > 0 {
+				field.Set(reflect.ValueOf(files))
+				keep = true
+			}
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.String:
+			if values := form.Value[name]; len(values) > 0 {
+				field.Set(reflect.ValueOf(values).Convert(f.Type))
+			}
+		default:
+			values := form.Value[name]
+			if len(values) == 0 {
+				continue
+			}
+			if err := setFormValue(field, values[0]); err != nil {
+				return false, fmt.Errorf("form field %q: %w", name, err)
+			}
+		}
+	}
+	return keep, nil
+}
+
+// setFormValue parses s into a scalar field.
+func setFormValue(field reflect.Value, s string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package hauler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newMultipartRequest builds a form with a name field, an age field, and a file of size bytes.
+func newMultipartRequest(t *testing.T, size int) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("name", "alice")
+	_ = mw.WriteField("age", "30")
+	_ = mw.WriteField("tags", "a")
+	_ = mw.WriteField("tags", "b")
+	fw, err := mw.CreateFormFile("avatar", "a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(bytes.Repeat([]byte("x"), size))
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestRead_MultipartStruct(t *testing.T) {
+	type upload struct {
+		Name   string                `form:"name"`
+		Age    int                   `form:"age"`
+		Tags   []string              `form:"tags"`
+		Avatar *multipart.FileHeader `form:"avatar"`
+		Skip   string                `form:"-"`
+	}
+
+	req := newMultipartRequest(t, 16)
+	var u upload
+	if err := Read(req, &u); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.Name != "alice" || u.Age != 30 {
+		t.Errorf("Expected alice/30, got %q/%d", u.Name, u.Age)
+	}
+	if len(u.Tags) != 2 || u.Tags[0] != "a" || u.Tags[1] != "b" {
+		t.Errorf("Expected tags [a b], got %v", u.Tags)
+	}
+	if u.Avatar == nil || u.Avatar.Filename != "a.png" || u.Avatar.Size != 16 {
+		t.Fatalf("Expected avatar a.png of 16 bytes, got %+v", u.Avatar)
+	}
+	if req.MultipartForm == nil {
+		t.Error("Expected form attached to request for cleanup")
+	}
+	f, err := u.Avatar.Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+	if data, _ := io.ReadAll(f); string(data) != strings.Repeat("x", 16) {
+		t.Errorf("Unexpected file content %q", data)
+	}
+}
+
+func TestRead_MultipartInvalidField(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("age", "old")
+	_ = mw.Close()
+	req := httptest.NewRequest("POST", "/", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var u struct {
+		Age int `form:"age"`
+	}
+	if err := Read(req, &u); err == nil || !strings.Contains(err.Error(), `"age"`) {
+		t.Errorf("Expected age field error, got %v", err)
+	}
+}
+
+func TestHauler_WithMaxMemory(t *testing.T) {
+	h := New().WithMaxMemory(1024)
+	req := newMultipartRequest(t, 64<<10)
+
+	var u struct {
+		Files []*multipart.FileHeader `form:"avatar"`
+	}
+	if err := h.Read(req, &u); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(u.Files) != 1 {
+		t.Fatalf("Expected one file, got %d", len(u.Files))
+	}
+	f, err := u.Files[0].Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	osf, onDisk := f.(*os.File)
+	if !onDisk {
+		f.Close()
+		t.Fatal("Expected large upload streamed to a temporary file")
+	}
+	name := osf.Name()
+	if n, _ := io.Copy(io.Discard, f); n != 64<<10 {
+		t.Errorf("Expected %d bytes, got %d", 64<<10, n)
+	}
+	f.Close()
+
+	if err := req.MultipartForm.RemoveAll(); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Errorf("Expected temporary file removed, got %v", err)
+	}
+}
+
+func TestRead_MultipartValuesRemoveFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	req := newMultipartRequest(t, 64<<10)
+	var values map[string][]string
+	if err := New().WithMaxMemory(1024).Read(req, &values); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if values["name"][0] != "alice" {
+		t.Errorf("Expected name alice, got %v", values["name"])
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected temporary files removed, found %d", len(entries))
+	}
+}
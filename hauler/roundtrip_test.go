@@ -0,0 +1,43 @@
+package hauler_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/olekukonko/beam"
+	"github.com/olekukonko/beam/hauler"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	t.Run("Protobuf", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := beam.NewRenderer(beam.Setting{Name: "test"}).WithContentType(beam.ContentTypeProtobuf)
+		if err := r.Push(rec, beam.Response{Data: wrapperspb.String("hello")}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/", rec.Body)
+		req.Header.Set("Content-Type", rec.Header().Get("Content-Type"))
+		var got wrapperspb.StringValue
+		if err := hauler.Read(req, &got); err != nil || got.GetValue() != "hello" {
+			t.Errorf("Expected hello, got %q (%v)", got.GetValue(), err)
+		}
+	})
+
+	t.Run("CBOR", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := beam.NewRenderer(beam.Setting{Name: "test"}).WithContentType(beam.ContentTypeCBOR)
+		if err := r.Push(rec, beam.Response{Data: map[string]int{"n": 7}}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/", rec.Body)
+		req.Header.Set("Content-Type", rec.Header().Get("Content-Type"))
+		var got struct {
+			Status string         `cbor:"status"`
+			Data   map[string]int `cbor:"data"`
+		}
+		if err := hauler.Read(req, &got); err != nil || got.Data["n"] != 7 || got.Status != beam.StatusSuccessful {
+			t.Errorf("Expected n=7, got %+v (%v)", got, err)
+		}
+	})
+}
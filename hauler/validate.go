@@ -0,0 +1,119 @@
+package hauler
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrValidation is matched by every *ValidationError.
+var ErrValidation = errors.New("validation failed")
+
+// Validator is implemented by request types that check themselves once decoded.
+// Read calls Validate after a successful parse; a non-nil error fails the Read
+// with a *ValidationError.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is a validation failure for one field.
+// Validators return FieldErrors, joined with errors.Join for several fields,
+// so callers can report failures per field.
+type FieldError struct {
+	Field   string `json:"field" xml:"field,attr" msgpack:"field"`
+	Message string `json:"message" xml:",chardata" msgpack:"message"`
+}
+
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Is reports whether target is ErrValidation.
+func (e *FieldError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// ValidationError reports a decoded body that failed validation.
+// Fields lists per-field failures; errors that are not FieldErrors appear
+// with an empty Field.
+type ValidationError struct {
+	Fields []FieldError
+	Err    error // Error returned by the validator
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i := range e.Fields {
+		msgs[i] = e.Fields[i].Error()
+	}
+	return ErrValidation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the validator's error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// WithValidator returns a copy of the Hauler that runs fn on every decoded
+// value, after the value's own Validate method if it implements Validator.
+// Failures are returned as a *ValidationError.
+func (r *Hauler) WithValidator(fn func(interface{}) error) *Hauler {
+	nr := r.clone()
+	nr.validator = fn
+	return nr
+}
+
+// validate runs v's Validator and the configured validator hook.
+func (r *Hauler) validate(v interface{}) error {
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return newValidationError(err)
+		}
+	}
+	if r.validator != nil {
+		if err := r.validator(v); err != nil {
+			return newValidationError(err)
+		}
+	}
+	return nil
+}
+
+// newValidationError wraps err, splitting joined errors into per-field entries.
+func newValidationError(err error) *ValidationError {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve
+	}
+	ve = &ValidationError{Err: err}
+	for _, e := range flatten(err) {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			ve.Fields = append(ve.Fields, *fe)
+		} else {
+			ve.Fields = append(ve.Fields, FieldError{Message: e.Error()})
+		}
+	}
+	return ve
+}
+
+// flatten expands errors.Join trees into their leaf errors.
+func flatten(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var out []error
+	for _, e := range joined.Unwrap() {
+		if e != nil {
+			out = append(out, flatten(e)...)
+		}
+	}
+	return out
+}
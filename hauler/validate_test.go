@@ -0,0 +1,72 @@
+package hauler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type signup struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func (s *signup) Validate() error {
+	var errs []error
+	if s.Name == "" {
+		errs = append(errs, &FieldError{Field: "name", Message: "is required"})
+	}
+	if s.Age < 18 {
+		errs = append(errs, &FieldError{Field: "age", Message: "must be at least 18"})
+	}
+	return errors.Join(errs...)
+}
+
+func TestRead_Validation(t *testing.T) {
+	read := func(h *Hauler, body string, v interface{}) error {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", ContentTypeJSON)
+		return h.Read(req, v)
+	}
+
+	t.Run("Validator", func(t *testing.T) {
+		var s signup
+		err := read(New(), `{"age":12}`, &s)
+		var ve *ValidationError
+		if !errors.As(err, &ve) || !errors.Is(err, ErrValidation) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		if len(ve.Fields) != 2 || ve.Fields[0].Field != "name" || ve.Fields[1].Field != "age" {
+			t.Errorf("Unexpected fields %+v", ve.Fields)
+		}
+		if err := read(New(), `{"name":"alice","age":30}`, &s); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("WithValidator", func(t *testing.T) {
+		h := New().WithValidator(func(v interface{}) error {
+			if m := *v.(*map[string]string); m["email"] == "" {
+				return errors.New("email is required")
+			}
+			return nil
+		})
+		var data map[string]string
+		err := read(h, `{"name":"alice"}`, &data)
+		var ve *ValidationError
+		if !errors.As(err, &ve) || len(ve.Fields) != 1 || ve.Fields[0].Field != "" || ve.Fields[0].Message != "email is required" {
+			t.Fatalf("Expected unscoped validation error, got %v", err)
+		}
+		if err := read(New(), `{}`, &data); err != nil {
+			t.Error("Expected validator not to leak into other Haulers")
+		}
+	})
+
+	t.Run("DecodeErrorSkipsValidation", func(t *testing.T) {
+		var s signup
+		if err := read(New(), `{"age":`, &s); err == nil || errors.Is(err, ErrValidation) {
+			t.Errorf("Expected decode error, got %v", err)
+		}
+	})
+}
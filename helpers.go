@@ -11,8 +11,8 @@ import (
 // It constructs a Response with StatusSuccessful and the provided message.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Msg(msg string) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -24,8 +24,8 @@ func (r *Renderer) Msg(msg string) error {
 // It formats the message using fmt.Sprintf and constructs a Response with StatusSuccessful.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Msgf(format string, args ...interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -37,8 +37,8 @@ func (r *Renderer) Msgf(format string, args ...interface{}) error {
 // It constructs a Response with StatusUnknown, the provided message, and info data.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Send(msg string, info interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.Push(r.writer, Response{
 		Status:  StatusUnknown,
@@ -51,8 +51,8 @@ func (r *Renderer) Send(msg string, info interface{}) error {
 // It constructs a Response with StatusSuccessful, the provided message, and info.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Info(msg string, info interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -65,8 +65,8 @@ func (r *Renderer) Info(msg string, info interface{}) error {
 // It constructs a Response with StatusSuccessful, the provided message, and data.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Data(msg string, data interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -79,8 +79,8 @@ func (r *Renderer) Data(msg string, data interface{}) error {
 // It constructs a Response with StatusSuccessful, the provided message, info, and data.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Response(msg string, info interface{}, data interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -94,8 +94,8 @@ func (r *Renderer) Response(msg string, info interface{}, data interface{}) erro
 // It constructs a Response with StatusPending and HTTP status 202 (Accepted).
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Pending(msg string, info interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusAccepted).Push(r.writer, Response{
 		Status:  StatusPending,
@@ -108,8 +108,8 @@ func (r *Renderer) Pending(msg string, info interface{}) error {
 // It constructs a Response with StatusSuccessful, the provided title, message, and info.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) Titled(title, msg string, info interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 	return r.WithStatus(http.StatusOK).Push(r.writer, Response{
 		Status:  StatusSuccessful,
@@ -192,8 +192,8 @@ func (r *Renderer) FatalInfo(message string, info interface{}, errs ...error) er
 // For fatal responses, it logs errors with additional context if a logger is present.
 // Returns an error if the writer is nil or sending the response fails.
 func (r *Renderer) handleErrorResponse(message string, isInitiallyFatal bool, info interface{}, errs ...error) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 
 	responseErrors, fatalErrors, hasHidden := r.processErrors(isInitiallyFatal, errs...)
@@ -219,7 +219,7 @@ func (r *Renderer) handleErrorResponse(message string, isInitiallyFatal bool, in
 		}
 	}
 
-	if r.showError.Enabled() {
+	if r.showsErrors() {
 		resp.Errors = finalErrors
 	}
 
@@ -243,6 +243,7 @@ func (r *Renderer) handleErrorResponse(message string, isInitiallyFatal bool, in
 		var logFields []interface{}
 		file, line, funcName := getCallerInfo()
 		logFields = append(logFields, fieldFile, file, fieldLine, line, fieldFunc, funcName)
+		logFields = append(logFields, r.annotationFields()...)
 
 		nilCount := 0
 		for _, err := range errs {
@@ -265,6 +266,8 @@ func (r *Renderer) handleErrorResponse(message string, isInitiallyFatal bool, in
 	statusCode := http.StatusBadRequest
 	if isEffectivelyFatal {
 		statusCode = http.StatusInternalServerError
+	} else if code := requestErrorStatus(errs); code != 0 {
+		statusCode = code
 	}
 
 	// Use the finalRenderer which may contain the new error header.
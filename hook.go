@@ -0,0 +1,126 @@
+package beam
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+)
+
+// errHookAborted reports an output stopped by a hook.
+var errHookAborted = errors.New("output aborted by hook")
+
+// HookStage identifies when a hook registered with WithHook runs.
+type HookStage int
+
+// Stages of Push and Raw at which hooks run, in order.
+const (
+	BeforeEncode HookStage = iota // Response (or Raw data), status, and headers may be changed
+	AfterEncode                   // Body holds the encoded bytes, before compression
+	BeforeWrite                   // Body holds the final bytes; headers are not yet sent
+	AfterWrite                    // The response was written; Err reports a write failure
+)
+
+// HookContext is the state of an output call passed to hooks.
+// Changes made by a hook are used by the rest of the output: the Response
+// and Data before encoding, Status and Header until BeforeWrite, and Body
+// after encoding and before writing.
+type HookContext struct {
+	Renderer *Renderer   // Renderer producing the output
+	Stage    HookStage   // Stage being run
+	Response *Response   // Envelope sent by Push, changed in place; nil for Raw
+	Data     interface{} // Value sent by Raw; nil for Push
+	Status   int         // HTTP status code
+	Header   http.Header // Headers to send
+	Body     []byte      // Encoded bytes from AfterEncode on
+	Err      error       // Write error at AfterWrite
+
+	aborted error
+}
+
+// Abort stops the output before anything is written; the output call returns err.
+// Has no effect at AfterWrite.
+func (hc *HookContext) Abort(err error) {
+	if err == nil {
+		err = errHookAborted
+	}
+	hc.aborted = err
+}
+
+// WithHook registers fn to intercept Push and Raw output at stage.
+// Hooks run in registration order and may change the response, headers,
+// or encoded bytes, e.g. to sign payloads or inject metadata centrally.
+// Returns a new Renderer with the hook added.
+func (r *Renderer) WithHook(stage HookStage, fn func(*HookContext)) *Renderer {
+	nr := r.clone()
+	// Copy on write so renderers sharing the previous map are unaffected.
+	nr.hooks = maps.Clone(r.hooks)
+	if nr.hooks == nil {
+		nr.hooks = make(map[HookStage][]func(*HookContext))
+	}
+	nr.hooks[stage] = append(slices.Clone(r.hooks[stage]), fn)
+	return nr
+}
+
+// newHookContext returns the context of an output call, or nil without hooks.
+func (r *Renderer) newHookContext(resp *Response, data interface{}) *HookContext {
+	if len(r.hooks) == 0 {
+		return nil
+	}
+	return &HookContext{Renderer: r, Response: resp, Data: data, Header: r.header}
+}
+
+// runHooks runs the hooks of stage on hc and applies the status they set.
+// Returns the error passed to Abort, if any.
+func (r *Renderer) runHooks(stage HookStage, hc *HookContext) error {
+	if hc == nil {
+		return nil
+	}
+	hooks := r.hooks[stage]
+	if len(hooks) == 0 {
+		return nil
+	}
+	hc.Stage, hc.Status = stage, r.code
+	for _, fn := range hooks {
+		fn(hc)
+		if hc.aborted != nil && stage != AfterWrite {
+			return hc.aborted
+		}
+	}
+	r.code = hc.Status
+	if hc.Header != nil {
+		r.header = hc.Header
+	}
+	return nil
+}
+
+// hookBody runs the hooks of stage with body as the encoded bytes.
+// Returns the bytes as left by the hooks.
+func (r *Renderer) hookBody(stage HookStage, hc *HookContext, body []byte) ([]byte, error) {
+	if hc == nil {
+		return body, nil
+	}
+	hc.Body = body
+	if err := r.runHooks(stage, hc); err != nil {
+		return nil, err
+	}
+	return hc.Body, nil
+}
+
+// hookWritten runs the AfterWrite hooks with the outcome of the write.
+func (r *Renderer) hookWritten(hc *HookContext, err error) {
+	if hc == nil {
+		return
+	}
+	hc.Err = err
+	_ = r.runHooks(AfterWrite, hc)
+}
+
+// hookAborted reports an output stopped by a hook.
+func (r *Renderer) hookAborted(w Writer, err error) error {
+	r.triggerCallbacks(r.id, StatusFatal, err.Error(), err)
+	if r.finalizer != nil {
+		r.finalizer(w, err)
+	}
+	return err
+}
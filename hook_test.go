@@ -0,0 +1,93 @@
+package beam
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	key := []byte("secret")
+	var stages []HookStage
+	record := func(hc *HookContext) { stages = append(stages, hc.Stage) }
+	r := NewRenderer(settings).
+		WithHook(BeforeEncode, record).
+		WithHook(BeforeEncode, func(hc *HookContext) {
+			if hc.Response != nil {
+				hc.Response.Tags = append(hc.Response.Tags, "hooked")
+			}
+			hc.Header.Set("X-Region", "eu")
+		}).
+		WithHook(AfterEncode, record).
+		WithHook(BeforeWrite, record).
+		WithHook(BeforeWrite, func(hc *HookContext) {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(hc.Body)
+			hc.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+		}).
+		WithHook(AfterWrite, record)
+
+	t.Run("Push", func(t *testing.T) {
+		stages = nil
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if len(stages) != 4 || stages[0] != BeforeEncode || stages[3] != AfterWrite {
+			t.Errorf("Expected every stage in order, got %v", stages)
+		}
+		if !strings.Contains(w.Body.String(), `"hooked"`) || w.Header().Get("X-Region") != "eu" {
+			t.Errorf("Expected the BeforeEncode changes, got %v %s", w.Header(), w.Body.String())
+		}
+		mac := hmac.New(sha256.New, key)
+		mac.Write(w.Body.Bytes())
+		if w.Header().Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			t.Error("Expected the signature of the written body")
+		}
+	})
+
+	t.Run("Raw", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := r.WithHook(BeforeEncode, func(hc *HookContext) {
+			hc.Data = map[string]string{"replaced": "yes"}
+			hc.Status = http.StatusAccepted
+		}).WithHook(AfterEncode, func(hc *HookContext) {
+			hc.Body = append(hc.Body, '\n')
+		}).WithWriter(w).Raw(map[string]string{"a": "b"})
+		if err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if w.Code != http.StatusAccepted || w.Body.String() != "{\"replaced\":\"yes\"}\n" {
+			t.Errorf("Expected the hooked data and status, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		denied := errors.New("signing key unavailable")
+		var written bool
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).
+			WithHook(BeforeWrite, func(hc *HookContext) { hc.Abort(denied) }).
+			WithHook(AfterWrite, func(*HookContext) { written = true }).
+			WithWriter(w).Data("ok", nil)
+		if !errors.Is(err, denied) {
+			t.Errorf("Expected the abort error, got %v", err)
+		}
+		if strings.Contains(w.Body.String(), `"ok"`) || written {
+			t.Errorf("Expected the payload not written, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		base := NewRenderer(settings)
+		_ = base.WithHook(BeforeEncode, func(*HookContext) {})
+		if len(base.hooks) != 0 {
+			t.Error("Expected the base renderer unaffected by derived hooks")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package beam
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderEarlyData is set by TLS 1.3/QUIC terminators on requests received as 0-RTT early data (RFC 8470).
+const HeaderEarlyData = "Early-Data"
+
+// AltSvc describes an alternative service advertised via the Alt-Svc header (RFC 7838).
+type AltSvc struct {
+	Protocol string        // ALPN protocol id, e.g. "h3"
+	Host     string        // Alternative host; empty means the same host
+	Port     int           // Alternative port
+	MaxAge   time.Duration // Freshness lifetime; zero uses the client default (24h)
+	Persist  bool          // Keep the entry across network changes
+}
+
+// String formats the entry as an Alt-Svc value, e.g. `h3=":443"; ma=86400`.
+func (a AltSvc) String() string {
+	var b strings.Builder
+	b.WriteString(a.Protocol)
+	b.WriteString(`="`)
+	b.WriteString(a.Host)
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(a.Port))
+	b.WriteByte('"')
+	if a.MaxAge > 0 {
+		b.WriteString("; ma=")
+		b.WriteString(strconv.FormatInt(int64(a.MaxAge/time.Second), 10))
+	}
+	if a.Persist {
+		b.WriteString("; persist=1")
+	}
+	return b.String()
+}
+
+// WithAltSvc advertises alternative services, typically HTTP/3 endpoints.
+// Lets clients reached over HTTP/1.1 or HTTP/2 upgrade to QUIC.
+// Returns a new Renderer with the Alt-Svc header set.
+func (r *Renderer) WithAltSvc(services ...AltSvc) *Renderer {
+	values := make([]string, len(services))
+	for i, s := range services {
+		values[i] = s.String()
+	}
+	nr := r.clone()
+	nr.header.Set("Alt-Svc", strings.Join(values, ", "))
+	return nr
+}
+
+// WithPriority sets the response priority hint (RFC 9218).
+// Urgency ranges from 0 (highest) to 7 (lowest) and is clamped; incremental
+// marks responses that are useful when delivered in parts.
+// Returns a new Renderer with the Priority header set.
+func (r *Renderer) WithPriority(urgency int, incremental bool) *Renderer {
+	value := "u=" + strconv.Itoa(min(max(urgency, 0), 7))
+	if incremental {
+		value += ", i"
+	}
+	nr := r.clone()
+	nr.header.Set("Priority", value)
+	return nr
+}
+
+// WithIdempotent marks the Renderer's route as safe to replay.
+// Requests arriving as 0-RTT early data are only served when the method is
+// idempotent or the route is marked; others receive 425 Too Early.
+// Returns a new Renderer with the updated setting.
+func (r *Renderer) WithIdempotent(enabled State) *Renderer {
+	nr := r.clone()
+	nr.idempotent = enabled
+	return nr
+}
+
+// IsEarlyData reports whether the request was received as 0-RTT early data.
+// Checks the Early-Data header and, for direct TLS, the connection state.
+func IsEarlyData(req *http.Request) bool {
+	if req == nil {
+		return false
+	}
+	if req.Header.Get(HeaderEarlyData) == "1" {
+		return true
+	}
+	return req.TLS != nil && !req.TLS.HandshakeComplete
+}
+
+// replaySafe reports whether req may be served even if it is replayed.
+func (r *Renderer) replaySafe(req *http.Request) bool {
+	if r.idempotent.Enabled() || !IsEarlyData(req) {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// tooEarly answers a replay-unsafe early-data request with 425 Too Early.
+// The client retries after the handshake completes.
+func (r *Renderer) tooEarly(w http.ResponseWriter, req *http.Request) {
+	r.WithWriter(w).WithRequest(req).WithStatus(http.StatusTooEarly).Push(w, Response{
+		Status:  StatusError,
+		Title:   http.StatusText(http.StatusTooEarly),
+		Message: "request must not be sent as early data",
+	})
+}
+
+// EarlyDataGuard rejects replay-unsafe requests received as 0-RTT early data.
+// Non-idempotent methods get 425 Too Early unless r is marked WithIdempotent.
+// Returns a middleware wrapping next.
+func (r *Renderer) EarlyDataGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !r.replaySafe(req) {
+			r.tooEarly(w, req)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
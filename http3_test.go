@@ -0,0 +1,68 @@
+package beam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTP3Features(t *testing.T) {
+	t.Run("AltSvcAndPriority", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).
+			WithAltSvc(AltSvc{Protocol: "h3", Port: 443, MaxAge: 24 * time.Hour}, AltSvc{Protocol: "h3", Host: "alt.example.com", Port: 8443, Persist: true}).
+			WithPriority(9, true)
+		if err := r.Push(w, Response{Message: "ok"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := w.Header().Get("Alt-Svc"); got != `h3=":443"; ma=86400, h3="alt.example.com:8443"; persist=1` {
+			t.Errorf("Unexpected Alt-Svc %q", got)
+		}
+		if got := w.Header().Get("Priority"); got != "u=7, i" {
+			t.Errorf("Expected clamped priority, got %q", got)
+		}
+	})
+
+	t.Run("EarlyData", func(t *testing.T) {
+		ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+		cases := []struct {
+			name   string
+			r      *Renderer
+			method string
+			early  bool
+			want   int
+		}{
+			{"SafeMethod", NewRenderer(settings), http.MethodGet, true, http.StatusNoContent},
+			{"UnsafeMethod", NewRenderer(settings), http.MethodPost, true, http.StatusTooEarly},
+			{"MarkedIdempotent", NewRenderer(settings).WithIdempotent(Yes), http.MethodPost, true, http.StatusNoContent},
+			{"FullHandshake", NewRenderer(settings), http.MethodPost, false, http.StatusNoContent},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(tc.method, "/orders", nil)
+				if tc.early {
+					req.Header.Set(HeaderEarlyData, "1")
+				}
+				w := httptest.NewRecorder()
+				tc.r.EarlyDataGuard(ok).ServeHTTP(w, req)
+				if w.Code != tc.want {
+					t.Errorf("Expected status %d, got %d", tc.want, w.Code)
+				}
+			})
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set(HeaderEarlyData, "1")
+		w := httptest.NewRecorder()
+		NewRenderer(settings).Handler(func(r *Renderer) error {
+			t.Error("Expected handler not to run for early data")
+			return nil
+		}).ServeHTTP(w, req)
+		if w.Code != http.StatusTooEarly {
+			t.Errorf("Expected status 425, got %d", w.Code)
+		}
+	})
+}
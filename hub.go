@@ -0,0 +1,171 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Subscriber receives events fanned out by a Hub.
+// Send must not block; SSESession implements it with a bounded per-client queue.
+type Subscriber interface {
+	Send(evt Event) error
+	Close()
+}
+
+// HubOptions configures a Hub.
+type HubOptions struct {
+	Backlog int                                   // Events kept for Last-Event-ID resume; zero disables
+	OnEvict func(sub Subscriber, topics []string) // Called after a slow subscriber was evicted
+}
+
+// HubStats reports a Hub's activity.
+type HubStats struct {
+	Subscribers int    // Currently subscribed
+	Published   uint64 // Events published
+	Delivered   uint64 // Events queued to subscribers
+	Evicted     uint64 // Subscribers evicted for falling behind
+}
+
+// Hub fans published events out to many subscribers, such as the SSE
+// sessions of a chat or notification endpoint. Subscribers whose queue is
+// full are evicted so one slow client never holds up the others.
+// Safe for concurrent use.
+type Hub struct {
+	opts    HubOptions
+	backlog *SSEBacklog
+	mu      sync.RWMutex
+	subs    map[Subscriber][]string
+
+	published atomic.Uint64
+	delivered atomic.Uint64
+	evicted   atomic.Uint64
+}
+
+// NewHub creates an empty Hub.
+func NewHub(opts HubOptions) *Hub {
+	h := &Hub{opts: opts, subs: make(map[Subscriber][]string)}
+	if opts.Backlog > 0 {
+		h.backlog = NewSSEBacklog(opts.Backlog)
+	}
+	return h
+}
+
+// Subscribe adds sub for events whose Type is one of topics.
+// Without topics, sub receives every event.
+func (h *Hub) Subscribe(sub Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = topics
+}
+
+// Unsubscribe removes sub; it is not closed.
+func (h *Hub) Unsubscribe(sub Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
+
+// Publish sends evt to every subscriber of its topic (evt.Type), recording
+// it in the backlog first so it carries a resumable ID.
+// Subscribers that cannot keep up are closed and evicted.
+// Returns the number of subscribers the event was queued for.
+func (h *Hub) Publish(evt Event) int {
+	h.published.Add(1)
+	if h.backlog != nil {
+		evt = h.backlog.Append(evt)
+	}
+	h.mu.RLock()
+	sent := 0
+	failed := make(map[Subscriber]error)
+	for sub, topics := range h.subs {
+		if len(topics) > 0 && !slices.Contains(topics, evt.Type) {
+			continue
+		}
+		if err := sub.Send(evt); err != nil {
+			failed[sub] = err
+			continue
+		}
+		sent++
+	}
+	h.mu.RUnlock()
+	h.delivered.Add(uint64(sent))
+	for sub, err := range failed {
+		h.evict(sub, err)
+	}
+	return sent
+}
+
+// evict removes a subscriber that failed to accept an event.
+// Subscribers that already closed are dropped without counting as evictions.
+func (h *Hub) evict(sub Subscriber, err error) {
+	h.mu.Lock()
+	topics, ok := h.subs[sub]
+	delete(h.subs, sub)
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	if errors.Is(err, ErrSSESessionClosed) {
+		return
+	}
+	sub.Close()
+	h.evicted.Add(1)
+	if h.opts.OnEvict != nil {
+		h.opts.OnEvict(sub, topics)
+	}
+}
+
+// Len returns the number of subscribers.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
+
+// Stats returns a snapshot of the Hub's counters.
+func (h *Hub) Stats() HubStats {
+	return HubStats{
+		Subscribers: h.Len(),
+		Published:   h.published.Load(),
+		Delivered:   h.delivered.Load(),
+		Evicted:     h.evicted.Load(),
+	}
+}
+
+// Close closes and removes every subscriber.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	subs := h.subs
+	h.subs = make(map[Subscriber][]string)
+	h.mu.Unlock()
+	for sub := range subs {
+		sub.Close()
+	}
+}
+
+// Handler serves an SSE endpoint subscribed to the Hub.
+// topics selects the topics of each request (nil subscribes to everything);
+// the Hub's backlog lets clients resume after Last-Event-ID.
+// Returns an http.HandlerFunc built with r.Handler.
+func (h *Hub) Handler(r *Renderer, opts SSEOptions, topics func(*http.Request) []string) http.HandlerFunc {
+	if opts.Backlog == nil {
+		opts.Backlog = h.backlog
+	}
+	return r.Handler(func(r *Renderer) error {
+		s := r.SSESession(opts)
+		var subscribed []string
+		if topics != nil {
+			subscribed = topics(r.request)
+		}
+		h.Subscribe(s, subscribed...)
+		defer h.Unsubscribe(s)
+		// Headers are already sent, so failures can only be logged.
+		if err := s.Run(); err != nil {
+			r.Log(err)
+		}
+		return nil
+	})
+}
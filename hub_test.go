@@ -0,0 +1,82 @@
+package beam
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHub(t *testing.T) {
+	t.Run("FanOutAndEviction", func(t *testing.T) {
+		var evicted []Subscriber
+		h := NewHub(HubOptions{OnEvict: func(sub Subscriber, _ []string) { evicted = append(evicted, sub) }})
+		r := NewRenderer(settings)
+		chat := r.SSESession(SSEOptions{})
+		all := r.SSESession(SSEOptions{})
+		slow := r.SSESession(SSEOptions{Buffer: 1})
+		h.Subscribe(chat, "chat")
+		h.Subscribe(all)
+		h.Subscribe(slow, "alerts")
+
+		if n := h.Publish(Event{Type: "chat", Data: "hi"}); n != 2 {
+			t.Errorf("Expected chat event queued twice, got %d", n)
+		}
+		h.Publish(Event{Type: "alerts", Data: 1})
+		if n := h.Publish(Event{Type: "alerts", Data: 2}); n != 1 {
+			t.Errorf("Expected the slow subscriber to miss the event, got %d deliveries", n)
+		}
+		if len(evicted) != 1 || evicted[0] != slow || h.Len() != 2 {
+			t.Errorf("Expected the slow subscriber evicted, got %d evictions and %d subscribers", len(evicted), h.Len())
+		}
+		if !slow.closed() {
+			t.Error("Expected the evicted session closed")
+		}
+		st := h.Stats()
+		if st.Published != 3 || st.Delivered != 5 || st.Evicted != 1 || st.Subscribers != 2 {
+			t.Errorf("Unexpected stats %+v", st)
+		}
+
+		chat.Close()
+		h.Publish(Event{Type: "chat", Data: "bye"})
+		if h.Len() != 1 || h.Stats().Evicted != 1 {
+			t.Errorf("Expected a closed session removed without eviction, got %+v", h.Stats())
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		h := NewHub(HubOptions{Backlog: 10})
+		defer h.Close()
+		srv := httptest.NewServer(h.Handler(NewRenderer(settings), SSEOptions{}, func(req *http.Request) []string {
+			return req.URL.Query()["topic"]
+		}))
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL + "?topic=news")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		deadline := time.Now().Add(time.Second)
+		for h.Len() == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		h.Publish(Event{Type: "sports", Data: "goal"})
+		h.Publish(Event{Type: "news", Data: "headline"})
+
+		br := bufio.NewReader(resp.Body)
+		var frame strings.Builder
+		for !strings.Contains(frame.String(), "data:") {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Read failed: %v (got %q)", err, frame.String())
+			}
+			frame.WriteString(line)
+		}
+		if got := frame.String(); !strings.Contains(got, "id: 2\n") || !strings.Contains(got, "headline") {
+			t.Errorf("Expected only the news event with backlog ID 2, got %q", got)
+		}
+	})
+}
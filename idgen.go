@@ -0,0 +1,105 @@
+package beam
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	mrand "math/rand/v2"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// WithIDGenerator sets the function generating request IDs when ID generation
+// is enabled and no inbound ID is found, e.g. ULID or an application's
+// snowflake generator. gen must be safe for concurrent use.
+// Returns a new Renderer with the generator set; nil restores UUIDv4.
+func (r *Renderer) WithIDGenerator(gen func() string) *Renderer {
+	if gen == nil {
+		return r.WithIDGeneratorAt(nil)
+	}
+	return r.WithIDGeneratorAt(func(time.Time) string { return gen() })
+}
+
+// WithIDGeneratorAt is WithIDGenerator for time-based generators such as
+// ULIDAt: gen receives the Renderer's clock time (see WithClock), so IDs
+// follow a fixed or simulated clock.
+// Returns a new Renderer with the generator set; nil restores UUIDv4.
+func (r *Renderer) WithIDGeneratorAt(gen func(now time.Time) string) *Renderer {
+	nr := r.clone()
+	nr.idGenerator = gen
+	return nr
+}
+
+// newID returns an ID from the configured generator, or a UUIDv4.
+func (r *Renderer) newID() string {
+	if r.idGenerator != nil {
+		return r.idGenerator(r.now())
+	}
+	return UUIDv4()
+}
+
+// UUIDv4 returns a random RFC 9562 version 4 UUID, such as
+// "0b5ad3f4-8c1e-4a7e-9d2b-3f6c1e0a9b71". It is the default ID generator.
+func UUIDv4() string {
+	var u [16]byte
+	randomBytes(u[:])
+	u[6] = u[6]&0x0f | 0x40 // Version 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 variant
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// ULID returns a ULID: a 26-character, lexicographically sortable ID made of
+// a millisecond timestamp and 80 random bits, such as "01ARZ3NDEKTSV4RRFFQ69G5FAV".
+// Suits IDs that are stored and scanned in creation order; note that it
+// reveals when the ID was generated.
+func ULID() string {
+	return ULIDAt(time.Now())
+}
+
+// ULIDAt returns a ULID carrying the timestamp now. Pass it to
+// WithIDGeneratorAt so request IDs follow the Renderer's clock.
+func ULIDAt(now time.Time) string {
+	var u [16]byte
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		u[i] = byte(ms)
+		ms >>= 8
+	}
+	randomBytes(u[6:])
+
+	// Encode the 128 bits as 26 base32 digits, the first carrying 3 bits.
+	var buf [26]byte
+	hi := uint64(u[0])<<56 | uint64(u[1])<<48 | uint64(u[2])<<40 | uint64(u[3])<<32 |
+		uint64(u[4])<<24 | uint64(u[5])<<16 | uint64(u[6])<<8 | uint64(u[7])
+	lo := uint64(u[8])<<56 | uint64(u[9])<<48 | uint64(u[10])<<40 | uint64(u[11])<<32 |
+		uint64(u[12])<<24 | uint64(u[13])<<16 | uint64(u[14])<<8 | uint64(u[15])
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+// randomBytes fills b from crypto/rand, falling back to math/rand/v2 on the
+// platforms where crypto/rand can still fail, so ID generation never errors.
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err == nil {
+		return
+	}
+	for i := range b {
+		b[i] = byte(mrand.Uint32())
+	}
+}
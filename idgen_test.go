@@ -0,0 +1,96 @@
+package beam
+
+import (
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIDGenerator(t *testing.T) {
+	t.Run("UUIDv4", func(t *testing.T) {
+		pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		for i := 0; i < 100; i++ {
+			if id := UUIDv4(); !pattern.MatchString(id) {
+				t.Fatalf("Invalid UUIDv4 %q", id)
+			}
+		}
+	})
+
+	t.Run("ULID", func(t *testing.T) {
+		before := time.Now().UnixMilli()
+		id := ULID()
+		if len(id) != 26 || strings.Trim(id, crockford) != Empty || id[0] > '7' {
+			t.Fatalf("Invalid ULID %q", id)
+		}
+		var ms int64
+		for _, c := range id[:10] {
+			ms = ms<<5 | int64(strings.IndexRune(crockford, c))
+		}
+		if ms < before || ms > time.Now().UnixMilli() {
+			t.Errorf("Expected the current time in %q, got %d", id, ms)
+		}
+		if later := (func() string { time.Sleep(2 * time.Millisecond); return ULID() })(); later <= id {
+			t.Errorf("Expected %q to sort after %q", later, id)
+		}
+	})
+
+	t.Run("Unique", func(t *testing.T) {
+		var (
+			mu   sync.Mutex
+			seen = make(map[string]bool)
+			wg   sync.WaitGroup
+		)
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					u, l := UUIDv4(), ULID()
+					mu.Lock()
+					if seen[u] || seen[l] {
+						t.Errorf("Duplicate ID %q or %q", u, l)
+					}
+					seen[u], seen[l] = true, true
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("Renderer", func(t *testing.T) {
+		var id string
+		w := httptest.NewRecorder()
+		at := time.Date(2016, 7, 30, 23, 54, 10, 259e6, time.UTC)
+		r := NewRenderer(settings).WithIDGeneration(Yes).WithIDGeneratorAt(ULIDAt).WithClock(FixedClock(at)).
+			WithCallback(func(d CallbackData) { id = d.ID })
+		if err := r.Push(w, Response{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if len(id) != 26 || w.Header().Get(HeaderRequestID) != id {
+			t.Errorf("Expected a ULID echoed in %s, got %q", HeaderRequestID, id)
+		}
+		if want := ULIDAt(at)[:10]; id[:10] != want {
+			t.Errorf("Expected the ULID timestamp from the Renderer's clock %q, got %q", want, id[:10])
+		}
+
+		id = Empty
+		if err := r.WithIDGenerator(ULID).Push(httptest.NewRecorder(), Response{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if len(id) != 26 {
+			t.Errorf("Expected a ULID from a plain generator, got %q", id)
+		}
+
+		id = Empty
+		if err := r.WithIDGenerator(nil).Push(httptest.NewRecorder(), Response{}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if len(id) != 36 {
+			t.Errorf("Expected the default UUIDv4, got %q", id)
+		}
+	})
+}
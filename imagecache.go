@@ -0,0 +1,238 @@
+package beam
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"image"
+	"image/color"
+	"sync"
+	"time"
+)
+
+// HeaderNameImageCache is the header key reporting whether Image was served
+// from the image cache, as ImageCacheHit or ImageCacheMiss. The full header
+// name is prefixed like other Beam headers (e.g., "X-beam-Image-Cache").
+const HeaderNameImageCache = "Image-Cache"
+
+// Values of the Image-Cache header.
+const (
+	ImageCacheHit  = "HIT"
+	ImageCacheMiss = "MISS"
+)
+
+// DefaultImageCacheSize is the capacity of a MemoryImageCache created with a
+// non-positive size.
+const DefaultImageCacheSize = 64 << 20
+
+// errImageCache wraps image cache failures, which are logged, never returned.
+var errImageCache = errors.New("image cache failed")
+
+// ImageCache stores encoded Image outputs so repeated requests for the same
+// image skip encoding. Returned data is shared and must not be modified.
+// Implementations must be safe for concurrent use.
+type ImageCache interface {
+	// Get returns the data stored under key and whether it was found.
+	Get(key string) ([]byte, bool, error)
+
+	// Set stores data under key for ttl; a non-positive ttl never expires.
+	Set(key string, data []byte, ttl time.Duration) error
+}
+
+// MemoryImageCache is an in-memory ImageCache bounded by the total size of
+// the stored images, evicting the least recently used ones first.
+// Suitable for single-instance deployments and tests.
+type MemoryImageCache struct {
+	mu      sync.Mutex
+	max     int64
+	size    int64
+	lru     *list.List // Front is the most recently used
+	entries map[string]*list.Element
+}
+
+// imageEntry is one image stored in a MemoryImageCache.
+type imageEntry struct {
+	key     string
+	data    []byte
+	expires time.Time // Zero never expires
+}
+
+// NewMemoryImageCache creates an empty MemoryImageCache holding up to
+// maxBytes of images; a non-positive maxBytes uses DefaultImageCacheSize.
+func NewMemoryImageCache(maxBytes int64) *MemoryImageCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultImageCacheSize
+	}
+	return &MemoryImageCache{max: maxBytes, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the unexpired image stored under key.
+func (c *MemoryImageCache) Get(key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*imageEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.remove(el)
+		return nil, false, nil
+	}
+	c.lru.MoveToFront(el)
+	return e.data, true, nil
+}
+
+// Set stores data under key, evicting the least recently used images to
+// stay within capacity. Images larger than the capacity are not stored.
+// Returns nil; the in-memory cache cannot fail.
+func (c *MemoryImageCache) Set(key string, data []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	if int64(len(data)) > c.max {
+		return nil
+	}
+	e := &imageEntry{key: key, data: data}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.size += int64(len(data))
+	for c.size > c.max {
+		c.remove(c.lru.Back())
+	}
+	return nil
+}
+
+// Len returns the number of stored images.
+func (c *MemoryImageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// remove drops an entry. Requires c.mu held.
+func (c *MemoryImageCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*imageEntry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.data))
+}
+
+// WithImageCache caches the encoded outputs of Image in cache for ttl, keyed
+// by content type and a hash of the source pixels, or the key set with
+// WithImageKey. Responses report the outcome in the prefixed Image-Cache header.
+// Returns a new Renderer with the cache set; nil disables caching.
+func (r *Renderer) WithImageCache(cache ImageCache, ttl time.Duration) *Renderer {
+	nr := r.clone()
+	nr.imageCache = cache
+	nr.imageCacheTTL = ttl
+	return nr
+}
+
+// WithImageKey identifies the source of the next Image calls for the image
+// cache, such as a file path and modification time, so Image skips hashing
+// the pixels. The key must change whenever the source does.
+// Returns a new Renderer with the key set; empty restores hashing.
+func (r *Renderer) WithImageKey(key string) *Renderer {
+	nr := r.clone()
+	nr.imageKey = key
+	return nr
+}
+
+// imageCacheKey returns the cache key of img encoded as contentType, or an
+// empty string when caching is disabled.
+func (r *Renderer) imageCacheKey(contentType string, img image.Image) string {
+	if r.imageCache == nil || img == nil {
+		return Empty
+	}
+	if r.imageKey != Empty {
+		return contentType + "|key|" + r.imageKey
+	}
+	h := sha256.New()
+	hashImage(h, img)
+	return contentType + "|sha256|" + hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedImage returns the cached encoding under key and sets the Image-Cache
+// header. Cache failures are logged and treated as misses.
+func (r *Renderer) cachedImage(key string) ([]byte, bool) {
+	if key == Empty {
+		return nil, false
+	}
+	data, ok, err := r.imageCache.Get(key)
+	if err != nil && r.logger != nil {
+		warnTo(r.logger, errors.Join(errImageCache, err), "id", r.id, "key", key)
+	}
+	if err != nil || !ok {
+		r.header.Set(r.headerName(HeaderNameImageCache), ImageCacheMiss)
+		return nil, false
+	}
+	r.header.Set(r.headerName(HeaderNameImageCache), ImageCacheHit)
+	return data, true
+}
+
+// storeImage caches an encoding under key, logging failures.
+func (r *Renderer) storeImage(key string, data []byte) {
+	if key == Empty {
+		return
+	}
+	if err := r.imageCache.Set(key, data, r.imageCacheTTL); err != nil && r.logger != nil {
+		warnTo(r.logger, errors.Join(errImageCache, err), "id", r.id, "key", key)
+	}
+}
+
+// hashImage writes img's type, bounds, and pixels to h. Common image types
+// are hashed from their pixel buffers; others pixel by pixel.
+func hashImage(h hash.Hash, img image.Image) {
+	b := img.Bounds()
+	fmt.Fprintf(h, "%T %v\n", img, b)
+	switch m := img.(type) {
+	case *image.RGBA:
+		hashPix(h, m.Pix, m.Stride)
+	case *image.NRGBA:
+		hashPix(h, m.Pix, m.Stride)
+	case *image.Gray:
+		hashPix(h, m.Pix, m.Stride)
+	case *image.Paletted:
+		for _, c := range m.Palette {
+			hashColor(h, c)
+		}
+		hashPix(h, m.Pix, m.Stride)
+	case *image.YCbCr:
+		fmt.Fprint(h, m.SubsampleRatio, m.YStride, m.CStride)
+		h.Write(m.Y)
+		h.Write(m.Cb)
+		h.Write(m.Cr)
+	default:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				hashColor(h, img.At(x, y))
+			}
+		}
+	}
+}
+
+// hashColor writes c as four fixed-width channels to h.
+func hashColor(h hash.Hash, c color.Color) {
+	var px [16]byte
+	cr, cg, cb, ca := c.RGBA()
+	binary.LittleEndian.PutUint32(px[0:], cr)
+	binary.LittleEndian.PutUint32(px[4:], cg)
+	binary.LittleEndian.PutUint32(px[8:], cb)
+	binary.LittleEndian.PutUint32(px[12:], ca)
+	h.Write(px[:])
+}
+
+// hashPix writes a pixel buffer and its stride to h. The whole buffer is
+// hashed; with the bounds, it determines the pixels of sub-images too.
+func hashPix(h hash.Hash, pix []byte, stride int) {
+	fmt.Fprint(h, stride, len(pix), "\n")
+	h.Write(pix)
+}
@@ -0,0 +1,114 @@
+package beam
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// failingImageCache fails every call.
+type failingImageCache struct{}
+
+func (failingImageCache) Get(string) ([]byte, bool, error) { return nil, false, errors.New("down") }
+
+func (failingImageCache) Set(string, []byte, time.Duration) error { return errors.New("down") }
+
+func TestImageCache(t *testing.T) {
+	solid := func(c color.Color) image.Image {
+		img := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+		for i := 0; i < 16*16; i++ {
+			img.Set(i%16, i/16, c)
+		}
+		return img
+	}
+	send := func(t *testing.T, r *Renderer, contentType string, img image.Image) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).Image(contentType, img); err != nil {
+			t.Fatalf("Image failed: %v", err)
+		}
+		return w
+	}
+	header := "X-test-" + HeaderNameImageCache
+	red, blue := solid(color.NRGBA{R: 0xff, A: 0xff}), solid(color.NRGBA{B: 0xff, A: 0xff})
+
+	t.Run("HitAndMiss", func(t *testing.T) {
+		cache := NewMemoryImageCache(0)
+		r := NewRenderer(settings).WithImageCache(cache, time.Minute)
+		first := send(t, r, ContentTypeWebP, red)
+		second := send(t, r, ContentTypeWebP, solid(color.NRGBA{R: 0xff, A: 0xff}))
+		if first.Header().Get(header) != ImageCacheMiss || second.Header().Get(header) != ImageCacheHit {
+			t.Errorf("Expected MISS then HIT, got %q and %q", first.Header().Get(header), second.Header().Get(header))
+		}
+		if !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) || second.Header().Get(HeaderContentType) != ContentTypeWebP {
+			t.Error("Expected the cached encoding to be served")
+		}
+		if w := send(t, r, ContentTypeWebP, blue); w.Header().Get(header) != ImageCacheMiss {
+			t.Error("Expected other pixels to miss")
+		}
+		if w := send(t, r, ContentTypePNG, red); w.Header().Get(header) != ImageCacheMiss {
+			t.Error("Expected another format to miss")
+		}
+		if cache.Len() != 3 {
+			t.Errorf("Expected 3 cached images, got %d", cache.Len())
+		}
+	})
+
+	t.Run("SourceKey", func(t *testing.T) {
+		r := NewRenderer(settings).WithImageCache(NewMemoryImageCache(0), 0).WithImageKey("avatars/7@v1")
+		first := send(t, r, ContentTypePNG, red)
+		if w := send(t, r, ContentTypePNG, blue); w.Header().Get(header) != ImageCacheHit || !bytes.Equal(w.Body.Bytes(), first.Body.Bytes()) {
+			t.Error("Expected the source key to be trusted over the pixels")
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		logger := &TestLogger{}
+		w := send(t, NewRenderer(settings).WithLogger(logger).WithImageCache(failingImageCache{}, 0), ContentTypePNG, red)
+		if w.Header().Get(header) != ImageCacheMiss || w.Body.Len() == 0 {
+			t.Error("Expected the image encoded despite the cache failure")
+		}
+		if len(logger.Entries) != 2 || !errors.Is(logger.Entries[0].Err, errImageCache) {
+			t.Errorf("Expected both cache failures logged, got %v", logger.Entries)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		if w := send(t, NewRenderer(settings), ContentTypePNG, red); w.Header().Get(header) != Empty {
+			t.Error("Expected no cache header without a cache")
+		}
+	})
+}
+
+func TestMemoryImageCache(t *testing.T) {
+	t.Run("Eviction", func(t *testing.T) {
+		c := NewMemoryImageCache(10)
+		_ = c.Set("a", make([]byte, 4), 0)
+		_ = c.Set("b", make([]byte, 4), 0)
+		_, _, _ = c.Get("a") // b becomes the least recently used
+		_ = c.Set("c", make([]byte, 4), 0)
+		if _, ok, _ := c.Get("b"); ok {
+			t.Error("Expected b to be evicted")
+		}
+		if _, ok, _ := c.Get("a"); !ok {
+			t.Error("Expected a to be kept")
+		}
+		_ = c.Set("huge", make([]byte, 11), 0)
+		if _, ok, _ := c.Get("huge"); ok || c.Len() != 2 {
+			t.Errorf("Expected oversized images skipped, got %d entries", c.Len())
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		c := NewMemoryImageCache(0)
+		_ = c.Set("a", []byte("x"), time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		if _, ok, _ := c.Get("a"); ok || c.Len() != 0 {
+			t.Error("Expected the expired image dropped")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// metaJob is the meta key describing an asynchronous job.
+const metaJob = "job"
+
+// DefaultJobPollInterval is the poll interval suggested for jobs that set none.
+const DefaultJobPollInterval = 5 * time.Second
+
+// JobState is the lifecycle state of an asynchronous job.
+type JobState string
+
+// Job states reported by Job and JobStatus.
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+	JobCanceled  JobState = "canceled"
+)
+
+// Done reports whether the job reached a final state.
+func (s JobState) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// errJobFailed reports a failed job that carries no error of its own.
+var errJobFailed = errors.New("job failed")
+
+// Job describes an asynchronous operation for JobStatus.
+type Job struct {
+	ID           string        // Job identifier
+	State        JobState      // Lifecycle state; empty is JobQueued
+	StatusURL    string        // Endpoint reporting the job, polled while it runs
+	ResultURL    string        // Where a succeeded job's result is fetched; empty returns Result inline
+	Progress     float64       // Completion ratio between 0 and 1, if known
+	Message      string        // Human-readable message; empty uses a default per state
+	Result       interface{}   // Result sent as data once succeeded, when ResultURL is empty
+	Err          error         // Failure reason of a failed job
+	Created      time.Time     // When the job was accepted
+	Updated      time.Time     // When the job last changed
+	PollInterval time.Duration // Suggested poll interval; zero uses DefaultJobPollInterval
+}
+
+// JobInfo is the meta.job block of job responses.
+type JobInfo struct {
+	ID       string   `json:"id" xml:"id" msgpack:"id"`
+	State    JobState `json:"state" xml:"state" msgpack:"state"`
+	Progress float64  `json:"progress,omitempty" xml:"progress,omitempty" msgpack:"progress,omitempty"`
+	Created  string   `json:"created,omitempty" xml:"created,omitempty" msgpack:"created,omitempty"`
+	Updated  string   `json:"updated,omitempty" xml:"updated,omitempty" msgpack:"updated,omitempty"`
+}
+
+// Job acknowledges an accepted asynchronous job with 202 Accepted, a
+// Location header pointing at statusURL, meta.job, and a poll action, so
+// clients can follow the job without endpoint-specific conventions.
+// Returns an error if the writer is nil or sending the response fails.
+func (r *Renderer) Job(jobID, statusURL string) error {
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
+	}
+	return r.JobStatus(Job{ID: jobID, StatusURL: statusURL, Created: r.now()})
+}
+
+// JobStatus reports job, typically from its status endpoint, with meta.job.
+// A queued job is sent as pending with 202, Location, and a poll action; a
+// running job likewise with 200. A succeeded job returns its Result, or 303
+// See Other to its ResultURL; a failed or canceled job is sent as an error.
+// Returns an error if the writer is nil or sending the response fails.
+func (r *Renderer) JobStatus(job Job) error {
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
+	}
+	if job.State == Empty {
+		job.State = JobQueued
+	}
+	info := JobInfo{ID: job.ID, State: job.State, Progress: job.Progress}
+	if !job.Created.IsZero() {
+		info.Created = job.Created.UTC().Format(time.RFC3339)
+	}
+	if !job.Updated.IsZero() {
+		info.Updated = job.Updated.UTC().Format(time.RFC3339)
+	}
+	nr := r.WithMeta(metaJob, info)
+
+	switch job.State {
+	case JobSucceeded:
+		resp := Response{Status: StatusSuccessful, Message: jobMessage(job, "Job succeeded")}
+		if job.ResultURL != Empty {
+			nr = nr.WithAction(RedirectAction(job.ResultURL)).WithStatus(http.StatusSeeOther)
+			nr.header.Set("Location", job.ResultURL)
+		} else {
+			nr = nr.WithStatus(http.StatusOK)
+			resp.Data = job.Result
+		}
+		return nr.Push(nr.writer, resp)
+	case JobFailed, JobCanceled:
+		reason := job.Err
+		if reason == nil {
+			reason = fmt.Errorf("%w: %s", errJobFailed, job.State)
+		}
+		return nr.WithStatus(http.StatusOK).Push(nr.writer, Response{
+			Status:  StatusError,
+			Message: jobMessage(job, "Job "+string(job.State)),
+			Errors:  ErrorList{reason},
+		})
+	}
+
+	interval := job.PollInterval
+	if interval <= 0 {
+		interval = DefaultJobPollInterval
+	}
+	if job.State == JobQueued {
+		nr = nr.WithStatus(http.StatusAccepted)
+		if job.StatusURL != Empty {
+			nr = nr.WithPollAction(job.StatusURL, interval)
+		}
+	} else {
+		nr = nr.WithStatus(http.StatusOK)
+		if job.StatusURL != Empty {
+			nr = nr.WithAction(PollAction(job.StatusURL, interval))
+			nr.header.Set("Retry-After", strconv.Itoa(intervalSeconds(interval)))
+		}
+	}
+	return nr.Push(nr.writer, Response{
+		Status:  StatusPending,
+		Message: jobMessage(job, "Job "+string(job.State)),
+	})
+}
+
+// jobMessage returns the job's message, or def when it has none.
+func jobMessage(job Job, def string) string {
+	if job.Message != Empty {
+		return job.Message
+	}
+	return def
+}
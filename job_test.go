@@ -0,0 +1,108 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJob(t *testing.T) {
+	type jobResponse struct {
+		Status  string                   `json:"status"`
+		Message string                   `json:"message"`
+		Data    map[string]string        `json:"data"`
+		Errors  []string                 `json:"errors"`
+		Meta    map[string]JobInfo       `json:"meta"`
+		Actions []map[string]interface{} `json:"actions"`
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) jobResponse {
+		t.Helper()
+		var resp jobResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Invalid JSON %s: %v", w.Body.String(), err)
+		}
+		return resp
+	}
+	r := NewRenderer(settings)
+
+	t.Run("Accepted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).Job("j-1", "/jobs/j-1"); err != nil {
+			t.Fatalf("Job failed: %v", err)
+		}
+		if w.Code != http.StatusAccepted || w.Header().Get("Location") != "/jobs/j-1" {
+			t.Errorf("Expected 202 with Location, got %d %v", w.Code, w.Header())
+		}
+		resp := decode(t, w)
+		if job := resp.Meta[metaJob]; job.ID != "j-1" || job.State != JobQueued || job.Created == Empty {
+			t.Errorf("Unexpected meta.job %+v", job)
+		}
+		if resp.Status != StatusPending || len(resp.Actions) != 1 || resp.Actions[0]["kind"] != "poll" {
+			t.Errorf("Expected a pending response with a poll action, got %+v", resp)
+		}
+	})
+
+	t.Run("Running", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := r.WithWriter(w).JobStatus(Job{
+			ID: "j-1", State: JobRunning, StatusURL: "/jobs/j-1", Progress: 0.5, PollInterval: time.Second,
+		})
+		if err != nil {
+			t.Fatalf("JobStatus failed: %v", err)
+		}
+		if w.Code != http.StatusOK || w.Header().Get("Retry-After") != "1" || w.Header().Get("Location") != Empty {
+			t.Errorf("Expected 200 with Retry-After only, got %d %v", w.Code, w.Header())
+		}
+		if job := decode(t, w).Meta[metaJob]; job.Progress != 0.5 || job.State != JobRunning {
+			t.Errorf("Unexpected meta.job %+v", job)
+		}
+	})
+
+	t.Run("SucceededInline", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := r.WithWriter(w).JobStatus(Job{ID: "j-1", State: JobSucceeded, Result: map[string]string{"rows": "42"}})
+		if err != nil {
+			t.Fatalf("JobStatus failed: %v", err)
+		}
+		if resp := decode(t, w); w.Code != http.StatusOK || resp.Status != StatusSuccessful || resp.Data["rows"] != "42" {
+			t.Errorf("Expected the inline result, got %d %+v", w.Code, resp)
+		}
+	})
+
+	t.Run("SucceededRedirect", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).JobStatus(Job{ID: "j-1", State: JobSucceeded, ResultURL: "/reports/9"}); err != nil {
+			t.Fatalf("JobStatus failed: %v", err)
+		}
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/reports/9" {
+			t.Errorf("Expected 303 to the result, got %d %v", w.Code, w.Header())
+		}
+		if resp := decode(t, w); len(resp.Actions) != 1 || resp.Actions[0]["kind"] != "redirect" {
+			t.Errorf("Expected a redirect action, got %+v", resp.Actions)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := r.WithWriter(w).JobStatus(Job{ID: "j-1", State: JobFailed, Err: errors.New("disk full")})
+		if err != nil {
+			t.Fatalf("JobStatus failed: %v", err)
+		}
+		resp := decode(t, w)
+		if w.Code != http.StatusOK || resp.Status != StatusError || len(resp.Errors) != 1 || resp.Errors[0] != "disk full" {
+			t.Errorf("Expected the failure reason, got %d %+v", w.Code, resp)
+		}
+		if len(resp.Actions) != 0 {
+			t.Errorf("Expected no poll action for a finished job, got %v", resp.Actions)
+		}
+	})
+
+	t.Run("Done", func(t *testing.T) {
+		if JobRunning.Done() || !JobCanceled.Done() {
+			t.Error("Unexpected Done states")
+		}
+	})
+}
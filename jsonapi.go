@@ -0,0 +1,358 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ContentTypeJSONAPI is the content type for JSON:API documents.
+const ContentTypeJSONAPI = "application/vnd.api+json"
+
+// jsonAPIVersion is the JSON:API version advertised in every document.
+const jsonAPIVersion = "1.1"
+
+// errNotResource is returned when Data cannot be mapped to JSON:API resource objects.
+var errNotResource = errors.New("value is not a JSON:API resource")
+
+// JSONAPIDocument is a JSON:API top-level document.
+// Data holds a *JSONAPIResource, a []JSONAPIResource, or nil; it is omitted
+// when Errors is set, as the spec forbids both in one document.
+type JSONAPIDocument struct {
+	Data    interface{}            `json:"data,omitempty"`
+	Errors  []JSONAPIError         `json:"errors,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+	JSONAPI JSONAPIVersion         `json:"jsonapi"`
+}
+
+// JSONAPIVersion is the document's jsonapi member.
+type JSONAPIVersion struct {
+	Version string `json:"version"`
+}
+
+// JSONAPIResource is a JSON:API resource object.
+type JSONAPIResource struct {
+	Type          string                         `json:"type"`
+	ID            string                         `json:"id,omitempty"`
+	Attributes    map[string]interface{}         `json:"attributes,omitempty"`
+	Relationships map[string]JSONAPIRelationship `json:"relationships,omitempty"`
+	Meta          map[string]interface{}         `json:"meta,omitempty"`
+}
+
+// JSONAPIRelationship links a resource to others by resource identifier.
+// Data is a JSONAPIIdentifier or a []JSONAPIIdentifier.
+type JSONAPIRelationship struct {
+	Data interface{} `json:"data"`
+}
+
+// JSONAPIIdentifier identifies a resource by type and id.
+type JSONAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIError is a JSON:API error object.
+type JSONAPIError struct {
+	Status string `json:"status,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// JSONAPIResourcer is implemented by types that build their own resource object.
+type JSONAPIResourcer interface {
+	JSONAPIResource() JSONAPIResource
+}
+
+// ResourceRegistry maps Go types to JSON:API resource objects for JSONAPIEncoder.
+// Safe for concurrent use.
+type ResourceRegistry struct {
+	mu      sync.RWMutex
+	mappers map[reflect.Type]func(interface{}) JSONAPIResource
+}
+
+// NewResourceRegistry creates an empty ResourceRegistry.
+func NewResourceRegistry() *ResourceRegistry {
+	return &ResourceRegistry{mappers: make(map[reflect.Type]func(interface{}) JSONAPIResource)}
+}
+
+// DefaultResources is the registry used by JSONAPIEncoder when none is set.
+var DefaultResources = NewResourceRegistry()
+
+// MapResource registers fn as the resource mapper for values of type T in reg.
+// Mappers take precedence over JSONAPIResourcer and `jsonapi` struct tags.
+func MapResource[T any](reg *ResourceRegistry, fn func(T) JSONAPIResource) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.mappers[reflect.TypeOf((*T)(nil)).Elem()] = func(v interface{}) JSONAPIResource {
+		return fn(v.(T))
+	}
+}
+
+// lookup returns the mapper registered for t.
+func (reg *ResourceRegistry) lookup(t reflect.Type) (func(interface{}) JSONAPIResource, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	fn, ok := reg.mappers[t]
+	return fn, ok
+}
+
+// JSONAPIEncoder encodes responses as JSON:API 1.1 documents.
+// Response.Data becomes the primary data, Response.Errors the error objects,
+// and the envelope fields and Meta the top-level meta member.
+// Data values are mapped by the Resources registry, JSONAPIResourcer, or
+// `jsonapi` struct tags: `jsonapi:"primary,<type>"` marks the id field,
+// `jsonapi:"attr,<name>"` an attribute, `jsonapi:"relation,<name>"` a
+// relationship, and `jsonapi:"-"` a skipped field; other exported fields are
+// attributes named by their json tag.
+type JSONAPIEncoder struct {
+	Resources *ResourceRegistry // Resource mappers; nil uses DefaultResources
+}
+
+// Marshal encodes v as a JSON:API document.
+// Response and ProblemDetails values become documents; other values are
+// encoded as primary data.
+// Returns an error if Data cannot be mapped to resource objects.
+func (e *JSONAPIEncoder) Marshal(v interface{}) ([]byte, error) {
+	var doc JSONAPIDocument
+	var err error
+	switch v := v.(type) {
+	case JSONAPIDocument:
+		doc = v
+	case *JSONAPIDocument:
+		doc = *v
+	case Response:
+		doc, err = e.document(&v)
+	case *Response:
+		doc, err = e.document(v)
+	case ProblemDetails:
+		doc = problemDocument(v)
+	default:
+		doc.Data, err = e.primary(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc.JSONAPI.Version = jsonAPIVersion
+	return json.Marshal(doc)
+}
+
+// Unmarshal decodes a JSON:API document into v.
+// A *Response receives the meta envelope fields, errors, and the raw primary data.
+// Returns an error if decoding fails.
+func (e *JSONAPIEncoder) Unmarshal(data []byte, v interface{}) error {
+	resp, ok := v.(*Response)
+	if !ok {
+		return json.Unmarshal(data, v)
+	}
+	var doc struct {
+		Data   json.RawMessage        `json:"data"`
+		Errors []JSONAPIError         `json:"errors"`
+		Meta   map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*resp = Response{}
+	for key, field := range map[string]*string{"status": &resp.Status, "title": &resp.Title, "message": &resp.Message} {
+		if s, ok := doc.Meta[key].(string); ok {
+			*field = s
+			delete(doc.Meta, key)
+		}
+	}
+	if len(doc.Meta) > 0 {
+		resp.Meta = doc.Meta
+	}
+	if len(doc.Data) > 0 && string(doc.Data) != "null" {
+		var primary interface{}
+		if err := json.Unmarshal(doc.Data, &primary); err != nil {
+			return err
+		}
+		resp.Data = primary
+	}
+	for _, it := range doc.Errors {
+		resp.Errors = append(resp.Errors, ErrorItem{Code: it.Code, Message: it.Detail}.err())
+	}
+	return nil
+}
+
+// ContentType returns the JSON:API content type.
+// Returns the constant "application/vnd.api+json".
+func (e *JSONAPIEncoder) ContentType() string {
+	return ContentTypeJSONAPI
+}
+
+// document builds the JSON:API document for resp.
+func (e *JSONAPIEncoder) document(resp *Response) (JSONAPIDocument, error) {
+	doc := JSONAPIDocument{Meta: make(map[string]interface{}, len(resp.Meta)+3)}
+	for k, v := range resp.Meta {
+		doc.Meta[k] = v
+	}
+	doc.Meta["status"] = resp.Status
+	if resp.Title != Empty {
+		doc.Meta["title"] = resp.Title
+	}
+	if resp.Message != Empty {
+		doc.Meta["message"] = resp.Message
+	}
+	if len(resp.Errors) > 0 {
+		for _, it := range resp.Errors.items() {
+			doc.Errors = append(doc.Errors, JSONAPIError{Code: it.Code, Title: resp.Message, Detail: it.Message})
+		}
+		return doc, nil
+	}
+	data, err := e.primary(resp.Data)
+	if err != nil {
+		return doc, err
+	}
+	doc.Data = data
+	return doc, nil
+}
+
+// problemDocument converts Problem Details into JSON:API error objects.
+func problemDocument(p ProblemDetails) JSONAPIDocument {
+	status := Empty
+	if p.Status != 0 {
+		status = fmt.Sprint(p.Status)
+	}
+	doc := JSONAPIDocument{}
+	if len(p.Errors) == 0 {
+		doc.Errors = []JSONAPIError{{Status: status, Title: p.Title, Detail: p.Detail}}
+		return doc
+	}
+	for _, it := range p.Errors.items() {
+		doc.Errors = append(doc.Errors, JSONAPIError{Status: status, Code: it.Code, Title: p.Title, Detail: it.Message})
+	}
+	return doc
+}
+
+// primary maps v to primary data: a resource object, a slice of them, or nil.
+func (e *JSONAPIEncoder) primary(v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		if _, ok := v.([]byte); ok {
+			return nil, fmt.Errorf("%w: %T", errNotResource, v)
+		}
+		out := make([]JSONAPIResource, rv.Len())
+		for i := range out {
+			res, err := e.resource(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = res
+		}
+		return out, nil
+	}
+	res, err := e.resource(rv)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// resource maps one value to a resource object.
+func (e *JSONAPIEncoder) resource(rv reflect.Value) (JSONAPIResource, error) {
+	for rv.Kind() == reflect.Interface && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return JSONAPIResource{}, errNotResource
+	}
+	reg := e.Resources
+	if reg == nil {
+		reg = DefaultResources
+	}
+	if fn, ok := reg.lookup(rv.Type()); ok {
+		return fn(rv.Interface()), nil
+	}
+	switch v := rv.Interface().(type) {
+	case JSONAPIResource:
+		return v, nil
+	case *JSONAPIResource:
+		return *v, nil
+	case JSONAPIResourcer:
+		return v.JSONAPIResource(), nil
+	}
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+		if fn, ok := reg.lookup(rv.Type()); ok {
+			return fn(rv.Interface()), nil
+		}
+	}
+	if rv.Kind() == reflect.Struct {
+		if res, ok := e.tagged(rv); ok {
+			return res, nil
+		}
+	}
+	return JSONAPIResource{}, fmt.Errorf("%w: %s", errNotResource, rv.Type())
+}
+
+// tagged builds a resource from `jsonapi` struct tags.
+// Reports false when the struct has no primary field.
+func (e *JSONAPIEncoder) tagged(rv reflect.Value) (JSONAPIResource, bool) {
+	var res JSONAPIResource
+	primary := false
+	for _, f := range reflect.VisibleFields(rv.Type()) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		fv := rv.FieldByIndex(f.Index)
+		kind, name, _ := strings.Cut(f.Tag.Get("jsonapi"), ",")
+		switch kind {
+		case "-":
+			continue
+		case "primary":
+			res.Type, res.ID, primary = name, fmt.Sprint(fv.Interface()), true
+			continue
+		case "relation":
+			if rel, ok := e.relationship(fv); ok {
+				if res.Relationships == nil {
+					res.Relationships = make(map[string]JSONAPIRelationship)
+				}
+				res.Relationships[name] = rel
+			}
+			continue
+		case "attr":
+		default:
+			name, _, _ = strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == Empty {
+				name = f.Name
+			}
+		}
+		if res.Attributes == nil {
+			res.Attributes = make(map[string]interface{})
+		}
+		res.Attributes[name] = fv.Interface()
+	}
+	return res, primary
+}
+
+// relationship builds resource linkage for a related value or slice of values.
+// Reports false for nil values or values that are not resources.
+func (e *JSONAPIEncoder) relationship(fv reflect.Value) (JSONAPIRelationship, bool) {
+	if fv.Kind() == reflect.Slice {
+		ids := make([]JSONAPIIdentifier, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			res, err := e.resource(fv.Index(i))
+			if err != nil {
+				return JSONAPIRelationship{}, false
+			}
+			ids = append(ids, JSONAPIIdentifier{Type: res.Type, ID: res.ID})
+		}
+		return JSONAPIRelationship{Data: ids}, true
+	}
+	res, err := e.resource(fv)
+	if err != nil {
+		return JSONAPIRelationship{}, false
+	}
+	return JSONAPIRelationship{Data: JSONAPIIdentifier{Type: res.Type, ID: res.ID}}, true
+}
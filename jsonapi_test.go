@@ -0,0 +1,117 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type apiPerson struct {
+	ID   int    `jsonapi:"primary,people"`
+	Name string `json:"name"`
+}
+
+type apiArticle struct {
+	ID       string      `jsonapi:"primary,articles"`
+	Title    string      `jsonapi:"attr,title"`
+	Author   *apiPerson  `jsonapi:"relation,author"`
+	Comments []apiPerson `jsonapi:"relation,commenters"`
+	Secret   string      `jsonapi:"-"`
+}
+
+func TestJSONAPIEncoder(t *testing.T) {
+	t.Run("StructTags", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := NewRenderer(settings).WithContentType(ContentTypeJSONAPI).WithWriter(rec)
+		err := r.Data("article", apiArticle{
+			ID: "1", Title: "JSON:API paints my bikeshed!", Secret: "x",
+			Author:   &apiPerson{ID: 9, Name: "Dan"},
+			Comments: []apiPerson{{ID: 5}, {ID: 12}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeJSONAPI {
+			t.Errorf("Expected %s, got %s", ContentTypeJSONAPI, ct)
+		}
+		var doc struct {
+			Data    JSONAPIResource        `json:"data"`
+			Meta    map[string]interface{} `json:"meta"`
+			JSONAPI JSONAPIVersion         `json:"jsonapi"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("Invalid document: %v\n%s", err, rec.Body)
+		}
+		if doc.Data.Type != "articles" || doc.Data.ID != "1" || doc.Data.Attributes["title"] != "JSON:API paints my bikeshed!" {
+			t.Errorf("Unexpected resource %+v", doc.Data)
+		}
+		if _, ok := doc.Data.Attributes["Secret"]; ok {
+			t.Error("Expected skipped field to be omitted")
+		}
+		author, _ := doc.Data.Relationships["author"].Data.(map[string]interface{})
+		if author["type"] != "people" || author["id"] != "9" {
+			t.Errorf("Unexpected author linkage %v", doc.Data.Relationships["author"])
+		}
+		if ids, _ := doc.Data.Relationships["commenters"].Data.([]interface{}); len(ids) != 2 {
+			t.Errorf("Expected 2 commenters, got %v", doc.Data.Relationships["commenters"])
+		}
+		if doc.Meta["status"] != StatusSuccessful || doc.JSONAPI.Version != "1.1" {
+			t.Errorf("Unexpected meta %v / %v", doc.Meta, doc.JSONAPI)
+		}
+	})
+
+	t.Run("MapperAndCollection", func(t *testing.T) {
+		reg := NewResourceRegistry()
+		type tag struct{ Name string }
+		MapResource(reg, func(t tag) JSONAPIResource {
+			return JSONAPIResource{Type: "tags", ID: t.Name}
+		})
+		out, err := (&JSONAPIEncoder{Resources: reg}).Marshal(Response{Status: StatusSuccessful, Data: []tag{{"go"}, {"api"}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc struct {
+			Data []JSONAPIResource `json:"data"`
+		}
+		_ = json.Unmarshal(out, &doc)
+		if len(doc.Data) != 2 || doc.Data[1].Type != "tags" || doc.Data[1].ID != "api" {
+			t.Errorf("Unexpected data %s", out)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := NewRenderer(settings).WithContentType(ContentTypeJSONAPI).WithWriter(rec).WithProblemDetails(Yes)
+		_ = r.WithStatus(http.StatusNotFound).ErrorMsg("missing", errors.New("article 7 not found"))
+		if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeJSONAPI {
+			t.Errorf("Expected %s, got %s", ContentTypeJSONAPI, ct)
+		}
+		var doc JSONAPIDocument
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Data != nil || len(doc.Errors) != 1 || doc.Errors[0].Detail != "article 7 not found" || doc.Errors[0].Status != "400" {
+			t.Errorf("Unexpected error document %s", rec.Body)
+		}
+	})
+
+	t.Run("NotResource", func(t *testing.T) {
+		if _, err := (&JSONAPIEncoder{}).Marshal(Response{Data: map[string]int{"a": 1}}); !errors.Is(err, errNotResource) {
+			t.Errorf("Expected errNotResource, got %v", err)
+		}
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		e := &JSONAPIEncoder{}
+		out, _ := e.Marshal(Response{Status: StatusError, Message: "bad", Errors: ErrorList{errors.New("boom")}})
+		var resp Response
+		if err := e.Unmarshal(out, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != StatusError || resp.Message != "bad" || len(resp.Errors) != 1 || resp.Errors[0].Error() != "boom" {
+			t.Errorf("Unexpected round trip %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Keepalive defaults applied to zero KeepAlive fields.
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultPingTimeout  = 10 * time.Second
+	defaultMaxMissed    = 2
+)
+
+// ErrPeerGone reports that a streaming peer stopped answering keepalive pings.
+var ErrPeerGone = errors.New("peer gone")
+
+// PeerGoneError describes a dead peer detected by keepalive.
+// Matches ErrPeerGone with errors.Is and unwraps to the last ping error.
+type PeerGoneError struct {
+	Missed   int       // Consecutive unanswered pings
+	LastPong time.Time // When the peer last answered
+	Err      error     // Error returned by the last ping
+}
+
+// Error returns a description of the dead peer.
+func (e *PeerGoneError) Error() string {
+	return fmt.Sprintf("peer gone: %d pings unanswered since %s: %v", e.Missed, e.LastPong.Format(time.RFC3339), e.Err)
+}
+
+// Is reports whether target is ErrPeerGone.
+func (e *PeerGoneError) Is(target error) bool { return target == ErrPeerGone }
+
+// Unwrap returns the last ping error.
+func (e *PeerGoneError) Unwrap() error { return e.Err }
+
+// Pinger sends a protocol-level ping and waits for the matching pong.
+// WebSocket connections implement it with ping/pong control frames;
+// Ping must return once the pong arrives or ctx is done.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingerFunc adapts a function to the Pinger interface.
+type PingerFunc func(ctx context.Context) error
+
+// Ping calls f(ctx).
+func (f PingerFunc) Ping(ctx context.Context) error { return f(ctx) }
+
+// KeepAlive configures ping/pong keepalive for streams.
+// Zero fields use defaults: 30s interval, 10s timeout, and 2 missed pings.
+type KeepAlive struct {
+	Interval  time.Duration // Time between pings
+	Timeout   time.Duration // Time to wait for each pong
+	MaxMissed int           // Consecutive failures before the peer is declared gone
+}
+
+// keepAliveState pairs a Pinger with its settings.
+type keepAliveState struct {
+	pinger Pinger
+	KeepAlive
+}
+
+// WithKeepAlive pings the peer while Stream runs and detects dead peers.
+// When MaxMissed pings go unanswered the Renderer's context is canceled and the
+// Stream callback is terminated with a *PeerGoneError matching ErrPeerGone.
+// Returns a new Renderer with keepalive configured; a nil Pinger disables it.
+func (r *Renderer) WithKeepAlive(p Pinger, ka KeepAlive) *Renderer {
+	nr := r.clone()
+	if p == nil {
+		nr.keepalive = nil
+		return nr
+	}
+	if ka.Interval <= 0 {
+		ka.Interval = defaultPingInterval
+	}
+	if ka.Timeout <= 0 {
+		ka.Timeout = defaultPingTimeout
+	}
+	if ka.MaxMissed <= 0 {
+		ka.MaxMissed = defaultMaxMissed
+	}
+	nr.keepalive = &keepAliveState{pinger: p, KeepAlive: ka}
+	return nr
+}
+
+// Context returns the Renderer's context.
+// Falls back to the bound request's context, then context.Background.
+// Long-running Stream callbacks should watch it to stop when the peer goes away.
+func (r *Renderer) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	if r.request != nil {
+		return r.request.Context()
+	}
+	return context.Background()
+}
+
+// startKeepAlive starts pinging the peer and binds a cancelable context to r.
+// Returns a function that stops the pinger.
+func (r *Renderer) startKeepAlive() func() {
+	if r.keepalive == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancelCause(r.Context())
+	r.ctx = ctx
+	ka := r.keepalive
+	go func() {
+		ticker := time.NewTicker(ka.Interval)
+		defer ticker.Stop()
+		missed, last := 0, time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			pctx, done := context.WithTimeout(ctx, ka.Timeout)
+			err := ka.pinger.Ping(pctx)
+			done()
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				missed, last = 0, time.Now()
+				continue
+			}
+			if missed++; missed >= ka.MaxMissed {
+				cancel(&PeerGoneError{Missed: missed, LastPong: last, Err: err})
+				return
+			}
+		}
+	}()
+	return func() { cancel(context.Canceled) }
+}
+
+// watchPeer wraps a Stream callback so it ends with the PeerGoneError once keepalive fails.
+func (r *Renderer) watchPeer(callback func(*Renderer) (interface{}, error)) func(*Renderer) (interface{}, error) {
+	if r.keepalive == nil {
+		return callback
+	}
+	gone := func() error {
+		if cause := context.Cause(r.ctx); errors.Is(cause, ErrPeerGone) {
+			return cause
+		}
+		return nil
+	}
+	return func(nr *Renderer) (interface{}, error) {
+		if err := gone(); err != nil {
+			return nil, err
+		}
+		data, err := callback(nr)
+		if err != nil {
+			if goneErr := gone(); goneErr != nil {
+				return nil, goneErr
+			}
+		}
+		return data, err
+	}
+}
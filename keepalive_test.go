@@ -0,0 +1,68 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestKeepAlive(t *testing.T) {
+	t.Run("DeadPeer", func(t *testing.T) {
+		var pings atomic.Int32
+		pinger := PingerFunc(func(ctx context.Context) error {
+			if pings.Add(1) > 1 {
+				<-ctx.Done() // no pong
+				return ctx.Err()
+			}
+			return nil
+		})
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithContentType(ContentTypeNDJSON).
+			WithKeepAlive(pinger, KeepAlive{Interval: 5 * time.Millisecond, Timeout: 5 * time.Millisecond, MaxMissed: 2})
+
+		err := r.Stream(func(r *Renderer) (interface{}, error) {
+			select {
+			case <-r.Context().Done():
+				return nil, r.Context().Err()
+			case <-time.After(2 * time.Millisecond):
+				return map[string]int{"n": 1}, nil
+			}
+		})
+		if !errors.Is(err, ErrPeerGone) {
+			t.Fatalf("Expected ErrPeerGone, got %v", err)
+		}
+		var pg *PeerGoneError
+		if !errors.As(err, &pg) || pg.Missed != 2 || !errors.Is(pg, context.DeadlineExceeded) {
+			t.Errorf("Expected PeerGoneError after 2 missed pings, got %+v", pg)
+		}
+	})
+
+	t.Run("HealthyPeer", func(t *testing.T) {
+		var pings atomic.Int32
+		pinger := PingerFunc(func(ctx context.Context) error {
+			pings.Add(1)
+			return nil
+		})
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithContentType(ContentTypeNDJSON).
+			WithKeepAlive(pinger, KeepAlive{Interval: 2 * time.Millisecond})
+		n := 0
+		err := r.Stream(func(r *Renderer) (interface{}, error) {
+			if n++; n > 10 {
+				return nil, io.EOF
+			}
+			time.Sleep(2 * time.Millisecond)
+			return n, nil
+		})
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		if pings.Load() == 0 {
+			t.Error("Expected pings while streaming")
+		}
+	})
+}
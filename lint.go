@@ -0,0 +1,171 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// LintMaxMetaSize is the encoded meta size in bytes above which Lint reports oversized meta.
+var LintMaxMetaSize = 4 << 10
+
+// Lint rule identifiers.
+const (
+	LintErrorWithData  = "error-with-data"
+	LintEmptyMessage   = "empty-message"
+	LintUnserializable = "unserializable"
+	LintOversizedMeta  = "oversized-meta"
+	LintUnknownStatus  = "unknown-status"
+)
+
+// maxLintDepth bounds how deep Lint descends into values, guarding against cycles.
+const maxLintDepth = 32
+
+// LintIssue is a single problem found by Lint.
+type LintIssue struct {
+	Rule    string // Rule identifier, e.g. LintErrorWithData
+	Path    string // Envelope path of the offending value, e.g. "data.items[2].cb"
+	Message string // Human-readable explanation
+}
+
+// String formats the issue as "rule: path: message".
+func (i LintIssue) String() string {
+	if i.Path == Empty {
+		return i.Rule + ": " + i.Message
+	}
+	return i.Rule + ": " + i.Path + ": " + i.Message
+}
+
+// Lint inspects a response for common mistakes before clients see it.
+// Flags Data on error statuses, missing messages, values no encoder can serialize
+// (channels, functions), unknown statuses, and meta larger than LintMaxMetaSize.
+// Returns the issues found, or nil for a clean response.
+func Lint(resp Response) []LintIssue {
+	var issues []LintIssue
+	add := func(rule, path, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Rule: rule, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	failed := resp.Status == StatusError || resp.Status == StatusFatal
+	switch resp.Status {
+	case StatusSuccessful, StatusError, StatusFatal, StatusPending, StatusWarning, StatusUnknown, StatusSlow, Empty:
+	default:
+		add(LintUnknownStatus, "status", "status %q is not a beam status", resp.Status)
+	}
+	if failed && !lintEmpty(resp.Data) {
+		add(LintErrorWithData, "data", "data set on %s response", resp.Status)
+	}
+	if failed && resp.Message == Empty && len(resp.Errors) == 0 {
+		add(LintEmptyMessage, "message", "error response has neither message nor errors")
+	}
+	if !failed && resp.Message == Empty && lintEmpty(resp.Data) && lintEmpty(resp.Info) {
+		add(LintEmptyMessage, "message", "response carries no message, info, or data")
+	}
+
+	for _, field := range []struct {
+		path  string
+		value interface{}
+	}{{"info", resp.Info}, {"data", resp.Data}, {"meta", resp.Meta}} {
+		lintValue(reflect.ValueOf(field.value), field.path, 0, func(path string, kind reflect.Kind) {
+			add(LintUnserializable, path, "%s values cannot be encoded", kind)
+		})
+	}
+
+	if len(resp.Meta) > 0 {
+		if b, err := json.Marshal(resp.Meta); err == nil && len(b) > LintMaxMetaSize {
+			add(LintOversizedMeta, "meta", "meta is %d bytes, limit %d", len(b), LintMaxMetaSize)
+		}
+	}
+	return issues
+}
+
+// lintEmpty reports whether v is nil or an empty slice or map.
+func lintEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// lintValue walks rv and reports values of kinds no encoder can serialize.
+func lintValue(rv reflect.Value, path string, depth int, report func(string, reflect.Kind)) {
+	if !rv.IsValid() || depth > maxLintDepth {
+		return
+	}
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		if rv.Kind() != reflect.Func || !rv.IsNil() {
+			report(path, rv.Kind())
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !rv.IsNil() {
+			lintValue(rv.Elem(), path, depth+1, report)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			lintValue(rv.Index(i), path+"["+strconv.Itoa(i)+"]", depth+1, report)
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		// Report in key order so results are stable.
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortFunc(order, func(a, b int) int { return strings.Compare(names[a], names[b]) })
+		for _, i := range order {
+			lintValue(rv.MapIndex(keys[i]), path+"."+names[i], depth+1, report)
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" {
+				continue
+			}
+			lintValue(rv.Field(i), path+"."+f.Name, depth+1, report)
+		}
+	}
+}
+
+// WithLint runs Lint on every Push response before it is encoded.
+// Intended for development: issues are logged (warn level when supported) and
+// reported to callbacks with StatusWarning; the response is still sent.
+// Returns a new Renderer with linting enabled or disabled.
+func (r *Renderer) WithLint(enabled State) *Renderer {
+	nr := r.clone()
+	nr.lint = enabled
+	return nr
+}
+
+// lintResponse reports Lint issues for resp when linting is enabled.
+func (r *Renderer) lintResponse(resp *Response) {
+	if !r.lint.Enabled() {
+		return
+	}
+	for _, issue := range Lint(*resp) {
+		err := errors.New(issue.String())
+		if r.logger != nil {
+			warnTo(r.logger, err, "id", r.id, "rule", issue.Rule, "path", issue.Path)
+		}
+		r.emit(newCallbackData(r.id, StatusWarning, issue.String(), err))
+	}
+}
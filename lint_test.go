@@ -0,0 +1,73 @@
+package beam
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	rules := func(issues []LintIssue) string {
+		var out []string
+		for _, i := range issues {
+			out = append(out, i.Rule+"@"+i.Path)
+		}
+		return strings.Join(out, ",")
+	}
+
+	t.Run("Clean", func(t *testing.T) {
+		if issues := Lint(Response{Status: StatusSuccessful, Message: "ok", Data: []int{1}}); issues != nil {
+			t.Errorf("Expected no issues, got %v", issues)
+		}
+	})
+
+	t.Run("ErrorWithData", func(t *testing.T) {
+		got := rules(Lint(Response{Status: StatusError, Data: map[string]int{"a": 1}}))
+		if got != "error-with-data@data,empty-message@message" {
+			t.Errorf("Unexpected issues %q", got)
+		}
+	})
+
+	t.Run("Unserializable", func(t *testing.T) {
+		type payload struct {
+			Items []interface{}
+			Skip  func() `json:"-"`
+		}
+		got := rules(Lint(Response{
+			Status:  StatusSuccessful,
+			Message: "ok",
+			Data:    payload{Items: []interface{}{1, make(chan int)}, Skip: func() {}},
+			Meta:    map[string]interface{}{"cb": func() {}},
+		}))
+		if got != "unserializable@data.Items[1],unserializable@meta.cb" {
+			t.Errorf("Unexpected issues %q", got)
+		}
+	})
+
+	t.Run("OversizedMetaAndStatus", func(t *testing.T) {
+		got := rules(Lint(Response{Status: "done", Message: "ok", Meta: map[string]interface{}{"blob": strings.Repeat("x", LintMaxMetaSize)}}))
+		if got != "unknown-status@status,oversized-meta@meta" {
+			t.Errorf("Unexpected issues %q", got)
+		}
+	})
+
+	t.Run("PushHook", func(t *testing.T) {
+		logger := &TestLogger{}
+		var warnings []string
+		r := NewRenderer(settings).WithLogger(logger).WithLint(Yes).WithCallback(func(d CallbackData) {
+			if d.Status == StatusWarning {
+				warnings = append(warnings, d.Message)
+			}
+		})
+		w := httptest.NewRecorder()
+		if err := r.Push(w, Response{Status: StatusError, Message: "bad", Data: "oops"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if len(warnings) != 1 || !strings.HasPrefix(warnings[0], LintErrorWithData) {
+			t.Errorf("Expected one lint warning, got %v", warnings)
+		}
+		if len(logger.Entries) == 0 {
+			t.Error("Expected lint issue to be logged")
+		}
+	})
+}
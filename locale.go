@@ -0,0 +1,132 @@
+package beam
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// metaLocale is the meta key describing the response language.
+const metaLocale = "locale"
+
+// LocaleInfo is the meta.locale block of a localized response.
+type LocaleInfo struct {
+	Locale    string   `json:"locale" xml:"locale" msgpack:"locale"`
+	Available []string `json:"available" xml:"available" msgpack:"available"`
+}
+
+// WithLocales enables localization with the locales the application
+// translates into, the first being the default. Responses then carry
+// Content-Language, Vary: Accept-Language, and meta.locale, so shared caches
+// keep each language apart.
+// Returns a new Renderer with the locales set; none disables localization.
+func (r *Renderer) WithLocales(available ...string) *Renderer {
+	nr := r.clone()
+	nr.locales = slices.Clone(available)
+	return nr
+}
+
+// WithLocale sets the response language explicitly, e.g. from a user profile,
+// instead of negotiating it from Accept-Language.
+// Returns a new Renderer with the locale set; Empty restores negotiation.
+func (r *Renderer) WithLocale(locale string) *Renderer {
+	nr := r.clone()
+	nr.locale = locale
+	return nr
+}
+
+// Locale returns the language of the response: the locale set with
+// WithLocale, or the best match for the bound request's Accept-Language
+// among the WithLocales locales, falling back to the first of them.
+// Returns Empty when localization is not enabled.
+func (r *Renderer) Locale() string {
+	if r.locale != Empty {
+		return r.locale
+	}
+	if len(r.locales) == 0 {
+		return Empty
+	}
+	if r.request != nil {
+		if l := negotiateLocale(r.request.Header.Values("Accept-Language"), r.locales); l != Empty {
+			return l
+		}
+	}
+	return r.locales[0]
+}
+
+// localize sets Content-Language and Vary: Accept-Language.
+func (r *Renderer) localize() {
+	locale := r.Locale()
+	if locale == Empty {
+		return
+	}
+	r.header.Set("Content-Language", locale)
+	if len(r.locales) > 0 {
+		addVary(r.header, "Accept-Language")
+	}
+}
+
+// markLocale adds meta.locale to a localized response.
+func (r *Renderer) markLocale(resp *Response) {
+	locale := r.Locale()
+	if locale == Empty {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaLocale] = LocaleInfo{Locale: locale, Available: slices.Clone(r.locales)}
+}
+
+// negotiateLocale picks the available locale best matching the Accept-Language
+// values, honoring client order and q weights. A range matches a locale
+// exactly, by primary language ("de" and "de-AT" match "de-DE"), or via "*".
+// Returns Empty if nothing is acceptable.
+func negotiateLocale(accept []string, available []string) string {
+	type langRange struct {
+		tag    string
+		weight float64
+	}
+	var ranges []langRange
+	for _, v := range accept {
+		for _, item := range strings.Split(v, ",") {
+			tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			tag = strings.TrimSpace(tag)
+			if tag == Empty {
+				continue
+			}
+			weight := 1.0
+			if k, val, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					weight = f
+				}
+			}
+			if weight > 0 {
+				ranges = append(ranges, langRange{tag, weight})
+			}
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b langRange) int { return cmp.Compare(b.weight, a.weight) })
+
+	primary := func(tag string) string {
+		base, _, _ := strings.Cut(tag, "-")
+		return base
+	}
+	for _, rg := range ranges {
+		if rg.tag == "*" {
+			return available[0]
+		}
+		for _, l := range available {
+			if strings.EqualFold(l, rg.tag) {
+				return l
+			}
+		}
+		for _, l := range available {
+			if strings.EqualFold(primary(l), primary(rg.tag)) {
+				return l
+			}
+		}
+	}
+	return Empty
+}
@@ -0,0 +1,83 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	available := []string{"en-US", "de-DE", "fr"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"de-DE", "de-DE"},
+		{"de-at, en;q=0.5", "de-DE"},
+		{"fr-CA;q=0.4, en-GB;q=0.8", "en-US"},
+		{"es, *;q=0.1", "en-US"},
+		{"es, it", ""},
+		{"de;q=0, fr", "fr"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateLocale([]string{tt.accept}, available); got != tt.want {
+			t.Errorf("negotiateLocale(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestLocale(t *testing.T) {
+	r := NewRenderer(settings).WithLocales("en", "de")
+	serve := func(r *Renderer, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if accept != Empty {
+			req.Header.Set("Accept-Language", accept)
+		}
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).WithRequest(req).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("Negotiated", func(t *testing.T) {
+		w := serve(r, "de-CH, en;q=0.8")
+		if got := w.Header().Get("Content-Language"); got != "de" {
+			t.Errorf("Expected Content-Language de, got %q", got)
+		}
+		if got := w.Header().Get("Vary"); got != "Accept-Language" {
+			t.Errorf("Expected Vary: Accept-Language, got %q", got)
+		}
+		var resp struct {
+			Meta map[string]LocaleInfo `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		info := resp.Meta[metaLocale]
+		if info.Locale != "de" || len(info.Available) != 2 {
+			t.Errorf("Unexpected meta.locale %+v", info)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		if got := serve(r, "ja").Header().Get("Content-Language"); got != "en" {
+			t.Errorf("Expected the default locale, got %q", got)
+		}
+	})
+
+	t.Run("Forced", func(t *testing.T) {
+		if got := serve(r.WithLocale("de"), "en").Header().Get("Content-Language"); got != "de" {
+			t.Errorf("Expected the forced locale, got %q", got)
+		}
+	})
+
+	t.Run("Inactive", func(t *testing.T) {
+		w := serve(NewRenderer(settings), "de")
+		if w.Header().Get("Content-Language") != Empty || w.Header().Get("Vary") != Empty {
+			t.Errorf("Expected no localization headers, got %v", w.Header())
+		}
+	})
+}
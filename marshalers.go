@@ -0,0 +1,231 @@
+package beam
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// maxMarshalerDepth bounds how deep applyTypeMarshalers descends, guarding against cycles.
+const maxMarshalerDepth = 32
+
+// typeMarshalers holds the process-wide type marshaler registry.
+// Consulted by every encoder before marshaling so domain types render consistently.
+var typeMarshalers = struct {
+	mu    sync.RWMutex
+	fns   map[reflect.Type]func(interface{}) interface{}
+	count atomic.Int32
+}{fns: make(map[reflect.Type]func(interface{}) interface{})}
+
+// RegisterTypeMarshaler registers a conversion applied to every value of type T before encoding.
+// Lets domain types (decimals, UUIDs, null wrappers) render the same way in every
+// format without implementing per-encoder marshal methods on each struct.
+// Registering a nil fn removes the conversion for T.
+func RegisterTypeMarshaler[T any](fn func(T) any) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	typeMarshalers.mu.Lock()
+	defer typeMarshalers.mu.Unlock()
+	if fn == nil {
+		delete(typeMarshalers.fns, t)
+	} else {
+		typeMarshalers.fns[t] = func(v interface{}) interface{} { return fn(v.(T)) }
+	}
+	typeMarshalers.count.Store(int32(len(typeMarshalers.fns)))
+}
+
+// lookupTypeMarshaler returns the conversion registered for t, if any.
+func lookupTypeMarshaler(t reflect.Type) (func(interface{}) interface{}, bool) {
+	typeMarshalers.mu.RLock()
+	defer typeMarshalers.mu.RUnlock()
+	fn, ok := typeMarshalers.fns[t]
+	return fn, ok
+}
+
+// applyTypeMarshalers replaces registered types anywhere within v.
+// Containers keep their original type where the converted values still fit,
+// so encoders that special-case Response or Event continue to recognize them.
+// Returns v unchanged when no marshalers are registered or nothing matched.
+func applyTypeMarshalers(v interface{}) interface{} {
+	if v == nil || typeMarshalers.count.Load() == 0 {
+		return v
+	}
+	out, changed := convertValue(reflect.ValueOf(v), 0)
+	if !changed {
+		return v
+	}
+	return out.Interface()
+}
+
+// convertValue walks rv and applies registered marshalers.
+// Returns the converted value and whether anything changed.
+func convertValue(rv reflect.Value, depth int) (reflect.Value, bool) {
+	if !rv.IsValid() || depth > maxMarshalerDepth {
+		return rv, false
+	}
+	if fn, ok := lookupTypeMarshaler(rv.Type()); ok && rv.CanInterface() {
+		return reflect.ValueOf(fn(rv.Interface())), true
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if rv.IsNil() {
+			return rv, false
+		}
+		inner, changed := convertValue(rv.Elem(), depth+1)
+		if !changed {
+			return rv, false
+		}
+		return inner, true
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return rv, false
+		}
+		elemType := rv.Type().Elem()
+		if elemType.Kind() == reflect.Uint8 {
+			return rv, false
+		}
+		items := make([]reflect.Value, rv.Len())
+		changed := false
+		fits := true
+		for i := range items {
+			item, c := convertValue(rv.Index(i), depth+1)
+			items[i] = item
+			if c {
+				changed = true
+				fits = fits && valueFits(item, elemType)
+			}
+		}
+		if !changed {
+			return rv, false
+		}
+		if !fits {
+			elemType = anyType
+		}
+		out := reflect.MakeSlice(reflect.SliceOf(elemType), len(items), len(items))
+		for i, item := range items {
+			setValue(out.Index(i), item)
+		}
+		return out, true
+
+	case reflect.Map:
+		if rv.IsNil() {
+			return rv, false
+		}
+		type entry struct{ key, value reflect.Value }
+		entries := make([]entry, 0, rv.Len())
+		elemType := rv.Type().Elem()
+		changed := false
+		fits := true
+		iter := rv.MapRange()
+		for iter.Next() {
+			value, c := convertValue(iter.Value(), depth+1)
+			if c {
+				changed = true
+				fits = fits && valueFits(value, elemType)
+			}
+			entries = append(entries, entry{iter.Key(), value})
+		}
+		if !changed {
+			return rv, false
+		}
+		if !fits {
+			elemType = anyType
+		}
+		out := reflect.MakeMapWithSize(reflect.MapOf(rv.Type().Key(), elemType), len(entries))
+		for _, e := range entries {
+			slot := reflect.New(elemType).Elem()
+			setValue(slot, e.value)
+			out.SetMapIndex(e.key, slot)
+		}
+		return out, true
+
+	case reflect.Struct:
+		return convertStruct(rv, depth)
+	}
+	return rv, false
+}
+
+// convertStruct applies marshalers to the exported fields of a struct.
+// The struct keeps its type when every converted field still fits; otherwise an
+// equivalent struct is built with the affected fields widened to interface{},
+// preserving field order and tags.
+func convertStruct(rv reflect.Value, depth int) (reflect.Value, bool) {
+	t := rv.Type()
+	values := make([]reflect.Value, t.NumField())
+	changed := false
+	fits := true
+	for i := range values {
+		values[i] = rv.Field(i)
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		value, c := convertValue(rv.Field(i), depth+1)
+		if c {
+			values[i] = value
+			changed = true
+			fits = fits && valueFits(value, t.Field(i).Type)
+		}
+	}
+	if !changed {
+		return rv, false
+	}
+
+	if fits {
+		out := reflect.New(t).Elem()
+		out.Set(rv)
+		for i, value := range values {
+			if t.Field(i).IsExported() {
+				setValue(out.Field(i), value)
+			}
+		}
+		return out, true
+	}
+
+	fields := make([]reflect.StructField, 0, t.NumField())
+	index := make([]int, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		f.Index, f.Offset = nil, 0
+		if !valueFits(values[i], f.Type) {
+			f.Type = anyType
+			f.Anonymous = false
+		}
+		if f.Anonymous && f.Type.NumMethod() > 0 {
+			// StructOf cannot promote methods; keep the field named instead.
+			f.Anonymous = false
+		}
+		fields = append(fields, f)
+		index = append(index, i)
+	}
+	out := reflect.New(reflect.StructOf(fields)).Elem()
+	for j, i := range index {
+		setValue(out.Field(j), values[i])
+	}
+	return out, true
+}
+
+// anyType is the reflect.Type of interface{}.
+var anyType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// valueFits reports whether v can be stored in a slot of type t.
+func valueFits(v reflect.Value, t reflect.Type) bool {
+	if !v.IsValid() {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			return true
+		}
+		return false
+	}
+	return v.Type().AssignableTo(t)
+}
+
+// setValue stores v in slot, leaving the zero value for invalid (nil) results.
+func setValue(slot, v reflect.Value) {
+	if v.IsValid() {
+		slot.Set(v)
+	}
+}
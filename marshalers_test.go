@@ -0,0 +1,55 @@
+package beam
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testMoney struct {
+	cents int64
+}
+
+func TestRegisterTypeMarshaler(t *testing.T) {
+	RegisterTypeMarshaler(func(m testMoney) any {
+		return fmt.Sprintf("$%d.%02d", m.cents/100, m.cents%100)
+	})
+	defer RegisterTypeMarshaler[testMoney](nil)
+
+	type order struct {
+		ID    int         `json:"id"`
+		Total testMoney   `json:"total"`
+		Items []testMoney `json:"items"`
+	}
+	o := order{ID: 1, Total: testMoney{cents: 500}, Items: []testMoney{{cents: 100}}}
+
+	t.Run("JSON", func(t *testing.T) {
+		out, err := NewRenderer(settings).encoders.Encode(ContentTypeJSON, o)
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+		expected := `{"id":1,"total":"$5.00","items":["$1.00"]}`
+		if string(out) != expected {
+			t.Errorf("Expected %s, got %s", expected, out)
+		}
+	})
+
+	t.Run("NestedInResponse", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithWriter(tw)
+		if err := r.Info("ok", map[string]interface{}{"price": testMoney{cents: 200}}); err != nil {
+			t.Fatalf("Info failed: %v", err)
+		}
+		if !strings.Contains(tw.Buffer.String(), `"price":"$2.00"`) {
+			t.Errorf("Expected converted value in response, got %s", tw.Buffer.String())
+		}
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		v := struct{ N int }{N: 3}
+		if got := applyTypeMarshalers(v); got != v {
+			t.Errorf("Expected value to pass through unchanged, got %v", got)
+		}
+	})
+}
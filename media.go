@@ -0,0 +1,376 @@
+package beam
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRangeNotSatisfiable is returned by Binary and Media after they answer a
+// Range request that does not overlap the content with 416, so handlers can
+// tell the empty response from a served body.
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// Range-related errors.
+// Returned when a Range header cannot be parsed or the media source is unusable.
+var (
+	errInvalidRange     = errors.New("invalid range")
+	errUnsupportedMedia = errors.New("unsupported media source; must be a path, io.ReadSeeker, or io.Reader")
+)
+
+// maxRanges bounds the ranges served from one Range header; larger requests get the full body.
+const maxRanges = 100
+
+// httpRange is a byte range of a representation.
+type httpRange struct {
+	start, length int64
+}
+
+// contentRange formats the Content-Range header value for the range.
+func (hr httpRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", hr.start, hr.start+hr.length-1, size)
+}
+
+// parseRange parses a Range header per RFC 7233 for content of the given size.
+// Supports "bytes=a-b", open-ended "bytes=a-", and suffix "bytes=-n" specs, comma separated.
+// Returns nil for an empty header, errInvalidRange for malformed input,
+// and ErrRangeNotSatisfiable when no spec overlaps the content.
+func parseRange(s string, size int64) ([]httpRange, error) {
+	if s == Empty {
+		return nil, nil
+	}
+	const b = "bytes="
+	if !strings.HasPrefix(s, b) {
+		return nil, errInvalidRange
+	}
+	var ranges []httpRange
+	noOverlap := false
+	for _, ra := range strings.Split(s[len(b):], ",") {
+		ra = strings.TrimSpace(ra)
+		if ra == Empty {
+			continue
+		}
+		startStr, endStr, ok := strings.Cut(ra, "-")
+		if !ok {
+			return nil, errInvalidRange
+		}
+		startStr, endStr = strings.TrimSpace(startStr), strings.TrimSpace(endStr)
+		var r httpRange
+		if startStr == Empty {
+			// Suffix range: the final n bytes.
+			if endStr == Empty {
+				return nil, errInvalidRange
+			}
+			n, err := strconv.ParseInt(endStr, 10, 64)
+			if err != nil || n < 0 {
+				return nil, errInvalidRange
+			}
+			if n == 0 {
+				noOverlap = true
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r.start = size - n
+			r.length = n
+		} else {
+			i, err := strconv.ParseInt(startStr, 10, 64)
+			if err != nil || i < 0 {
+				return nil, errInvalidRange
+			}
+			if i >= size {
+				noOverlap = true
+				continue
+			}
+			r.start = i
+			if endStr == Empty {
+				r.length = size - r.start
+			} else {
+				j, err := strconv.ParseInt(endStr, 10, 64)
+				if err != nil || r.start > j {
+					return nil, errInvalidRange
+				}
+				if j >= size {
+					j = size - 1
+				}
+				r.length = j - r.start + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	if noOverlap && len(ranges) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+	return ranges, nil
+}
+
+// serveRange applies the bound request's Range header to an in-memory body.
+// The header is taken from rangeHeader, which gates it on method and If-Range. Sets Accept-Ranges, and for satisfiable ranges the 206 status with either a
+// Content-Range header (one range) or a multipart/byteranges body (several, merged where they overlap).
+// Like net/http, headers with over maxRanges ranges or summing past the content are ignored.
+// Returns the body and content type to send, whether a range was served, and
+// ErrRangeNotSatisfiable after preparing a 416 response.
+func (r *Renderer) serveRange(data []byte, contentType string) ([]byte, string, bool, error) {
+	if r.request == nil || r.code != http.StatusOK {
+		return data, contentType, false, nil
+	}
+	r.header.Set("Accept-Ranges", "bytes")
+	size := int64(len(data))
+	ranges, err := parseRange(r.rangeHeader(time.Time{}), size)
+	switch {
+	case errors.Is(err, ErrRangeNotSatisfiable):
+		r.header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+		r.code = http.StatusRequestedRangeNotSatisfiable
+		return nil, contentType, true, err
+	case err != nil || len(ranges) == 0 || len(ranges) > maxRanges || sumRanges(ranges) > size:
+		// Malformed Range headers are ignored and the full body is sent, as
+		// are ones asking for more bytes than the content holds, which would
+		// otherwise amplify the response.
+		return data, contentType, false, nil
+	}
+	ranges = coalesceRanges(ranges)
+	if len(ranges) == 1 {
+		ra := ranges[0]
+		r.header.Set("Content-Range", ra.contentRange(size))
+		r.code = http.StatusPartialContent
+		return data[ra.start : ra.start+ra.length], contentType, true, nil
+	}
+	body, boundary := byteranges(data, ranges, contentType)
+	r.code = http.StatusPartialContent
+	return body, "multipart/byteranges; boundary=" + boundary, true, nil
+}
+
+// rangeHeader returns the bound request's Range header when it applies: only
+// plain 200 responses to GET and HEAD are ranged, and an If-Range precondition
+// must still match the response's strong ETag or, for a date, modTime.
+// Returns an empty string when the full body should be sent.
+func (r *Renderer) rangeHeader(modTime time.Time) string {
+	if r.request == nil || r.code != http.StatusOK {
+		return Empty
+	}
+	if m := r.request.Method; m != http.MethodGet && m != http.MethodHead {
+		return Empty
+	}
+	if ir := r.request.Header.Get("If-Range"); ir != Empty && !r.ifRangeMatches(ir, modTime) {
+		return Empty
+	}
+	return r.request.Header.Get("Range")
+}
+
+// ifRangeMatches reports whether an If-Range value still describes the response.
+// Entity tags use strong comparison against the ETag header, so weak tags never
+// match; dates must equal modTime at the one-second resolution of HTTP dates.
+func (r *Renderer) ifRangeMatches(ir string, modTime time.Time) bool {
+	if strings.HasPrefix(ir, `"`) || strings.HasPrefix(ir, "W/") {
+		etag := r.header.Get("ETag")
+		return strings.HasPrefix(ir, `"`) && strings.HasPrefix(etag, `"`) && ir == etag
+	}
+	t, err := http.ParseTime(ir)
+	return err == nil && !modTime.IsZero() && modTime.Truncate(time.Second).Equal(t)
+}
+
+// sumRanges returns the total length of ranges.
+func sumRanges(ranges []httpRange) int64 {
+	var n int64
+	for _, ra := range ranges {
+		n += ra.length
+	}
+	return n
+}
+
+// coalesceRanges sorts ranges and merges those that overlap or touch.
+func coalesceRanges(ranges []httpRange) []httpRange {
+	slices.SortFunc(ranges, func(a, b httpRange) int { return cmp.Compare(a.start, b.start) })
+	out := ranges[:1]
+	for _, ra := range ranges[1:] {
+		last := &out[len(out)-1]
+		if end := last.start + last.length; ra.start <= end {
+			last.length = max(end, ra.start+ra.length) - last.start
+			continue
+		}
+		out = append(out, ra)
+	}
+	return out
+}
+
+// byteranges builds a multipart/byteranges body holding each range of data.
+// Returns the body and its multipart boundary.
+func byteranges(data []byte, ranges []httpRange, contentType string) ([]byte, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	size := int64(len(data))
+	for _, ra := range ranges {
+		part, _ := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":  {contentType},
+			"Content-Range": {ra.contentRange(size)},
+		})
+		_, _ = part.Write(data[ra.start : ra.start+ra.length])
+	}
+	_ = mw.Close()
+	return buf.Bytes(), mw.Boundary()
+}
+
+// Media serves audio, video, or other large media tuned for browser playback.
+// Accepts a file path, an io.ReadSeeker, or a plain io.Reader as src.
+// Advertises Accept-Ranges, answers GET and HEAD Range requests with 206 Partial
+// Content (or 416 when unsatisfiable) unless If-Range no longer matches, marks the body no-transform so it is not
+// compressed, and answers HEAD requests with headers only.
+// The content type is inferred from the path or content when empty.
+// A missing file sends the WithImageFallback image, when configured.
+// Returns ErrRangeNotSatisfiable after a 416, or an error if the source cannot
+// be opened or writing fails.
+func (r *Renderer) Media(src interface{}, contentType string) error {
+	nr := r.clone()
+	nr.start = nr.now()
+	w := nr.writer
+	if err := nr.checkOutput(w); err != nil {
+		return err
+	}
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
+	}
+
+	var (
+		rd      io.Reader
+		seeker  io.ReadSeeker
+		size    int64 = -1
+		modTime time.Time
+	)
+	switch s := src.(type) {
+	case string:
+		f, err := os.Open(s)
+		if errors.Is(err, fs.ErrNotExist) && nr.imageFallback != nil {
+			return nr.MissingImage()
+		}
+		if err != nil {
+			nr.triggerCallbacks(nr.id, StatusError, err.Error(), err)
+			if nr.finalizer != nil {
+				nr.finalizer(w, err)
+			}
+			return err
+		}
+		defer f.Close()
+		if info, err := f.Stat(); err == nil {
+			size = info.Size()
+			modTime = info.ModTime()
+		}
+		if contentType == Empty {
+			contentType = mime.TypeByExtension(filepath.Ext(s))
+		}
+		rd, seeker = f, f
+	case io.ReadSeeker:
+		end, err := s.Seek(0, io.SeekEnd)
+		if err == nil {
+			if _, err = s.Seek(0, io.SeekStart); err == nil {
+				size = end
+			}
+		}
+		rd, seeker = s, s
+	case io.Reader:
+		rd = s
+	default:
+		err := errors.Join(errUnsupportedMedia, fmt.Errorf("%T", src))
+		nr.triggerCallbacks(nr.id, StatusFatal, err.Error(), err)
+		if nr.finalizer != nil {
+			nr.finalizer(w, err)
+		}
+		return err
+	}
+
+	if contentType == Empty && seeker != nil {
+		var sniff [512]byte
+		n, _ := io.ReadFull(seeker, sniff[:])
+		contentType = http.DetectContentType(sniff[:n])
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return errors.Join(errWriteFailed, err)
+		}
+	}
+	if contentType == Empty {
+		contentType = ContentTypeBinary
+	}
+
+	nr.header.Set("Cache-Control", "no-transform")
+	if !modTime.IsZero() {
+		nr.header.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	}
+
+	if nr.code == 0 {
+		nr.code = http.StatusOK
+	}
+	length := size
+	if seeker != nil && size >= 0 {
+		nr.header.Set("Accept-Ranges", "bytes")
+		ranges, err := parseRange(nr.rangeHeader(modTime), size)
+		switch {
+		case errors.Is(err, ErrRangeNotSatisfiable):
+			nr.header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+			nr.code = http.StatusRequestedRangeNotSatisfiable
+			if hdrErr := nr.applyCommonHeaders(w, contentType); hdrErr != nil {
+				return errors.Join(errHeaderWriteFailed, hdrErr)
+			}
+			nr.triggerCallbacks(nr.id, StatusError, err.Error(), err)
+			return err
+		case err == nil && len(ranges) == 1:
+			// Serve a single range; multiple ranges fall back to the full body.
+			ra := ranges[0]
+			if _, err := seeker.Seek(ra.start, io.SeekStart); err != nil {
+				wrapped := errors.Join(errWriteFailed, err)
+				nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+				return wrapped
+			}
+			nr.header.Set("Content-Range", ra.contentRange(size))
+			nr.code = http.StatusPartialContent
+			length = ra.length
+		}
+	}
+	if length >= 0 {
+		nr.header.Set("Content-Length", strconv.FormatInt(length, 10))
+	}
+
+	if err := nr.applyCommonHeaders(w, contentType); err != nil {
+		wrapped := errors.Join(errHeaderWriteFailed, err)
+		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+		if nr.finalizer != nil {
+			nr.finalizer(w, wrapped)
+		}
+		return wrapped
+	}
+
+	if nr.request != nil && nr.request.Method == http.MethodHead {
+		nr.triggerCallbacks(nr.id, StatusSuccessful, "Media headers sent", nil)
+		return nil
+	}
+
+	var err error
+	if length >= 0 {
+		_, err = io.CopyN(w, rd, length)
+	} else {
+		_, err = io.Copy(w, rd)
+	}
+	if err != nil {
+		wrapped := errors.Join(errWriteFailed, err)
+		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+		if nr.finalizer != nil {
+			nr.finalizer(w, wrapped)
+		}
+		return wrapped
+	}
+
+	nr.triggerCallbacks(nr.id, StatusSuccessful, "Media sent", nil)
+	return nil
+}
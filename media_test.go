@@ -0,0 +1,256 @@
+package beam
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header  string
+		ranges  []httpRange
+		wantErr error
+	}{
+		{"", nil, nil},
+		{"bytes=0-4", []httpRange{{0, 5}}, nil},
+		{"bytes=5-", []httpRange{{5, 5}}, nil},
+		{"bytes=-3", []httpRange{{7, 3}}, nil},
+		{"bytes=0-1,4-5", []httpRange{{0, 2}, {4, 2}}, nil},
+		{"bytes=8-100", []httpRange{{8, 2}}, nil},
+		{"bytes=20-30", nil, ErrRangeNotSatisfiable},
+		{"items=0-1", nil, errInvalidRange},
+		{"bytes=5-2", nil, errInvalidRange},
+	}
+	for _, tt := range tests {
+		got, err := parseRange(tt.header, 10)
+		if err != tt.wantErr {
+			t.Errorf("%q: expected error %v, got %v", tt.header, tt.wantErr, err)
+			continue
+		}
+		if len(got) != len(tt.ranges) {
+			t.Errorf("%q: expected %v, got %v", tt.header, tt.ranges, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.ranges[i] {
+				t.Errorf("%q: expected %v, got %v", tt.header, tt.ranges, got)
+			}
+		}
+	}
+}
+
+func TestRenderer_Media(t *testing.T) {
+	payload := []byte("0123456789")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clip.mp4")
+	if err := os.WriteFile(path, payload, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(method, rangeHeader string, src interface{}) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/clip", nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).WithRequest(req).Media(src, ""); err != nil {
+			t.Fatalf("Media failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("FullFile", func(t *testing.T) {
+		w := serve(http.MethodGet, "", path)
+		if w.Code != http.StatusOK || w.Body.String() != string(payload) {
+			t.Errorf("Expected full body with 200, got %d %q", w.Code, w.Body.String())
+		}
+		if w.Header().Get("Accept-Ranges") != "bytes" {
+			t.Error("Expected Accept-Ranges header")
+		}
+		if ct := w.Header().Get(HeaderContentType); ct != "video/mp4" {
+			t.Errorf("Expected video/mp4, got %q", ct)
+		}
+	})
+
+	t.Run("PartialContent", func(t *testing.T) {
+		w := serve(http.MethodGet, "bytes=2-5", bytes.NewReader(payload))
+		if w.Code != http.StatusPartialContent {
+			t.Fatalf("Expected 206, got %d", w.Code)
+		}
+		if w.Body.String() != "2345" {
+			t.Errorf("Expected %q, got %q", "2345", w.Body.String())
+		}
+		if cr := w.Header().Get("Content-Range"); cr != "bytes 2-5/10" {
+			t.Errorf("Expected Content-Range bytes 2-5/10, got %q", cr)
+		}
+	})
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/clip", nil)
+		req.Header.Set("Range", "bytes=50-60")
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).WithWriter(w).WithRequest(req).Media(bytes.NewReader(payload), "")
+		if !errors.Is(err, ErrRangeNotSatisfiable) {
+			t.Errorf("Expected ErrRangeNotSatisfiable, got %v", err)
+		}
+		if w.Code != http.StatusRequestedRangeNotSatisfiable {
+			t.Errorf("Expected 416, got %d", w.Code)
+		}
+		if cr := w.Header().Get("Content-Range"); cr != "bytes */10" {
+			t.Errorf("Expected Content-Range bytes */10, got %q", cr)
+		}
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		w := serve(http.MethodHead, "", bytes.NewReader(payload))
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected empty body for HEAD, got %q", w.Body.String())
+		}
+		if cl := w.Header().Get("Content-Length"); cl != "10" {
+			t.Errorf("Expected Content-Length 10, got %q", cl)
+		}
+	})
+
+	t.Run("OtherMethod", func(t *testing.T) {
+		w := serve(http.MethodPost, "bytes=2-5", bytes.NewReader(payload))
+		if w.Code != http.StatusOK || w.Body.String() != string(payload) {
+			t.Errorf("Expected Range ignored for POST, got %d %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("IfRange", func(t *testing.T) {
+		modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		send := func(ifRange string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/clip", nil)
+			req.Header.Set("Range", "bytes=2-5")
+			req.Header.Set("If-Range", ifRange)
+			w := httptest.NewRecorder()
+			if err := NewRenderer(settings).WithWriter(w).WithRequest(req).Media(path, ""); err != nil {
+				t.Fatalf("Media failed: %v", err)
+			}
+			return w
+		}
+		if w := send(modTime.Format(http.TimeFormat)); w.Code != http.StatusPartialContent || w.Body.String() != "2345" {
+			t.Errorf("Expected a matching If-Range to serve 206, got %d %q", w.Code, w.Body.String())
+		}
+		stale := modTime.Add(-time.Hour).Format(http.TimeFormat)
+		if w := send(stale); w.Code != http.StatusOK || w.Body.String() != string(payload) {
+			t.Errorf("Expected a stale If-Range to serve the full body, got %d %q", w.Code, w.Body.String())
+		}
+		if w := send(`"v1"`); w.Code != http.StatusOK {
+			t.Errorf("Expected an unmatched entity tag to serve 200, got %d", w.Code)
+		}
+	})
+}
+
+func TestBinaryRange(t *testing.T) {
+	data := []byte("0123456789")
+	send := func(rangeHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/", nil)
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithRequest(req)
+		if err := r.Binary(ContentTypeBinary, data); err != nil {
+			t.Fatalf("Binary failed: %v", err)
+		}
+		return w
+	}
+
+	t.Run("Full", func(t *testing.T) {
+		w := send("")
+		if w.Code != http.StatusOK || w.Body.String() != string(data) || w.Header().Get("Accept-Ranges") != "bytes" {
+			t.Errorf("Expected full body with Accept-Ranges, got %d %q %v", w.Code, w.Body.String(), w.Header())
+		}
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		w := send("bytes=2-4")
+		if w.Code != http.StatusPartialContent || w.Body.String() != "234" {
+			t.Errorf("Expected 206 with 234, got %d %q", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("Content-Range"); got != "bytes 2-4/10" {
+			t.Errorf("Expected Content-Range bytes 2-4/10, got %q", got)
+		}
+	})
+
+	t.Run("Multiple", func(t *testing.T) {
+		w := send("bytes=0-1,-2")
+		mt, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if w.Code != http.StatusPartialContent || err != nil || mt != "multipart/byteranges" {
+			t.Fatalf("Expected 206 multipart/byteranges, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+		mr := multipart.NewReader(w.Body, params["boundary"])
+		var parts []string
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			body, _ := io.ReadAll(p)
+			parts = append(parts, p.Header.Get("Content-Range")+"="+string(body))
+		}
+		if strings.Join(parts, ";") != "bytes 0-1/10=01;bytes 8-9/10=89" {
+			t.Errorf("Unexpected parts %v", parts)
+		}
+	})
+
+	t.Run("Amplification", func(t *testing.T) {
+		for _, h := range []string{
+			"bytes=0-,0-,0-",
+			"bytes=" + strings.Repeat("0-0,", maxRanges+1) + "0-0",
+		} {
+			if w := send(h); w.Code != http.StatusOK || w.Body.String() != string(data) {
+				t.Errorf("Expected the full body once, got %d %q", w.Code, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Coalesced", func(t *testing.T) {
+		w := send("bytes=4-6,0-1,5-7")
+		mt, params, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if w.Code != http.StatusPartialContent || mt != "multipart/byteranges" {
+			t.Fatalf("Expected 206 multipart/byteranges, got %d %q", w.Code, w.Header().Get("Content-Type"))
+		}
+		mr := multipart.NewReader(w.Body, params["boundary"])
+		var parts []string
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			body, _ := io.ReadAll(p)
+			parts = append(parts, p.Header.Get("Content-Range")+"="+string(body))
+		}
+		if strings.Join(parts, ";") != "bytes 0-1/10=01;bytes 4-7/10=4567" {
+			t.Errorf("Expected sorted, merged parts, got %v", parts)
+		}
+	})
+
+	t.Run("Unsatisfiable", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Range", "bytes=20-")
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).WithWriter(w).WithRequest(req).Binary(ContentTypeBinary, data)
+		if !errors.Is(err, ErrRangeNotSatisfiable) {
+			t.Errorf("Expected ErrRangeNotSatisfiable, got %v", err)
+		}
+		if w.Code != http.StatusRequestedRangeNotSatisfiable || w.Header().Get("Content-Range") != "bytes */10" || w.Body.Len() != 0 {
+			t.Errorf("Expected empty 416 with bytes */10, got %d %v", w.Code, w.Header())
+		}
+	})
+}
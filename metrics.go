@@ -0,0 +1,120 @@
+package beam
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+// Metric names emitted by the Renderer.
+const (
+	MetricRequestsShed   = "beam_requests_shed_total"     // Requests rejected by the concurrency limiter
+	MetricResponses      = "beam_responses_total"         // Output calls by operation, status code, content type, and result
+	MetricEncodeDuration = "beam_encode_duration_seconds" // Time spent encoding a response or stream item
+	MetricResponseBytes  = "beam_response_bytes"          // Bytes written per output call
+)
+
+// Metric label keys and values.
+const (
+	labelOp          = "op"
+	labelCode        = "code"
+	labelContentType = "content_type"
+	labelResult      = "result"
+	resultOK         = "ok"
+	resultError      = "error"
+)
+
+// Collector receives operational metrics from the Renderer.
+// Labels are passed as alternating key/value pairs, like Logger fields.
+// Implementations must be safe for concurrent use.
+type Collector interface {
+	// Count adds delta to the named counter.
+	Count(name string, delta float64, labels ...string)
+
+	// Observe records a sample in the named histogram or summary.
+	Observe(name string, value float64, labels ...string)
+}
+
+// A Collector also receives request parsing metrics from hauler.
+var _ hauler.Collector = Collector(nil)
+
+// WithMetrics sets the Collector that receives the Renderer's metrics:
+// Push, Raw, Stream, and Binary calls by status code and content type, their
+// encode duration, and their payload size. See PrometheusCollector.
+// Returns a new Renderer with the collector set.
+func (r *Renderer) WithMetrics(c Collector) *Renderer {
+	nr := r.clone()
+	nr.metrics = c
+	return nr
+}
+
+// count adds delta to a counter when a collector is configured.
+func (r *Renderer) count(name string, delta float64, labels ...string) {
+	if r.metrics != nil {
+		r.metrics.Count(name, delta, labels...)
+	}
+}
+
+// observe records a sample when a collector is configured.
+func (r *Renderer) observe(name string, value float64, labels ...string) {
+	if r.metrics != nil {
+		r.metrics.Observe(name, value, labels...)
+	}
+}
+
+// outputCall measures the current output call for tracing and metrics.
+type outputCall struct {
+	op    string
+	span  Span
+	bytes int64
+}
+
+// begin starts measuring an output call named op when tracing or metrics
+// are enabled.
+func (r *Renderer) begin(op string) {
+	if r.tracer == nil && r.metrics == nil {
+		return
+	}
+	r.output = &outputCall{op: op}
+	r.startSpan()
+}
+
+// finish records the outcome of the output call begun with begin.
+func (r *Renderer) finish(contentType string, err error) {
+	if r.output == nil {
+		return
+	}
+	if r.metrics != nil {
+		result := resultOK
+		if err != nil {
+			result = resultError
+		}
+		r.count(MetricResponses, 1, labelOp, r.output.op, labelCode, strconv.Itoa(r.code),
+			labelContentType, contentType, labelResult, result)
+		r.observe(MetricResponseBytes, float64(r.output.bytes), labelOp, r.output.op, labelContentType, contentType)
+	}
+	r.endSpan(contentType, err)
+}
+
+// timeEncode records the time spent encoding since start.
+func (r *Renderer) timeEncode(contentType string, start time.Time) {
+	if r.metrics != nil {
+		r.observe(MetricEncodeDuration, r.now().Sub(start).Seconds(), labelContentType, contentType)
+	}
+}
+
+// countWriter wraps w so bytes written by a Streamer encoder are counted.
+func (r *Renderer) countWriter(w Writer) Writer {
+	if r.output == nil {
+		return w
+	}
+	return &progressWriter{Writer: w, n: &r.output.bytes}
+}
+
+// written counts n bytes written by the output call.
+func (r *Renderer) written(n int) {
+	if r.output != nil {
+		r.output.bytes += int64(n)
+	}
+}
@@ -0,0 +1,90 @@
+package beam
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderMetrics(t *testing.T) {
+	metrics := NewPrometheusCollector()
+	r := NewRenderer(settings).WithMetrics(metrics)
+
+	w := httptest.NewRecorder()
+	if err := r.WithWriter(w).Data("ok", nil); err != nil {
+		t.Fatalf("Data failed: %v", err)
+	}
+	if err := r.WithWriter(httptest.NewRecorder()).WithStatus(http.StatusCreated).Binary("image/png", []byte("png")); err != nil {
+		t.Fatalf("Binary failed: %v", err)
+	}
+	n := 0
+	err := r.WithWriter(httptest.NewRecorder()).WithContentType(ContentTypeMsgPack).Stream(func(*Renderer) (interface{}, error) {
+		if n++; n > 3 {
+			return nil, io.EOF
+		}
+		return n, nil
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if err := r.WithWriter(&TestWriter{Headers: http.Header{}, WriteError: io.ErrClosedPipe}).Raw("x"); err == nil {
+		t.Fatal("Expected a write error")
+	}
+
+	responses := metrics.counters[MetricResponses]
+	for _, want := range []string{
+		`{op="Push",code="200",content_type="application/json",result="ok"}`,
+		`{op="Binary",code="201",content_type="image/png",result="ok"}`,
+		`{op="Stream",code="200",content_type="application/msgpack",result="ok"}`,
+		`{op="Raw",code="200",content_type="application/json",result="error"}`,
+	} {
+		if responses[want] != 1 {
+			t.Errorf("Expected one response %s, got %v", want, responses)
+		}
+	}
+
+	size := metrics.histograms[MetricResponseBytes][`{op="Push",content_type="application/json"}`]
+	if size == nil || size.sum != float64(w.Body.Len()) {
+		t.Errorf("Expected the Push payload size %d, got %+v", w.Body.Len(), size)
+	}
+	encode := metrics.histograms[MetricEncodeDuration]
+	if h := encode[`{content_type="application/msgpack"}`]; h == nil || h.count != 3 {
+		t.Errorf("Expected an encode duration per stream item, got %+v", h)
+	}
+	if h := encode[`{content_type="application/json"}`]; h == nil || h.count != 2 {
+		t.Errorf("Expected encode durations for Push and Raw, got %+v", h)
+	}
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	c := NewPrometheusCollector().WithBuckets("latency_seconds", 1, 0.1)
+	c.Count("hits_total", 2, "path", `/a"b`)
+	c.Count("hits_total", 1, "path", `/a"b`)
+	c.Count("up", 1)
+	c.Observe("latency_seconds", 0.05, "op", "x")
+	c.Observe("latency_seconds", 0.5, "op", "x")
+	c.Observe("latency_seconds", 3, "op", "x")
+
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := w.Header().Get(HeaderContentType); got != ContentTypePrometheus {
+		t.Errorf("Expected the exposition content type, got %q", got)
+	}
+	want := strings.Join([]string{
+		`# TYPE hits_total counter`,
+		`hits_total{path="/a\"b"} 3`,
+		`# TYPE up counter`,
+		`up 1`,
+		`# TYPE latency_seconds histogram`,
+		`latency_seconds_bucket{op="x",le="0.1"} 1`,
+		`latency_seconds_bucket{op="x",le="1"} 2`,
+		`latency_seconds_bucket{op="x",le="+Inf"} 3`,
+		`latency_seconds_sum{op="x"} 3.55`,
+		`latency_seconds_count{op="x"} 3`,
+	}, "\n") + "\n"
+	if w.Body.String() != want {
+		t.Errorf("Unexpected exposition:\n%s\nwant:\n%s", w.Body.String(), want)
+	}
+}
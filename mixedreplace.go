@@ -0,0 +1,183 @@
+package beam
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ContentTypeMixedReplace is the multipart content type used for MJPEG-style streams.
+const ContentTypeMixedReplace = "multipart/x-mixed-replace"
+
+// defaultMixedReplaceBoundary is the part boundary used when none is configured.
+const defaultMixedReplaceBoundary = "beamframe"
+
+// MediaTyper is an optional interface for encoders whose Content-Type header
+// must carry parameters, such as a multipart boundary.
+// Renderer uses MediaType for the header while still keying the registry on ContentType.
+type MediaTyper interface {
+	MediaType() string
+}
+
+// MixedReplaceEncoder streams frames as multipart/x-mixed-replace parts.
+// Each frame replaces the previous one in the client, which makes it suitable
+// for camera previews (MJPEG) and other live image feeds.
+// FrameInterval throttles the stream to at most one frame per interval.
+type MixedReplaceEncoder struct {
+	Boundary      string        // Part boundary; defaults to "beamframe"
+	PartType      string        // Content type for raw frames; defaults to image/jpeg
+	FrameInterval time.Duration // Minimum delay between frames; zero disables throttling
+	Quality       int           // JPEG quality for image.Image frames; defaults to 80
+}
+
+// NewMJPEGEncoder creates a MixedReplaceEncoder for JPEG frames capped at fps.
+// A non-positive fps disables throttling.
+// Returns a *MixedReplaceEncoder ready to register with UseEncoder.
+func NewMJPEGEncoder(fps int) *MixedReplaceEncoder {
+	e := &MixedReplaceEncoder{PartType: ContentTypeJPEG}
+	if fps > 0 {
+		e.FrameInterval = time.Second / time.Duration(fps)
+	}
+	return e
+}
+
+// Marshal encodes a single frame as a multipart part with headers and boundary.
+// Accepts []byte, string, Chunk (for per-frame content types), or image.Image (JPEG-encoded).
+// Returns the encoded part or an error if the frame type is unsupported.
+func (e *MixedReplaceEncoder) Marshal(v interface{}) ([]byte, error) {
+	partType := e.partType()
+	if c, ok := v.(Chunk); ok {
+		if c.ContentType != Empty {
+			partType = c.ContentType
+		}
+		v = c.Data
+	}
+
+	var frame []byte
+	switch d := v.(type) {
+	case []byte:
+		frame = d
+	case string:
+		frame = []byte(d)
+	case image.Image:
+		quality := e.Quality
+		if quality <= 0 {
+			quality = 80
+		}
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if err := jpeg.Encode(buf, d, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, errors.Join(errors.New("JPEG encoding failed"), err)
+		}
+		frame = buf.Bytes()
+		partType = ContentTypeJPEG
+	default:
+		return nil, fmt.Errorf("unsupported frame type %T", v)
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(frame) + 128)
+	out.WriteString("--")
+	out.WriteString(e.boundary())
+	out.WriteString("\r\nContent-Type: ")
+	out.WriteString(partType)
+	out.WriteString("\r\nContent-Length: ")
+	out.WriteString(strconv.Itoa(len(frame)))
+	out.WriteString("\r\n\r\n")
+	out.Write(frame)
+	out.WriteString("\r\n")
+	return out.Bytes(), nil
+}
+
+// Unmarshal is a no-op for multipart streams.
+// Always returns nil, as decoding is not supported.
+func (e *MixedReplaceEncoder) Unmarshal(data []byte, v interface{}) error {
+	return nil
+}
+
+// ContentType returns the multipart/x-mixed-replace content type.
+// Used by EncoderRegistry to map this encoder.
+func (e *MixedReplaceEncoder) ContentType() string {
+	return ContentTypeMixedReplace
+}
+
+// MediaType returns the content type including the boundary parameter.
+// Used by Renderer when writing the Content-Type header.
+func (e *MixedReplaceEncoder) MediaType() string {
+	return ContentTypeMixedReplace + "; boundary=" + e.boundary()
+}
+
+// AcceptsChunks reports that Chunks reach Marshal intact for per-part content types.
+// Returns true.
+func (e *MixedReplaceEncoder) AcceptsChunks() bool {
+	return true
+}
+
+// Stream writes frames produced by callback until it returns io.EOF.
+// Equivalent to StreamContext with a background context.
+// Returns an error if the callback, encoding, or writing fails.
+func (e *MixedReplaceEncoder) Stream(w Writer, callback func() (interface{}, error)) error {
+	return e.StreamContext(context.Background(), w, callback)
+}
+
+// StreamContext writes frames produced by callback until it returns io.EOF.
+// Waits between frames to honor FrameInterval and flushes after each frame.
+// Returns ErrContextCanceled if ctx ends while waiting, or an error if the
+// callback, encoding, or writing fails.
+func (e *MixedReplaceEncoder) StreamContext(ctx context.Context, w Writer, callback func() (interface{}, error)) error {
+	var last time.Time
+	for {
+		if e.FrameInterval > 0 && !last.IsZero() {
+			if wait := e.FrameInterval - time.Since(last); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ErrContextCanceled
+				case <-timer.C:
+				}
+			}
+		}
+		data, err := callback()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("stream callback failed: %w", err)
+		}
+		last = time.Now()
+		encoded, err := e.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("encoding failed: %w", err)
+		}
+		if _, err := w.Write(encoded); err != nil {
+			return fmt.Errorf("write failed: %w", err)
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+// boundary returns the configured boundary or the default.
+func (e *MixedReplaceEncoder) boundary() string {
+	if e.Boundary == Empty {
+		return defaultMixedReplaceBoundary
+	}
+	return e.Boundary
+}
+
+// partType returns the configured frame content type or image/jpeg.
+func (e *MixedReplaceEncoder) partType() string {
+	if e.PartType == Empty {
+		return ContentTypeJPEG
+	}
+	return e.PartType
+}
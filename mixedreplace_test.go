@@ -0,0 +1,116 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMixedReplaceStream(t *testing.T) {
+	tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+	r := NewRenderer(settings).
+		UseEncoder(&MixedReplaceEncoder{Boundary: "cam", FrameInterval: 20 * time.Millisecond}).
+		WithContentType(ContentTypeMixedReplace).
+		WithWriter(tfw)
+
+	frames := [][]byte{[]byte("f1"), []byte("f2"), []byte("f3")}
+	i := 0
+	start := time.Now()
+	err := r.Stream(func(r *Renderer) (interface{}, error) {
+		if i >= len(frames) {
+			return nil, io.EOF
+		}
+		i++
+		return frames[i-1], nil
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	if ct := tfw.Headers.Get(HeaderContentType); ct != "multipart/x-mixed-replace; boundary=cam" {
+		t.Errorf("Expected boundary in content type, got %q", ct)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Expected throttling to take at least 40ms, took %v", elapsed)
+	}
+	out := tfw.Buffer.String()
+	if strings.Count(out, "--cam\r\n") != 3 {
+		t.Errorf("Expected 3 parts, got %q", out)
+	}
+	if !strings.Contains(out, "--cam\r\nContent-Type: image/jpeg\r\nContent-Length: 2\r\n\r\nf1\r\n") {
+		t.Errorf("Unexpected part framing: %q", out)
+	}
+	if tfw.FlushCalled != 3 {
+		t.Errorf("Expected 3 flushes, got %d", tfw.FlushCalled)
+	}
+}
+
+func TestMixedReplaceStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+	r := NewRenderer(settings).
+		UseEncoder(&MixedReplaceEncoder{FrameInterval: time.Hour}).
+		WithContentType(ContentTypeMixedReplace).
+		WithContext(ctx).
+		WithWriter(tfw)
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Stream(func(r *Renderer) (interface{}, error) {
+			return []byte("frame"), nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrContextCanceled) {
+			t.Errorf("Expected ErrContextCanceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the frame wait to end with the context")
+	}
+}
+
+// framedEncoder is encoder middleware that forwards streaming to the encoder it wraps.
+type framedEncoder struct {
+	Encoder
+}
+
+func (e framedEncoder) Stream(w Writer, callback func() (interface{}, error)) error {
+	return e.Encoder.(Streamer).Stream(w, callback)
+}
+
+func (e framedEncoder) AcceptsChunks() bool {
+	ca, ok := e.Encoder.(ChunkAccepter)
+	return ok && ca.AcceptsChunks()
+}
+
+func TestMixedReplaceWrappedChunks(t *testing.T) {
+	tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+	r := NewRenderer(settings).
+		UseEncoder(&MixedReplaceEncoder{Boundary: "cam"}).
+		WrapEncoder(ContentTypeMixedReplace, func(next Encoder) Encoder {
+			return framedEncoder{Encoder: next}
+		}).
+		WithContentType(ContentTypeMixedReplace).
+		WithWriter(tfw)
+
+	sent := false
+	err := r.Stream(func(r *Renderer) (interface{}, error) {
+		if sent {
+			return nil, io.EOF
+		}
+		sent = true
+		return NewChunk(ContentTypePNG, []byte("p1")), nil
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	if out := tfw.Buffer.String(); !strings.Contains(out, "Content-Type: image/png\r\n") {
+		t.Errorf("Expected the wrapped encoder to keep the chunk content type, got %q", out)
+	}
+}
@@ -0,0 +1,105 @@
+package beam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// MessagePack extension type IDs used by beam.
+// time.Time uses the standard timestamp extension defined by the MessagePack spec.
+// ErrorList is not an extension: it encodes as a plain array of {code, message}
+// maps so any MessagePack client can read it.
+const (
+	MsgPackExtTime     int8 = -1 // Standard timestamp extension (built into msgpack)
+	MsgPackExtDuration int8 = 1  // time.Duration as 8-byte big-endian nanoseconds, after RegisterDurationExt
+)
+
+// errMsgPackExtLength is returned when an extension payload has an unexpected size.
+var errMsgPackExtLength = errors.New("invalid msgpack extension length")
+
+// durationExtOnce guards RegisterDurationExt.
+var durationExtOnce sync.Once
+
+// RegisterDurationExt encodes time.Duration values as the MsgPackExtDuration
+// extension process-wide instead of plain integer nanoseconds. Clients that do
+// not know the extension cannot read such durations, so it is opt-in; call it
+// once at startup, before encoding. Safe to call more than once.
+func RegisterDurationExt() {
+	durationExtOnce.Do(func() {
+		RegisterMsgPackExt(MsgPackExtDuration, encodeDurationExt, decodeDurationExt)
+	})
+}
+
+// RegisterMsgPackExt registers a MessagePack extension codec for values of type T.
+// The registration is process-wide, matching the msgpack library's registry, so
+// domain types encode the same way wherever MsgPackEncoder is used.
+// IDs 0-15 are reserved for beam; use 16-127 for application types.
+// T should be a struct or scalar type; slice and map types need msgpack.CustomEncoder instead.
+func RegisterMsgPackExt[T any](id int8, encode func(T) ([]byte, error), decode func([]byte) (T, error)) {
+	var zero T
+	msgpack.RegisterExtEncoder(id, zero, func(_ *msgpack.Encoder, v reflect.Value) ([]byte, error) {
+		return encode(v.Interface().(T))
+	})
+	msgpack.RegisterExtDecoder(id, zero, func(dec *msgpack.Decoder, v reflect.Value, extLen int) error {
+		buf := make([]byte, extLen)
+		if extLen > 0 {
+			if err := dec.ReadFull(buf); err != nil {
+				return err
+			}
+		}
+		value, err := decode(buf)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	})
+}
+
+// encodeDurationExt encodes a duration as 8-byte big-endian nanoseconds.
+func encodeDurationExt(d time.Duration) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, uint64(d)), nil
+}
+
+// decodeDurationExt decodes a duration extension payload.
+func decodeDurationExt(b []byte) (time.Duration, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("%w: duration needs 8 bytes, got %d", errMsgPackExtLength, len(b))
+	}
+	return time.Duration(binary.BigEndian.Uint64(b)), nil
+}
+
+// EncodeMsgpack encodes an ErrorList as an array of {code, message} maps.
+// Implements msgpack.CustomEncoder so errors are not lost as empty maps.
+func (el ErrorList) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(el.items())
+}
+
+// DecodeMsgpack decodes an ErrorList from {code, message} maps or plain strings.
+// Implements msgpack.CustomDecoder.
+func (el *ErrorList) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var raw []interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*el = make(ErrorList, 0, len(raw))
+	for _, v := range raw {
+		switch item := v.(type) {
+		case string:
+			*el = append(*el, errors.New(item))
+		case map[string]interface{}:
+			code, _ := item["code"].(string)
+			msg, _ := item["message"].(string)
+			*el = append(*el, ErrorItem{Code: code, Message: msg}.err())
+		default:
+			return fmt.Errorf("msgpack: unexpected error item %T", v)
+		}
+	}
+	return nil
+}
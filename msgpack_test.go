@@ -0,0 +1,70 @@
+package beam
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testPoint struct {
+	X, Y int16
+}
+
+func TestMsgPackExtensions(t *testing.T) {
+	e := &MsgPackEncoder{}
+
+	t.Run("ErrorList", func(t *testing.T) {
+		data, err := e.Marshal(Response{Status: StatusError, Errors: ErrorList{errors.New("bad input"), errors.New("too long")}})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var resp Response
+		if err := e.Unmarshal(data, &resp); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if len(resp.Errors) != 2 || resp.Errors[1].Error() != "too long" {
+			t.Errorf("Expected errors to round-trip, got %v", resp.Errors)
+		}
+	})
+
+	t.Run("DurationDefault", func(t *testing.T) {
+		data, err := e.Marshal(struct{ Took time.Duration }{1500 * time.Millisecond})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var out struct{ Took int64 }
+		if err := e.Unmarshal(data, &out); err != nil || out.Took != int64(1500*time.Millisecond) {
+			t.Errorf("Expected durations as plain nanoseconds by default, got %x (%v)", data, err)
+		}
+	})
+
+	t.Run("TimeAndDuration", func(t *testing.T) {
+		RegisterDurationExt()
+		type sample struct {
+			At    time.Time
+			Took  time.Duration
+			Point testPoint
+		}
+		RegisterMsgPackExt(16, func(p testPoint) ([]byte, error) {
+			return []byte{byte(p.X), byte(p.Y)}, nil
+		}, func(b []byte) (testPoint, error) {
+			return testPoint{X: int16(b[0]), Y: int16(b[1])}, nil
+		})
+		in := sample{At: time.Unix(1700000000, 5).UTC(), Took: 1500 * time.Millisecond, Point: testPoint{3, 4}}
+		data, err := e.Marshal(in)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var out sample
+		if err := e.Unmarshal(data, &out); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if !out.At.Equal(in.At) || out.Took != in.Took || out.Point != in.Point {
+			t.Errorf("Expected %+v, got %+v", in, out)
+		}
+		if !bytes.Contains(data, []byte{0xd7, byte(MsgPackExtDuration)}) {
+			t.Errorf("Expected the duration extension once registered, got %x", data)
+		}
+	})
+}
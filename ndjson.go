@@ -0,0 +1,113 @@
+package beam
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// NDJSONEncoder encodes newline-delimited JSON (one JSON value per line).
+// Slices and arrays are emitted as one line per element; other values as a single line.
+// Implements Streamer so Renderer.Stream writes and flushes one record at a time.
+type NDJSONEncoder struct {
+	Numbers NumberFormat
+}
+
+// Marshal encodes v as newline-delimited JSON.
+// Takes a slice (one line per element) or any other JSON-serializable value.
+// Returns the encoded lines, each terminated by "\n", or an error if encoding fails.
+func (e *NDJSONEncoder) Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8 {
+		var out bytes.Buffer
+		for i := 0; i < rv.Len(); i++ {
+			line, err := marshalJSON(rv.Index(i).Interface(), e.Numbers, Empty)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(line)
+			out.WriteByte('\n')
+		}
+		return out.Bytes(), nil
+	}
+	line, err := marshalJSON(v, e.Numbers, Empty)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
+
+// Unmarshal decodes newline-delimited JSON into v.
+// A pointer to a slice receives one element per line; any other pointer receives the first line.
+// Returns an error if v is not a pointer or a line fails to decode.
+func (e *NDJSONEncoder) Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("NDJSON requires a non-nil pointer")
+	}
+	elem := rv.Elem()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	if elem.Kind() != reflect.Slice || elem.Type().Elem().Kind() == reflect.Uint8 {
+		for scanner.Scan() {
+			if line := bytes.TrimSpace(scanner.Bytes()); len(line) > 0 {
+				return json.Unmarshal(line, v)
+			}
+		}
+		return scanner.Err()
+	}
+	elem.SetLen(0)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		item := reflect.New(elem.Type().Elem())
+		if err := json.Unmarshal(line, item.Interface()); err != nil {
+			return err
+		}
+		elem.Set(reflect.Append(elem, item.Elem()))
+	}
+	return scanner.Err()
+}
+
+// ContentType returns the NDJSON content type.
+// Returns the constant "application/x-ndjson".
+func (e *NDJSONEncoder) ContentType() string {
+	return ContentTypeNDJSON
+}
+
+// withNumbers returns a copy of the encoder using the given NumberFormat.
+func (e *NDJSONEncoder) withNumbers(nf NumberFormat) Encoder {
+	return &NDJSONEncoder{Numbers: nf}
+}
+
+// Stream writes one JSON line per callback result until io.EOF.
+// Flushes after every record if the writer supports it.
+// Returns an error if the callback, encoding, or writing fails.
+func (e *NDJSONEncoder) Stream(w Writer, callback func() (interface{}, error)) error {
+	for {
+		data, err := callback()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("stream callback failed: %w", err)
+		}
+		line, err := marshalJSON(data, e.Numbers, Empty)
+		if err != nil {
+			return fmt.Errorf("encoding failed: %w", err)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("write failed: %w", err)
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package beam
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HeaderNameNonce is the header key used to emit and echo replay-protection nonces.
+// The full header name is prefixed like other Beam headers (e.g., "X-beam-Nonce").
+const HeaderNameNonce = "Nonce"
+
+// metaNonce is the meta key under which an issued nonce is stored in responses.
+const metaNonce = "nonce"
+
+// Nonce verification errors.
+// Returned by VerifyNonce so handlers can reject replayed or forged follow-up requests.
+var (
+	ErrNonceMissing = errors.New("nonce missing")
+	ErrNonceInvalid = errors.New("nonce invalid or already used")
+	errNonceStore   = errors.New("nonce store failed")
+)
+
+// Defaults of WithNonce and MemoryNonceStore.
+const (
+	DefaultNonceTTL       = 10 * time.Minute // Lifetime of nonces issued with a non-positive ttl
+	DefaultNonceStoreSize = 1 << 16          // Capacity of a MemoryNonceStore created with a non-positive size
+)
+
+// nonceSweepInterval bounds how often MemoryNonceStore scans for expired nonces.
+const nonceSweepInterval = time.Minute
+
+// NonceStore persists issued nonces until they are consumed or expire.
+// Times come from the Renderer's clock (see WithClock), so stores never read
+// the system clock themselves. Implementations must be safe for concurrent use.
+// Used by Renderer to support replay protection for multi-step flows.
+type NonceStore interface {
+	// Save records a nonce issued at now and valid for ttl.
+	Save(nonce string, now time.Time, ttl time.Duration) error
+
+	// Consume removes the nonce and reports whether it was still valid at now.
+	Consume(nonce string, now time.Time) (bool, error)
+}
+
+// MemoryNonceStore is an in-memory NonceStore bounded in size.
+// Expired nonces are purged at most once per minute, amortizing the scan over
+// many Saves; when full, the oldest nonce is evicted first.
+// Suitable for single-instance deployments and tests.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	max    int
+	swept  time.Time  // Time of the last purge of expired nonces
+	order  *list.List // Nonces in issue order; front is the oldest
+	nonces map[string]*list.Element
+}
+
+// nonceEntry is one nonce stored in a MemoryNonceStore.
+type nonceEntry struct {
+	nonce   string
+	expires time.Time
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore holding up to
+// maxNonces nonces; a non-positive maxNonces uses DefaultNonceStoreSize.
+// Returns a *MemoryNonceStore ready for use.
+func NewMemoryNonceStore(maxNonces int) *MemoryNonceStore {
+	if maxNonces <= 0 {
+		maxNonces = DefaultNonceStoreSize
+	}
+	return &MemoryNonceStore{max: maxNonces, order: list.New(), nonces: make(map[string]*list.Element)}
+}
+
+// Save records a nonce valid for ttl from now, purging expired nonces when
+// the sweep interval has passed and evicting the oldest ones beyond capacity.
+// A non-positive ttl uses DefaultNonceTTL, so every nonce expires.
+// Returns nil; the in-memory store cannot fail.
+func (s *MemoryNonceStore) Save(nonce string, now time.Time, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultNonceTTL
+	}
+	expires := now.Add(ttl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.swept) >= nonceSweepInterval || now.Before(s.swept) {
+		s.sweep(now)
+	}
+	if el, ok := s.nonces[nonce]; ok {
+		s.order.Remove(el)
+	}
+	s.nonces[nonce] = s.order.PushBack(&nonceEntry{nonce: nonce, expires: expires})
+	for s.order.Len() > s.max {
+		e := s.order.Remove(s.order.Front()).(*nonceEntry)
+		delete(s.nonces, e.nonce)
+	}
+	return nil
+}
+
+// Consume removes a nonce and reports whether it was present and unexpired
+// at now. A nonce can only be consumed once, which prevents replays.
+// Returns false for unknown or expired nonces.
+func (s *MemoryNonceStore) Consume(nonce string, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.nonces[nonce]
+	if !ok {
+		return false, nil
+	}
+	e := s.order.Remove(el).(*nonceEntry)
+	delete(s.nonces, nonce)
+	return now.Before(e.expires), nil
+}
+
+// Len returns the number of stored nonces, including expired ones not yet purged.
+func (s *MemoryNonceStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// sweep drops nonces expired at now. Requires s.mu held.
+func (s *MemoryNonceStore) sweep(now time.Time) {
+	for el := s.order.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*nonceEntry); !now.Before(e.expires) {
+			s.order.Remove(el)
+			delete(s.nonces, e.nonce)
+		}
+		el = next
+	}
+	s.swept = now
+}
+
+// WithNonce enables nonce issuance for structured responses.
+// Every Push generates a random nonce, saves it in the store with the given ttl,
+// and exposes it in Response.Meta["nonce"] and the prefixed Nonce header.
+// A non-positive ttl uses DefaultNonceTTL.
+// Returns a new Renderer with nonce generation enabled.
+func (r *Renderer) WithNonce(store NonceStore, ttl time.Duration) *Renderer {
+	if ttl <= 0 {
+		ttl = DefaultNonceTTL
+	}
+	nr := r.clone()
+	nr.nonceStore = store
+	nr.nonceTTL = ttl
+	return nr
+}
+
+// NonceHeader returns the header name clients must use to echo a nonce.
+// The name follows the Renderer's header prefix (e.g., "X-beam-Nonce").
+func (r *Renderer) NonceHeader() string {
+	return r.headerName(HeaderNameNonce)
+}
+
+// VerifyNonce checks the nonce echoed by a follow-up request.
+// Reads the prefixed Nonce header and consumes it from the Renderer's store,
+// checking expiry against the Renderer's clock.
+// Returns a *ConfigError without a store (see WithNonce), ErrNonceMissing if
+// absent, and ErrNonceInvalid if unknown, expired, or replayed.
+func (r *Renderer) VerifyNonce(req *http.Request) error {
+	if r.nonceStore == nil {
+		return &ConfigError{Missing: []string{"nonce store (use WithNonce)"}}
+	}
+	if req == nil {
+		return ErrNonceMissing
+	}
+	return consumeNonce(r.nonceStore, req.Header.Get(r.NonceHeader()), r.now())
+}
+
+// VerifyNonce consumes a nonce from the given store, checking expiry against
+// the system clock.
+// Returns a *ConfigError for a nil store, ErrNonceMissing for an empty nonce,
+// and ErrNonceInvalid when the nonce is unknown, expired, or already used.
+func VerifyNonce(store NonceStore, nonce string) error {
+	return consumeNonce(store, nonce, time.Now())
+}
+
+// consumeNonce consumes a nonce from store as of now, as VerifyNonce does.
+func consumeNonce(store NonceStore, nonce string, now time.Time) error {
+	if store == nil {
+		return &ConfigError{Missing: []string{"nonce store"}}
+	}
+	if nonce == Empty {
+		return ErrNonceMissing
+	}
+	ok, err := store.Consume(nonce, now)
+	if err != nil {
+		return errors.Join(errNonceStore, err)
+	}
+	if !ok {
+		return ErrNonceInvalid
+	}
+	return nil
+}
+
+// issueNonce generates, stores, and attaches a nonce to the response.
+// Sets the nonce in meta and in the Renderer's header map.
+// Returns an error if random generation or the store fails.
+func (r *Renderer) issueNonce(resp *Response) error {
+	var raw [16]byte
+	if _, err := rand.Read(raw[:]); err != nil {
+		return errors.Join(errNonceStore, err)
+	}
+	nonce := hex.EncodeToString(raw[:])
+	if err := r.nonceStore.Save(nonce, r.now(), r.nonceTTL); err != nil {
+		return errors.Join(errNonceStore, err)
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaNonce] = nonce
+	r.header.Set(r.NonceHeader(), nonce)
+	return nil
+}
@@ -0,0 +1,109 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRenderer_Nonce(t *testing.T) {
+	store := NewMemoryNonceStore(0)
+	r := NewRenderer(settings).WithNonce(store, time.Minute)
+
+	t.Run("IssuedInMetaAndHeader", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).Msg("confirm payment"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var resp Response
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		nonce, _ := resp.Meta["nonce"].(string)
+		if nonce == "" {
+			t.Fatal("Expected nonce in meta")
+		}
+		if got := w.Header().Get("X-test-Nonce"); got != nonce {
+			t.Errorf("Expected header nonce %q, got %q", nonce, got)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/confirm", nil)
+		req.Header.Set(r.NonceHeader(), nonce)
+		if err := r.VerifyNonce(req); err != nil {
+			t.Errorf("Expected nonce to verify, got %v", err)
+		}
+		if err := r.VerifyNonce(req); !errors.Is(err, ErrNonceInvalid) {
+			t.Errorf("Expected ErrNonceInvalid on replay, got %v", err)
+		}
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/confirm", nil)
+		if err := r.VerifyNonce(req); !errors.Is(err, ErrNonceMissing) {
+			t.Errorf("Expected ErrNonceMissing, got %v", err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		s := NewMemoryNonceStore(0)
+		_ = s.Save("abc", time.Now(), time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		if err := VerifyNonce(s, "abc"); !errors.Is(err, ErrNonceInvalid) {
+			t.Errorf("Expected ErrNonceInvalid for expired nonce, got %v", err)
+		}
+	})
+
+	t.Run("Clock", func(t *testing.T) {
+		now := time.Unix(1000, 0)
+		clock := ClockFunc(func() time.Time { return now })
+		w := httptest.NewRecorder()
+		cr := NewRenderer(settings).WithClock(clock).WithNonce(NewMemoryNonceStore(0), time.Minute)
+		if err := cr.WithWriter(w).Msg("confirm"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/confirm", nil)
+		req.Header.Set(cr.NonceHeader(), w.Header().Get(cr.NonceHeader()))
+		now = now.Add(2 * time.Minute)
+		if err := cr.VerifyNonce(req); !errors.Is(err, ErrNonceInvalid) {
+			t.Errorf("Expected expiry on the Renderer's clock, got %v", err)
+		}
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		s := NewMemoryNonceStore(2)
+		now := time.Unix(1000, 0)
+		for _, n := range []string{"a", "b", "c"} {
+			_ = s.Save(n, now, 0)
+		}
+		if s.Len() != 2 {
+			t.Errorf("Expected the store capped at 2, got %d", s.Len())
+		}
+		if ok, _ := s.Consume("a", now); ok {
+			t.Error("Expected the oldest nonce to be evicted")
+		}
+		if ok, _ := s.Consume("c", now.Add(DefaultNonceTTL-time.Second)); !ok {
+			t.Error("Expected a non-positive ttl to use DefaultNonceTTL")
+		}
+
+		// Expired nonces are purged once the sweep interval has passed.
+		_ = s.Save("d", now, time.Second)
+		_ = s.Save("e", now.Add(DefaultNonceTTL+time.Minute), time.Minute)
+		if s.Len() != 1 {
+			t.Errorf("Expected expired nonces purged, got %d left", s.Len())
+		}
+	})
+
+	t.Run("NoStore", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/confirm", nil)
+		req.Header.Set(r.NonceHeader(), "abc")
+		if err := NewRenderer(settings).VerifyNonce(req); !errors.Is(err, ErrMisconfigured) {
+			t.Errorf("Expected ErrMisconfigured without a store, got %v", err)
+		}
+		if err := VerifyNonce(nil, "abc"); !errors.Is(err, ErrMisconfigured) {
+			t.Errorf("Expected ErrMisconfigured for a nil store, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntegerMode controls how JSON integers are emitted.
+type IntegerMode int
+
+// IntegerMode constants select which integers are emitted as JSON strings.
+const (
+	IntegerAsNumber       IntegerMode = iota // Emit integers as numbers (default)
+	IntegerUnsafeAsString                    // Emit integers beyond ±(2^53-1) as strings
+	IntegerAsString                          // Emit every integer as a string
+)
+
+// maxSafeInteger is the largest integer JavaScript can represent exactly.
+const maxSafeInteger = 1<<53 - 1
+
+// NumberFormat configures number handling for JSON-based encoders.
+// Applied consistently by the JSON, EventStream, and NDJSON encoders so
+// int64/uint64 values survive JavaScript clients and floats render predictably.
+type NumberFormat struct {
+	Integers       IntegerMode // How integers are emitted
+	FloatFormat    byte        // strconv format ('f', 'e', 'g'); zero keeps the default encoding
+	FloatPrecision int         // Digits for FloatFormat; -1 for the shortest representation
+}
+
+// active reports whether the format changes default JSON output.
+func (nf NumberFormat) active() bool {
+	return nf.Integers != IntegerAsNumber || nf.FloatFormat != 0
+}
+
+// numberConfigurable is implemented by encoders that honor a NumberFormat.
+// Used by WithNumberFormat to derive configured copies of registered encoders.
+type numberConfigurable interface {
+	withNumbers(nf NumberFormat) Encoder
+}
+
+// WithNumberFormat configures number handling for JSON-based encoders.
+// Derives a private encoder registry so the base Renderer is unaffected,
+// replacing every encoder that supports number formatting with a configured copy.
+// Returns a new Renderer with the updated encoders.
+func (r *Renderer) WithNumberFormat(nf NumberFormat) *Renderer {
+	nr := r.clone()
+	nr.encoders = nr.encoders.Clone()
+	for _, e := range nr.encoders.All() {
+		if nc, ok := e.(numberConfigurable); ok {
+			nr.encoders.Register(nc.withNumbers(nf))
+		}
+	}
+	return nr
+}
+
+// marshalJSON encodes v as compact (or indented) JSON and applies the NumberFormat.
+// Returns the encoded bytes without a trailing newline.
+func marshalJSON(v interface{}, nf NumberFormat, indent string) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	enc := json.NewEncoder(buf)
+	if indent != Empty {
+		enc.SetIndent("", indent)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if nf.active() {
+		out, err := rewriteNumbers(data, nf)
+		if err != nil {
+			return nil, err
+		}
+		if indent != Empty {
+			var pretty bytes.Buffer
+			if err := json.Indent(&pretty, out, "", indent); err != nil {
+				return nil, err
+			}
+			return pretty.Bytes(), nil
+		}
+		return out, nil
+	}
+	result := make([]byte, len(data))
+	copy(result, data)
+	return result, nil
+}
+
+// rewriteNumbers re-emits compact JSON with numbers transformed per nf.
+// Walks the token stream so object key order is preserved.
+// Returns the rewritten JSON or an error if data is malformed.
+func rewriteNumbers(data []byte, nf NumberFormat) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out bytes.Buffer
+	out.Grow(len(data) + 16)
+
+	// Track container state to place commas and colons correctly.
+	type frame struct {
+		object bool
+		count  int
+	}
+	var stack []frame
+	writeSep := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		if top.object {
+			if top.count%2 == 1 {
+				out.WriteByte(':')
+			} else if top.count > 0 {
+				out.WriteByte(',')
+			}
+		} else if top.count > 0 {
+			out.WriteByte(',')
+		}
+		top.count++
+	}
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				writeSep()
+				out.WriteByte(byte(t))
+				stack = append(stack, frame{object: t == '{'})
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+				out.WriteByte(byte(t))
+			}
+		case json.Number:
+			writeSep()
+			out.WriteString(formatNumber(t, nf))
+		case string:
+			writeSep()
+			s, _ := json.Marshal(t)
+			out.Write(s)
+		case bool:
+			writeSep()
+			out.WriteString(strconv.FormatBool(t))
+		case nil:
+			writeSep()
+			out.WriteString("null")
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// formatNumber renders a JSON number literal according to nf.
+func formatNumber(n json.Number, nf NumberFormat) string {
+	lit := n.String()
+	if !strings.ContainsAny(lit, ".eE") {
+		switch nf.Integers {
+		case IntegerAsString:
+			return strconv.Quote(lit)
+		case IntegerUnsafeAsString:
+			if i, err := strconv.ParseInt(lit, 10, 64); err != nil || i > maxSafeInteger || i < -maxSafeInteger {
+				return strconv.Quote(lit)
+			}
+		}
+		return lit
+	}
+	if nf.FloatFormat == 0 {
+		return lit
+	}
+	f, err := n.Float64()
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return lit
+	}
+	return strconv.FormatFloat(f, nf.FloatFormat, nf.FloatPrecision, 64)
+}
@@ -0,0 +1,89 @@
+package beam
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+	type payload struct {
+		Big   int64   `json:"big"`
+		Small int     `json:"small"`
+		Ratio float64 `json:"ratio"`
+	}
+	data := payload{Big: 9007199254740993, Small: 42, Ratio: 0.1}
+
+	t.Run("UnsafeIntegersAsStrings", func(t *testing.T) {
+		out, err := marshalJSON(data, NumberFormat{Integers: IntegerUnsafeAsString}, Empty)
+		if err != nil {
+			t.Fatalf("marshalJSON failed: %v", err)
+		}
+		expected := `{"big":"9007199254740993","small":42,"ratio":0.1}`
+		if string(out) != expected {
+			t.Errorf("Expected %s, got %s", expected, out)
+		}
+	})
+
+	t.Run("AllIntegersAsStrings", func(t *testing.T) {
+		out, err := marshalJSON([]int{1, -2}, NumberFormat{Integers: IntegerAsString}, Empty)
+		if err != nil {
+			t.Fatalf("marshalJSON failed: %v", err)
+		}
+		if string(out) != `["1","-2"]` {
+			t.Errorf("Expected quoted integers, got %s", out)
+		}
+	})
+
+	t.Run("FloatFormat", func(t *testing.T) {
+		out, err := marshalJSON(data, NumberFormat{FloatFormat: 'f', FloatPrecision: 3}, Empty)
+		if err != nil {
+			t.Fatalf("marshalJSON failed: %v", err)
+		}
+		if !strings.Contains(string(out), `"ratio":0.100`) {
+			t.Errorf("Expected fixed precision float, got %s", out)
+		}
+	})
+
+	t.Run("ConsistentAcrossEncoders", func(t *testing.T) {
+		base := NewRenderer(settings)
+		r := base.WithNumberFormat(NumberFormat{Integers: IntegerUnsafeAsString})
+		inputs := map[string]interface{}{
+			ContentTypeJSON:        data,
+			ContentTypeEventStream: Event{Data: data},
+			ContentTypeNDJSON:      data,
+		}
+		for ct, v := range inputs {
+			out, err := r.encoders.Encode(ct, v)
+			if err != nil {
+				t.Fatalf("%s marshal failed: %v", ct, err)
+			}
+			if !strings.Contains(string(out), `"big":"9007199254740993"`) {
+				t.Errorf("Expected %s to quote unsafe integer, got %s", ct, out)
+			}
+		}
+		out, _ := base.encoders.Encode(ContentTypeJSON, data)
+		if !strings.Contains(string(out), `"big":9007199254740993`) {
+			t.Errorf("Expected base renderer to be unaffected, got %s", out)
+		}
+	})
+}
+
+func TestNDJSONEncoder(t *testing.T) {
+	tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+	r := NewRenderer(settings).WithContentType(ContentTypeNDJSON).WithWriter(tfw)
+	if err := r.Raw([]map[string]int{{"a": 1}, {"b": 2}}); err != nil {
+		t.Fatalf("Raw failed: %v", err)
+	}
+	if got := tfw.Buffer.String(); got != "{\"a\":1}\n{\"b\":2}\n" {
+		t.Errorf("Expected one line per element, got %q", got)
+	}
+
+	var decoded []map[string]int
+	if err := (&NDJSONEncoder{}).Unmarshal(tfw.Buffer.Bytes(), &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(decoded) != 2 || decoded[1]["b"] != 2 {
+		t.Errorf("Expected 2 decoded records, got %v", decoded)
+	}
+}
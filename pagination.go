@@ -0,0 +1,165 @@
+package beam
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// metaPagination is the meta key carrying the Pagination of a paginated response.
+const metaPagination = "pagination"
+
+// Query parameters used to build pagination links and read page requests.
+var (
+	PageParam    = "page"
+	PerPageParam = "per_page"
+	CursorParam  = "cursor"
+)
+
+// DefaultPerPage is the page size used when a Pagination leaves PerPage unset.
+var DefaultPerPage = 20
+
+// MaxPerPage caps the page size clients can request through PageRequest, so
+// per_page=1000000 cannot make a handler load a whole table.
+var MaxPerPage = 100
+
+// Pagination describes one page of a collection.
+// Offset mode uses Page, PerPage and Total (0 when unknown); cursor mode is
+// selected by setting Cursor, NextCursor or PrevCursor.
+// Pages is computed by Paginate from Total and PerPage.
+type Pagination struct {
+	Page       int    `json:"page,omitempty" xml:"page,omitempty" msgpack:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty" xml:"per_page,omitempty" msgpack:"per_page,omitempty"`
+	Total      int64  `json:"total,omitempty" xml:"total,omitempty" msgpack:"total,omitempty"`
+	Pages      int64  `json:"pages,omitempty" xml:"pages,omitempty" msgpack:"pages,omitempty"`
+	Cursor     string `json:"cursor,omitempty" xml:"cursor,omitempty" msgpack:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty" xml:"next_cursor,omitempty" msgpack:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty" xml:"prev_cursor,omitempty" msgpack:"prev_cursor,omitempty"`
+}
+
+// IsCursor reports whether the Pagination uses cursor mode.
+func (p Pagination) IsCursor() bool {
+	return p.Cursor != Empty || p.NextCursor != Empty || p.PrevCursor != Empty
+}
+
+// PageRequest reads the requested page from the bound request's query string.
+// Page defaults to 1 and PerPage to DefaultPerPage, clamped to MaxPerPage;
+// Cursor is set when present.
+// Returns the defaults if no request is bound or the parameters are invalid.
+func (r *Renderer) PageRequest() Pagination {
+	p := Pagination{Page: 1, PerPage: DefaultPerPage}
+	if r.request == nil || r.request.URL == nil {
+		return p
+	}
+	q := r.request.URL.Query()
+	if n, err := strconv.Atoi(q.Get(PageParam)); err == nil && n > 0 {
+		p.Page = n
+	}
+	if n, err := strconv.Atoi(q.Get(PerPageParam)); err == nil && n > 0 {
+		p.PerPage = min(n, MaxPerPage)
+	}
+	p.Cursor = q.Get(CursorParam)
+	return p
+}
+
+// Paginate sends a successful HTTP response with a message and one page of items.
+// The page is described in meta "pagination" and as RFC 5988 Link headers
+// (first/prev/next/last) built from the bound request's URL.
+// Returns an error if the writer is nil or sending the response fails.
+func (r *Renderer) Paginate(msg string, items interface{}, page Pagination) error {
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
+	}
+	page = page.normalize()
+	nr := r.WithMeta(metaPagination, page)
+	if links := page.links(r.request, itemCount(items)); links != Empty {
+		nr = nr.WithHeader("Link", links)
+	}
+	return nr.WithStatus(http.StatusOK).Push(r.writer, Response{
+		Status:  StatusSuccessful,
+		Message: msg,
+		Data:    items,
+	})
+}
+
+// normalize fills in defaults and the derived page count.
+func (p Pagination) normalize() Pagination {
+	if p.IsCursor() {
+		return p
+	}
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage < 1 {
+		p.PerPage = DefaultPerPage
+	}
+	if p.Total > 0 {
+		p.Pages = (p.Total + int64(p.PerPage) - 1) / int64(p.PerPage)
+	}
+	return p
+}
+
+// links formats the Link header value for the page.
+// Without a known total, next is offered only when the page is full.
+func (p Pagination) links(req *http.Request, count int) string {
+	var base url.URL
+	if req != nil && req.URL != nil {
+		base = *req.URL
+	}
+	var parts []string
+	link := func(rel string, set map[string]string) {
+		u := base
+		q := u.Query()
+		for k, v := range set {
+			if v == Empty {
+				q.Del(k)
+			} else {
+				q.Set(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+		parts = append(parts, "<"+u.String()+`>; rel="`+rel+`"`)
+	}
+
+	if p.IsCursor() {
+		link("first", map[string]string{CursorParam: Empty})
+		if p.PrevCursor != Empty {
+			link("prev", map[string]string{CursorParam: p.PrevCursor})
+		}
+		if p.NextCursor != Empty {
+			link("next", map[string]string{CursorParam: p.NextCursor})
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	page := func(n int64) map[string]string {
+		return map[string]string{
+			PageParam:    strconv.FormatInt(n, 10),
+			PerPageParam: strconv.Itoa(p.PerPage),
+		}
+	}
+	current := int64(p.Page)
+	link("first", page(1))
+	if current > 1 {
+		link("prev", page(current-1))
+	}
+	if (p.Pages > 0 && current < p.Pages) || (p.Total == 0 && count >= p.PerPage) {
+		link("next", page(current+1))
+	}
+	if p.Pages > 0 {
+		link("last", page(p.Pages))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// itemCount returns the length of a slice or array, or -1 for other values.
+func itemCount(items interface{}) int {
+	rv := reflect.ValueOf(items)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return rv.Len()
+	}
+	return -1
+}
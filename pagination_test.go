@@ -0,0 +1,74 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Run("Offset", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=10&sort=name", nil)
+		r := NewRenderer(settings).WithWriter(w).WithRequest(req)
+		page := r.PageRequest()
+		if page.Page != 2 || page.PerPage != 10 {
+			t.Fatalf("Expected page 2 of 10, got %+v", page)
+		}
+		page.Total = 35
+		if err := r.Paginate("users", []int{11, 12}, page); err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		want := `</users?page=1&per_page=10&sort=name>; rel="first", ` +
+			`</users?page=1&per_page=10&sort=name>; rel="prev", ` +
+			`</users?page=3&per_page=10&sort=name>; rel="next", ` +
+			`</users?page=4&per_page=10&sort=name>; rel="last"`
+		if got := w.Header().Get("Link"); got != want {
+			t.Errorf("Expected Link %q, got %q", want, got)
+		}
+		var body struct {
+			Meta struct {
+				Pagination Pagination `json:"pagination"`
+			} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if p := body.Meta.Pagination; p.Page != 2 || p.Total != 35 || p.Pages != 4 {
+			t.Errorf("Expected page 2 of 4, got %+v", p)
+		}
+	})
+
+	t.Run("UnknownTotal", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithRequest(httptest.NewRequest(http.MethodGet, "/items", nil))
+		if err := r.Paginate("items", []int{1}, Pagination{PerPage: 2}); err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		if got, want := w.Header().Get("Link"), `</items?page=1&per_page=2>; rel="first"`; got != want {
+			t.Errorf("Expected Link %q for a short page, got %q", want, got)
+		}
+	})
+
+	t.Run("MaxPerPage", func(t *testing.T) {
+		r := NewRenderer(settings).WithRequest(httptest.NewRequest(http.MethodGet, "/users?per_page=1000000", nil))
+		if page := r.PageRequest(); page.PerPage != MaxPerPage {
+			t.Errorf("Expected per_page clamped to %d, got %d", MaxPerPage, page.PerPage)
+		}
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithRequest(httptest.NewRequest(http.MethodGet, "/events?cursor=b", nil))
+		page := r.PageRequest()
+		page.NextCursor, page.PrevCursor = "c", "a"
+		if err := r.Paginate("events", []string{"x"}, page); err != nil {
+			t.Fatalf("Paginate failed: %v", err)
+		}
+		want := `</events>; rel="first", </events?cursor=a>; rel="prev", </events?cursor=c>; rel="next"`
+		if got := w.Header().Get("Link"); got != want {
+			t.Errorf("Expected Link %q, got %q", want, got)
+		}
+	})
+}
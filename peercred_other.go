@@ -0,0 +1,10 @@
+//go:build !linux
+
+package beam
+
+import "net"
+
+// peerCred is unsupported outside Linux.
+func peerCred(c *net.UnixConn) (PeerCred, error) {
+	return PeerCred{}, ErrPeerCredUnsupported
+}
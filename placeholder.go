@@ -0,0 +1,133 @@
+package beam
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Defaults of ImageFallback.
+const (
+	DefaultPlaceholderSize   = 128
+	DefaultPlaceholderMaxAge = 5 * time.Minute
+)
+
+// Placeholder colors used when ImageFallback leaves them unset.
+var (
+	DefaultPlaceholderBackground color.Color = color.RGBA{R: 0xcb, G: 0xd5, B: 0xe1, A: 0xff}
+	DefaultPlaceholderForeground color.Color = color.White
+)
+
+// ImageFallback configures the image sent in place of a missing one, so
+// <img> tags render a placeholder instead of breaking on a JSON error.
+// A configured Image takes precedence over a generated placeholder.
+type ImageFallback struct {
+	Image       []byte // Error image to send; nil generates a placeholder
+	ContentType string // Content type of Image; detected when empty
+
+	Width, Height int         // Placeholder size; zero uses DefaultPlaceholderSize
+	Background    color.Color // Placeholder color; nil uses DefaultPlaceholderBackground
+	Foreground    color.Color // Initials color; nil uses DefaultPlaceholderForeground
+	Initials      string      // Letters drawn centered on the placeholder, e.g. a user's initials
+
+	Status int           // HTTP status; zero uses 404 Not Found
+	MaxAge time.Duration // Cache lifetime, kept short so the real image shows once it exists; zero uses DefaultPlaceholderMaxAge
+}
+
+// WithImageFallback makes Image (given a nil image), Media (given a missing
+// file), and MissingImage send fb's image instead of an error.
+// Returns a new Renderer with the fallback set.
+func (r *Renderer) WithImageFallback(fb ImageFallback) *Renderer {
+	nr := r.clone()
+	nr.imageFallback = &fb
+	return nr
+}
+
+// MissingImage sends the image configured with WithImageFallback, or a
+// default placeholder, for image endpoints whose source is missing.
+// Returns an error if encoding or writing fails.
+func (r *Renderer) MissingImage() error {
+	fb := ImageFallback{}
+	if r.imageFallback != nil {
+		fb = *r.imageFallback
+	}
+	data, contentType := fb.Image, fb.ContentType
+	if data == nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, placeholderImage(fb)); err != nil {
+			return err
+		}
+		data, contentType = buf.Bytes(), ContentTypePNG
+	} else if contentType == Empty {
+		contentType = http.DetectContentType(data)
+	}
+
+	status := fb.Status
+	if status == 0 {
+		status = http.StatusNotFound
+	}
+	maxAge := fb.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultPlaceholderMaxAge
+	}
+	nr := r.WithStatus(status)
+	nr.header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	return nr.Binary(contentType, data)
+}
+
+// placeholderImage draws a solid placeholder with fb's initials.
+func placeholderImage(fb ImageFallback) image.Image {
+	w, h := fb.Width, fb.Height
+	if w <= 0 {
+		w = DefaultPlaceholderSize
+	}
+	if h <= 0 {
+		h = DefaultPlaceholderSize
+	}
+	bg, fg := fb.Background, fb.Foreground
+	if bg == nil {
+		bg = DefaultPlaceholderBackground
+	}
+	if fg == nil {
+		fg = DefaultPlaceholderForeground
+	}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	initials := strings.ToUpper(strings.TrimSpace(fb.Initials))
+	if initials == Empty {
+		return img
+	}
+	// Render the text with the built-in bitmap font, then scale it up to
+	// roughly half the placeholder's height.
+	face := basicfont.Face7x13
+	d := &font.Drawer{Face: face, Src: image.Opaque}
+	tw, th := d.MeasureString(initials).Ceil(), face.Height
+	mask := image.NewAlpha(image.Rect(0, 0, tw, th))
+	d.Dst, d.Dot = mask, fixed.P(0, face.Ascent)
+	d.DrawString(initials)
+
+	scale := max(min(w*6/10/max(tw, 1), h/2/th), 1)
+	ox, oy := (w-tw*scale)/2, (h-th*scale)/2
+	ink := image.NewUniform(fg)
+	for y := 0; y < th; y++ {
+		for x := 0; x < tw; x++ {
+			if mask.AlphaAt(x, y).A == 0 {
+				continue
+			}
+			cell := image.Rect(ox+x*scale, oy+y*scale, ox+(x+1)*scale, oy+(y+1)*scale)
+			draw.Draw(img, cell, ink, image.Point{}, draw.Over)
+		}
+	}
+	return img
+}
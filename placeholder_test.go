@@ -0,0 +1,78 @@
+package beam
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestImageFallback(t *testing.T) {
+	t.Run("Placeholder", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		red := color.RGBA{R: 0xff, A: 0xff}
+		err := NewRenderer(settings).
+			WithImageFallback(ImageFallback{Width: 80, Height: 40, Background: red, Initials: "jd"}).
+			WithWriter(w).
+			Image(ContentTypePNG, nil)
+		if err != nil {
+			t.Fatalf("Image failed: %v", err)
+		}
+		if w.Code != http.StatusNotFound || w.Header().Get(HeaderContentType) != ContentTypePNG {
+			t.Errorf("Expected a 404 PNG, got %d %v", w.Code, w.Header())
+		}
+		if got := w.Header().Get("Cache-Control"); got != "public, max-age=300" {
+			t.Errorf("Expected a short cache lifetime, got %q", got)
+		}
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("Invalid PNG: %v", err)
+		}
+		if b := img.Bounds(); b.Dx() != 80 || b.Dy() != 40 {
+			t.Errorf("Expected an 80x40 placeholder, got %v", b)
+		}
+		if r, g, b, _ := img.At(0, 0).RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+			t.Error("Expected the background color in the corner")
+		}
+		inked := false
+		for x := 0; x < 80 && !inked; x++ {
+			r, g, b, _ := img.At(x, 20).RGBA()
+			inked = r>>8 == 0xff && g>>8 == 0xff && b>>8 == 0xff
+		}
+		if !inked {
+			t.Error("Expected the initials drawn across the middle")
+		}
+	})
+
+	t.Run("ErrorImage", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, placeholderImage(ImageFallback{Width: 2, Height: 2})); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).
+			WithImageFallback(ImageFallback{Image: buf.Bytes(), Status: http.StatusOK, MaxAge: time.Minute}).
+			WithWriter(w).
+			Media(filepath.Join(t.TempDir(), "missing.jpg"), Empty)
+		if err != nil {
+			t.Fatalf("Media failed: %v", err)
+		}
+		if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), buf.Bytes()) {
+			t.Errorf("Expected the configured error image, got %d", w.Code)
+		}
+		if w.Header().Get(HeaderContentType) != ContentTypePNG || w.Header().Get("Cache-Control") != "public, max-age=60" {
+			t.Errorf("Unexpected headers %v", w.Header())
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).Media(filepath.Join(t.TempDir(), "missing.jpg"), Empty); err == nil {
+			t.Error("Expected an error without a fallback")
+		}
+	})
+}
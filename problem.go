@@ -0,0 +1,96 @@
+package beam
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Problem Details media types (RFC 7807).
+const (
+	ContentTypeProblemJSON = "application/problem+json"
+	ContentTypeProblemXML  = "application/problem+xml"
+)
+
+// problemBlank is the RFC 7807 type for problems described by their status code alone.
+const problemBlank = "about:blank"
+
+// ProblemDetails is the RFC 7807 form of an error or fatal Response.
+// Errors and Info are extension members carrying the response's errors and info.
+type ProblemDetails struct {
+	XMLName xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem" msgpack:"-"`
+
+	Type     string      `json:"type" xml:"type" msgpack:"type"`
+	Title    string      `json:"title,omitempty" xml:"title,omitempty" msgpack:"title,omitempty"`
+	Status   int         `json:"status,omitempty" xml:"status,omitempty" msgpack:"status,omitempty"`
+	Detail   string      `json:"detail,omitempty" xml:"detail,omitempty" msgpack:"detail,omitempty"`
+	Instance string      `json:"instance,omitempty" xml:"instance,omitempty" msgpack:"instance,omitempty"`
+	Errors   ErrorList   `json:"errors,omitempty" xml:"errors,omitempty" msgpack:"errors,omitempty"`
+	Info     interface{} `json:"info,omitempty" xml:"info,omitempty" msgpack:"info,omitempty"`
+}
+
+// ProblemTyper is implemented by errors that identify their RFC 7807 problem type.
+// The first response error implementing it sets the problem's type URI.
+type ProblemTyper interface {
+	ProblemType() string
+}
+
+// WithProblemDetails enables or disables RFC 7807 output for Push.
+// Error and fatal responses are then encoded as ProblemDetails with an
+// application/problem+json (or +xml) content type; other responses are unchanged.
+// Returns a new Renderer with the updated setting.
+func (r *Renderer) WithProblemDetails(enabled State) *Renderer {
+	nr := r.clone()
+	nr.problem = enabled
+	return nr
+}
+
+// isProblem reports whether resp is rendered as Problem Details.
+func (r *Renderer) isProblem(resp *Response) bool {
+	return r.problem.Enabled() && (resp.Status == StatusError || resp.Status == StatusFatal)
+}
+
+// problemDetails derives the RFC 7807 object for resp from the response and status code.
+// type defaults to about:blank, whose title is the status text;
+// detail is the message and instance the request URI, if a request is bound.
+func (r *Renderer) problemDetails(resp *Response) ProblemDetails {
+	p := ProblemDetails{
+		Type:   problemBlank,
+		Title:  http.StatusText(r.code),
+		Status: r.code,
+		Detail: resp.Message,
+		Errors: resp.Errors,
+		Info:   resp.Info,
+	}
+	for _, err := range resp.Errors {
+		var pt ProblemTyper
+		if errors.As(err, &pt) && pt.ProblemType() != Empty {
+			p.Type = pt.ProblemType()
+			break
+		}
+	}
+	if p.Type != problemBlank && resp.Title != Empty {
+		p.Title = resp.Title
+	}
+	if r.request != nil {
+		p.Instance = r.request.URL.RequestURI()
+	}
+	return p
+}
+
+// responseType returns the Content-Type header for resp.
+// Problem Details use the problem media type matching the encoding's suffix.
+func (r *Renderer) responseType(resp *Response) string {
+	if !r.isProblem(resp) || r.contentType == ContentTypeJSONAPI {
+		// JSON:API renders problems as its own error objects.
+		return r.contentType
+	}
+	switch {
+	case r.contentType == ContentTypeJSON || strings.HasSuffix(r.contentType, "+json"):
+		return ContentTypeProblemJSON
+	case r.contentType == ContentTypeXML || strings.HasSuffix(r.contentType, "+xml"):
+		return ContentTypeProblemXML
+	}
+	return r.contentType
+}
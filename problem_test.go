@@ -0,0 +1,78 @@
+package beam
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type outOfCreditError struct{}
+
+func (outOfCreditError) Error() string       { return "balance too low" }
+func (outOfCreditError) ProblemType() string { return "https://example.com/probs/out-of-credit" }
+
+func TestWithProblemDetails(t *testing.T) {
+	base := NewRenderer(settings).WithProblemDetails(Yes)
+	req := httptest.NewRequest(http.MethodPost, "/accounts/12345/msgs?x=1", nil)
+
+	t.Run("JSON", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_ = base.WithWriter(rec).WithRequest(req).ErrorMsg("name is required", errors.New("missing name"))
+		if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeProblemJSON {
+			t.Errorf("Expected %s, got %s", ContentTypeProblemJSON, ct)
+		}
+		var p ProblemDetails
+		if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+			t.Fatalf("Invalid problem JSON: %v", err)
+		}
+		if p.Type != "about:blank" || p.Title != "Bad Request" || p.Status != http.StatusBadRequest ||
+			p.Detail != "name is required" || p.Instance != "/accounts/12345/msgs?x=1" {
+			t.Errorf("Unexpected problem %+v", p)
+		}
+	})
+
+	t.Run("ProblemTyper", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_ = base.WithWriter(rec).WithStatus(http.StatusForbidden).Push(rec, Response{
+			Status:  StatusError,
+			Title:   "You do not have enough credit.",
+			Message: "Your current balance is 30, but that costs 50.",
+			Errors:  ErrorList{outOfCreditError{}},
+		})
+		var p ProblemDetails
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if p.Type != "https://example.com/probs/out-of-credit" || p.Title != "You do not have enough credit." || p.Status != http.StatusForbidden {
+			t.Errorf("Unexpected problem %+v", p)
+		}
+	})
+
+	t.Run("XML", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_ = base.WithContentType(ContentTypeXML).WithWriter(rec).Fatal(errors.New("db down"))
+		if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeProblemXML {
+			t.Errorf("Expected %s, got %s", ContentTypeProblemXML, ct)
+		}
+		if !strings.Contains(rec.Body.String(), `<problem xmlns="urn:ietf:rfc:7807">`) {
+			t.Errorf("Expected RFC 7807 XML root, got %s", rec.Body)
+		}
+		var p ProblemDetails
+		if err := xml.Unmarshal(rec.Body.Bytes(), &p); err != nil || p.Status != http.StatusInternalServerError {
+			t.Errorf("Unexpected problem %+v (%v)", p, err)
+		}
+	})
+
+	t.Run("SuccessUnchanged", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		_ = base.WithWriter(rec).Msg("fine")
+		if ct := rec.Header().Get(HeaderContentType); ct != ContentTypeJSON {
+			t.Errorf("Expected %s, got %s", ContentTypeJSON, ct)
+		}
+		if !strings.Contains(rec.Body.String(), `"status":"+ok"`) {
+			t.Errorf("Expected regular envelope, got %s", rec.Body)
+		}
+	})
+}
@@ -0,0 +1,295 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidSetting matches errors returned by LoadSetting for unreadable or
+// malformed configuration.
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// DefaultEnvPrefix is the environment variable prefix used by FromEnv when given none.
+const DefaultEnvPrefix = "BEAM_"
+
+// Profile is a Renderer configuration loaded by LoadSetting, so deployments
+// can change content type, headers, presets, system metadata, and compression
+// without code changes.
+type Profile struct {
+	Setting     Setting
+	System      System
+	Show        SystemShow   // Where System metadata is displayed
+	Compression *Compression // Nil leaves compression disabled
+}
+
+// Renderer creates a Renderer configured by the profile.
+func (p Profile) Renderer() *Renderer {
+	r := NewRenderer(p.Setting).WithHeadersEnabled(p.Setting.EnableHeaders)
+	if p.System != (System{}) || p.Show != SystemShowNone {
+		r = r.WithSystem(p.Show, p.System)
+	}
+	if p.Compression != nil {
+		r = r.WithCompression(*p.Compression)
+	}
+	return r
+}
+
+// SettingSource applies one layer of configuration to a Profile.
+type SettingSource func(p *Profile) error
+
+// LoadSetting builds a Profile from sources applied in order, later sources
+// overriding earlier ones, e.g. LoadSetting(FromFile("beam.yaml"), FromEnv("")).
+// Headers are enabled unless a source disables them.
+// Returns an error matching ErrInvalidSetting if any source fails.
+func LoadSetting(sources ...SettingSource) (Profile, error) {
+	p := Profile{Setting: Setting{EnableHeaders: true}}
+	for _, src := range sources {
+		if err := src(&p); err != nil {
+			return Profile{}, err
+		}
+	}
+	return p, nil
+}
+
+// settingFile is the JSON/YAML layout read by FromFile.
+// Pointer fields distinguish absent keys from zero values.
+type settingFile struct {
+	Name        *string               `json:"name" yaml:"name"`
+	ContentType *string               `json:"content_type" yaml:"content_type"`
+	Headers     *bool                 `json:"headers" yaml:"headers"`
+	Presets     map[string]presetFile `json:"presets" yaml:"presets"`
+	System      *systemFile           `json:"system" yaml:"system"`
+	Compression *compressionFile      `json:"compression" yaml:"compression"`
+}
+
+type presetFile struct {
+	ContentType string            `json:"content_type" yaml:"content_type"`
+	Headers     map[string]string `json:"headers" yaml:"headers"`
+}
+
+type systemFile struct {
+	App     *string `json:"app" yaml:"app"`
+	Server  *string `json:"server" yaml:"server"`
+	Version *string `json:"version" yaml:"version"`
+	Build   *string `json:"build" yaml:"build"`
+	Show    *string `json:"show" yaml:"show"`
+}
+
+type compressionFile struct {
+	Enabled   *bool          `json:"enabled" yaml:"enabled"`
+	Encodings []string       `json:"encodings" yaml:"encodings"`
+	MinSize   *int           `json:"min_size" yaml:"min_size"`
+	MinSizes  map[string]int `json:"min_sizes" yaml:"min_sizes"`
+	Level     *int           `json:"level" yaml:"level"`
+}
+
+// FromFile reads a JSON (.json) or YAML (any other extension) config file
+// with the keys name, content_type, headers, presets, system (app, server,
+// version, build, show), and compression (enabled, encodings, min_size,
+// min_sizes, level). Unknown keys are rejected so typos surface at startup.
+func FromFile(path string) SettingSource {
+	return func(p *Profile) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSetting, err)
+		}
+		var f settingFile
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			err = dec.Decode(&f)
+		} else {
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true)
+			if err = dec.Decode(&f); errors.Is(err, io.EOF) {
+				err = nil // Empty file
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidSetting, path, err)
+		}
+		return f.apply(p)
+	}
+}
+
+// apply overlays the keys present in the file onto p.
+func (f settingFile) apply(p *Profile) error {
+	setString(&p.Setting.Name, f.Name)
+	setString(&p.Setting.ContentType, f.ContentType)
+	if f.Headers != nil {
+		p.Setting.EnableHeaders = *f.Headers
+	}
+	for name, pf := range f.Presets {
+		if p.Setting.Presets == nil {
+			p.Setting.Presets = make(map[string]Preset)
+		}
+		preset := Preset{ContentType: pf.ContentType, Headers: make(http.Header)}
+		for k, v := range pf.Headers {
+			preset.Headers.Set(k, v)
+		}
+		p.Setting.Presets[name] = preset
+	}
+	if s := f.System; s != nil {
+		setString(&p.System.App, s.App)
+		setString(&p.System.Server, s.Server)
+		setString(&p.System.Version, s.Version)
+		setString(&p.System.Build, s.Build)
+		if s.Show != nil {
+			show, err := parseSystemShow(*s.Show)
+			if err != nil {
+				return err
+			}
+			p.Show = show
+		}
+	}
+	if c := f.Compression; c != nil {
+		if c.Enabled != nil && !*c.Enabled {
+			p.Compression = nil
+			return nil
+		}
+		comp := p.compression()
+		if len(c.Encodings) > 0 {
+			comp.Encodings = c.Encodings
+		}
+		if c.MinSize != nil {
+			comp.MinSize = *c.MinSize
+		}
+		if c.MinSizes != nil {
+			comp.MinSizes = c.MinSizes
+		}
+		if c.Level != nil {
+			comp.Level = *c.Level
+		}
+	}
+	return nil
+}
+
+// FromEnv reads configuration from environment variables named prefix plus
+// NAME, CONTENT_TYPE, HEADERS, APP, SERVER, VERSION, BUILD, SYSTEM_SHOW,
+// COMPRESSION (comma-separated codings, or "off"), COMPRESSION_MIN_SIZE, and
+// COMPRESSION_LEVEL. The last two tune compression only when it is enabled, so
+// they never override COMPRESSION=off. An empty prefix uses DefaultEnvPrefix;
+// unset variables leave the Profile unchanged.
+func FromEnv(prefix string) SettingSource {
+	if prefix == Empty {
+		prefix = DefaultEnvPrefix
+	}
+	return func(p *Profile) error {
+		env := func(key string) (string, bool) {
+			return os.LookupEnv(prefix + key)
+		}
+		if v, ok := env("NAME"); ok {
+			p.Setting.Name = v
+		}
+		if v, ok := env("CONTENT_TYPE"); ok {
+			p.Setting.ContentType = v
+		}
+		if v, ok := env("HEADERS"); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%w: %sHEADERS: %v", ErrInvalidSetting, prefix, err)
+			}
+			p.Setting.EnableHeaders = b
+		}
+		for key, field := range map[string]*string{
+			"APP": &p.System.App, "SERVER": &p.System.Server,
+			"VERSION": &p.System.Version, "BUILD": &p.System.Build,
+		} {
+			if v, ok := env(key); ok {
+				*field = v
+			}
+		}
+		if v, ok := env("SYSTEM_SHOW"); ok {
+			show, err := parseSystemShow(v)
+			if err != nil {
+				return err
+			}
+			p.Show = show
+		}
+		if v, ok := env("COMPRESSION"); ok {
+			switch strings.ToLower(strings.TrimSpace(v)) {
+			case "off", "false", "none", Empty:
+				p.Compression = nil
+			case "on", "true":
+				p.compression()
+			default:
+				comp := p.compression()
+				comp.Encodings = nil
+				for _, enc := range strings.Split(v, ",") {
+					comp.Encodings = append(comp.Encodings, strings.TrimSpace(enc))
+				}
+			}
+		}
+		minSize := func(n int) {
+			if p.Compression != nil {
+				p.Compression.MinSize = n
+			}
+		}
+		level := func(n int) {
+			if p.Compression != nil {
+				p.Compression.Level = n
+			}
+		}
+		if err := envInt(prefix, "COMPRESSION_MIN_SIZE", minSize); err != nil {
+			return err
+		}
+		if err := envInt(prefix, "COMPRESSION_LEVEL", level); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// compression returns the Profile's Compression, enabling it if needed.
+func (p *Profile) compression() *Compression {
+	if p.Compression == nil {
+		p.Compression = &Compression{}
+	}
+	return p.Compression
+}
+
+// envInt passes the integer value of prefix+key to set when the variable is set.
+func envInt(prefix, key string, set func(int)) error {
+	v, ok := os.LookupEnv(prefix + key)
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s%s: %v", ErrInvalidSetting, prefix, key, err)
+	}
+	set(n)
+	return nil
+}
+
+// setString assigns *src to dst when src is set.
+func setString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// parseSystemShow parses "none", "headers", "body", or "both".
+func parseSystemShow(s string) (SystemShow, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "none", Empty:
+		return SystemShowNone, nil
+	case "headers":
+		return SystemShowHeaders, nil
+	case "body":
+		return SystemShowBody, nil
+	case "both":
+		return SystemShowBoth, nil
+	}
+	return SystemShowNone, fmt.Errorf("%w: unknown system show %q", ErrInvalidSetting, s)
+}
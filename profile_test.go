@@ -0,0 +1,99 @@
+package beam
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSetting(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "beam.yaml")
+	err := os.WriteFile(yamlPath, []byte(`
+name: api
+content_type: application/xml
+presets:
+  report:
+    content_type: text/csv
+    headers:
+      X-Report: daily
+system:
+  app: billing
+  version: "1.2"
+  show: headers
+compression:
+  encodings: [gzip]
+  min_size: 10
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("File", func(t *testing.T) {
+		p, err := LoadSetting(FromFile(yamlPath))
+		if err != nil {
+			t.Fatalf("LoadSetting failed: %v", err)
+		}
+		if p.Setting.Name != "api" || p.Setting.ContentType != ContentTypeXML || !p.Setting.EnableHeaders {
+			t.Errorf("Unexpected setting %+v", p.Setting)
+		}
+		if got := p.Setting.Presets["report"].Headers.Get("X-Report"); got != "daily" {
+			t.Errorf("Expected preset header, got %q", got)
+		}
+		if p.System.App != "billing" || p.Show != SystemShowHeaders {
+			t.Errorf("Unexpected system %+v (show %d)", p.System, p.Show)
+		}
+		if c := p.Compression; c == nil || len(c.Encodings) != 1 || c.MinSize != 10 {
+			t.Errorf("Unexpected compression %+v", c)
+		}
+	})
+
+	t.Run("EnvOverridesFile", func(t *testing.T) {
+		t.Setenv("TEST_CONTENT_TYPE", ContentTypeJSON)
+		t.Setenv("TEST_HEADERS", "false")
+		t.Setenv("TEST_COMPRESSION", "off")
+		t.Setenv("TEST_COMPRESSION_MIN_SIZE", "512")
+		t.Setenv("TEST_COMPRESSION_LEVEL", "3")
+		p, err := LoadSetting(FromFile(yamlPath), FromEnv("TEST_"))
+		if err != nil {
+			t.Fatalf("LoadSetting failed: %v", err)
+		}
+		if p.Setting.Name != "api" || p.Setting.ContentType != ContentTypeJSON || p.Setting.EnableHeaders {
+			t.Errorf("Unexpected setting %+v", p.Setting)
+		}
+		if p.Compression != nil {
+			t.Errorf("Expected compression disabled, got %+v", p.Compression)
+		}
+	})
+
+	t.Run("Renderer", func(t *testing.T) {
+		p, err := LoadSetting(FromFile(yamlPath))
+		if err != nil {
+			t.Fatalf("LoadSetting failed: %v", err)
+		}
+		r := p.Renderer().WithContentType(ContentTypeJSON)
+		w := httptest.NewRecorder()
+		if err := r.Push(w, Response{Message: "ok"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if got := w.Header().Get(r.headerName(HeaderNameApp)); got != "billing" {
+			t.Errorf("Expected app header billing, got %v", w.Header())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		jsonPath := filepath.Join(dir, "beam.json")
+		if err := os.WriteFile(jsonPath, []byte(`{"content_typ": "x"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSetting(FromFile(jsonPath)); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("Expected ErrInvalidSetting for unknown key, got %v", err)
+		}
+		t.Setenv("TEST_SYSTEM_SHOW", "sideways")
+		if _, err := LoadSetting(FromEnv("TEST_")); !errors.Is(err, ErrInvalidSetting) {
+			t.Errorf("Expected ErrInvalidSetting for bad show, got %v", err)
+		}
+	})
+}
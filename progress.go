@@ -0,0 +1,141 @@
+package beam
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StreamProgress describes how far a Stream has got.
+// Carried by StatusProgress callbacks so long-lived streams can be observed
+// between their start and end.
+type StreamProgress struct {
+	Chunk   int           `json:"chunk"`   // Number of chunks written so far
+	Bytes   int64         `json:"bytes"`   // Bytes written so far
+	Elapsed time.Duration `json:"elapsed"` // Time since the stream started
+}
+
+// progressPolicy sets how often Stream emits progress callbacks.
+type progressPolicy struct {
+	every    int           // Emit every this many chunks; zero disables
+	interval time.Duration // Emit when this much time passed since the last emission; zero disables
+}
+
+// WithStreamProgress makes Stream trigger StatusProgress callbacks carrying a
+// StreamProgress: every `every` chunks, or once interval has passed since the
+// last progress callback, whichever comes first. Zero disables either trigger.
+// Returns a new Renderer with the progress frequency set.
+func (r *Renderer) WithStreamProgress(every int, interval time.Duration) *Renderer {
+	nr := r.clone()
+	nr.progress = progressPolicy{every: every, interval: interval}
+	return nr
+}
+
+// progressTracker counts a stream's chunks and bytes and emits progress callbacks.
+type progressTracker struct {
+	r      *Renderer
+	policy progressPolicy
+	start  time.Time
+	last   time.Time
+	chunks int
+	bytes  int64
+}
+
+// newProgressTracker returns a tracker for r, or nil when progress is disabled.
+func (r *Renderer) newProgressTracker() *progressTracker {
+	if r.progress.every <= 0 && r.progress.interval <= 0 {
+		return nil
+	}
+	return &progressTracker{r: r, policy: r.progress, start: r.start, last: r.start}
+}
+
+// chunk records a written chunk and emits a progress callback when one is due.
+func (p *progressTracker) chunk() {
+	if p == nil {
+		return
+	}
+	p.chunks++
+	now := p.r.now()
+	due := p.policy.every > 0 && p.chunks%p.policy.every == 0
+	if p.policy.interval > 0 && now.Sub(p.last) >= p.policy.interval {
+		due = true
+	}
+	if !due {
+		return
+	}
+	p.last = now
+	progress := StreamProgress{Chunk: p.chunks, Bytes: p.bytes, Elapsed: now.Sub(p.start)}
+	p.r.emit(CallbackData{
+		ID:       p.r.id,
+		Status:   StatusProgress,
+		Message:  fmt.Sprintf("streamed %d chunks, %d bytes", progress.Chunk, progress.Bytes),
+		Progress: &progress,
+	})
+}
+
+// writer wraps w so bytes written by a Streamer encoder are counted.
+// The returned writer keeps flushing and header access available.
+func (p *progressTracker) writer(w Writer) Writer {
+	if p == nil {
+		return w
+	}
+	return &progressWriter{Writer: w, n: &p.bytes}
+}
+
+// wrap counts each item next hands to a Streamer encoder as a chunk once the
+// encoder asks for the following one, by which time the item has been written.
+func (p *progressTracker) wrap(next func() (interface{}, error)) func() (interface{}, error) {
+	if p == nil {
+		return next
+	}
+	pending := false
+	return func() (interface{}, error) {
+		if pending {
+			p.chunk()
+		}
+		data, err := next()
+		pending = err == nil
+		return data, err
+	}
+}
+
+// progressWriter counts bytes written through it into n.
+type progressWriter struct {
+	Writer
+	n *int64
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	n, err := w.Writer.Write(b)
+	*w.n += int64(n)
+	return n, err
+}
+
+// Header exposes the underlying ResponseWriter's headers, if any.
+func (w *progressWriter) Header() http.Header {
+	if hw, ok := w.Writer.(http.ResponseWriter); ok {
+		return hw.Header()
+	}
+	return http.Header{}
+}
+
+// WriteHeader forwards the status code to the underlying ResponseWriter, if any.
+func (w *progressWriter) WriteHeader(code int) {
+	if hw, ok := w.Writer.(http.ResponseWriter); ok {
+		hw.WriteHeader(code)
+	}
+}
+
+// Flush forwards to the underlying writer when it supports flushing.
+func (w *progressWriter) Flush() {
+	if f, ok := w.Writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying ResponseWriter, if any, to
+// http.ResponseController and server push.
+func (w *progressWriter) Unwrap() http.ResponseWriter {
+	hw, _ := w.Writer.(http.ResponseWriter)
+	return hw
+}
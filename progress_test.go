@@ -0,0 +1,121 @@
+package beam
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// deadlineRecorder is a ResponseRecorder supporting write deadlines.
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadline time.Time
+}
+
+func (d *deadlineRecorder) SetWriteDeadline(t time.Time) error {
+	d.deadline = t
+	return nil
+}
+
+func TestWithStreamProgress(t *testing.T) {
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeNDJSON} {
+		t.Run(contentType, func(t *testing.T) {
+			var progress []StreamProgress
+			rec := httptest.NewRecorder()
+			r := NewRenderer(settings).
+				WithWriter(rec).
+				WithContentType(contentType).
+				WithStreamProgress(2, 0).
+				WithCallback(func(d CallbackData) {
+					if d.Status == StatusProgress {
+						progress = append(progress, *d.Progress)
+					}
+				})
+
+			n := 0
+			err := r.Stream(func(*Renderer) (interface{}, error) {
+				if n == 5 {
+					return nil, io.EOF
+				}
+				n++
+				return map[string]int{"n": n}, nil
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(progress) != 2 || progress[0].Chunk != 2 || progress[1].Chunk != 4 {
+				t.Fatalf("Expected progress at chunks 2 and 4, got %+v", progress)
+			}
+			if progress[0].Bytes == 0 || progress[1].Bytes <= progress[0].Bytes {
+				t.Errorf("Expected growing byte counts, got %+v", progress)
+			}
+			if progress[1].Bytes > int64(rec.Body.Len()) {
+				t.Errorf("Progress bytes %d exceed body length %d", progress[1].Bytes, rec.Body.Len())
+			}
+		})
+	}
+
+	t.Run("Interval", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		var progress []StreamProgress
+		r := NewRenderer(settings).
+			WithWriter(httptest.NewRecorder()).
+			WithClock(ClockFunc(func() time.Time { return now })).
+			WithStreamProgress(0, time.Second).
+			WithCallback(func(d CallbackData) {
+				if d.Status == StatusProgress {
+					progress = append(progress, *d.Progress)
+				}
+			})
+
+		n := 0
+		_ = r.Stream(func(*Renderer) (interface{}, error) {
+			if n == 4 {
+				return nil, io.EOF
+			}
+			n++
+			now = now.Add(600 * time.Millisecond)
+			return n, nil
+		})
+		// Chunks end at 0.6s, 1.2s, 1.8s, 2.4s: emitted after the 2nd and 4th.
+		if len(progress) != 2 || progress[0].Chunk != 2 || progress[1].Elapsed != 2400*time.Millisecond {
+			t.Errorf("Unexpected interval progress %+v", progress)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		called := false
+		r := NewRenderer(settings).WithWriter(httptest.NewRecorder()).WithCallback(func(d CallbackData) {
+			if d.Status == StatusProgress {
+				called = true
+			}
+		})
+		done := false
+		_ = r.Stream(func(*Renderer) (interface{}, error) {
+			if done {
+				return nil, io.EOF
+			}
+			done = true
+			return 1, nil
+		})
+		if called {
+			t.Error("Expected no progress callbacks by default")
+		}
+	})
+
+	t.Run("Unwrap", func(t *testing.T) {
+		pr := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		var n int64
+		w := &progressWriter{Writer: pr, n: &n}
+		if findPusher(w) != pr {
+			t.Error("Expected findPusher to unwrap the progress writer")
+		}
+		dw := &deadlineRecorder{ResponseRecorder: httptest.NewRecorder()}
+		w = &progressWriter{Writer: dw, n: &n}
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now()); err != nil || dw.deadline.IsZero() {
+			t.Errorf("Expected ResponseController to reach the underlying writer, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package beam
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+// ContentTypePrometheus is the content type of the Prometheus text exposition format.
+const ContentTypePrometheus = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the histogram buckets, in seconds, used for durations.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets are the histogram buckets, in bytes, used for payload sizes.
+var DefaultSizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+
+// PrometheusCollector is a ready-made Collector keeping metrics in memory and
+// serving them in the Prometheus text exposition format, so Beam and hauler
+// metrics can be scraped without a client library:
+//
+//	metrics := beam.NewPrometheusCollector()
+//	mux.Handle("/metrics", metrics)
+//	r := beam.NewRenderer(s).WithMetrics(metrics)
+//
+// Counts become counters and observations histograms.
+type PrometheusCollector struct {
+	mu         sync.Mutex
+	buckets    map[string][]float64
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+// histogram is one labeled series of a Prometheus histogram.
+type histogram struct {
+	buckets []float64
+	counts  []uint64 // Per bucket, not cumulative
+	sum     float64
+	count   uint64
+}
+
+// NewPrometheusCollector returns an empty PrometheusCollector. Durations use
+// DefaultBuckets and the Beam and hauler size metrics DefaultSizeBuckets.
+func NewPrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{
+		buckets: map[string][]float64{
+			MetricResponseBytes:    DefaultSizeBuckets,
+			hauler.MetricBodyBytes: DefaultSizeBuckets,
+		},
+		counters:   make(map[string]map[string]float64),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+// WithBuckets sets the histogram bucket upper bounds of the named metric.
+// Series already observed keep their buckets.
+// Returns the collector for chaining.
+func (c *PrometheusCollector) WithBuckets(name string, buckets ...float64) *PrometheusCollector {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buckets[name] = slices.Sorted(slices.Values(buckets))
+	return c
+}
+
+// Count adds delta to the named counter.
+func (c *PrometheusCollector) Count(name string, delta float64, labels ...string) {
+	key := promLabels(labels)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, ok := c.counters[name]
+	if !ok {
+		series = make(map[string]float64)
+		c.counters[name] = series
+	}
+	series[key] += delta
+}
+
+// Observe records a sample in the named histogram.
+func (c *PrometheusCollector) Observe(name string, value float64, labels ...string) {
+	key := promLabels(labels)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, ok := c.histograms[name]
+	if !ok {
+		series = make(map[string]*histogram)
+		c.histograms[name] = series
+	}
+	h, ok := series[key]
+	if !ok {
+		buckets := c.bucketsOf(name)
+		h = &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		series[key] = h
+	}
+	if i, _ := slices.BinarySearch(h.buckets, value); i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.sum += value
+	h.count++
+}
+
+// bucketsOf returns the bucket upper bounds of the named histogram.
+func (c *PrometheusCollector) bucketsOf(name string) []float64 {
+	if b, ok := c.buckets[name]; ok {
+		return b
+	}
+	return DefaultBuckets
+}
+
+// WriteTo writes all metrics in the Prometheus text exposition format,
+// sorted by name and labels.
+// Returns the number of bytes written and any write error.
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	c.mu.Lock()
+	for _, name := range sortedKeys(c.counters) {
+		series := c.counters[name]
+		buf.WriteString("# TYPE " + name + " counter\n")
+		for _, key := range sortedKeys(series) {
+			buf.WriteString(name + key + " " + promFloat(series[key]) + "\n")
+		}
+	}
+	for _, name := range sortedKeys(c.histograms) {
+		series := c.histograms[name]
+		buf.WriteString("# TYPE " + name + " histogram\n")
+		for _, key := range sortedKeys(series) {
+			h := series[key]
+			var cumulative uint64
+			for i, le := range h.buckets {
+				cumulative += h.counts[i]
+				buf.WriteString(name + "_bucket" + promWithLabel(key, "le", promFloat(le)) + " " + strconv.FormatUint(cumulative, 10) + "\n")
+			}
+			buf.WriteString(name + "_bucket" + promWithLabel(key, "le", "+Inf") + " " + strconv.FormatUint(h.count, 10) + "\n")
+			buf.WriteString(name + "_sum" + key + " " + promFloat(h.sum) + "\n")
+			buf.WriteString(name + "_count" + key + " " + strconv.FormatUint(h.count, 10) + "\n")
+		}
+	}
+	c.mu.Unlock()
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set(HeaderContentType, ContentTypePrometheus)
+	_, _ = c.WriteTo(w)
+}
+
+// promLabels formats alternating key/value pairs as a Prometheus label set.
+// A trailing key without a value is dropped.
+func promLabels(labels []string) string {
+	if len(labels) < 2 {
+		return Empty
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString(`="`)
+		b.WriteString(promEscaper.Replace(labels[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// promWithLabel appends a label to a formatted label set.
+func promWithLabel(set, key, value string) string {
+	label := key + `="` + value + `"`
+	if set == Empty {
+		return "{" + label + "}"
+	}
+	return set[:len(set)-1] + "," + label + "}"
+}
+
+// promEscaper escapes label values as the exposition format requires.
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promFloat formats a sample value.
+func promFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
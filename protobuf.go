@@ -0,0 +1,48 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentTypeProtobuf is the content type for Protocol Buffers payloads.
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// errNotProtoMessage is returned when the protobuf encoder receives a value that is not a proto.Message.
+var errNotProtoMessage = errors.New("value is not a proto.Message")
+
+// ProtobufEncoder encodes proto.Message values in the Protocol Buffers wire format.
+// Protobuf clients expect their own message type rather than beam's envelope,
+// so a Response is encoded as its Data message; status, ID, and system
+// information travel in headers only.
+type ProtobufEncoder struct{}
+
+// Marshal encodes v, or the Data of a Response, as a protobuf message.
+// Takes a proto.Message or a Response whose Data is a proto.Message.
+// Returns the wire bytes or an error if v carries no proto.Message.
+func (e *ProtobufEncoder) Marshal(v interface{}) ([]byte, error) {
+	v = unwrapResponse(v)
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errNotProtoMessage, v)
+	}
+	return proto.Marshal(m)
+}
+
+// Unmarshal decodes protobuf wire data into the proto.Message v.
+// Returns an error if v is not a proto.Message or decoding fails.
+func (e *ProtobufEncoder) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T", errNotProtoMessage, v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// ContentType returns the Protocol Buffers content type.
+// Returns the constant "application/x-protobuf".
+func (e *ProtobufEncoder) ContentType() string {
+	return ContentTypeProtobuf
+}
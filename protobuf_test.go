@@ -0,0 +1,52 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtobufEncoder(t *testing.T) {
+	msg := wrapperspb.String("hello")
+	want, _ := proto.Marshal(msg)
+
+	t.Run("Raw", func(t *testing.T) {
+		w := &TestWriter{Headers: http.Header{}}
+		r := NewRenderer(settings).WithWriter(w).WithContentType(ContentTypeProtobuf)
+		if err := r.Raw(msg); err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		var got wrapperspb.StringValue
+		if err := proto.Unmarshal(w.Buffer.Bytes(), &got); err != nil || got.GetValue() != "hello" {
+			t.Errorf("Expected hello, got %q (%v)", got.GetValue(), err)
+		}
+	})
+
+	t.Run("PushData", func(t *testing.T) {
+		w := &TestWriter{Headers: http.Header{}}
+		r := NewRenderer(settings).WithContentType(ContentTypeProtobuf)
+		if err := r.Push(w, Response{Data: msg}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if string(w.Buffer.Bytes()) != string(want) {
+			t.Errorf("Expected %x, got %x", want, w.Buffer.Bytes())
+		}
+		if ct := w.Headers.Get(HeaderContentType); ct != ContentTypeProtobuf {
+			t.Errorf("Expected %s, got %s", ContentTypeProtobuf, ct)
+		}
+	})
+
+	t.Run("NotMessage", func(t *testing.T) {
+		e := &ProtobufEncoder{}
+		if _, err := e.Marshal(map[string]int{"a": 1}); !errors.Is(err, errNotProtoMessage) {
+			t.Errorf("Expected errNotProtoMessage, got %v", err)
+		}
+		var got wrapperspb.StringValue
+		if err := e.Unmarshal(want, &got); err != nil || got.GetValue() != "hello" {
+			t.Errorf("Expected hello, got %q (%v)", got.GetValue(), err)
+		}
+	})
+}
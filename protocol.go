@@ -6,7 +6,8 @@ import (
 
 // Protocol defines protocol-specific behavior.
 // Specifies a method to apply headers to a Writer.
-// Implemented by HTTPProtocol, TCPProtocol, and custom protocols.
+// Implemented by HTTPProtocol, TCPProtocol, WebSocketProtocol, FramedProtocol,
+// and custom protocols.
 type Protocol interface {
 	ApplyHeaders(w Writer, code int) error
 }
@@ -53,7 +54,8 @@ func (p *HTTPProtocol) ApplyHeaders(w Writer, code int) error {
 
 // TCPProtocol implements a basic TCP protocol.
 // Provides TCP-specific header application (currently a no-op).
-// Suitable for protocols without header requirements.
+// Suitable for protocols without header requirements; use FramedProtocol
+// when clients need message boundaries.
 type TCPProtocol struct{}
 
 // ApplyHeaders applies TCP-specific headers (none in this basic implementation).
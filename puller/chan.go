@@ -0,0 +1,118 @@
+package puller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// DefaultChanBuffer is the channel capacity used when a non-positive buffer is given.
+const DefaultChanBuffer = 16
+
+// JSONChan decodes a stream of JSON values into T and sends them on a channel.
+// Accepts concatenated or newline-delimited values as well as a top-level
+// array, whose elements are sent one by one. The value channel holds at most
+// buffer items, so a slow consumer pauses decoding.
+// Returns the value channel and an error channel that receives at most one
+// error; both are closed when the stream ends, fails, or ctx is canceled.
+func JSONChan[T any](ctx context.Context, s *Streamer, buffer int) (<-chan T, <-chan error) {
+	return decodeChan(ctx, s, buffer, func(r io.Reader) func(*T) error {
+		br := bufio.NewReader(r)
+		dec := json.NewDecoder(br)
+		array := false
+		started := false
+		return func(v *T) error {
+			if !started {
+				started = true
+				if b, err := peekNonSpace(br); err == nil && b == '[' {
+					if _, err := dec.Token(); err != nil {
+						return err
+					}
+					array = true
+				}
+			}
+			if array && !dec.More() {
+				return io.EOF
+			}
+			return dec.Decode(v)
+		}
+	})
+}
+
+// MsgPackChan decodes a stream of MessagePack values into T and sends them on a channel.
+// Behaves like JSONChan for buffering, errors, and cancellation.
+func MsgPackChan[T any](ctx context.Context, s *Streamer, buffer int) (<-chan T, <-chan error) {
+	return decodeChan(ctx, s, buffer, func(r io.Reader) func(*T) error {
+		dec := msgpack.NewDecoder(r)
+		return func(v *T) error { return dec.Decode(v) }
+	})
+}
+
+// XMLChan decodes a stream of top-level XML elements into T and sends them on a channel.
+// Behaves like JSONChan for buffering, errors, and cancellation.
+func XMLChan[T any](ctx context.Context, s *Streamer, buffer int) (<-chan T, <-chan error) {
+	return decodeChan(ctx, s, buffer, func(r io.Reader) func(*T) error {
+		dec := xml.NewDecoder(r)
+		return func(v *T) error { return dec.Decode(v) }
+	})
+}
+
+// decodeChan runs a decode loop in a goroutine, sending each value on a bounded channel.
+// newDecode builds the per-stream decode function; io.EOF ends the stream cleanly.
+func decodeChan[T any](ctx context.Context, s *Streamer, buffer int, newDecode func(io.Reader) func(*T) error) (<-chan T, <-chan error) {
+	if buffer <= 0 {
+		buffer = DefaultChanBuffer
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	out := make(chan T, buffer)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(out)
+		defer s.close()
+		decode := newDecode(s.r)
+		for {
+			if ctx.Err() != nil || s.checkContext() != nil {
+				errc <- ErrContextCanceled
+				return
+			}
+			var v T
+			if err := decode(&v); err != nil {
+				if !errors.Is(err, io.EOF) {
+					errc <- errors.Join(ErrDecodingFailed, err)
+				}
+				return
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				errc <- ErrContextCanceled
+				return
+			}
+		}
+	}()
+	return out, errc
+}
+
+// peekNonSpace returns the first non-whitespace byte without consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = br.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
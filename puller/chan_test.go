@@ -0,0 +1,74 @@
+package puller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type chanItem struct {
+	ID int `json:"id" msgpack:"id" xml:"id"`
+}
+
+func collect[T any](values <-chan T, errc <-chan error) ([]T, error) {
+	var got []T
+	for v := range values {
+		got = append(got, v)
+	}
+	return got, <-errc
+}
+
+func TestJSONChan(t *testing.T) {
+	for name, input := range map[string]string{
+		"NDJSON": "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+		"Array":  " [{\"id\":1},{\"id\":2},{\"id\":3}]",
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := collect(JSONChan[chanItem](context.Background(), NewStreamer(strings.NewReader(input)), 1))
+			if err != nil || len(got) != 3 || got[2].ID != 3 {
+				t.Errorf("Expected 3 items, got %v (%v)", got, err)
+			}
+		})
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		got, err := collect(JSONChan[chanItem](context.Background(), NewStreamer(strings.NewReader(`{"id":1}{"id":`)), 0))
+		if len(got) != 1 || !errors.Is(err, ErrDecodingFailed) {
+			t.Errorf("Expected 1 item and ErrDecodingFailed, got %v (%v)", got, err)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		values, errc := JSONChan[chanItem](ctx, NewStreamer(strings.NewReader(strings.Repeat(`{"id":1}`, 100))), 1)
+		<-values
+		cancel()
+		for range values {
+		}
+		if err := <-errc; !errors.Is(err, ErrContextCanceled) {
+			t.Errorf("Expected ErrContextCanceled, got %v", err)
+		}
+	})
+}
+
+func TestMsgPackAndXMLChan(t *testing.T) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	for i := 1; i <= 2; i++ {
+		_ = enc.Encode(chanItem{ID: i})
+	}
+	got, err := collect(MsgPackChan[chanItem](context.Background(), NewStreamer(&buf), 0))
+	if err != nil || len(got) != 2 || got[1].ID != 2 {
+		t.Errorf("Expected 2 msgpack items, got %v (%v)", got, err)
+	}
+
+	xmlIn := "<item><id>1</id></item>\n<item><id>2</id></item>"
+	got, err = collect(XMLChan[chanItem](context.Background(), NewStreamer(strings.NewReader(xmlIn)), 0))
+	if err != nil || len(got) != 2 || got[1].ID != 2 {
+		t.Errorf("Expected 2 xml items, got %v (%v)", got, err)
+	}
+}
@@ -0,0 +1,300 @@
+package puller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultCheckpointInterval is the number of records between checkpoint saves
+// when WithCheckpoint is given a non-positive interval.
+var DefaultCheckpointInterval = 1000
+
+// errCheckpoint wraps failures to load, save, or apply a checkpoint.
+var errCheckpoint = errors.New("checkpoint error")
+
+// Checkpoint records how far a resumable Streamer got through its source.
+// Offset is the absolute byte offset just past the last fully processed record;
+// Records counts the callbacks that completed successfully.
+type Checkpoint struct {
+	Offset  int64 `json:"offset"`
+	Records int64 `json:"records"`
+}
+
+// CheckpointStore persists checkpoints by key, typically one key per import.
+// Load reports false when no checkpoint exists for the key.
+// Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	Load(key string) (Checkpoint, bool, error)
+	Save(key string, cp Checkpoint) error
+	Delete(key string) error
+}
+
+// MemoryCheckpoints is an in-process CheckpointStore.
+// Survives retries within one process but not restarts; use FileCheckpoints for that.
+type MemoryCheckpoints struct {
+	mu  sync.Mutex
+	cps map[string]Checkpoint
+}
+
+// NewMemoryCheckpoints creates an empty in-memory CheckpointStore.
+func NewMemoryCheckpoints() *MemoryCheckpoints {
+	return &MemoryCheckpoints{cps: make(map[string]Checkpoint)}
+}
+
+// Load returns the checkpoint stored under key.
+func (m *MemoryCheckpoints) Load(key string) (Checkpoint, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp, ok := m.cps[key]
+	return cp, ok, nil
+}
+
+// Save stores cp under key.
+func (m *MemoryCheckpoints) Save(key string, cp Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cps[key] = cp
+	return nil
+}
+
+// Delete removes the checkpoint stored under key.
+func (m *MemoryCheckpoints) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cps, key)
+	return nil
+}
+
+// FileCheckpoints is a CheckpointStore keeping one JSON file per key in a directory.
+// Saves write a temporary file and rename it, so a crash never leaves a torn checkpoint.
+type FileCheckpoints struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileCheckpoints creates a CheckpointStore in dir, creating the directory if needed.
+// Returns an error if the directory cannot be created.
+func NewFileCheckpoints(dir string) (*FileCheckpoints, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Join(errCheckpoint, err)
+	}
+	return &FileCheckpoints{dir: dir}, nil
+}
+
+// Load reads the checkpoint file for key.
+func (f *FileCheckpoints) Load(key string) (Checkpoint, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := os.ReadFile(f.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, false, err
+	}
+	return cp, true, nil
+}
+
+// Save atomically replaces the checkpoint file for key.
+func (f *FileCheckpoints) Save(key string, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tmp, err := os.CreateTemp(f.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Delete removes the checkpoint file for key; a missing file is not an error.
+func (f *FileCheckpoints) Delete(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.Remove(f.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps key to a file name, escaping separators so distinct keys never collide.
+func (f *FileCheckpoints) path(key string) string {
+	return filepath.Join(f.dir, url.QueryEscape(key)+".json")
+}
+
+// checkpointer tracks a resumable Streamer's progress and saves it periodically.
+type checkpointer struct {
+	store    CheckpointStore
+	key      string
+	every    int64
+	cp       Checkpoint
+	base     int64 // Absolute offset at which the current reader starts
+	restored bool  // Whether the reader is already positioned at base
+	pending  bool  // Whether cp changed since the last save
+}
+
+// WithCheckpoint makes the Streamer resumable.
+// Progress is saved to store under key every `every` records (default:
+// DefaultCheckpointInterval), and again when streaming fails or is canceled.
+// The next stream with the same key resumes after the last checkpoint,
+// seeking the source if it implements io.Seeker and discarding bytes otherwise.
+// The checkpoint is deleted once the source is fully consumed.
+// Returns the Streamer for chaining.
+func (s *Streamer) WithCheckpoint(store CheckpointStore, key string, every int) *Streamer {
+	if every <= 0 {
+		every = DefaultCheckpointInterval
+	}
+	s.cp = &checkpointer{store: store, key: key, every: int64(every)}
+	return s
+}
+
+// NewResumableStreamer creates a Streamer that resumes from the checkpoint in store.
+// open is called with the offset to resume from (0 without a checkpoint) and
+// must return a reader starting at that offset, e.g. a ranged object storage GET.
+// Returns an error if the checkpoint cannot be loaded or open fails.
+func NewResumableStreamer(ctx context.Context, store CheckpointStore, key string, every int, open func(offset int64) (io.Reader, error)) (*Streamer, error) {
+	cp, _, err := store.Load(key)
+	if err != nil {
+		return nil, errors.Join(errCheckpoint, err)
+	}
+	r, err := open(cp.Offset)
+	if err != nil {
+		return nil, err
+	}
+	s := NewStreamerWithContext(ctx, r).WithCheckpoint(store, key, every)
+	s.cp.cp, s.cp.base, s.cp.restored = cp, cp.Offset, true
+	return s, nil
+}
+
+// Checkpoint returns the Streamer's current progress.
+// Returns the zero Checkpoint if the Streamer is not resumable.
+func (s *Streamer) Checkpoint() Checkpoint {
+	if s.cp == nil {
+		return Checkpoint{}
+	}
+	return s.cp.cp
+}
+
+// restore loads the last checkpoint and positions the source after it.
+// No-op for non-resumable Streamers or readers already positioned by NewResumableStreamer.
+func (s *Streamer) restore() error {
+	c := s.cp
+	if c == nil || c.restored {
+		return nil
+	}
+	c.restored = true
+	cp, ok, err := c.store.Load(c.key)
+	if err != nil {
+		return errors.Join(errCheckpoint, err)
+	}
+	if !ok || cp.Offset == 0 {
+		c.cp = cp
+		return nil
+	}
+	if seeker, ok := s.r.(io.Seeker); ok {
+		if _, err := seeker.Seek(cp.Offset, io.SeekStart); err != nil {
+			return errors.Join(errCheckpoint, err)
+		}
+	} else if _, err := io.CopyN(io.Discard, s.r, cp.Offset); err != nil {
+		return errors.Join(errCheckpoint, err)
+	}
+	c.cp, c.base = cp, cp.Offset
+	return nil
+}
+
+// advance records a completed record ending at offset bytes into the current reader.
+// Saves the checkpoint every c.every records.
+func (s *Streamer) advance(offset int64) error {
+	c := s.cp
+	if c == nil {
+		return nil
+	}
+	c.cp.Offset = c.base + offset
+	c.cp.Records++
+	c.pending = true
+	if c.cp.Records%c.every != 0 {
+		return nil
+	}
+	return s.saveCheckpoint()
+}
+
+// saveCheckpoint persists unsaved progress.
+func (s *Streamer) saveCheckpoint() error {
+	c := s.cp
+	if c == nil || !c.pending {
+		return nil
+	}
+	if err := c.store.Save(c.key, c.cp); err != nil {
+		return errors.Join(errCheckpoint, err)
+	}
+	c.pending = false
+	return nil
+}
+
+// finish ends a resumable stream: on success the checkpoint is deleted,
+// on failure the last good checkpoint is saved and joined with err.
+func (s *Streamer) finish(err error) error {
+	c := s.cp
+	if c == nil {
+		return err
+	}
+	if err != nil {
+		return errors.Join(err, s.saveCheckpoint())
+	}
+	if derr := c.store.Delete(c.key); derr != nil {
+		return errors.Join(errCheckpoint, derr)
+	}
+	return nil
+}
+
+// countingReader counts the bytes consumed through it, including single-byte
+// reads and unreads, so decoders that scan byte by byte report exact offsets.
+type countingReader struct {
+	br *bufio.Reader
+	n  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.br.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.br.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+func (c *countingReader) UnreadByte() error {
+	err := c.br.UnreadByte()
+	if err == nil {
+		c.n--
+	}
+	return err
+}
@@ -0,0 +1,186 @@
+package puller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const ndjson = `{"id":1}
+{"id":2}
+{"id":3}
+{"id":4}
+`
+
+type record struct {
+	ID int `json:"id" msgpack:"id"`
+}
+
+// importJSON decodes records until failAt (0 never fails), returning the IDs seen.
+func importJSON(s *Streamer, failAt int) ([]int, error) {
+	var ids []int
+	err := s.JSON(func(dec *json.Decoder) error {
+		var r record
+		if err := dec.Decode(&r); err != nil {
+			return err
+		}
+		if r.ID == failAt {
+			return errors.New("boom")
+		}
+		ids = append(ids, r.ID)
+		return nil
+	})
+	return ids, err
+}
+
+func TestStreamerCheckpoint(t *testing.T) {
+	t.Run("ResumeAfterFailure", func(t *testing.T) {
+		for name, wrap := range map[string]func(string) io.Reader{
+			"Seeker":    func(s string) io.Reader { return strings.NewReader(s) },
+			"NonSeeker": func(s string) io.Reader { return io.NopCloser(strings.NewReader(s)) },
+		} {
+			t.Run(name, func(t *testing.T) {
+				store := NewMemoryCheckpoints()
+				ids, err := importJSON(NewStreamer(wrap(ndjson)).WithCheckpoint(store, "import", 1), 3)
+				if err == nil || len(ids) != 2 {
+					t.Fatalf("Expected failure after 2 records, got %v (%v)", ids, err)
+				}
+				cp, ok, _ := store.Load("import")
+				if !ok || cp.Records != 2 || cp.Offset != int64(strings.Index(ndjson, "\n{\"id\":3")) {
+					t.Fatalf("Unexpected checkpoint %+v (%v)", cp, ok)
+				}
+
+				s := NewStreamer(wrap(ndjson)).WithCheckpoint(store, "import", 1)
+				ids, err = importJSON(s, 0)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if len(ids) != 2 || ids[0] != 3 || ids[1] != 4 {
+					t.Errorf("Expected resume at 3, got %v", ids)
+				}
+				if got := s.Checkpoint(); got.Records != 4 {
+					t.Errorf("Expected 4 records, got %+v", got)
+				}
+				if _, ok, _ := store.Load("import"); ok {
+					t.Error("Expected checkpoint deleted after completion")
+				}
+			})
+		}
+	})
+
+	t.Run("SavesOnlyEveryInterval", func(t *testing.T) {
+		store := NewMemoryCheckpoints()
+		ctx, cancel := context.WithCancel(context.Background())
+		s := NewStreamerWithContext(ctx, strings.NewReader(ndjson)).WithCheckpoint(store, "k", 2)
+		seen := 0
+		err := s.JSON(func(dec *json.Decoder) error {
+			var r record
+			if err := dec.Decode(&r); err != nil {
+				return err
+			}
+			if seen++; seen == 3 {
+				cancel()
+			}
+			return nil
+		})
+		if !errors.Is(err, ErrContextCanceled) {
+			t.Fatalf("Expected ErrContextCanceled, got %v", err)
+		}
+		// Cancellation flushes the unsaved third record.
+		if cp, _, _ := store.Load("k"); cp.Records != 3 {
+			t.Errorf("Expected 3 records checkpointed, got %+v", cp)
+		}
+	})
+
+	t.Run("NewResumableStreamer", func(t *testing.T) {
+		store := NewMemoryCheckpoints()
+		offset := int64(strings.Index(ndjson, "{\"id\":4"))
+		_ = store.Save("obj", Checkpoint{Offset: offset, Records: 3})
+		var opened int64 = -1
+		s, err := NewResumableStreamer(context.Background(), store, "obj", 0, func(off int64) (io.Reader, error) {
+			opened = off
+			return strings.NewReader(ndjson[off:]), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids, err := importJSON(s, 0)
+		if err != nil || opened != offset || len(ids) != 1 || ids[0] != 4 {
+			t.Errorf("Expected ranged open at %d yielding [4], got %d %v (%v)", offset, opened, ids, err)
+		}
+		if cp := s.Checkpoint(); cp.Records != 4 || cp.Offset != int64(len(ndjson)-1) {
+			t.Errorf("Unexpected final checkpoint %+v", cp)
+		}
+	})
+
+	t.Run("MsgPackOffsets", func(t *testing.T) {
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		var ends []int64
+		for i := 1; i <= 3; i++ {
+			_ = enc.Encode(record{ID: i})
+			ends = append(ends, int64(buf.Len()))
+		}
+		store := NewMemoryCheckpoints()
+		s := NewStreamer(bytes.NewReader(buf.Bytes())).WithCheckpoint(store, "mp", 1)
+		err := s.MsgPack(func(dec *msgpack.Decoder) error {
+			var r record
+			if err := dec.Decode(&r); err != nil {
+				return err
+			}
+			if r.ID == 3 {
+				return errors.New("boom")
+			}
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Expected error")
+		}
+		if cp, _, _ := store.Load("mp"); cp.Offset != ends[1] || cp.Records != 2 {
+			t.Errorf("Expected offset %d after 2 records, got %+v", ends[1], cp)
+		}
+	})
+
+	t.Run("BytesResume", func(t *testing.T) {
+		store := NewMemoryCheckpoints()
+		_ = store.Save("b", Checkpoint{Offset: 4, Records: 1})
+		var got []byte
+		err := NewStreamer(strings.NewReader("abcdefgh")).WithCheckpoint(store, "b", 1).Bytes(func(chunk []byte) error {
+			got = append(got, chunk...)
+			return nil
+		}, 2)
+		if err != nil || string(got) != "efgh" {
+			t.Errorf("Expected efgh, got %q (%v)", got, err)
+		}
+	})
+}
+
+func TestFileCheckpoints(t *testing.T) {
+	store, err := NewFileCheckpoints(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := store.Load("s3://bucket/a.ndjson"); ok || err != nil {
+		t.Fatalf("Expected no checkpoint, got %v (%v)", ok, err)
+	}
+	want := Checkpoint{Offset: 42, Records: 7}
+	_ = store.Save("s3://other/a.ndjson", Checkpoint{Offset: 1})
+	if err := store.Save("s3://bucket/a.ndjson", want); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := store.Load("s3://bucket/a.ndjson"); !ok || err != nil || got != want {
+		t.Errorf("Expected %+v, got %+v (%v, %v)", want, got, ok, err)
+	}
+	if err := store.Delete("s3://bucket/a.ndjson"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("s3://bucket/a.ndjson"); err != nil {
+		t.Errorf("Expected deleting a missing checkpoint to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package puller
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// Size classes of the buffer pool are powers of two from minPoolShift to maxPoolShift.
+// Requests above the largest class are allocated directly and never pooled.
+const (
+	minPoolShift = 10 // 1KB
+	maxPoolShift = 24 // 16MB
+)
+
+// PoolStats reports buffer pool activity for tuning buffer sizes.
+type PoolStats struct {
+	Gets     uint64 // Buffers requested
+	Hits     uint64 // Requests served by a pooled buffer
+	Misses   uint64 // Requests that allocated a new buffer
+	Puts     uint64 // Buffers returned to the pool
+	Oversize uint64 // Requests above the largest size class, never pooled
+}
+
+// PoolDebugger exposes the package buffer pool for diagnostics.
+// Obtain it with Debug; it is not needed for normal use.
+type PoolDebugger interface {
+	// Stats returns a snapshot of the pool counters.
+	Stats() PoolStats
+
+	// ResetStats zeroes the pool counters.
+	ResetStats()
+}
+
+// Debug returns the package buffer pool's debug interface.
+func Debug() PoolDebugger {
+	return &buffers
+}
+
+// buffers is the package buffer pool used by Streamer.Bytes.
+var buffers bufferPool
+
+// bufferPool keeps one sync.Pool per power-of-two size class, so a request is
+// always served by a buffer at least as large as asked for and buffers of one
+// size never displace those of another.
+type bufferPool struct {
+	classes [maxPoolShift + 1]sync.Pool
+
+	gets, hits, misses, puts, oversize atomic.Uint64
+}
+
+// get returns a buffer of length size from the matching size class.
+// The caller must return it with put.
+func (p *bufferPool) get(size int) *[]byte {
+	p.gets.Add(1)
+	shift := classShift(size)
+	if shift > maxPoolShift {
+		p.oversize.Add(1)
+		buf := make([]byte, size)
+		return &buf
+	}
+	if v := p.classes[shift].Get(); v != nil {
+		p.hits.Add(1)
+		buf := v.(*[]byte)
+		*buf = (*buf)[:size]
+		return buf
+	}
+	p.misses.Add(1)
+	buf := make([]byte, size, 1<<shift)
+	return &buf
+}
+
+// put returns buf to the size class matching its capacity.
+// Buffers that do not match a class exactly are dropped.
+func (p *bufferPool) put(buf *[]byte) {
+	c := cap(*buf)
+	shift := bits.Len(uint(c)) - 1
+	if shift < minPoolShift || shift > maxPoolShift || c != 1<<shift {
+		return
+	}
+	p.puts.Add(1)
+	*buf = (*buf)[:0]
+	p.classes[shift].Put(buf)
+}
+
+// Stats returns a snapshot of the pool counters.
+func (p *bufferPool) Stats() PoolStats {
+	return PoolStats{
+		Gets:     p.gets.Load(),
+		Hits:     p.hits.Load(),
+		Misses:   p.misses.Load(),
+		Puts:     p.puts.Load(),
+		Oversize: p.oversize.Load(),
+	}
+}
+
+// ResetStats zeroes the pool counters.
+func (p *bufferPool) ResetStats() {
+	p.gets.Store(0)
+	p.hits.Store(0)
+	p.misses.Store(0)
+	p.puts.Store(0)
+	p.oversize.Store(0)
+}
+
+// classShift returns the size class for size: the smallest power of two that
+// fits it, but no smaller than InitialBufferCapacity or 1KB.
+func classShift(size int) int {
+	size = max(size, config.InitialBufferCapacity, 1<<minPoolShift)
+	return bits.Len(uint(size - 1))
+}
@@ -0,0 +1,48 @@
+package puller
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBufferPool(t *testing.T) {
+	t.Run("SizeClasses", func(t *testing.T) {
+		var p bufferPool
+		buf := p.get(5000)
+		if len(*buf) != 5000 || cap(*buf) != 8192 {
+			t.Errorf("Expected len 5000 cap 8192, got len %d cap %d", len(*buf), cap(*buf))
+		}
+		p.put(buf)
+		again := p.get(6000)
+		if cap(*again) != 8192 || len(*again) != 6000 {
+			t.Errorf("Expected reused 8192 buffer, got len %d cap %d", len(*again), cap(*again))
+		}
+		small := p.get(10)
+		if cap(*small) < config.InitialBufferCapacity {
+			t.Errorf("Expected capacity >= %d, got %d", config.InitialBufferCapacity, cap(*small))
+		}
+		big := p.get(1<<maxPoolShift + 1)
+		p.put(big)
+		if st := p.Stats(); st.Gets != 4 || st.Oversize != 1 || st.Puts != 1 || st.Hits+st.Misses != 3 {
+			t.Errorf("Unexpected stats %+v", st)
+		}
+	})
+
+	t.Run("BytesReuse", func(t *testing.T) {
+		Debug().ResetStats()
+		for i := 0; i < 20; i++ {
+			s := NewStreamer(bytes.NewReader([]byte("data")))
+			if err := s.Bytes(func([]byte) error { return nil }, 0); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		st := Debug().Stats()
+		if st.Gets != 20 || st.Puts != 20 {
+			t.Errorf("Expected 20 gets and puts, got %+v", st)
+		}
+		// sync.Pool may drop items at GC; most requests should still be served from the pool.
+		if st.Misses > 15 {
+			t.Errorf("Expected buffers to be reused, got %+v", st)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package puller
 
 import (
 	"errors"
-	"sync"
 )
 
 // Common errors for the puller package.
@@ -34,7 +33,7 @@ var (
 type Config struct {
 	DefaultBufferSize     int // Default chunk size for streaming operations.
 	LargeContentThreshold int // Content size threshold to favor streaming.
-	InitialBufferCapacity int // Initial capacity for pooled buffers.
+	InitialBufferCapacity int // Smallest pooled buffer size; requests are rounded up to it.
 }
 
 // Global package configuration with sensible defaults.
@@ -46,15 +45,6 @@ var config = Config{
 	InitialBufferCapacity: 4096,        // 4KB
 }
 
-// byteBufferPool reuses buffers for reading operations.
-// Provides a sync.Pool for byte slices with initial capacity.
-// Used in streaming methods to reduce memory allocations.
-var byteBufferPool = sync.Pool{
-	New: func() interface{} {
-		return make([]byte, 0, config.InitialBufferCapacity)
-	},
-}
-
 // SetConfig updates the package configuration.
 // Takes a Config struct with desired settings.
 // Updates non-zero fields in the global config.
@@ -0,0 +1,111 @@
+package puller
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// WithRateLimit caps the rate at which the Reader consumes its source.
+// Takes the sustained rate in bytes per second and an optional burst size in
+// bytes (default: one second's worth). Waiting honors the Reader's context.
+// Returns the Reader for chaining.
+func (r *Reader) WithRateLimit(bytesPerSec int64, burst ...int64) *Reader {
+	r.r = throttle(r.ctx, r.r, bytesPerSec, burst...)
+	return r
+}
+
+// WithRateLimit caps the rate at which the Streamer consumes its source.
+// Takes the sustained rate in bytes per second and an optional burst size in
+// bytes (default: one second's worth). Waiting honors the Streamer's context.
+// Returns the Streamer for chaining.
+func (s *Streamer) WithRateLimit(bytesPerSec int64, burst ...int64) *Streamer {
+	s.r = throttle(s.ctx, s.r, bytesPerSec, burst...)
+	return s
+}
+
+// throttle wraps src in a token bucket limited reader; a non-positive rate disables limiting.
+func throttle(ctx context.Context, src io.Reader, bytesPerSec int64, burst ...int64) io.Reader {
+	if bytesPerSec <= 0 {
+		return src
+	}
+	if t, ok := src.(*throttledReader); ok {
+		src = t.r
+	}
+	size := bytesPerSec
+	if len(burst) > 0 && burst[0] > 0 {
+		size = burst[0]
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &throttledReader{
+		r:      src,
+		ctx:    ctx,
+		rate:   float64(bytesPerSec),
+		burst:  size,
+		tokens: float64(size),
+		last:   time.Now(),
+	}
+}
+
+// throttledReader is a token bucket limited reader.
+// Each read is capped at the burst size; bytes read beyond the available
+// tokens put the bucket in debt, which is paid off by pausing before the next read.
+type throttledReader struct {
+	r   io.Reader
+	ctx context.Context
+
+	mu     sync.Mutex
+	rate   float64 // Tokens (bytes) added per second
+	burst  int64   // Bucket capacity and per-read cap
+	tokens float64
+	last   time.Time
+}
+
+// Read waits for tokens, then reads at most burst bytes from the source.
+// Returns ErrContextCanceled if the context ends while waiting.
+func (t *throttledReader) Read(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.wait(); err != nil {
+		return 0, err
+	}
+	if int64(len(p)) > t.burst {
+		p = p[:t.burst]
+	}
+	n, err := t.r.Read(p)
+	t.tokens -= float64(n)
+	return n, err
+}
+
+// wait refills the bucket and pauses until it is no longer in debt.
+func (t *throttledReader) wait() error {
+	now := time.Now()
+	t.tokens = min(t.tokens+now.Sub(t.last).Seconds()*t.rate, float64(t.burst))
+	t.last = now
+	if t.tokens > 0 {
+		return nil
+	}
+	pause := time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-t.ctx.Done():
+		return ErrContextCanceled
+	case <-timer.C:
+	}
+	now = time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	t.last = now
+	return nil
+}
+
+// Close closes the source if it implements io.Closer.
+func (t *throttledReader) Close() error {
+	if c, ok := t.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
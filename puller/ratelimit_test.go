@@ -0,0 +1,51 @@
+package puller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("Reader", func(t *testing.T) {
+		data := bytes.Repeat([]byte("x"), 300)
+		start := time.Now()
+		got, err := NewReader(bytes.NewReader(data)).WithRateLimit(1000, 100).Pull()
+		elapsed := time.Since(start)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("Expected all data, got %d bytes (%v)", len(got), err)
+		}
+		// The first 100 bytes use the burst; the remaining 200 need about 200ms.
+		if elapsed < 150*time.Millisecond {
+			t.Errorf("Expected throttled read to take at least 150ms, took %v", elapsed)
+		}
+	})
+
+	t.Run("StreamerChunks", func(t *testing.T) {
+		s := NewStreamer(bytes.NewReader(bytes.Repeat([]byte("x"), 64))).WithRateLimit(1<<20, 16)
+		err := s.Bytes(func(chunk []byte) error {
+			if len(chunk) > 16 {
+				t.Errorf("Expected chunks capped at burst 16, got %d", len(chunk))
+			}
+			return nil
+		}, 1024)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("CanceledWhilePaused", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		rd := NewPullerWithContext(ctx, bytes.NewReader(bytes.Repeat([]byte("x"), 100))).WithRateLimit(10, 10)
+		start := time.Now()
+		if _, err := rd.Pull(); !errors.Is(err, ErrContextCanceled) {
+			t.Errorf("Expected ErrContextCanceled, got %v", err)
+		}
+		if time.Since(start) > time.Second {
+			t.Errorf("Expected cancellation to interrupt the pause")
+		}
+	})
+}
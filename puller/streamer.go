@@ -1,6 +1,7 @@
 package puller
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -13,10 +14,13 @@ import (
 // Streamer provides efficient streaming operations on an io.Reader.
 // Manages streaming of data formats like JSON, XML, or raw bytes.
 // Closes the reader if it implements io.Closer after processing.
+// With WithCheckpoint, each completed callback is a record whose end offset
+// is checkpointed so an interrupted stream can resume.
 type Streamer struct {
 	r      io.Reader
 	closer io.Closer
 	ctx    context.Context
+	cp     *checkpointer // Set by WithCheckpoint for resumable streams
 }
 
 // NewStreamer creates a new Streamer instance.
@@ -43,19 +47,26 @@ func NewStreamerWithContext(ctx context.Context, r io.Reader) *Streamer {
 // Takes a callback function to process MessagePack decoder output.
 // Returns an error if streaming or callback fails, nil on io.EOF.
 func (s *Streamer) MsgPack(callback func(*msgpack.Decoder) error) error {
-	decoder := msgpack.NewDecoder(s.r)
 	defer s.close()
+	if err := s.restore(); err != nil {
+		return err
+	}
+	counter := &countingReader{br: bufio.NewReader(s.r)}
+	decoder := msgpack.NewDecoder(counter)
 
 	for {
 		if err := s.checkContext(); err != nil {
-			return err
+			return s.finish(err)
 		}
 
 		if err := callback(decoder); err != nil {
 			if err == io.EOF {
-				return nil
+				return s.finish(nil)
 			}
-			return errors.Join(errMsgPackStreaming, err)
+			return s.finish(errors.Join(errMsgPackStreaming, err))
+		}
+		if err := s.advance(counter.n); err != nil {
+			return s.finish(err)
 		}
 	}
 }
@@ -64,19 +75,25 @@ func (s *Streamer) MsgPack(callback func(*msgpack.Decoder) error) error {
 // Takes a callback function to process JSON decoder output.
 // Returns an error if streaming or callback fails, nil on io.EOF.
 func (s *Streamer) JSON(callback func(*json.Decoder) error) error {
-	decoder := json.NewDecoder(s.r)
 	defer s.close()
+	if err := s.restore(); err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(s.r)
 
 	for {
 		if err := s.checkContext(); err != nil {
-			return err
+			return s.finish(err)
 		}
 
 		if err := callback(decoder); err != nil {
 			if err == io.EOF {
-				return nil
+				return s.finish(nil)
 			}
-			return errors.Join(errJSONStreaming, err)
+			return s.finish(errors.Join(errJSONStreaming, err))
+		}
+		if err := s.advance(decoder.InputOffset()); err != nil {
+			return s.finish(err)
 		}
 	}
 }
@@ -85,19 +102,25 @@ func (s *Streamer) JSON(callback func(*json.Decoder) error) error {
 // Takes a callback function to process XML decoder output.
 // Returns an error if streaming or callback fails, nil on io.EOF.
 func (s *Streamer) XML(callback func(*xml.Decoder) error) error {
-	decoder := xml.NewDecoder(s.r)
 	defer s.close()
+	if err := s.restore(); err != nil {
+		return err
+	}
+	decoder := xml.NewDecoder(s.r)
 
 	for {
 		if err := s.checkContext(); err != nil {
-			return err
+			return s.finish(err)
 		}
 
 		if err := callback(decoder); err != nil {
 			if err == io.EOF {
-				return nil
+				return s.finish(nil)
 			}
-			return errors.Join(errXMLStreaming, err)
+			return s.finish(errors.Join(errXMLStreaming, err))
+		}
+		if err := s.advance(decoder.InputOffset()); err != nil {
+			return s.finish(err)
 		}
 	}
 }
@@ -109,32 +132,36 @@ func (s *Streamer) Bytes(callback func([]byte) error, bufSize int) error {
 	if bufSize <= 0 {
 		bufSize = config.DefaultBufferSize
 	}
-	buf := byteBufferPool.Get().([]byte)
-	if cap(buf) < bufSize {
-		buf = make([]byte, bufSize)
-	} else {
-		buf = buf[:bufSize]
-	}
-	defer byteBufferPool.Put(buf)
+	pooled := buffers.get(bufSize)
+	defer buffers.put(pooled)
+	buf := *pooled
 	defer s.close()
+	if err := s.restore(); err != nil {
+		return err
+	}
 
+	var offset int64
 	for {
 		if err := s.checkContext(); err != nil {
-			return err
+			return s.finish(err)
 		}
 
 		n, err := s.r.Read(buf)
 		if n > 0 {
 			if err := callback(buf[:n]); err != nil {
-				return errors.Join(errCallbackStreaming, err)
+				return s.finish(errors.Join(errCallbackStreaming, err))
+			}
+			offset += int64(n)
+			if err := s.advance(offset); err != nil {
+				return s.finish(err)
 			}
 		}
 
 		if err != nil {
 			if err == io.EOF {
-				return nil
+				return s.finish(nil)
 			}
-			return errors.Join(errReadStreaming, err)
+			return s.finish(errors.Join(errReadStreaming, err))
 		}
 	}
 }
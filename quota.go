@@ -0,0 +1,134 @@
+package beam
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// metaQuota is the meta key carrying the client's quota usage.
+const metaQuota = "quota"
+
+// Quota usage headers set by WithQuota and WithQuotaProvider.
+const (
+	HeaderQuotaLimit     = "X-Quota-Limit"     // Units allowed per period
+	HeaderQuotaUsed      = "X-Quota-Used"      // Units used in the current period
+	HeaderQuotaRemaining = "X-Quota-Remaining" // Units left in the current period
+	HeaderQuotaPeriod    = "X-Quota-Period"    // Period length in seconds
+	HeaderQuotaReset     = "X-Quota-Reset"     // Seconds until the period resets
+)
+
+// Quota is a client's usage of a metered allowance, such as API calls per month.
+type Quota struct {
+	Used   int64         // Units used in the current period
+	Limit  int64         // Units allowed per period
+	Period time.Duration // Length of the period
+	Reset  time.Time     // When the current period ends; zero if unknown
+}
+
+// Remaining returns the units left in the current period, never below zero.
+func (q Quota) Remaining() int64 {
+	return max(q.Limit-q.Used, 0)
+}
+
+// QuotaUsage is the meta.quota block of a response.
+type QuotaUsage struct {
+	Used      int64  `json:"used" xml:"used" msgpack:"used"`
+	Limit     int64  `json:"limit" xml:"limit" msgpack:"limit"`
+	Remaining int64  `json:"remaining" xml:"remaining" msgpack:"remaining"`
+	Period    string `json:"period,omitempty" xml:"period,omitempty" msgpack:"period,omitempty"`
+	Reset     string `json:"reset,omitempty" xml:"reset,omitempty" msgpack:"reset,omitempty"`
+}
+
+// QuotaProvider looks up the quota of the client making req.
+// It is called at most once per output call and only when quota reporting
+// is enabled, so lookups may hit a database or remote service.
+// req is nil when no request is bound.
+type QuotaProvider interface {
+	Quota(req *http.Request) (Quota, error)
+}
+
+// QuotaProviderFunc adapts a function to the QuotaProvider interface.
+type QuotaProviderFunc func(req *http.Request) (Quota, error)
+
+// Quota calls f(req).
+func (f QuotaProviderFunc) Quota(req *http.Request) (Quota, error) { return f(req) }
+
+// WithQuota reports a known quota usage on every output.
+// Sets the X-Quota-* headers and, for enveloped responses, meta.quota.
+// Returns a new Renderer reporting the quota.
+func (r *Renderer) WithQuota(used, limit int64, period time.Duration) *Renderer {
+	q := Quota{Used: used, Limit: limit, Period: period}
+	return r.WithQuotaProvider(QuotaProviderFunc(func(*http.Request) (Quota, error) { return q, nil }))
+}
+
+// WithQuotaProvider reports the quota fetched from p on every output.
+// Lookup errors are logged as warnings and the quota is omitted.
+// Returns a new Renderer using p; nil disables quota reporting.
+func (r *Renderer) WithQuotaProvider(p QuotaProvider) *Renderer {
+	nr := r.clone()
+	nr.quota = p
+	return nr
+}
+
+// quotaLookup caches the result of one QuotaProvider call.
+type quotaLookup struct {
+	quota Quota
+	ok    bool
+}
+
+// loadQuota fetches the quota once per output call; clones start without it.
+// Returns nil when reporting is disabled or the lookup failed.
+func (r *Renderer) loadQuota() *Quota {
+	if r.quota == nil {
+		return nil
+	}
+	if r.usage == nil {
+		q, err := r.quota.Quota(r.request)
+		if err != nil && r.logger != nil {
+			warnTo(r.logger, err, "id", r.id, "component", metaQuota)
+		}
+		r.usage = &quotaLookup{quota: q, ok: err == nil}
+	}
+	if !r.usage.ok {
+		return nil
+	}
+	return &r.usage.quota
+}
+
+// markQuota adds meta.quota to a response.
+func (r *Renderer) markQuota(resp *Response) {
+	q := r.loadQuota()
+	if q == nil {
+		return
+	}
+	usage := QuotaUsage{Used: q.Used, Limit: q.Limit, Remaining: q.Remaining()}
+	if q.Period > 0 {
+		usage.Period = q.Period.String()
+	}
+	if !q.Reset.IsZero() {
+		usage.Reset = q.Reset.UTC().Format(time.RFC3339)
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaQuota] = usage
+}
+
+// quotaHeaders sets the X-Quota-* headers.
+func (r *Renderer) quotaHeaders() {
+	q := r.loadQuota()
+	if q == nil {
+		return
+	}
+	r.header.Set(HeaderQuotaLimit, strconv.FormatInt(q.Limit, 10))
+	r.header.Set(HeaderQuotaUsed, strconv.FormatInt(q.Used, 10))
+	r.header.Set(HeaderQuotaRemaining, strconv.FormatInt(q.Remaining(), 10))
+	if q.Period > 0 {
+		r.header.Set(HeaderQuotaPeriod, strconv.FormatInt(int64(q.Period/time.Second), 10))
+	}
+	if !q.Reset.IsZero() {
+		secs := int64(max(q.Reset.Sub(r.now()), 0) / time.Second)
+		r.header.Set(HeaderQuotaReset, strconv.FormatInt(secs, 10))
+	}
+}
@@ -0,0 +1,92 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuota(t *testing.T) {
+	t.Run("Static", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithWriter(w).WithQuota(950, 1000, 24*time.Hour)
+		if err := r.Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		for header, want := range map[string]string{
+			HeaderQuotaLimit:     "1000",
+			HeaderQuotaUsed:      "950",
+			HeaderQuotaRemaining: "50",
+			HeaderQuotaPeriod:    "86400",
+		} {
+			if got := w.Header().Get(header); got != want {
+				t.Errorf("Expected %s %q, got %q", header, want, got)
+			}
+		}
+		var resp struct {
+			Meta map[string]QuotaUsage `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		want := QuotaUsage{Used: 950, Limit: 1000, Remaining: 50, Period: "24h0m0s"}
+		if got := resp.Meta[metaQuota]; got != want {
+			t.Errorf("Expected meta.quota %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("ProviderLazy", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		calls := 0
+		p := QuotaProviderFunc(func(req *http.Request) (Quota, error) {
+			calls++
+			if req.Header.Get(HeaderAPIKey) != "k1" {
+				t.Errorf("Expected the bound request, got %v", req.Header)
+			}
+			return Quota{Used: 1200, Limit: 1000, Reset: now.Add(90 * time.Second)}, nil
+		})
+		r := NewRenderer(settings).WithClock(FixedClock(now)).WithQuotaProvider(p)
+		if calls != 0 {
+			t.Fatalf("Expected no lookup before output, got %d", calls)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderAPIKey, "k1")
+		w := httptest.NewRecorder()
+		if err := r.WithWriter(w).WithRequest(req).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("Expected one lookup per output, got %d", calls)
+		}
+		if got := w.Header().Get(HeaderQuotaRemaining); got != "0" {
+			t.Errorf("Expected remaining clamped to 0, got %q", got)
+		}
+		if got := w.Header().Get(HeaderQuotaReset); got != "90" {
+			t.Errorf("Expected reset in 90 seconds, got %q", got)
+		}
+		if w.Header().Get(HeaderQuotaPeriod) != Empty {
+			t.Error("Expected no period header for an unknown period")
+		}
+	})
+
+	t.Run("ProviderError", func(t *testing.T) {
+		log := &TestLogger{}
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithLogger(log).WithWriter(w).
+			WithQuotaProvider(QuotaProviderFunc(func(*http.Request) (Quota, error) {
+				return Quota{}, errors.New("quota store down")
+			}))
+		if err := r.Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if w.Header().Get(HeaderQuotaLimit) != Empty {
+			t.Error("Expected no quota headers after a failed lookup")
+		}
+		if len(log.Entries) != 1 {
+			t.Errorf("Expected one logged lookup failure, got %+v", log.Entries)
+		}
+	})
+}
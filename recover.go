@@ -0,0 +1,104 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// errStreamAborted is the client-facing message sent when a stream ends due to a panic.
+var errStreamAborted = errors.New("stream aborted")
+
+// PanicError wraps a value recovered from a panicking Stream callback.
+// Carries the stack trace captured at the point of recovery for logging.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error returns the recovered value formatted as an error message.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in stream callback: %v", e.Value)
+}
+
+// Unwrap returns the recovered value when it is itself an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// StreamTerminator is implemented by streaming encoders that can close a stream
+// with a protocol-appropriate final frame after a failure.
+// Used by Renderer.Stream so clients see a clean end instead of a torn connection.
+type StreamTerminator interface {
+	Terminate(w Writer, err error) error
+}
+
+// recoverStream wraps a Stream callback so panics are returned as *PanicError.
+func recoverStream(nr *Renderer, callback func(*Renderer) (interface{}, error)) func() (interface{}, error) {
+	return func() (data interface{}, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				data, err = nil, &PanicError{Value: v, Stack: debug.Stack()}
+			}
+		}()
+		return callback(nr)
+	}
+}
+
+// abortStream finishes a stream whose callback panicked.
+// Logs the panic with its stack, reports it to callbacks, and writes the encoder's
+// terminator frame if it has one; the finalizer runs only when no terminator was written.
+// Returns the error to hand back to the caller.
+func (r *Renderer) abortStream(w Writer, encoder Encoder, pe *PanicError, err error) error {
+	if r.logger != nil {
+		r.logger.Fatal(pe, "id", r.id, "stack", string(pe.Stack))
+	}
+	r.triggerCallbacks(r.id, StatusFatal, err.Error(), err)
+	if t, ok := encoder.(StreamTerminator); ok {
+		if tErr := t.Terminate(w, errStreamAborted); tErr == nil {
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			// The stream is closed cleanly; the finalizer would corrupt it.
+			return err
+		}
+	}
+	if r.finalizer != nil {
+		r.finalizer(w, err)
+	}
+	return err
+}
+
+// Terminate writes a final "event: error" frame carrying a fatal envelope.
+func (e *EventStreamEncoder) Terminate(w Writer, err error) error {
+	encoded, mErr := e.Marshal(Event{
+		Type:     EventTypeError,
+		Envelope: true,
+		Data:     Response{Status: StatusFatal, Title: "error", Message: err.Error()},
+	})
+	if mErr != nil {
+		return mErr
+	}
+	_, wErr := w.Write(encoded)
+	return wErr
+}
+
+// Terminate writes a final JSON line carrying a fatal envelope.
+func (e *NDJSONEncoder) Terminate(w Writer, err error) error {
+	line, mErr := marshalJSON(Response{Status: StatusFatal, Title: "error", Message: err.Error()}, e.Numbers, Empty)
+	if mErr != nil {
+		return mErr
+	}
+	_, wErr := w.Write(append(line, '\n'))
+	return wErr
+}
+
+// Terminate writes the closing multipart boundary.
+func (e *MixedReplaceEncoder) Terminate(w Writer, err error) error {
+	_, wErr := w.Write([]byte("--" + e.boundary() + "--\r\n"))
+	return wErr
+}
@@ -0,0 +1,58 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestStreamPanicRecovery(t *testing.T) {
+	panicky := func() func(*Renderer) (interface{}, error) {
+		i := 0
+		return func(r *Renderer) (interface{}, error) {
+			i++
+			if i == 2 {
+				panic("boom")
+			}
+			return Event{Data: map[string]int{"n": i}}, nil
+		}
+	}
+
+	t.Run("EventStream", func(t *testing.T) {
+		tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+		logger := &TestLogger{}
+		r := NewRenderer(settings).WithContentType(ContentTypeEventStream).WithLogger(logger).WithWriter(tfw)
+
+		err := r.Stream(panicky())
+		var pe *PanicError
+		if !errors.As(err, &pe) {
+			t.Fatalf("Expected PanicError, got %v", err)
+		}
+		out := tfw.Buffer.String()
+		if !strings.Contains(out, "event: error\n") || !strings.HasSuffix(out, "\n\n") {
+			t.Errorf("Expected a terminating error event, got %q", out)
+		}
+		if strings.Contains(out, "boom") {
+			t.Errorf("Expected panic value to be withheld from the client, got %q", out)
+		}
+		logged := false
+		for _, entry := range logger.Entries {
+			if entry.Level == "fatal" && errors.As(entry.Err, &pe) && len(pe.Stack) > 0 {
+				logged = true
+			}
+		}
+		if !logged {
+			t.Errorf("Expected fatal log entry with stack, got %+v", logger.Entries)
+		}
+	})
+
+	t.Run("GenericStream", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithWriter(tw)
+		err := r.Stream(panicky())
+		if err == nil || !strings.Contains(err.Error(), "panic in stream callback: boom") {
+			t.Errorf("Expected recovered panic error, got %v", err)
+		}
+	})
+}
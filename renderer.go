@@ -25,6 +25,14 @@ import (
 // Renderer is the core Beam renderer for constructing and sending responses.
 // Manages response configuration, encoding, and output with support for multiple formats.
 // Thread-safe through immutable cloning for concurrent modifications.
+//
+// Concurrency contract: a Renderer is never mutated after construction, so one
+// base Renderer may be shared by any number of goroutines. With* methods return
+// a modified copy and Push, Stream, and the other output methods work on a
+// private clone; only explicitly shared state (the callback functions, the
+// Logger, NonceStore, Lifecycle, and a writer bound with WithWriter) is reached
+// concurrently and must itself be safe for concurrent use.
+// beamtest.Race exercises this contract under the race detector.
 type Renderer struct {
 	s            Setting
 	name         string
@@ -42,17 +50,91 @@ type Renderer struct {
 	callbacks    *CallbackManager
 	contentType  string // Current content type (e.g., "application/json")
 	errorFilters ErrorFilterSet
-	logger       Logger              // Optional logger
+	logger       Logger              // Optional logger, routed by tags
+	baseLogger   Logger              // Logger set via WithLogger
+	tagRoutes    *TagRoutes          // Optional tag-based log and callback routing
+	runtime      *Runtime            // Optional operator-adjustable settings
 	writer       Writer              // Default writer
 	httpWriter   http.ResponseWriter // Concrete HTTP writer, if applicable
 	finalizer    Finalizer           // Error finalizer
 	system       System              // System metadata configuration
-	mu           sync.RWMutex
+	mu           *sync.RWMutex
 
 	showSystem     SystemShow
 	errorHeaderKey string
 	generateID     State // Enable automatic ID generation
+	typeNames      State // Emit info_type/data_type discriminators
 	showError      State
+
+	nonceStore NonceStore    // Optional replay-protection store
+	nonceTTL   time.Duration // Lifetime of issued nonces
+	stream     *streamState  // State shared by renderers bound to the same writer
+	request    *http.Request // Inbound request, if bound via WithRequest
+
+	statusHooks map[int][]StatusHook // Hooks run for responses with a given status code
+
+	slowThreshold time.Duration // Responses slower than this are annotated
+	received      time.Time     // When the bound request was received
+
+	blueprints   map[string]Blueprint    // Named response skeletons (copy-on-write)
+	deprecations map[string]string       // Deprecated Data field paths and notes (copy-on-write)
+	optional     []string                // Envelope fields sent only to clients advertising them
+	compact      State                   // Encode responses with the compact envelope
+	problem      State                   // Encode error responses as RFC 7807 Problem Details
+	dictionary   *Dictionary             // Optional shared zstd dictionary for dcz responses
+	idempotent   State                   // Route is safe to serve from 0-RTT early data
+	keepalive    *keepAliveState         // Optional ping/pong keepalive for streams
+	lifecycle    *Lifecycle              // Shutdown coordination; nil uses the default
+	precision    time.Duration           // Duration header/meta precision; zero means milliseconds
+	clock        Clock                   // Time source; nil uses the system clock
+	stampFormat  TimestampFormat         // Format of the Timestamp header
+	lint         State                   // Run Lint on Push responses (development)
+	retry        RetryPolicy             // Retries for transient write errors
+	deadLetter   DeadLetterSink          // Optional sink for responses whose writes failed
+	reqFormat    string                  // Content type forced on Request; empty uses the header
+	bodyLimits   []bodyLimit             // Request body size limits applied by Request
+	validator    func(interface{}) error // Validation hook applied by Request
+	compression  *Compression            // Optional Accept-Encoding negotiated compression
+	progress     progressPolicy          // Frequency of Stream progress callbacks
+	took         time.Duration           // Duration measured for the output call started at measured
+	measured     time.Time
+
+	limiter *limiter  // Optional Handler concurrency limiter
+	metrics Collector // Optional metrics sink
+
+	schemas     *SchemaRegistry // Optional response contract validation
+	schema      string          // Name of the schema responses must satisfy
+	schemaCheck State           // Whether schemas are checked; Unknown reads SchemaCheckEnv
+
+	identify func(*http.Request) string // Resolves the client identity for pinning
+	clients  *ClientRegistry            // Optional per-client content type and envelope pins
+
+	quota QuotaProvider // Optional quota usage reporting
+	usage *quotaLookup  // Quota fetched for the current output call
+
+	pushes []PushResource // Assets sent with HTTP/2 server push
+
+	locales []string // Locales the application translates into; the first is the default
+	locale  string   // Response language forced with WithLocale
+
+	hooks map[HookStage][]func(*HookContext) // Output interception hooks (copy-on-write)
+
+	charsets []string // Legacy charsets offered besides UTF-8
+	charset  string   // Charset the current output call was transcoded to
+
+	tracer Tracer      // Optional tracing of output calls
+	output *outputCall // Tracing and metrics state of the current output call
+
+	imageFallback *ImageFallback // Image sent in place of a missing one
+	imageCache    ImageCache     // Optional cache of encoded images
+	imageCacheTTL time.Duration  // Lifetime of cached images
+	imageKey      string         // Source key replacing the pixel hash in cache keys
+
+	idContextKey interface{}            // Context key holding inbound request IDs
+	idHeaders    []string               // Headers holding inbound request IDs; nil uses DefaultIDHeaders
+	idGenerator  func(time.Time) string // Generates request IDs; nil uses UUIDv4
+
+	annotationMeta State // Copy context annotations into meta.annotations
 }
 
 // NewRenderer creates a new Renderer with the provided settings and default content type.
@@ -103,6 +185,7 @@ func NewRenderer(s Setting) *Renderer {
 		showError:  Yes,
 		showSystem: No,
 		generateID: No,
+		mu:         &sync.RWMutex{},
 	}
 	// Ensure EnableHeaders defaults to true if not set
 	if !r.s.EnableHeaders {
@@ -120,6 +203,7 @@ func (r *Renderer) WithWriter(w Writer) *Renderer {
 		nr.httpWriter = hw
 	}
 	nr.writer = w
+	nr.stream = &streamState{}
 	return nr
 }
 
@@ -149,7 +233,8 @@ func (r *Renderer) WithConvertFilter(filters ...func(error) error) *Renderer {
 // Returns a new Renderer with the updated logger.
 func (r *Renderer) WithLogger(l Logger) *Renderer {
 	nr := r.clone()
-	nr.logger = l
+	nr.baseLogger = l
+	nr.routeLogger()
 	return nr
 }
 
@@ -190,8 +275,20 @@ func (r *Renderer) WithSystem(show SystemShow, sys System) *Renderer {
 	return nr
 }
 
-// WithIDGeneration enables or disables automatic ID generation.
-// Toggles the generateID field in a new Renderer copy.
+// WithDurationPrecision sets the precision of the Duration header and system meta.
+// Takes a unit such as time.Nanosecond, time.Microsecond, or time.Millisecond (the default);
+// header and body always report the same truncated value.
+// Returns a new Renderer with the updated precision.
+func (r *Renderer) WithDurationPrecision(p time.Duration) *Renderer {
+	nr := r.clone()
+	nr.precision = p
+	return nr
+}
+
+// WithIDGeneration enables or disables automatic request IDs.
+// When enabled, an ID found in the context or the bound request's headers is
+// reused before one is generated (see WithIDGenerator), and echoed in
+// X-Request-ID and meta.request_id.
 // Returns a new Renderer with the updated ID generation setting.
 func (r *Renderer) WithIDGeneration(enabled State) *Renderer {
 	nr := r.clone()
@@ -199,6 +296,16 @@ func (r *Renderer) WithIDGeneration(enabled State) *Renderer {
 	return nr
 }
 
+// WithRequestFormat forces the parser used by Request regardless of the
+// request's Content-Type, for endpoints receiving mislabeled bodies.
+// An empty contentType restores header-based selection.
+// Returns a new Renderer with the updated request format.
+func (r *Renderer) WithRequestFormat(contentType string) *Renderer {
+	nr := r.clone()
+	nr.reqFormat = contentType
+	return nr
+}
+
 // WithContext sets the context for the Renderer.
 // Assigns a context.Context for cancellation and deadlines.
 // Returns a new Renderer with the updated context.
@@ -208,6 +315,33 @@ func (r *Renderer) WithContext(ctx context.Context) *Renderer {
 	return nr
 }
 
+// WithRequest binds the inbound HTTP request to the Renderer.
+// Enables request-aware features such as Range handling and HEAD probing,
+// marks the request start used for slow-response detection, and surfaces
+// Unix socket peer credentials as meta "peer". Applies the client's pin
+// when WithClientPinning is configured.
+// Returns a new Renderer with the updated request.
+func (r *Renderer) WithRequest(req *http.Request) *Renderer {
+	nr := r.clone()
+	nr.request = req
+	nr.received = nr.now()
+	if req != nil {
+		if cred, ok := PeerCredFromContext(req.Context()); ok {
+			if nr.meta == nil {
+				nr.meta = make(map[string]interface{})
+			}
+			nr.meta[metaPeer] = cred
+		}
+	}
+	nr.pinClient()
+	return nr
+}
+
+// HTTPRequest returns the inbound request bound via WithRequest, if any.
+func (r *Renderer) HTTPRequest() *http.Request {
+	return r.request
+}
+
 // WithStatus sets the HTTP status code for the Renderer.
 // Assigns the provided HTTP status code (e.g., http.StatusOK).
 // Returns a new Renderer with the updated status code.
@@ -285,6 +419,7 @@ func (r *Renderer) WithMetaKV(kvs ...interface{}) *Renderer {
 func (r *Renderer) WithTag(tags ...string) *Renderer {
 	nr := r.clone()
 	nr.tags = append(nr.tags, tags...)
+	nr.routeLogger()
 	return nr
 }
 
@@ -310,8 +445,37 @@ func (r *Renderer) WithTitle(t string) *Renderer {
 // Adds the provided callback functions to handle response events.
 // Returns a new Renderer with updated callbacks.
 func (r *Renderer) WithCallback(cb ...func(data CallbackData)) *Renderer {
+	nr, _ := r.WithCallbackHandle(cb...)
+	return nr
+}
+
+// WithCallbackHandle adds callbacks like WithCallback and also returns their handle.
+// Pass the handle to WithoutCallback to derive a Renderer without them.
+// Returns a new Renderer with updated callbacks and the handle.
+func (r *Renderer) WithCallbackHandle(cb ...func(data CallbackData)) (*Renderer, CallbackHandle) {
+	nr := r.clone()
+	h := nr.callbacks.AddCallback(cb...)
+	return nr, h
+}
+
+// WithoutCallback removes the callbacks registered under h.
+// Returns a new Renderer without them; r keeps its callbacks.
+func (r *Renderer) WithoutCallback(h CallbackHandle) *Renderer {
+	nr := r.clone()
+	nr.callbacks.Remove(h)
+	return nr
+}
+
+// OnStatus registers a hook run for every Push response with the given status code.
+// Hooks run after handler logic and before encoding, so they can attach
+// remediation actions, documentation links, or meta to matching responses.
+// Returns a new Renderer with the hook added.
+func (r *Renderer) OnStatus(code int, hook StatusHook) *Renderer {
 	nr := r.clone()
-	nr.callbacks.AddCallback(cb...)
+	if nr.statusHooks == nil {
+		nr.statusHooks = make(map[int][]StatusHook)
+	}
+	nr.statusHooks[code] = append(nr.statusHooks[code], hook)
 	return nr
 }
 
@@ -366,6 +530,17 @@ func (r *Renderer) UseEncoder(e Encoder) *Renderer {
 	return nr
 }
 
+// WrapEncoder decorates the encoder for a content type with middleware.
+// Uses a private copy of the encoder registry so the base Renderer is unaffected.
+// Wrappers that do not implement Streamer fall back to generic chunked streaming.
+// Returns a new Renderer with the wrapped encoder.
+func (r *Renderer) WrapEncoder(contentType string, mw func(next Encoder) Encoder) *Renderer {
+	nr := r.clone()
+	nr.encoders = nr.encoders.Clone()
+	nr.encoders.Wrap(contentType, mw)
+	return nr
+}
+
 // WithContentType sets the output content type for the Renderer.
 // Assigns the provided content type string (e.g., "application/json").
 // Returns a new Renderer with the updated content type.
@@ -405,54 +580,38 @@ func (r *Renderer) WithShowError(show State) error {
 
 // Push sends a structured Response using the Renderer’s configuration.
 // Encodes and writes the Response with headers, handling errors with fallbacks.
-// Returns an error if encoding, header application, or writing fails.
-func (r *Renderer) Push(w Writer, d Response) error {
+// A nil w uses the writer bound with WithWriter.
+// Returns a *ConfigError if required configuration is missing, or an error if
+// encoding, header application, or writing fails.
+func (r *Renderer) Push(w Writer, d Response) (err error) {
 	nr := r.clone()
+	nr.begin("Push")
+	defer func() { nr.finish(nr.contentType, err) }()
 	// Only set start time if not already set (allows tests to preset it)
 	if nr.start.IsZero() {
-		nr.start = time.Now()
+		nr.start = nr.now()
 	}
 
 	// Check context cancellation first.
-	if nr.ctx != nil {
-		select {
-		case <-nr.ctx.Done():
-			nr.triggerCallbacks(nr.id, StatusError, "operation canceled", ErrContextCanceled)
-			return ErrContextCanceled
-		default:
-		}
+	if err := nr.canceled(); err != nil {
+		return err
 	}
 
 	if w == nil && nr.writer != nil {
 		w = nr.writer
 	}
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkConfig(w); err != nil {
+		return err
 	}
 
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 
 	resp := getResponse()
 	defer putResponse(resp)
-	resp.Status = d.Status
-	resp.Title = d.Title
-	resp.Message = d.Message
-	resp.Info = d.Info
-	resp.Data = d.Data
-	resp.Tags = slices.Clone(nr.tags)
-	resp.Actions = slices.Clone(nr.actions)
-	resp.Errors = d.Errors
-
-	if resp.Status == Empty {
-		resp.Status = StatusSuccessful
-	}
-	if resp.Title == Empty && resp.Status == StatusError {
-		resp.Title = "error"
-	}
+	nr.assemble(resp, d)
 
 	// Set default status codes if not already defined.
 	if nr.code == 0 {
@@ -468,28 +627,41 @@ func (r *Renderer) Push(w Writer, d Response) error {
 		}
 	}
 
-	// Merge metadata from Renderer to Response.
-	if len(nr.meta) > 0 {
-		if resp.Meta == nil {
-			resp.Meta = make(map[string]interface{})
-		}
-		for k, v := range nr.meta {
-			resp.Meta[k] = v
-		}
+	nr.markSlow(resp)
+	nr.markQuota(resp)
+	nr.markLocale(resp)
+	nr.markTrace(resp)
+	nr.markRequestID(resp)
+	nr.deprecate(resp)
+	nr.negotiateFields(resp)
+	nr.lintResponse(resp)
+
+	// Apply per-status hooks before encoding.
+	for _, hook := range nr.statusHooks[nr.code] {
+		hook(nr, resp)
 	}
 
-	// If system display is enabled, include system info in meta.
-	if nr.showSystem == SystemShowBody || nr.showSystem == SystemShowBoth {
-		if resp.Meta == nil {
-			resp.Meta = make(map[string]interface{})
+	// Issue a replay-protection nonce if enabled.
+	if nr.nonceStore != nil {
+		if err := nr.issueNonce(resp); err != nil {
+			nr.triggerCallbacks(nr.id, StatusFatal, err.Error(), err)
+			if nr.finalizer != nil {
+				nr.finalizer(w, err)
+			}
+			return err
 		}
-		sysCopy := nr.system
-		sysCopy.Duration = time.Since(nr.start).Truncate(time.Second)
-		resp.Meta["system"] = sysCopy
+	}
+
+	hc := nr.newHookContext(resp, nil)
+	if err := nr.runHooks(BeforeEncode, hc); err != nil {
+		return nr.hookAborted(w, err)
 	}
 
 	// Use the fallback-capable encoder.
-	encoded, err := nr.encoders.EncodeWithFallback(nr.contentType, *resp)
+	encodeStart := nr.now()
+	body := nr.envelope(resp)
+	encoded, err := nr.encoders.EncodeWithFallback(nr.contentType, body)
+	nr.timeEncode(nr.contentType, encodeStart)
 	if err != nil {
 		// We expect an EncoderError if encoding failed.
 		var encErr *EncoderError
@@ -508,7 +680,7 @@ func (r *Renderer) Push(w Writer, d Response) error {
 				}
 				return hdrErr
 			}
-			if _, wErr := w.Write(encoded); wErr != nil {
+			if _, wErr := nr.write(w, encoded); wErr != nil {
 				wrapped := errors.Join(errWriteFailed, wErr)
 				nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 				if nr.finalizer != nil {
@@ -528,7 +700,19 @@ func (r *Renderer) Push(w Writer, d Response) error {
 		return wrapped
 	}
 
-	if err := nr.applyCommonHeaders(w, nr.contentType); err != nil {
+	if err := nr.checkSchema(w, body); err != nil {
+		return err
+	}
+	if encoded, err = nr.hookBody(AfterEncode, hc, encoded); err != nil {
+		return nr.hookAborted(w, err)
+	}
+	encoded = nr.compress(nr.compressDictionary(nr.transcode(encoded, nr.responseType(resp))), nr.contentType)
+	nr.serverPush(w)
+	if encoded, err = nr.hookBody(BeforeWrite, hc, encoded); err != nil {
+		return nr.hookAborted(w, err)
+	}
+
+	if err := nr.applyCommonHeaders(w, nr.responseType(resp)); err != nil {
 		wrapped := errors.Join(errHeaderWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 		if nr.finalizer != nil {
@@ -537,7 +721,9 @@ func (r *Renderer) Push(w Writer, d Response) error {
 		return wrapped
 	}
 
-	if _, err := w.Write(encoded); err != nil {
+	_, err = nr.write(w, encoded)
+	nr.hookWritten(hc, err)
+	if err != nil {
 		wrapped := errors.Join(errWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 		if nr.finalizer != nil {
@@ -552,24 +738,36 @@ func (r *Renderer) Push(w Writer, d Response) error {
 
 // Raw sends raw data using the Renderer’s current content type.
 // Encodes and writes the provided data with headers, handling errors.
-// Returns an error if encoding, header application, or writing fails.
-func (r *Renderer) Raw(data interface{}) error {
+// Returns a *ConfigError if the writer or encoder is missing, or an error if
+// encoding, header application, or writing fails.
+func (r *Renderer) Raw(data interface{}) (err error) {
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.begin("Raw")
+	defer func() { nr.finish(nr.contentType, err) }()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkConfig(w); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Raw
 	}
 
+	hc := nr.newHookContext(nil, data)
+	if err := nr.runHooks(BeforeEncode, hc); err != nil {
+		return nr.hookAborted(w, err)
+	}
+	if hc != nil {
+		data = hc.Data
+	}
+
+	encodeStart := nr.now()
 	encoded, err := nr.encoders.Encode(nr.contentType, data)
+	nr.timeEncode(nr.contentType, encodeStart)
 	if err != nil {
 		wrapped := errors.Join(errEncodingFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -579,6 +777,18 @@ func (r *Renderer) Raw(data interface{}) error {
 		return wrapped
 	}
 
+	if err := nr.checkSchema(w, data); err != nil {
+		return err
+	}
+	if encoded, err = nr.hookBody(AfterEncode, hc, encoded); err != nil {
+		return nr.hookAborted(w, err)
+	}
+	encoded = nr.compress(nr.transcode(encoded, nr.contentType), nr.contentType)
+	nr.serverPush(w)
+	if encoded, err = nr.hookBody(BeforeWrite, hc, encoded); err != nil {
+		return nr.hookAborted(w, err)
+	}
+
 	if err := nr.applyCommonHeaders(w, nr.contentType); err != nil {
 		wrapped := errors.Join(errHeaderWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -588,7 +798,8 @@ func (r *Renderer) Raw(data interface{}) error {
 		return wrapped
 	}
 
-	_, err = w.Write(encoded)
+	_, err = nr.write(w, encoded)
+	nr.hookWritten(hc, err)
 	if err != nil {
 		wrapped := errors.Join(errWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -609,15 +820,14 @@ func (r *Renderer) Raw(data interface{}) error {
 func (r *Renderer) Rest(data interface{}) error {
 	nr := r.clone()
 	nr.contentType = ContentTypeJSON // Force JSON
-	nr.start = time.Now()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkConfig(w); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Rest
@@ -633,6 +843,10 @@ func (r *Renderer) Rest(data interface{}) error {
 		return wrapped
 	}
 
+	if err := nr.checkSchema(w, data); err != nil {
+		return err
+	}
+
 	if err := nr.applyCommonHeaders(w, nr.contentType); err != nil {
 		wrapped := errors.Join(errHeaderWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -642,7 +856,7 @@ func (r *Renderer) Rest(data interface{}) error {
 		return wrapped
 	}
 
-	_, err = w.Write(encoded)
+	_, err = nr.write(w, encoded)
 	if err != nil {
 		wrapped := errors.Join(errWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -658,22 +872,33 @@ func (r *Renderer) Rest(data interface{}) error {
 
 // Stream sends data incrementally using a callback to produce chunks.
 // Writes encoded chunks with headers, flushing if supported by the writer.
+// The callback may return a Chunk to encode an item with its own content type;
+// streaming formats such as NDJSON reject Chunks of other content types.
 // Returns an error if encoding, header application, or writing fails.
-func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
+func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) (err error) {
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.begin("Stream")
+	defer func() { nr.finish(nr.contentType, err) }()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkConfig(w); err != nil {
+		return err
+	}
+	if err := nr.canceled(); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Stream
 	}
+	untrack := nr.life().track(nr)
+	defer untrack()
+	stopKeepAlive := nr.startKeepAlive()
+	defer stopKeepAlive()
+	callback = nr.watchClient(nr.watchPeer(nr.watchShutdown(callback)))
 
 	// Check if the encoder supports streaming
 	encoder, ok := nr.encoders.Get(nr.contentType)
@@ -685,9 +910,14 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 		}
 		return err
 	}
+	headerType := nr.contentType
+	if mt, ok := encoder.(MediaTyper); ok {
+		headerType = mt.MediaType()
+	}
+	nr.stream.markHeaders()
 	if streamer, supportsStreaming := encoder.(Streamer); supportsStreaming {
 		// Delegate to the encoder's streaming implementation
-		if err := nr.applyCommonHeaders(w, nr.contentType); err != nil {
+		if err := nr.applyCommonHeaders(w, headerType); err != nil {
 			wrapped := errors.Join(errHeaderWriteFailed, err)
 			nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 			if nr.finalizer != nil {
@@ -695,11 +925,30 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 			}
 			return wrapped
 		}
-		return streamer.Stream(w, func() (interface{}, error) { return callback(nr) })
+		progress := nr.newProgressTracker()
+		next := progress.wrap(recoverStream(nr, callback))
+		item := func() (interface{}, error) {
+			data, err := next()
+			if err == nil {
+				data, err = streamerItem(encoder, nr.contentType, data)
+			}
+			return applyTypeMarshalers(data), err
+		}
+		var err error
+		if cs, ok := encoder.(ContextStreamer); ok {
+			err = cs.StreamContext(nr.Context(), nr.countWriter(progress.writer(w)), item)
+		} else {
+			err = streamer.Stream(nr.countWriter(progress.writer(w)), item)
+		}
+		var pe *PanicError
+		if errors.As(err, &pe) {
+			return nr.abortStream(w, encoder, pe, errors.Join(errors.New("stream callback failed"), err))
+		}
+		return err
 	}
 
 	// Fallback to generic streaming if no Streamer implementation
-	if err := nr.applyCommonHeaders(w, nr.contentType); err != nil {
+	if err := nr.applyCommonHeaders(w, headerType); err != nil {
 		wrapped := errors.Join(errHeaderWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 		if nr.finalizer != nil {
@@ -711,14 +960,20 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 	buf := streamBufferPool.Get().([]byte)
 	defer streamBufferPool.Put(buf[:0])
 
+	progress := nr.newProgressTracker()
+	next := recoverStream(nr, callback)
 	for {
-		data, err := callback(nr)
+		data, err := next()
 		if err != nil {
 			if errors.Is(err, io.EOF) { // End of stream
 				nr.triggerCallbacks(nr.id, StatusSuccessful, "Stream completed", nil)
 				return nil
 			}
 			wrapped := errors.Join(errors.New("stream callback failed"), err)
+			var pe *PanicError
+			if errors.As(err, &pe) {
+				return nr.abortStream(w, encoder, pe, wrapped)
+			}
 			nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 			if nr.finalizer != nil {
 				nr.finalizer(w, wrapped)
@@ -726,7 +981,9 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 			return wrapped
 		}
 
-		encoded, err := nr.encoders.Encode(nr.contentType, data)
+		encodeStart := nr.now()
+		encoded, err := nr.encodeStreamItem(data)
+		nr.timeEncode(nr.contentType, encodeStart)
 		if err != nil {
 			wrapped := errors.Join(errEncodingFailed, err)
 			nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -736,7 +993,8 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 			return wrapped
 		}
 
-		if _, err := w.Write(encoded); err != nil {
+		n, err := nr.write(w, encoded)
+		if err != nil {
 			wrapped := errors.Join(errWriteFailed, err)
 			nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
 			if nr.finalizer != nil {
@@ -748,6 +1006,10 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 		if flusher, ok := w.(http.Flusher); ok {
 			flusher.Flush()
 		}
+		if progress != nil {
+			progress.bytes += int64(n)
+			progress.chunk()
+		}
 	}
 }
 
@@ -756,15 +1018,14 @@ func (r *Renderer) Stream(callback func(*Renderer) (interface{}, error)) error {
 // Returns an error if data is not string or []byte, or if header application or writing fails.
 func (r *Renderer) Relay(data interface{}) error {
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkOutput(w); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Dump
@@ -795,7 +1056,7 @@ func (r *Renderer) Relay(data interface{}) error {
 		return wrapped
 	}
 
-	_, err := w.Write(bytesData)
+	_, err := nr.write(w, bytesData)
 	if err != nil {
 		wrapped := errors.Join(errWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -810,24 +1071,43 @@ func (r *Renderer) Relay(data interface{}) error {
 }
 
 // Binary sends binary data with the specified content type and headers.
-// Writes the provided byte slice with appropriate headers.
-// Returns an error if header application or writing fails.
-func (r *Renderer) Binary(contentType string, data []byte) error {
+// Writes the provided byte slice with appropriate headers. With a request bound
+// via WithRequest, Range headers are answered with 206 Partial Content (as
+// multipart/byteranges for several ranges) or 416 when unsatisfiable.
+// Returns ErrRangeNotSatisfiable after a 416, or an error if header
+// application or writing fails.
+func (r *Renderer) Binary(contentType string, data []byte) (err error) {
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.begin("Binary")
+	defer func() { nr.finish(contentType, err) }()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkOutput(w); err != nil {
+		return err
+	}
+	if err := nr.canceled(); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Binary
 	}
 
+	data, contentType, ranged, err := nr.serveRange(data, contentType)
+	if err != nil {
+		if hdrErr := nr.applyCommonHeaders(w, contentType); hdrErr != nil {
+			return errors.Join(errHeaderWriteFailed, hdrErr)
+		}
+		nr.triggerCallbacks(nr.id, StatusError, err.Error(), err)
+		return err
+	}
+	if !ranged {
+		data = nr.compress(data, contentType)
+	}
+
 	if err := nr.applyCommonHeaders(w, contentType); err != nil {
 		wrapped := errors.Join(errHeaderWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -837,7 +1117,7 @@ func (r *Renderer) Binary(contentType string, data []byte) error {
 		return wrapped
 	}
 
-	_, err := w.Write(data)
+	_, err = nr.write(w, data)
 	if err != nil {
 		wrapped := errors.Join(errWriteFailed, err)
 		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
@@ -856,15 +1136,14 @@ func (r *Renderer) Binary(contentType string, data []byte) error {
 // Returns an error if header application or writing fails.
 func (r *Renderer) Pusher(contentType string, data io.Reader) error {
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkOutput(w); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Loader
@@ -895,23 +1174,32 @@ func (r *Renderer) Pusher(contentType string, data io.Reader) error {
 
 // Image encodes and sends an image with the specified content type.
 // Encodes the provided image.Image (PNG, JPEG, GIF, WebP) and sends as binary data.
+// A nil image sends the WithImageFallback image instead, when configured.
+// Encodings are reused from the WithImageCache cache, when configured.
 // Returns an error if encoding, header application, or writing fails.
 func (r *Renderer) Image(contentType string, img image.Image) error {
+	if img == nil && r.imageFallback != nil {
+		return r.MissingImage()
+	}
 	nr := r.clone()
-	nr.start = time.Now()
+	nr.start = nr.now()
 	w := nr.writer
-	if w == nil {
-		return errNoWriter
+	if err := nr.checkOutput(w); err != nil {
+		return err
 	}
-	if nr.generateID.Enabled() && nr.id == Empty {
-		var buf [20]byte
-		n := len(strconv.AppendInt(buf[:0], time.Now().UnixNano(), 10))
-		nr.id = "req-" + string(buf[:n])
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
 	}
 	if nr.code == 0 {
 		nr.code = http.StatusOK // Default for Image
 	}
 
+	key := nr.imageCacheKey(contentType, img)
+	if data, ok := nr.cachedImage(key); ok {
+		return nr.Binary(contentType, data)
+	}
+
 	buf := bytes.NewBuffer(make([]byte, 0, 4096))
 	switch contentType {
 	case ContentTypePNG:
@@ -960,6 +1248,7 @@ func (r *Renderer) Image(contentType string, img image.Image) error {
 		return err
 	}
 
+	nr.storeImage(key, buf.Bytes())
 	return nr.Binary(contentType, buf.Bytes())
 }
 
@@ -967,8 +1256,8 @@ func (r *Renderer) Image(contentType string, img image.Image) error {
 // Sends a Response with StatusWarning and filtered errors, if any.
 // Returns an error if the writer is unset or sending fails; skips if all errors filtered.
 func (r *Renderer) Warning(errs ...error) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 
 	filteredErrs := r.filterErrorsForLogging(errs)
@@ -989,8 +1278,8 @@ func (r *Renderer) Warning(errs ...error) error {
 // Formats the message with provided args, sending StatusWarning with filtered errors.
 // Returns an error if the writer is unset or sending fails; skips if all errors filtered.
 func (r *Renderer) Warningf(format string, args ...interface{}) error {
-	if r.writer == nil {
-		return errNoWriter
+	if err := r.checkConfig(r.writer); err != nil {
+		return err
 	}
 
 	errorList := Any2Error(args...)
@@ -1031,7 +1320,7 @@ func (r *Renderer) Log(err error) {
 		return
 	}
 	if r.logger != nil {
-		r.logger.Error(err)
+		r.logger.Error(err, r.annotationFields()...)
 	}
 }
 
@@ -1065,12 +1354,38 @@ func (r *Renderer) Logf(format string, args ...interface{}) {
 }
 
 // Handler wraps a function into an HTTP handler, handling errors with Fatal.
-// Takes a function that processes the Renderer and returns an error.
+// Takes a function that processes the Renderer and returns an error;
+// oversized request bodies are answered with a 413 and validation failures
+// with a 422 listing the failed fields instead.
+// Sheds excess requests with a 503 when WithLoadShedding is configured,
+// rejects replay-unsafe 0-RTT requests with a 425, and answers 503 while a
+// Runtime is in maintenance mode. The request context carries an
+// annotation collector for Annotate.
 // Returns an http.HandlerFunc for use in HTTP servers.
 func (r *Renderer) Handler(fn func(r *Renderer) error) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		renderer := r.WithWriter(w)
+		if r.limiter != nil {
+			if !r.limiter.acquire() {
+				r.shed(w, req)
+				return
+			}
+			defer r.limiter.release()
+		}
+		if !r.replaySafe(req) {
+			r.tooEarly(w, req)
+			return
+		}
+		if r.inMaintenance() {
+			r.maintenance(w, req)
+			return
+		}
+		req = req.WithContext(WithAnnotations(req.Context()))
+		renderer := r.WithWriter(w).WithRequest(req)
 		if err := fn(renderer); err != nil {
+			if requestErrorStatus([]error{err}) != 0 {
+				_ = renderer.requestError(err)
+				return
+			}
 			_ = renderer.Fatal(err)
 		}
 	}
@@ -1084,15 +1399,33 @@ func (r *Renderer) Reader() *hauler.Hauler {
 }
 
 // Request reads and parses an HTTP request body into the provided value.
-// Uses the Hauler to parse the request body based on content type.
+// Uses the Hauler to parse the request body based on content type, or the
+// format forced with WithRequestFormat, reporting parse metrics to the
+// Collector set with WithMetrics and enforcing WithMaxBodySize limits.
+// Decoded values are validated via hauler.Validator and WithValidator.
 // Returns an error if the request is nil or parsing fails; logs errors if applicable.
 func (r *Renderer) Request(req *http.Request, v interface{}) error {
 	if req == nil {
 		return hauler.ErrNilRequest
 	}
 
-	// Use the default reader
-	err := hauler.Read(req, v)
+	// Use the default reader, forcing the parser if a request format is set
+	reader := hauler.DefaultReader
+	if r.metrics != nil {
+		reader = reader.WithMetrics(r.metrics)
+	}
+	for _, l := range r.bodyLimits {
+		reader = reader.WithMaxBodySize(l.limit, l.contentTypes...)
+	}
+	if r.validator != nil {
+		reader = reader.WithValidator(r.validator)
+	}
+	var err error
+	if r.reqFormat != "" {
+		err = reader.ReadAs(req, r.reqFormat, v)
+	} else {
+		err = reader.Read(req, v)
+	}
 	if err != nil {
 		// Log the error if we have a logger
 		r.Log(err)
@@ -1184,9 +1517,98 @@ func (r *Renderer) clone() *Renderer {
 	newRenderer.header = cloneHeader(r.header)
 	newRenderer.callbacks = r.callbacks.Clone()
 	newRenderer.errorFilters = r.errorFilters.clone()
+	newRenderer.statusHooks = cloneStatusHooks(r.statusHooks)
+	newRenderer.mu = &sync.RWMutex{}
+	newRenderer.usage = nil
+	newRenderer.charset = Empty
+	newRenderer.output = nil
 	return &newRenderer
 }
 
+// assemble populates resp from d and the Renderer's configuration.
+// Copies the response fields, applies default status, title, and type
+// discriminators, and merges tags, actions, metadata, and system information
+// from the Renderer.
+func (r *Renderer) assemble(resp *Response, d Response) {
+	resp.Status = d.Status
+	resp.Title = d.Title
+	resp.Message = d.Message
+	resp.Info = d.Info
+	resp.Data = d.Data
+	resp.InfoType = d.InfoType
+	resp.DataType = d.DataType
+	resp.Tags = slices.Clone(r.tags)
+	resp.Actions = slices.Clone(r.actions)
+	resp.Errors = d.Errors
+
+	if resp.Status == Empty {
+		resp.Status = StatusSuccessful
+	}
+	if resp.Title == Empty && resp.Status == StatusError {
+		resp.Title = "error"
+	}
+	r.discriminate(resp)
+
+	// Merge metadata from Renderer to Response.
+	if len(r.meta) > 0 {
+		if resp.Meta == nil {
+			resp.Meta = make(map[string]interface{})
+		}
+		for k, v := range r.meta {
+			resp.Meta[k] = v
+		}
+	}
+
+	// If system display is enabled, include system info in meta.
+	if r.showSystem == SystemShowBody || r.showSystem == SystemShowBoth {
+		if resp.Meta == nil {
+			resp.Meta = make(map[string]interface{})
+		}
+		sysCopy := r.system
+		sysCopy.Duration = r.duration()
+		resp.Meta["system"] = sysCopy
+	}
+	r.annotate(resp)
+}
+
+// ensureID assigns an ID when ID generation is enabled and none is set.
+// Inbound IDs are preferred over ones from the WithIDGenerator generator.
+func (r *Renderer) ensureID() {
+	if !r.generateID.Enabled() || r.id != Empty {
+		return
+	}
+	if id := r.inboundID(); id != Empty {
+		r.id = id
+		return
+	}
+	r.id = r.newID()
+}
+
+// duration returns the time elapsed since the output call, truncated to the configured precision.
+// Measured once per output call so the header and body carry the same value.
+func (r *Renderer) duration() time.Duration {
+	if r.measured.Equal(r.start) {
+		return r.took
+	}
+	p := r.precision
+	if p <= 0 {
+		p = time.Millisecond
+	}
+	r.took, r.measured = r.now().Sub(r.start).Truncate(p), r.start
+	return r.took
+}
+
+// headerName builds a prefixed Beam header name for the given key.
+// Uses "X-<name>" when the Renderer has a name and HeaderPrefix otherwise.
+// Returns the full header name (e.g., "X-beam-Duration").
+func (r *Renderer) headerName(key string) string {
+	prefix := HeaderPrefix
+	if r.s.Name != Empty {
+		prefix = "X-" + r.s.Name
+	}
+	return prefix + "-" + key
+}
+
 // applyCommonHeaders builds and applies common headers to the writer.
 // Sets headers including content type, system metadata, and presets.
 // Returns an error if the writer or protocol is nil or header application fails.
@@ -1200,19 +1622,21 @@ func (r *Renderer) applyCommonHeaders(w Writer, contentType string) error {
 
 	// Build common headers with a prefix based on the application name.
 	setHeader := func(key, value string) {
-		prefix := HeaderPrefix
-		if r.s.Name != Empty {
-			prefix = "X-" + r.s.Name
-		}
-		r.header.Set(prefix+"-"+key, value)
+		r.header.Set(r.headerName(key), value)
 	}
 
+	r.annotateSlow()
+	r.quotaHeaders()
+	r.localize()
+	r.traceHeaders()
+	r.requestIDHeader()
+
 	if r.s.EnableHeaders {
-		r.header.Set(HeaderContentType, contentType)
+		r.header.Set(HeaderContentType, r.charsetType(contentType))
 		// Optionally include system metadata in headers.
 		if r.showSystem == SystemShowHeaders || r.showSystem == SystemShowBoth {
-			setHeader(HeaderNameDuration, time.Since(r.start).String())
-			setHeader(HeaderNameTimestamp, strconv.FormatInt(time.Now().Unix(), 10))
+			setHeader(HeaderNameDuration, r.duration().String())
+			setHeader(HeaderNameTimestamp, r.timestamp())
 			if r.system.App != Empty {
 				setHeader(HeaderNameApp, r.system.App)
 			}
@@ -1230,8 +1654,8 @@ func (r *Renderer) applyCommonHeaders(w Writer, contentType string) error {
 		// Apply preset headers if available.
 		if r.s.Presets != nil {
 			if preset, ok := r.s.Presets[contentType]; ok && preset.Headers != nil {
-				for key, values := range preset.Headers {
-					for _, value := range values {
+				for _, key := range sortedKeys(preset.Headers) {
+					for _, value := range preset.Headers[key] {
 						r.header.Add(key, value)
 					}
 				}
@@ -1239,14 +1663,14 @@ func (r *Renderer) applyCommonHeaders(w Writer, contentType string) error {
 		}
 		// If httpWriter is set, use it directly to avoid type assertion.
 		if r.httpWriter != nil {
-			for key, values := range r.header {
-				for _, value := range values {
+			for _, key := range sortedKeys(r.header) {
+				for _, value := range r.header[key] {
 					r.httpWriter.Header().Add(key, value)
 				}
 			}
 		} else if hw, ok := w.(http.ResponseWriter); ok {
-			for key, values := range r.header {
-				for _, value := range values {
+			for _, key := range sortedKeys(r.header) {
+				for _, value := range r.header[key] {
 					hw.Header().Add(key, value)
 				}
 			}
@@ -1259,8 +1683,21 @@ func (r *Renderer) applyCommonHeaders(w Writer, contentType string) error {
 // Triggers callbacks with the provided ID, status, message, and error.
 // Logs errors via the Renderer’s logger if present; no return value.
 func (r *Renderer) triggerCallbacks(id, status, msg string, err error) {
-	r.callbacks.Trigger(id, status, msg, err)
+	r.emit(newCallbackData(id, status, msg, err))
 	if err != nil && r.logger != nil {
 		r.logger.Error(err)
 	}
 }
+
+// reportCallbacks logs callbacks that panicked while being triggered.
+func (r *Renderer) reportCallbacks(err error) {
+	if err == nil || r.logger == nil {
+		return
+	}
+	var pe *CallbackPanicError
+	if errors.As(err, &pe) {
+		r.logger.Error(err, "id", r.id, "stack", string(pe.Stack))
+		return
+	}
+	r.logger.Error(err, "id", r.id)
+}
@@ -264,6 +264,37 @@ func TestRenderer_Push(t *testing.T) {
 	})
 }
 
+func TestRenderer_OnStatus(t *testing.T) {
+	base := NewRenderer(settings).OnStatus(http.StatusNotFound, func(r *Renderer, resp *Response) {
+		resp.Actions = append(resp.Actions, Action{Name: "docs", Href: "https://example.com/docs/404"})
+	})
+
+	t.Run("MatchingStatus", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		err := base.WithWriter(tw).WithStatus(http.StatusNotFound).Push(tw, Response{Status: StatusError, Message: "missing"})
+		if err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		var result Response
+		if err := json.Unmarshal(tw.Buffer.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(result.Actions) != 1 || result.Actions[0].Name != "docs" {
+			t.Errorf("Expected hook to add docs action, got %+v", result.Actions)
+		}
+	})
+
+	t.Run("OtherStatus", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		if err := base.WithWriter(tw).Push(tw, Response{Status: StatusSuccessful}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		if strings.Contains(tw.Buffer.String(), "docs") {
+			t.Errorf("Expected hook not to run for 200, got %s", tw.Buffer.String())
+		}
+	})
+}
+
 func TestRenderer_Raw(t *testing.T) {
 	t.Run("SuccessfulRaw", func(t *testing.T) {
 		tw := &TestWriter{Headers: make(http.Header)}
@@ -288,12 +319,47 @@ func TestRenderer_Raw(t *testing.T) {
 		r := NewRenderer(settings) // No writer set
 
 		err := r.Raw("test")
-		if err == nil || !strings.Contains(err.Error(), "no writer set") {
+		if !errors.Is(err, errNoWriter) {
 			t.Errorf("Expected no writer error, got %v", err)
 		}
 	})
 }
 
+// sizeEncoder is an encoder middleware that records marshaled byte counts.
+type sizeEncoder struct {
+	Encoder
+	total *int
+}
+
+func (e sizeEncoder) Marshal(v interface{}) ([]byte, error) {
+	data, err := e.Encoder.Marshal(v)
+	*e.total += len(data)
+	return data, err
+}
+
+func TestRenderer_WrapEncoder(t *testing.T) {
+	total := 0
+	base := NewRenderer(settings)
+	tw := &TestWriter{Headers: make(http.Header)}
+	r := base.WrapEncoder(ContentTypeJSON, func(next Encoder) Encoder {
+		return sizeEncoder{Encoder: next, total: &total}
+	}).WithWriter(tw)
+
+	if err := r.Raw(map[string]string{"key": "value"}); err != nil {
+		t.Fatalf("Raw failed: %v", err)
+	}
+	if total != tw.Buffer.Len() {
+		t.Errorf("Expected middleware to count %d bytes, got %d", tw.Buffer.Len(), total)
+	}
+
+	if err := base.WithWriter(&TestWriter{Headers: make(http.Header)}).Raw("x"); err != nil {
+		t.Fatalf("Raw failed: %v", err)
+	}
+	if total != tw.Buffer.Len() {
+		t.Errorf("Expected base renderer to be unwrapped, counted %d bytes", total)
+	}
+}
+
 func TestRenderer_Stream(t *testing.T) {
 	t.Run("SuccessfulStreamEventStream", func(t *testing.T) {
 		tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
@@ -360,12 +426,62 @@ func TestRenderer_Stream(t *testing.T) {
 		}
 	})
 
+	t.Run("MixedChunks", func(t *testing.T) {
+		tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+		r := NewRenderer(settings).WithWriter(tfw)
+
+		items := []interface{}{
+			map[string]string{"state": "start"},
+			NewChunk(ContentTypeBinary, []byte{0x01, 0x02}),
+			NewChunk(ContentTypeText, 42),
+		}
+		i := 0
+		err := r.Stream(func(r *Renderer) (interface{}, error) {
+			if i >= len(items) {
+				return nil, io.EOF
+			}
+			i++
+			return items[i-1], nil
+		})
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+
+		expected := "{\"state\":\"start\"}\x01\x0242"
+		if output := tfw.Buffer.String(); output != expected {
+			t.Errorf("Expected output %q, got %q", expected, output)
+		}
+	})
+
+	t.Run("ChunksOnStreamer", func(t *testing.T) {
+		stream := func(items ...interface{}) (string, error) {
+			tfw := &TestFlusherWriter{TestWriter: TestWriter{Headers: make(http.Header)}}
+			r := NewRenderer(settings).WithContentType(ContentTypeNDJSON).WithWriter(tfw)
+			i := 0
+			err := r.Stream(func(r *Renderer) (interface{}, error) {
+				if i >= len(items) {
+					return nil, io.EOF
+				}
+				i++
+				return items[i-1], nil
+			})
+			return tfw.Buffer.String(), err
+		}
+		out, err := stream(NewChunk(ContentTypeNDJSON, map[string]int{"n": 1}), NewChunk(Empty, map[string]int{"n": 2}))
+		if err != nil || out != "{\"n\":1}\n{\"n\":2}\n" {
+			t.Errorf("Expected matching chunks unwrapped into lines, got %q (%v)", out, err)
+		}
+		if _, err := stream(NewChunk(ContentTypeBinary, []byte{0x01})); !errors.Is(err, errChunkType) {
+			t.Errorf("Expected errChunkType for a foreign chunk, got %v", err)
+		}
+	})
+
 	t.Run("NoWriter", func(t *testing.T) {
 		r := NewRenderer(settings).WithContentType(ContentTypeEventStream)
 		err := r.Stream(func(r *Renderer) (interface{}, error) {
 			return Event{Data: "test"}, nil
 		})
-		if err == nil || !strings.Contains(err.Error(), "no writer set") {
+		if !errors.Is(err, errNoWriter) {
 			t.Errorf("Expected no writer error, got %v", err)
 		}
 	})
@@ -756,3 +872,108 @@ func TestSpecificCase(t *testing.T) {
 		t.Errorf("Expected errors [first, third], got %v", resp.Errors)
 	}
 }
+
+func TestRenderer_DurationPrecision(t *testing.T) {
+	for _, p := range []time.Duration{time.Nanosecond, time.Microsecond, time.Millisecond} {
+		t.Run(p.String(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := NewRenderer(settings).WithSystem(SystemShowBoth, System{App: "app"}).WithDurationPrecision(p)
+			r.start = time.Now().Add(-1234567 * time.Nanosecond)
+			if err := r.Push(w, Response{Message: "ok"}); err != nil {
+				t.Fatalf("Push failed: %v", err)
+			}
+			header := w.Header().Get("X-test-Duration")
+			var body struct {
+				Meta struct {
+					System struct {
+						Duration string `json:"duration"`
+					} `json:"system"`
+				} `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+			if header != body.Meta.System.Duration {
+				t.Errorf("Expected header and body to match, got %q and %q", header, body.Meta.System.Duration)
+			}
+			d, err := time.ParseDuration(header)
+			if err != nil || d%p != 0 || d < (1234567*time.Nanosecond).Truncate(p) {
+				t.Errorf("Expected duration truncated to %s, got %q", p, header)
+			}
+		})
+	}
+}
+
+func TestStableXMLOutput(t *testing.T) {
+	e := &XMLEncoder{}
+	m := map[string]interface{}{"zeta": 1, "alpha": 2, "mid": map[string]interface{}{"y": 1, "b": 2, "k": 3}}
+
+	t.Run("Map", func(t *testing.T) {
+		flat := map[string]interface{}{"zeta": 1, "alpha": 2, "mid": 3, "beta": 4}
+		first, err := e.Marshal(flat)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		for i := 0; i < 20; i++ {
+			next, _ := e.Marshal(flat)
+			if !bytes.Equal(first, next) {
+				t.Fatalf("Expected stable output, got %s and %s", first, next)
+			}
+		}
+		if !strings.HasPrefix(string(first), "<alpha>") {
+			t.Errorf("Expected sorted elements, got %s", first)
+		}
+	})
+
+	t.Run("NestedMeta", func(t *testing.T) {
+		out, err := e.Marshal(Response{Status: StatusSuccessful, Meta: m})
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		order := []string{"<alpha>", "<mid>", "<b>", "<k>", "<y>", "<zeta>"}
+		last := -1
+		for _, tag := range order {
+			i := strings.Index(string(out), tag)
+			if i < last {
+				t.Fatalf("Expected sorted nested meta, got %s", out)
+			}
+			last = i
+		}
+		if last < 0 {
+			t.Errorf("Expected sorted nested meta, got %s", out)
+		}
+	})
+
+	t.Run("PresetHeaders", func(t *testing.T) {
+		s := Setting{Name: "test", EnableHeaders: true, Presets: map[string]Preset{
+			ContentTypeJSON: {Headers: http.Header{"x-a": {"1"}, "X-A": {"2"}, "X-a": {"3"}}},
+		}}
+		for i := 0; i < 20; i++ {
+			w := httptest.NewRecorder()
+			if err := NewRenderer(s).Push(w, Response{}); err != nil {
+				t.Fatalf("Push failed: %v", err)
+			}
+			if got := strings.Join(w.Header().Values("X-A"), ","); got != "2,3,1" {
+				t.Fatalf("Expected values in sorted key order, got %q", got)
+			}
+		}
+	})
+}
+
+func TestWithRequestFormat(t *testing.T) {
+	newReq := func() *http.Request {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"alice"}`))
+		req.Header.Set("Content-Type", "text/plain")
+		return req
+	}
+	base := NewRenderer(settings)
+
+	var text string
+	if err := base.Request(newReq(), &text); err != nil || text != `{"name":"alice"}` {
+		t.Errorf("Expected header-based text parsing, got %q (%v)", text, err)
+	}
+	var data map[string]string
+	if err := base.WithRequestFormat(ContentTypeJSON).Request(newReq(), &data); err != nil || data["name"] != "alice" {
+		t.Errorf("Expected forced JSON parsing, got %v (%v)", data, err)
+	}
+}
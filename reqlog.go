@@ -0,0 +1,200 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HeaderRequestID is the header used to read and propagate request IDs.
+const HeaderRequestID = "X-Request-ID"
+
+// InfoLogger is an optional Logger extension for non-error entries.
+// Request logging uses Info when the Renderer's logger implements it.
+type InfoLogger interface {
+	Info(msg string, fields ...interface{})
+}
+
+// UnparseableBody replaces captured bodies that cannot be redacted safely.
+const UnparseableBody = "[unparseable body redacted]"
+
+// redactCaptureLimit bounds the body captured for redactors, which need the
+// whole body: a truncated JSON prefix cannot be parsed and redacted.
+const redactCaptureLimit = 1 << 20
+
+// infoTo logs msg at info level, falling back to warn and then Error for
+// loggers without Info.
+func infoTo(l Logger, msg string, fields ...interface{}) {
+	if il, ok := l.(InfoLogger); ok {
+		il.Info(msg, fields...)
+		return
+	}
+	warnTo(l, errors.New(msg), fields...)
+}
+
+// RequestLogOptions configures the LogRequests middleware.
+// The zero value logs every request without capturing bodies.
+type RequestLogOptions struct {
+	BodySampleRate float64               // Fraction of requests (0..1) whose response body is captured
+	MaxBodySize    int                   // Maximum captured bytes (default 4KB)
+	Redact         []func([]byte) []byte // Applied in order to whole captured bodies before truncation and logging
+}
+
+// RedactKeys returns a body redactor that masks the values of the given JSON keys.
+// Keys are matched case-insensitively at any depth; bodies that are not valid
+// JSON are replaced by UnparseableBody, since they cannot be checked.
+func RedactKeys(keys ...string) func([]byte) []byte {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[strings.ToLower(k)] = true
+	}
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, val := range t {
+				if set[strings.ToLower(k)] {
+					t[k] = "*hidden*"
+				} else {
+					t[k] = walk(val)
+				}
+			}
+		case []interface{}:
+			for i := range t {
+				t[i] = walk(t[i])
+			}
+		}
+		return v
+	}
+	return func(body []byte) []byte {
+		var doc interface{}
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return []byte(UnparseableBody)
+		}
+		out, err := json.Marshal(walk(doc))
+		if err != nil {
+			return []byte(UnparseableBody)
+		}
+		return out
+	}
+}
+
+// LogRequests returns middleware that logs one entry per request through the Renderer's logger.
+// Records method, path, status, size, duration, and request ID; a sampled fraction of
+// response bodies is captured, passed through the redactors, and truncated to
+// MaxBodySize. Bodies too large to redact whole are logged as UnparseableBody.
+// Entries are logged at info level, warn for 4xx, and error for 5xx.
+// Requests without an X-Request-ID header are assigned one, echoed in the response.
+func (r *Renderer) LogRequests(opts RequestLogOptions) func(http.Handler) http.Handler {
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = 4 << 10
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if r.logger == nil {
+				next.ServeHTTP(w, req)
+				return
+			}
+			start := time.Now()
+			id := req.Header.Get(HeaderRequestID)
+			if id == Empty {
+				id = r.newID()
+				req.Header.Set(HeaderRequestID, id)
+			}
+			w.Header().Set(HeaderRequestID, id)
+
+			cw := &captureWriter{ResponseWriter: w, limit: -1}
+			if opts.BodySampleRate > 0 && rand.Float64() < opts.BodySampleRate {
+				cw.limit = opts.MaxBodySize
+				if len(opts.Redact) > 0 {
+					cw.limit = max(opts.MaxBodySize, redactCaptureLimit)
+				}
+			}
+			next.ServeHTTP(cw, req)
+			if cw.status == 0 {
+				cw.status = http.StatusOK
+			}
+
+			fields := []interface{}{
+				"method", req.Method,
+				"path", req.URL.Path,
+				"status", cw.status,
+				"size", cw.size,
+				"duration", time.Since(start),
+				"request_id", id,
+			}
+			if cw.limit >= 0 {
+				body := cw.body.Bytes()
+				if cw.truncated && len(opts.Redact) > 0 {
+					body = []byte(UnparseableBody)
+				} else {
+					for _, redact := range opts.Redact {
+						body = redact(body)
+					}
+				}
+				fields = append(fields, "body", string(body[:min(len(body), opts.MaxBodySize)]))
+			}
+
+			msg := fmt.Sprintf("%s %s %d", req.Method, req.URL.Path, cw.status)
+			switch {
+			case cw.status >= http.StatusInternalServerError:
+				r.logger.Error(errors.New(msg), fields...)
+			case cw.status >= http.StatusBadRequest:
+				warnTo(r.logger, errors.New(msg), fields...)
+			default:
+				infoTo(r.logger, msg, fields...)
+			}
+		})
+	}
+}
+
+// captureWriter records the status, size, and optionally a prefix of the body.
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	limit  int // Bytes of body to capture; negative disables capture
+	body   bytes.Buffer
+
+	truncated bool // Body exceeded the capture limit
+}
+
+// WriteHeader records the status code before delegating.
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.status == 0 {
+		cw.status = code
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write records size and captured body before delegating.
+func (cw *captureWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if cw.limit >= 0 {
+		room := cw.limit - cw.body.Len()
+		cw.body.Write(p[:max(min(room, len(p)), 0)])
+		cw.truncated = cw.truncated || room < len(p)
+	}
+	n, err := cw.ResponseWriter.Write(p)
+	cw.size += n
+	return n, err
+}
+
+// Flush forwards to the underlying writer when it supports flushing.
+func (cw *captureWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (cw *captureWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
@@ -0,0 +1,123 @@
+package beam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// infoLogger extends TestLogger with Info entries.
+type infoLogger struct {
+	TestLogger
+}
+
+func (l *infoLogger) Info(msg string, fields ...interface{}) {
+	l.Entries = append(l.Entries, LogEntry{Level: "info", Fields: fields})
+}
+
+// warnLogger extends TestLogger with Warn entries only.
+type warnLogger struct {
+	TestLogger
+}
+
+func (l *warnLogger) Warn(err error, fields ...interface{}) {
+	l.Entries = append(l.Entries, LogEntry{Level: "warn", Err: err, Fields: fields})
+}
+
+// logFields indexes the key/value fields of a log entry.
+func logFields(e *LogEntry) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(e.Fields); i += 2 {
+		fields[e.Fields[i].(string)] = e.Fields[i+1]
+	}
+	return fields
+}
+
+func TestLogRequests(t *testing.T) {
+	logger := &infoLogger{}
+	r := NewRenderer(settings).WithLogger(logger)
+	mw := r.LogRequests(RequestLogOptions{
+		BodySampleRate: 1,
+		Redact:         []func([]byte) []byte{RedactKeys("token")},
+	})
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"user":"ada","token":"secret"}`))
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	if rec.Header().Get(HeaderRequestID) == Empty {
+		t.Error("Expected request ID to be assigned")
+	}
+	entry := logger.LastEntry()
+	if entry == nil || entry.Level != "info" {
+		t.Fatalf("Expected info entry, got %+v", entry)
+	}
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(entry.Fields); i += 2 {
+		fields[entry.Fields[i].(string)] = entry.Fields[i+1]
+	}
+	if fields["status"] != http.StatusCreated || fields["size"] != 31 || fields["path"] != "/users" {
+		t.Errorf("Unexpected fields: %+v", fields)
+	}
+	body, _ := fields["body"].(string)
+	if strings.Contains(body, "secret") || !strings.Contains(body, "ada") {
+		t.Errorf("Expected redacted body capture, got %q", body)
+	}
+
+	t.Run("RedactBeforeTruncate", func(t *testing.T) {
+		body := `{"token":"secret","items":"` + strings.Repeat("x", 100) + `"}`
+		capture := func(body string, opts RequestLogOptions) string {
+			logger := &infoLogger{}
+			opts.BodySampleRate = 1
+			h := NewRenderer(settings).WithLogger(logger).LogRequests(opts)(
+				http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					w.Write([]byte(body))
+				}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			got, _ := logFields(logger.LastEntry())["body"].(string)
+			return got
+		}
+		redact := []func([]byte) []byte{RedactKeys("token")}
+		if got := capture(body, RequestLogOptions{MaxBodySize: 20, Redact: redact}); strings.Contains(got, "secret") || len(got) != 20 {
+			t.Errorf("Expected a redacted 20-byte capture, got %q", got)
+		}
+		if got := capture("token=secret", RequestLogOptions{Redact: redact}); got != UnparseableBody {
+			t.Errorf("Expected unparseable bodies hidden, got %q", got)
+		}
+		huge := `{"token":"secret","items":"` + strings.Repeat("x", redactCaptureLimit) + `"}`
+		if got := capture(huge, RequestLogOptions{Redact: redact}); got != UnparseableBody {
+			t.Errorf("Expected bodies too large to redact hidden, got %.40q", got)
+		}
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		logger := &warnLogger{}
+		status := http.StatusOK
+		h := NewRenderer(settings).WithLogger(logger).LogRequests(RequestLogOptions{})(
+			http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(status)
+			}))
+		for _, status = range []int{http.StatusOK, http.StatusNotFound} {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			if entry := logger.LastEntry(); entry == nil || entry.Level != "warn" {
+				t.Errorf("Expected %d logged at warn without Info, got %+v", status, entry)
+			}
+		}
+	})
+
+	t.Run("ServerErrorsUseError", func(t *testing.T) {
+		logger := &infoLogger{}
+		h := NewRenderer(settings).WithLogger(logger).LogRequests(RequestLogOptions{})(
+			http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		if entry := logger.LastEntry(); entry == nil || entry.Level != "error" {
+			t.Errorf("Expected error entry for 502, got %+v", entry)
+		}
+	})
+}
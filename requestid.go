@@ -0,0 +1,132 @@
+package beam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// metaRequestID is the meta key echoing the request ID.
+const metaRequestID = "request_id"
+
+// HeaderTraceparent is the W3C Trace Context header; its trace ID is used as
+// the request ID when no X-Request-ID is present.
+const HeaderTraceparent = "traceparent"
+
+// maxRequestIDLen bounds inbound request IDs, which are echoed verbatim.
+const maxRequestIDLen = 128
+
+// DefaultIDHeaders are the inbound headers request IDs are read from, in order.
+var DefaultIDHeaders = []string{HeaderRequestID, HeaderTraceparent}
+
+// requestIDKey is the default context key of request IDs.
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying id, for middleware
+// that assigns request IDs before the Renderer sees the request.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// WithIDContextKey reads request IDs from the context value under key, such
+// as a router's request ID middleware key, instead of ContextWithRequestID's.
+// Returns a new Renderer with the key set; nil restores the default key.
+func (r *Renderer) WithIDContextKey(key interface{}) *Renderer {
+	nr := r.clone()
+	nr.idContextKey = key
+	return nr
+}
+
+// WithIDHeaders sets the inbound headers request IDs are read from, in order.
+// A traceparent header contributes its trace ID. Requires the request bound
+// with WithRequest.
+// Returns a new Renderer with the headers set; none disables header lookup.
+func (r *Renderer) WithIDHeaders(headers ...string) *Renderer {
+	nr := r.clone()
+	nr.idHeaders = append([]string{}, headers...)
+	return nr
+}
+
+// inboundID returns the request ID carried by the Renderer's context or the
+// bound request's headers, or an empty string if there is none.
+func (r *Renderer) inboundID() string {
+	var key interface{} = requestIDKey{}
+	if r.idContextKey != nil {
+		key = r.idContextKey
+	}
+	switch v := r.Context().Value(key).(type) {
+	case string:
+		if validRequestID(v) {
+			return v
+		}
+	case fmt.Stringer:
+		if id := v.String(); validRequestID(id) {
+			return id
+		}
+	}
+
+	if r.request == nil {
+		return Empty
+	}
+	headers := r.idHeaders
+	if headers == nil {
+		headers = DefaultIDHeaders
+	}
+	for _, h := range headers {
+		id := strings.TrimSpace(r.request.Header.Get(h))
+		if strings.EqualFold(h, HeaderTraceparent) {
+			id = traceparentID(id)
+		}
+		if validRequestID(id) {
+			return id
+		}
+	}
+	return Empty
+}
+
+// traceparentID returns the trace ID of a W3C traceparent value
+// ("version-traceid-parentid-flags"), or an empty string if it is malformed.
+func traceparentID(v string) string {
+	parts := strings.Split(v, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == Empty {
+		return Empty
+	}
+	for _, c := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return Empty
+		}
+	}
+	return parts[1]
+}
+
+// validRequestID reports whether an inbound ID is safe to echo: non-empty,
+// bounded, and printable ASCII.
+func validRequestID(id string) bool {
+	if id == Empty || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDHeader echoes the request ID when ID generation is enabled.
+func (r *Renderer) requestIDHeader() {
+	if r.generateID.Enabled() && r.id != Empty {
+		r.header.Set(HeaderRequestID, r.id)
+	}
+}
+
+// markRequestID adds meta.request_id when ID generation is enabled.
+func (r *Renderer) markRequestID(resp *Response) {
+	if !r.generateID.Enabled() || r.id == Empty {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaRequestID] = r.id
+}
@@ -0,0 +1,94 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type routerIDKey struct{}
+
+func TestRequestID(t *testing.T) {
+	push := func(t *testing.T, r *Renderer) (string, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := r.Push(w, Response{Message: "ok"}); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		var body struct {
+			Meta map[string]interface{} `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		id, _ := body.Meta[metaRequestID].(string)
+		if got := w.Header().Get(HeaderRequestID); got != id {
+			t.Errorf("Expected header %q to match meta %q", got, id)
+		}
+		return id, w.Header().Get(HeaderRequestID)
+	}
+	base := NewRenderer(settings).WithIDGeneration(Yes).WithIDGenerator(func() string { return "generated" })
+
+	t.Run("Header", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, "upstream-42")
+		req.Header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		if id, _ := push(t, base.WithRequest(req)); id != "upstream-42" {
+			t.Errorf("Expected the upstream ID, got %q", id)
+		}
+	})
+
+	t.Run("Traceparent", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderTraceparent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		if id, _ := push(t, base.WithRequest(req)); id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("Expected the trace ID, got %q", id)
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, "from-header")
+		req = req.WithContext(ContextWithRequestID(req.Context(), "from-context"))
+		if id, _ := push(t, base.WithRequest(req)); id != "from-context" {
+			t.Errorf("Expected the context ID, got %q", id)
+		}
+
+		ctx := context.WithValue(context.Background(), routerIDKey{}, "router-7")
+		if id, _ := push(t, base.WithIDContextKey(routerIDKey{}).WithContext(ctx)); id != "router-7" {
+			t.Errorf("Expected the custom key's ID, got %q", id)
+		}
+	})
+
+	t.Run("CustomHeaders", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, "ignored")
+		req.Header.Set("X-Correlation-ID", "corr-1")
+		if id, _ := push(t, base.WithIDHeaders("X-Correlation-ID").WithRequest(req)); id != "corr-1" {
+			t.Errorf("Expected the correlation ID, got %q", id)
+		}
+		if id, _ := push(t, base.WithIDHeaders().WithRequest(req)); id != "generated" {
+			t.Errorf("Expected a generated ID with header lookup disabled, got %q", id)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, strings.Repeat("x", maxRequestIDLen+1))
+		req.Header.Set(HeaderTraceparent, "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+		if id, _ := push(t, base.WithRequest(req)); id != "generated" {
+			t.Errorf("Expected a generated ID, got %q", id)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, "upstream-42")
+		if id, header := push(t, NewRenderer(settings).WithRequest(req)); id != Empty || header != Empty {
+			t.Errorf("Expected no echo without ID generation, got %q %q", id, header)
+		}
+	})
+}
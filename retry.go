@@ -0,0 +1,73 @@
+package beam
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy retries writes that fail with transient errors.
+// Only the bytes not yet accepted by the writer are re-sent, so a retried
+// body is never duplicated or reordered. Framed writers (Framed, TCPConn)
+// re-send whole frames, and a frame cut short is never retried.
+type RetryPolicy struct {
+	Attempts   int              // Total write attempts, including the first (values below 2 disable retries)
+	Backoff    time.Duration    // Delay before the first retry; doubled after each attempt
+	MaxBackoff time.Duration    // Upper bound for the delay; zero means unbounded
+	Retryable  func(error) bool // Classifies errors as transient; nil uses IsTransient
+}
+
+// IsTransient reports whether err is a known-transient write error.
+// Matches EAGAIN/EWOULDBLOCK, EINTR, ENOBUFS, and network timeouts.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EWOULDBLOCK) ||
+		errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.ENOBUFS) {
+		return true
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// WithWriteRetry retries writer errors that are known-transient instead of
+// failing the whole output call on the first EAGAIN.
+// Returns a new Renderer with the retry policy set.
+func (r *Renderer) WithWriteRetry(p RetryPolicy) *Renderer {
+	nr := r.clone()
+	nr.retry = p
+	return nr
+}
+
+// write writes b to w, retrying transient errors according to the retry policy.
+// Partial writes resume from the first unwritten byte; a write that still fails
+// is handed to the dead-letter sink.
+// Returns the total number of bytes written and the last error.
+func (r *Renderer) write(w Writer, b []byte) (int, error) {
+	retryable := r.retry.Retryable
+	if retryable == nil {
+		retryable = IsTransient
+	}
+	total, delay := 0, r.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		n, err := w.Write(b[total:])
+		total += n
+		r.written(n)
+		if err == nil {
+			return total, nil
+		}
+		if attempt >= r.retry.Attempts || !retryable(err) || errors.Is(err, errPartialFrame) || (r.ctx != nil && r.ctx.Err() != nil) {
+			r.captureDeadLetter(b, total, err)
+			return total, err
+		}
+		if delay > 0 {
+			time.Sleep(delay)
+			delay *= 2
+			if r.retry.MaxBackoff > 0 && delay > r.retry.MaxBackoff {
+				delay = r.retry.MaxBackoff
+			}
+		}
+	}
+}
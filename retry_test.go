@@ -0,0 +1,103 @@
+package beam
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+)
+
+// flakyWriter accepts a few bytes per call and fails with err until fails runs out.
+type flakyWriter struct {
+	TestWriter
+	fails int
+	err   error
+}
+
+func (f *flakyWriter) Write(p []byte) (int, error) {
+	if f.fails > 0 {
+		f.fails--
+		n := min(len(p), 5)
+		f.Buffer.Write(p[:n])
+		return n, f.err
+	}
+	return f.Buffer.Write(p)
+}
+
+// stallWriter fails without accepting anything until fails runs out.
+type stallWriter struct {
+	bytes.Buffer
+	fails int
+}
+
+func (s *stallWriter) Write(p []byte) (int, error) {
+	if s.fails > 0 {
+		s.fails--
+		return 0, syscall.EAGAIN
+	}
+	return s.Buffer.Write(p)
+}
+
+func TestWriteRetry(t *testing.T) {
+	newWriter := func(fails int, err error) *flakyWriter {
+		return &flakyWriter{TestWriter: TestWriter{Headers: make(http.Header)}, fails: fails, err: err}
+	}
+	base := NewRenderer(settings).WithProtocol(&TCPProtocol{})
+
+	t.Run("TransientRecovered", func(t *testing.T) {
+		w := newWriter(2, syscall.EAGAIN)
+		if err := base.WithWriteRetry(RetryPolicy{Attempts: 3}).Push(w, Response{Message: "hello"}); err != nil {
+			t.Fatalf("Expected retry to succeed, got %v", err)
+		}
+		if !bytes.Contains(w.Buffer.Bytes(), []byte(`"message":"hello"`)) || bytes.Count(w.Buffer.Bytes(), []byte(`"status"`)) != 1 {
+			t.Errorf("Expected body written exactly once, got %s", w.Buffer.String())
+		}
+	})
+
+	t.Run("AttemptsExhausted", func(t *testing.T) {
+		w := newWriter(5, syscall.EAGAIN)
+		err := base.WithWriteRetry(RetryPolicy{Attempts: 3}).Push(w, Response{Message: "hello"})
+		if !errors.Is(err, syscall.EAGAIN) {
+			t.Errorf("Expected EAGAIN after exhausting attempts, got %v", err)
+		}
+	})
+
+	t.Run("PermanentNotRetried", func(t *testing.T) {
+		w := newWriter(1, syscall.EPIPE)
+		err := base.WithWriteRetry(RetryPolicy{Attempts: 3}).Push(w, Response{Message: "hello"})
+		if !errors.Is(err, syscall.EPIPE) || w.fails != 0 {
+			t.Errorf("Expected EPIPE without retry, got %v", err)
+		}
+	})
+
+	t.Run("PartialFrameNotRetried", func(t *testing.T) {
+		w := newWriter(2, syscall.EAGAIN)
+		r := NewRenderer(settings).WithWriteRetry(RetryPolicy{Attempts: 3}).WithWriter(w).Framed(&FramedProtocol{})
+		if err := r.Push(nil, Response{Message: "hello"}); !errors.Is(err, errPartialFrame) || w.fails != 1 {
+			t.Errorf("Expected the cut frame to fail without retry, got %v after %d attempts", err, 2-w.fails)
+		}
+	})
+
+	t.Run("WholeFrameRetried", func(t *testing.T) {
+		w := &stallWriter{fails: 2}
+		p := &FramedProtocol{}
+		r := NewRenderer(settings).WithWriteRetry(RetryPolicy{Attempts: 3}).WithWriter(w).Framed(p)
+		if err := r.Push(nil, Response{Message: "hello"}); err != nil {
+			t.Fatalf("Expected retry to succeed, got %v", err)
+		}
+		if msg, err := p.ReadMessage(&w.Buffer); err != nil || !bytes.Contains(msg, []byte(`"message":"hello"`)) {
+			t.Errorf("Expected one intact frame, got %q (%v)", msg, err)
+		}
+		if w.Len() != 0 {
+			t.Errorf("Expected nothing after the frame, got %q", w.String())
+		}
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		w := newWriter(1, syscall.EAGAIN)
+		if err := base.Push(w, Response{Message: "hello"}); !errors.Is(err, syscall.EAGAIN) {
+			t.Errorf("Expected EAGAIN without a policy, got %v", err)
+		}
+	})
+}
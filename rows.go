@@ -0,0 +1,82 @@
+package beam
+
+import (
+	"errors"
+	"io"
+)
+
+// errRowsColumns reports that StreamRows needs a mapFn for rows without column names.
+var errRowsColumns = errors.New("rows do not report columns; a mapFn is required")
+
+// Rows is the database cursor StreamRows iterates; *sql.Rows implements it.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Close() error
+}
+
+// columner is implemented by cursors reporting their column names, such as *sql.Rows.
+type columner interface {
+	Columns() ([]string, error)
+}
+
+// StreamRows streams one record per database row without buffering the result set.
+// mapFn receives the row's Scan and returns the record to encode; a nil mapFn
+// emits each row as a map keyed by column name. Rows are streamed with Stream,
+// so NDJSON, SSE, and CSV renderers write and flush one row at a time.
+// Rows is closed when streaming ends.
+// Returns an error if iterating, mapping, encoding, or writing fails.
+func (r *Renderer) StreamRows(rows Rows, mapFn func(scan func(dest ...interface{}) error) (interface{}, error)) (err error) {
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if mapFn == nil {
+		if mapFn, err = columnMapper(rows); err != nil {
+			return err
+		}
+	}
+	return r.Stream(func(*Renderer) (interface{}, error) {
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return mapFn(rows.Scan)
+	})
+}
+
+// columnMapper returns a mapFn scanning each row into a map keyed by column.
+// Byte slices are converted to strings so text columns encode readably.
+func columnMapper(rows Rows) (func(scan func(dest ...interface{}) error) (interface{}, error), error) {
+	c, ok := rows.(columner)
+	if !ok {
+		return nil, errRowsColumns
+	}
+	cols, err := c.Columns()
+	if err != nil {
+		return nil, err
+	}
+	return func(scan func(dest ...interface{}) error) (interface{}, error) {
+		values := make([]interface{}, len(cols))
+		dest := make([]interface{}, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(cols))
+		for i, col := range cols {
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+				continue
+			}
+			row[col] = values[i]
+		}
+		return row, nil
+	}, nil
+}
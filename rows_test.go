@@ -0,0 +1,99 @@
+package beam
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeRows is an in-memory Rows with the *sql.Rows scanning contract.
+type fakeRows struct {
+	cols   []string
+	data   [][]interface{}
+	pos    int
+	err    error
+	closed bool
+}
+
+func (f *fakeRows) Columns() ([]string, error) { return f.cols, nil }
+func (f *fakeRows) Err() error                 { return f.err }
+func (f *fakeRows) Close() error               { f.closed = true; return nil }
+
+func (f *fakeRows) Next() bool {
+	if f.pos >= len(f.data) {
+		return false
+	}
+	f.pos++
+	return true
+}
+
+func (f *fakeRows) Scan(dest ...interface{}) error {
+	row := f.data[f.pos-1]
+	for i, d := range dest {
+		switch p := d.(type) {
+		case *interface{}:
+			*p = row[i]
+		case *string:
+			*p = row[i].(string)
+		case *int64:
+			*p = row[i].(int64)
+		}
+	}
+	return nil
+}
+
+func TestStreamRows(t *testing.T) {
+	newRows := func() *fakeRows {
+		return &fakeRows{
+			cols: []string{"id", "name"},
+			data: [][]interface{}{{int64(1), []byte("ada")}, {int64(2), []byte("bob")}},
+		}
+	}
+
+	t.Run("Columns", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		rows := newRows()
+		if err := NewRenderer(settings).WithContentType(ContentTypeNDJSON).WithWriter(w).StreamRows(rows, nil); err != nil {
+			t.Fatalf("StreamRows failed: %v", err)
+		}
+		want := "{\"id\":1,\"name\":\"ada\"}\n{\"id\":2,\"name\":\"bob\"}\n"
+		if got := w.Body.String(); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+		if !rows.closed {
+			t.Error("Expected rows to be closed")
+		}
+	})
+
+	t.Run("MapFn", func(t *testing.T) {
+		type user struct {
+			ID int64 `json:"id"`
+		}
+		w := httptest.NewRecorder()
+		err := NewRenderer(settings).WithContentType(ContentTypeNDJSON).WithWriter(w).StreamRows(newRows(),
+			func(scan func(dest ...interface{}) error) (interface{}, error) {
+				var u user
+				var name interface{}
+				if err := scan(&u.ID, &name); err != nil {
+					return nil, err
+				}
+				return u, nil
+			})
+		if err != nil {
+			t.Fatalf("StreamRows failed: %v", err)
+		}
+		if got, want := w.Body.String(), "{\"id\":1}\n{\"id\":2}\n"; got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("CursorError", func(t *testing.T) {
+		rows := newRows()
+		rows.err = errors.New("connection reset")
+		rows.data = nil
+		err := NewRenderer(settings).WithContentType(ContentTypeNDJSON).WithWriter(httptest.NewRecorder()).StreamRows(rows, nil)
+		if err == nil || !errors.Is(err, rows.err) {
+			t.Errorf("Expected cursor error, got %v", err)
+		}
+	})
+}
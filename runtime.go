@@ -0,0 +1,264 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// errRuntimeConfig reports an invalid runtime settings update.
+var errRuntimeConfig = errors.New("invalid runtime settings")
+
+// maxRuntimeBody caps the size of a RuntimeHandler update.
+const maxRuntimeBody = 64 << 10
+
+// RuntimeConfig holds the renderer settings operators may change while the
+// server runs, e.g. during an incident. Pointer fields override the value
+// configured on the Renderer when set and defer to it when nil.
+type RuntimeConfig struct {
+	LogSampling        float64        // Fraction (0..1) of non-fatal error logs kept; fatals are always logged
+	ShowError          *bool          // Overrides WithShowError
+	SlowThreshold      *time.Duration // Overrides WithSlowThreshold
+	Maintenance        bool           // Handler answers every request with a 503
+	MaintenanceMessage string         // Message of the maintenance response
+}
+
+// runtimeJSON is the wire form of RuntimeConfig used by RuntimeHandler.
+type runtimeJSON struct {
+	LogSampling        float64 `json:"log_sampling"`
+	ShowError          *bool   `json:"show_error"`
+	SlowThreshold      *string `json:"slow_threshold"`
+	Maintenance        bool    `json:"maintenance"`
+	MaintenanceMessage string  `json:"maintenance_message,omitempty"`
+}
+
+// MarshalJSON encodes the config with the slow threshold as a duration string.
+func (c RuntimeConfig) MarshalJSON() ([]byte, error) {
+	out := runtimeJSON{
+		LogSampling:        c.LogSampling,
+		ShowError:          c.ShowError,
+		Maintenance:        c.Maintenance,
+		MaintenanceMessage: c.MaintenanceMessage,
+	}
+	if c.SlowThreshold != nil {
+		s := c.SlowThreshold.String()
+		out.SlowThreshold = &s
+	}
+	return json.Marshal(out)
+}
+
+// Runtime is the shared, mutable home of RuntimeConfig.
+// Every Renderer derived from one given to WithRuntime consults it at output
+// time, so changes apply to in-flight servers immediately. Safe for concurrent use.
+type Runtime struct {
+	mu  sync.RWMutex
+	cfg RuntimeConfig
+}
+
+// NewRuntime creates a Runtime keeping every log and overriding nothing.
+func NewRuntime() *Runtime {
+	return &Runtime{cfg: RuntimeConfig{LogSampling: 1}}
+}
+
+// Config returns a snapshot of the current settings.
+func (rt *Runtime) Config() RuntimeConfig {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return rt.cfg
+}
+
+// Set replaces the current settings.
+// Returns an error if LogSampling is outside 0..1 or SlowThreshold is negative.
+func (rt *Runtime) Set(cfg RuntimeConfig) error {
+	if cfg.LogSampling < 0 || cfg.LogSampling > 1 {
+		return fmt.Errorf("%w: log_sampling %v outside 0..1", errRuntimeConfig, cfg.LogSampling)
+	}
+	if cfg.SlowThreshold != nil && *cfg.SlowThreshold < 0 {
+		return fmt.Errorf("%w: negative slow_threshold", errRuntimeConfig)
+	}
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.cfg = cfg
+	return nil
+}
+
+// update applies a JSON patch of runtime settings: keys present in the body
+// replace the current values, and null clears an override.
+func (rt *Runtime) update(body []byte) (RuntimeConfig, error) {
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return RuntimeConfig{}, errors.Join(errRuntimeConfig, err)
+	}
+	cfg := rt.Config()
+	for key, raw := range patch {
+		var err error
+		null := string(raw) == "null"
+		switch key {
+		case "log_sampling":
+			err = json.Unmarshal(raw, &cfg.LogSampling)
+		case "show_error":
+			cfg.ShowError = nil
+			if !null {
+				cfg.ShowError = new(bool)
+				err = json.Unmarshal(raw, cfg.ShowError)
+			}
+		case "slow_threshold":
+			cfg.SlowThreshold = nil
+			if !null {
+				var s string
+				if err = json.Unmarshal(raw, &s); err == nil {
+					var d time.Duration
+					d, err = time.ParseDuration(s)
+					cfg.SlowThreshold = &d
+				}
+			}
+		case "maintenance":
+			err = json.Unmarshal(raw, &cfg.Maintenance)
+		case "maintenance_message":
+			err = json.Unmarshal(raw, &cfg.MaintenanceMessage)
+		default:
+			err = fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return RuntimeConfig{}, fmt.Errorf("%w: %s: %v", errRuntimeConfig, key, err)
+		}
+	}
+	if err := rt.Set(cfg); err != nil {
+		return RuntimeConfig{}, err
+	}
+	return cfg, nil
+}
+
+// WithRuntime makes the Renderer honor the runtime settings in rt.
+// Returns a new Renderer consulting rt.
+func (r *Renderer) WithRuntime(rt *Runtime) *Renderer {
+	nr := r.clone()
+	nr.runtime = rt
+	nr.routeLogger()
+	return nr
+}
+
+// RuntimeHandler serves the Renderer's Runtime for operators: GET returns the
+// current settings and PATCH or PUT updates the keys in the JSON body.
+// auth decides who may use the endpoint; a nil auth rejects every request
+// so the endpoint is never exposed by accident. The endpoint stays reachable
+// in maintenance mode so operators can turn it off again.
+func (r *Renderer) RuntimeHandler(auth func(*http.Request) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		_ = r.WithWriter(w).WithRequest(req).serveRuntime(w, req, auth)
+	}
+}
+
+// serveRuntime answers one RuntimeHandler request.
+func (r *Renderer) serveRuntime(w http.ResponseWriter, req *http.Request, auth func(*http.Request) bool) error {
+	rt := r.runtime
+	if rt == nil {
+		return r.WithStatus(http.StatusNotFound).Msg("runtime settings are not enabled")
+	}
+	if auth == nil || !auth(req) {
+		return r.WithStatus(http.StatusForbidden).Push(w, Response{
+			Status:  StatusError,
+			Title:   http.StatusText(http.StatusForbidden),
+			Message: "not allowed to access runtime settings",
+		})
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return r.Info("runtime settings", rt.Config())
+	case http.MethodPatch, http.MethodPut:
+		body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRuntimeBody))
+		if err != nil {
+			return r.ErrorMsg("invalid runtime settings", err)
+		}
+		cfg, err := rt.update(body)
+		if err != nil {
+			return r.ErrorMsg("invalid runtime settings", err)
+		}
+		return r.Info("runtime settings updated", cfg)
+	}
+	return r.WithStatus(http.StatusMethodNotAllowed).
+		WithHeader("Allow", "GET, HEAD, PATCH, PUT").
+		Push(w, Response{
+			Status:  StatusError,
+			Title:   http.StatusText(http.StatusMethodNotAllowed),
+			Message: "use GET to read or PATCH to update runtime settings",
+		})
+}
+
+// inMaintenance reports whether the Runtime has maintenance mode on.
+func (r *Renderer) inMaintenance() bool {
+	return r.runtime != nil && r.runtime.Config().Maintenance
+}
+
+// maintenance writes the 503 response served while maintenance mode is on.
+func (r *Renderer) maintenance(w http.ResponseWriter, req *http.Request) {
+	msg := r.runtime.Config().MaintenanceMessage
+	if msg == Empty {
+		msg = "service is under maintenance, retry later"
+	}
+	nr := r.WithWriter(w).WithRequest(req).
+		WithStatus(http.StatusServiceUnavailable).
+		WithHeader("Retry-After", "60")
+	_ = nr.Push(w, Response{
+		Status:  StatusError,
+		Title:   http.StatusText(http.StatusServiceUnavailable),
+		Message: msg,
+	})
+}
+
+// showsErrors reports whether error details are included in responses,
+// honoring a Runtime override.
+func (r *Renderer) showsErrors() bool {
+	if r.runtime != nil {
+		if show := r.runtime.Config().ShowError; show != nil {
+			return *show
+		}
+	}
+	return r.showError.Enabled()
+}
+
+// slowLimit returns the slow response threshold, honoring a Runtime override.
+func (r *Renderer) slowLimit() time.Duration {
+	if r.runtime != nil {
+		if d := r.runtime.Config().SlowThreshold; d != nil {
+			return *d
+		}
+	}
+	return r.slowThreshold
+}
+
+// logger wraps l so non-fatal error logs are sampled by the Runtime.
+// Returns l unchanged when rt or l is nil.
+func (rt *Runtime) logger(l Logger) Logger {
+	if rt == nil || l == nil {
+		return l
+	}
+	return &sampledLogger{Logger: l, rt: rt}
+}
+
+// sampledLogger drops a share of Error logs according to the Runtime's LogSampling.
+// Fatal, Warn, and Info logs pass through unsampled.
+type sampledLogger struct {
+	Logger
+	rt *Runtime
+}
+
+func (l *sampledLogger) Error(err error, fields ...interface{}) {
+	if rate := l.rt.Config().LogSampling; rate < 1 && rand.Float64() >= rate {
+		return
+	}
+	l.Logger.Error(err, fields...)
+}
+
+func (l *sampledLogger) Warn(err error, fields ...interface{}) {
+	warnTo(l.Logger, err, fields...)
+}
+
+func (l *sampledLogger) Info(msg string, fields ...interface{}) {
+	infoTo(l.Logger, msg, fields...)
+}
@@ -0,0 +1,107 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuntime(t *testing.T) {
+	rt := NewRuntime()
+	logger := &TestLogger{}
+	r := NewRenderer(settings).WithLogger(logger).WithRuntime(rt)
+	admin := r.RuntimeHandler(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Bearer ops"
+	})
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/admin/runtime", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer ops")
+		w := httptest.NewRecorder()
+		admin.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Auth", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		admin.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/runtime", nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 without credentials, got %d", w.Code)
+		}
+		w = httptest.NewRecorder()
+		NewRenderer(settings).WithRuntime(rt).RuntimeHandler(nil).
+			ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/runtime", nil))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected nil auth to reject, got %d", w.Code)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		w := patch(`{"show_error": false, "slow_threshold": "250ms", "log_sampling": 0}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+		}
+		var body struct {
+			Info map[string]interface{} `json:"info"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Info["slow_threshold"] != "250ms" || body.Info["show_error"] != false {
+			t.Errorf("Unexpected settings %v", body.Info)
+		}
+		if got := r.slowLimit(); got != 250*time.Millisecond {
+			t.Errorf("Expected slow threshold override, got %s", got)
+		}
+
+		w = httptest.NewRecorder()
+		_ = r.WithWriter(w).Error(errors.New("secret detail"))
+		if strings.Contains(w.Body.String(), "secret detail") {
+			t.Errorf("Expected error details hidden, got %s", w.Body)
+		}
+		r.Log(errors.New("sampled away"))
+		if len(logger.Entries) != 0 {
+			t.Errorf("Expected error logs sampled away, got %+v", logger.Entries)
+		}
+
+		if w := patch(`{"show_error": null, "log_sampling": 1}`); w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d", w.Code)
+		}
+		if rt.Config().ShowError != nil {
+			t.Error("Expected null to clear the show_error override")
+		}
+		r.Log(errors.New("kept"))
+		if len(logger.Entries) != 1 {
+			t.Errorf("Expected error log kept, got %d entries", len(logger.Entries))
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, body := range []string{`{"log_sampling": 2}`, `{"colour": true}`, `{"slow_threshold": "soon"}`} {
+			if w := patch(body); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400 for %s, got %d", body, w.Code)
+			}
+		}
+	})
+
+	t.Run("Maintenance", func(t *testing.T) {
+		patch(`{"maintenance": true, "maintenance_message": "back soon"}`)
+		h := r.Handler(func(r *Renderer) error { return r.Msg("ok") })
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "back soon") {
+			t.Errorf("Expected maintenance 503, got %d: %s", w.Code, w.Body)
+		}
+		if w := patch(`{"maintenance": false}`); w.Code != http.StatusOK {
+			t.Fatalf("Expected admin endpoint reachable in maintenance, got %d", w.Code)
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected 200 after maintenance, got %d", w.Code)
+		}
+	})
+}
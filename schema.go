@@ -0,0 +1,489 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrSchemaViolation is returned when a response does not match its registered schema.
+// Only produced when schema validation is enabled via WithSchemas.
+var ErrSchemaViolation = errors.New("response schema violation")
+
+// errSchemaUnknown is returned when WithSchema names a schema that was never registered.
+var errSchemaUnknown = errors.New("unknown schema")
+
+// Schema validates a decoded JSON document.
+// Implement this to plug in a full JSON Schema engine; SchemaRegistry.Register
+// compiles a built-in validator covering the commonly used keywords.
+type Schema interface {
+	Validate(doc interface{}) error
+}
+
+// SchemaError describes a single schema violation.
+// Path is a JSON pointer to the offending value.
+type SchemaError struct {
+	Path    string
+	Message string
+}
+
+// Error returns the violation formatted as "path: message".
+func (e *SchemaError) Error() string {
+	path := e.Path
+	if path == Empty {
+		path = "/"
+	}
+	return path + ": " + e.Message
+}
+
+// SchemaRegistry maps names to response schemas.
+// Intended for development and staging: set it with WithSchemas and switch
+// checks on in non-production environments, with WithSchemaCheck or
+// SchemaCheckEnv, to catch contract drift before clients do.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]Schema
+}
+
+// NewSchemaRegistry creates an empty SchemaRegistry.
+// Returns a pointer ready for Register calls.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: make(map[string]Schema)}
+}
+
+// Register compiles a JSON Schema document and stores it under name.
+// Supports type, properties, required, additionalProperties, items, enum, const,
+// numeric and length bounds, pattern, allOf/anyOf/oneOf/not, and local $ref.
+// Returns an error if the schema is not valid JSON or uses an invalid pattern.
+func (sr *SchemaRegistry) Register(name string, schema []byte) error {
+	var root jsonSchema
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return fmt.Errorf("schema %q: %w", name, err)
+	}
+	if err := root.compile(&root); err != nil {
+		return fmt.Errorf("schema %q: %w", name, err)
+	}
+	sr.Use(name, &root)
+	return nil
+}
+
+// Use stores a custom Schema implementation under name.
+func (sr *SchemaRegistry) Use(name string, s Schema) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+	sr.schemas[name] = s
+}
+
+// Get returns the schema registered under name.
+func (sr *SchemaRegistry) Get(name string) (Schema, bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	s, ok := sr.schemas[name]
+	return s, ok
+}
+
+// Validate checks v against the schema registered under name.
+// The value is encoded to JSON first, so validation sees exactly what a JSON client would.
+// Returns an error wrapping ErrSchemaViolation on mismatch.
+func (sr *SchemaRegistry) Validate(name string, v interface{}) error {
+	s, ok := sr.Get(name)
+	if !ok {
+		return errors.Join(errSchemaUnknown, errors.New(name))
+	}
+	data, err := marshalJSON(applyTypeMarshalers(v), NumberFormat{}, Empty)
+	if err != nil {
+		return errors.Join(errEncodingFailed, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return errors.Join(errEncodingFailed, err)
+	}
+	if err := s.Validate(doc); err != nil {
+		return errors.Join(ErrSchemaViolation, err)
+	}
+	return nil
+}
+
+// SchemaCheckEnv is the environment variable switching schema validation on
+// when WithSchemaCheck is left unset, e.g. BEAM_SCHEMA_CHECK=1 in development
+// and CI. Any value strconv.ParseBool accepts as true enables it.
+const SchemaCheckEnv = "BEAM_SCHEMA_CHECK"
+
+// WithSchemas sets the registry responses are validated against.
+// Validation only runs where it is switched on, see WithSchemaCheck, since it
+// re-encodes every checked response.
+// Returns a new Renderer with the registry set.
+func (r *Renderer) WithSchemas(reg *SchemaRegistry) *Renderer {
+	nr := r.clone()
+	nr.schemas = reg
+	return nr
+}
+
+// WithSchema selects the registered schema that responses must satisfy.
+// Has no effect unless a registry is configured with WithSchemas.
+// Returns a new Renderer with the schema name set.
+func (r *Renderer) WithSchema(name string) *Renderer {
+	nr := r.clone()
+	nr.schema = name
+	return nr
+}
+
+// WithSchemaCheck switches schema validation on (Yes) or off (No) for this
+// Renderer, e.g. from the application's own environment setting. Unknown, the
+// default, enables it only when SchemaCheckEnv is set to true.
+// Returns a new Renderer with the switch set.
+func (r *Renderer) WithSchemaCheck(check State) *Renderer {
+	nr := r.clone()
+	nr.schemaCheck = check
+	return nr
+}
+
+// schemaChecked reports whether responses are validated against a schema.
+func (r *Renderer) schemaChecked() bool {
+	if r.schemas == nil || r.schema == Empty || r.schemaCheck.Disabled() {
+		return false
+	}
+	if r.schemaCheck.Enabled() {
+		return true
+	}
+	on, _ := strconv.ParseBool(os.Getenv(SchemaCheckEnv))
+	return on
+}
+
+// checkSchema validates v when schema validation is switched on.
+// On violation it logs the error and sends a 500 fatal response through Push,
+// in the negotiated content type, listing the violation only when errors are
+// shown. Returns the violation, or nil when validation is off or v conforms.
+func (r *Renderer) checkSchema(w Writer, v interface{}) error {
+	if !r.schemaChecked() {
+		return nil
+	}
+	err := r.schemas.Validate(r.schema, v)
+	if err == nil {
+		return nil
+	}
+	if r.logger != nil {
+		r.logger.Error(err, "schema", r.schema, "id", r.id)
+	}
+	resp := Response{Status: StatusFatal, Message: defaultFatalMessage}
+	if r.showsErrors() {
+		resp.Errors = ErrorList{err}
+	}
+	nr := r.clone()
+	nr.schema = Empty   // The error response is not validated again
+	nr.nonceStore = nil // Nor does it issue a second nonce
+	nr.code = http.StatusInternalServerError
+	if pushErr := nr.Push(w, resp); pushErr != nil {
+		return errors.Join(err, pushErr)
+	}
+	return err
+}
+
+// jsonSchema is the built-in validator for a subset of JSON Schema.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 json.RawMessage        `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	Const                *json.RawMessage       `json:"const"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Pattern              string                 `json:"pattern"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	Not                  *jsonSchema            `json:"not"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+
+	root       *jsonSchema
+	types      []string
+	pattern    *regexp.Regexp
+	additional *jsonSchema
+	noExtra    bool
+	constant   interface{}
+}
+
+// compile resolves derived fields recursively.
+func (s *jsonSchema) compile(root *jsonSchema) error {
+	if s == nil {
+		return nil
+	}
+	s.root = root
+	if len(s.Type) > 0 {
+		var one string
+		if err := json.Unmarshal(s.Type, &one); err == nil {
+			s.types = []string{one}
+		} else if err := json.Unmarshal(s.Type, &s.types); err != nil {
+			return fmt.Errorf("invalid type: %w", err)
+		}
+	}
+	if s.Pattern != Empty {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	if len(s.AdditionalProperties) > 0 {
+		var allowed bool
+		if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+			s.noExtra = !allowed
+		} else {
+			s.additional = &jsonSchema{}
+			if err := json.Unmarshal(s.AdditionalProperties, s.additional); err != nil {
+				return fmt.Errorf("invalid additionalProperties: %w", err)
+			}
+		}
+	}
+	if s.Const != nil {
+		if err := json.Unmarshal(*s.Const, &s.constant); err != nil {
+			return err
+		}
+	}
+	children := []*jsonSchema{s.Items, s.additional, s.Not}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, m := range []map[string]*jsonSchema{s.Properties, s.Defs, s.Definitions} {
+		for _, c := range m {
+			children = append(children, c)
+		}
+	}
+	for _, c := range children {
+		if err := c.compile(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks doc against the schema.
+func (s *jsonSchema) Validate(doc interface{}) error {
+	return s.validate(doc, Empty)
+}
+
+// resolve follows a local $ref ("#/$defs/x" or "#/definitions/x").
+func (s *jsonSchema) resolve(path string) (*jsonSchema, error) {
+	ref := s.Ref
+	if ref == "#" {
+		return s.root, nil
+	}
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if name, ok := strings.CutPrefix(ref, prefix); ok {
+			defs := s.root.Defs
+			if prefix == "#/definitions/" {
+				defs = s.root.Definitions
+			}
+			if target, ok := defs[name]; ok {
+				return target, nil
+			}
+		}
+	}
+	return nil, &SchemaError{Path: path, Message: "unresolvable $ref " + ref}
+}
+
+// validate checks doc at the given JSON pointer path.
+func (s *jsonSchema) validate(doc interface{}, path string) error {
+	if s.Ref != Empty {
+		target, err := s.resolve(path)
+		if err != nil {
+			return err
+		}
+		return target.validate(doc, path)
+	}
+	if len(s.types) > 0 && !matchesAnyType(doc, s.types) {
+		return &SchemaError{Path: path, Message: fmt.Sprintf("expected type %s, got %s", strings.Join(s.types, " or "), jsonTypeOf(doc))}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if jsonEqual(doc, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &SchemaError{Path: path, Message: "value not in enum"}
+		}
+	}
+	if s.Const != nil && !jsonEqual(doc, s.constant) {
+		return &SchemaError{Path: path, Message: "value does not match const"}
+	}
+
+	switch v := doc.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("%v is less than minimum %v", v, *s.Minimum)}
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("%v is greater than maximum %v", v, *s.Maximum)}
+		}
+	case string:
+		n := len([]rune(v))
+		if s.MinLength != nil && n < *s.MinLength {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("length %d is less than minLength %d", n, *s.MinLength)}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("length %d is greater than maxLength %d", n, *s.MaxLength)}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return &SchemaError{Path: path, Message: "does not match pattern " + s.Pattern}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("%d items is less than minItems %d", len(v), *s.MinItems)}
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("%d items is greater than maxItems %d", len(v), *s.MaxItems)}
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(item, path+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return &SchemaError{Path: path, Message: "missing required property " + strconv.Quote(name)}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "/" + escapePointer(k)
+			if prop, ok := s.Properties[k]; ok {
+				if err := prop.validate(v[k], child); err != nil {
+					return err
+				}
+				continue
+			}
+			if s.noExtra {
+				return &SchemaError{Path: child, Message: "additional property not allowed"}
+			}
+			if s.additional != nil {
+				if err := s.additional.validate(v[k], child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		if err := sub.validate(doc, path); err != nil {
+			return err
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		ok := false
+		for _, sub := range s.AnyOf {
+			if sub.validate(doc, path) == nil {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return &SchemaError{Path: path, Message: "does not match any schema in anyOf"}
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, sub := range s.OneOf {
+			if sub.validate(doc, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return &SchemaError{Path: path, Message: fmt.Sprintf("matches %d schemas in oneOf, expected exactly 1", matches)}
+		}
+	}
+	if s.Not != nil && s.Not.validate(doc, path) == nil {
+		return &SchemaError{Path: path, Message: "matches schema in not"}
+	}
+	return nil
+}
+
+// jsonTypeOf returns the JSON Schema type name of a decoded value.
+func jsonTypeOf(v interface{}) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := n.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// matchesAnyType reports whether v has one of the given JSON Schema types.
+func matchesAnyType(v interface{}, types []string) bool {
+	actual := jsonTypeOf(v)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares two decoded JSON values, treating numbers numerically.
+func jsonEqual(a, b interface{}) bool {
+	an, aok := toFloat(a)
+	bn, bok := toFloat(b)
+	if aok || bok {
+		return aok && bok && an == bn
+	}
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return bytes.Equal(aj, bj)
+}
+
+// toFloat converts JSON numeric representations to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// escapePointer escapes a key for use in a JSON pointer.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
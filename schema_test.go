@@ -0,0 +1,144 @@
+package beam
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testUserSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 1},
+		"tags": {"type": "array", "items": {"$ref": "#/$defs/tag"}}
+	},
+	"$defs": {"tag": {"type": "string", "enum": ["admin", "user"]}}
+}`
+
+// schemaUser is a user missing its required id, encodable as JSON and XML.
+type schemaUser struct {
+	XMLName xml.Name `json:"-" xml:"user"`
+	Name    string   `json:"name" xml:"name"`
+}
+
+func TestSchemaValidation(t *testing.T) {
+	reg := NewSchemaRegistry()
+	if err := reg.Register("user", []byte(testUserSchema)); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	t.Run("Conforming", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithSchemas(reg).WithSchema("user").WithSchemaCheck(Yes).WithWriter(tw)
+		err := r.Raw(map[string]interface{}{"id": 1, "name": "ada", "tags": []string{"admin"}})
+		if err != nil {
+			t.Fatalf("Expected conforming response, got %v", err)
+		}
+		if tw.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", tw.StatusCode)
+		}
+	})
+
+	t.Run("Violation", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		logger := &TestLogger{}
+		r := NewRenderer(settings).WithSchemas(reg).WithSchema("user").WithSchemaCheck(Yes).WithLogger(logger).WithWriter(tw)
+		err := r.Raw(map[string]interface{}{"id": 1, "name": "ada", "tags": []string{"root"}})
+		if !errors.Is(err, ErrSchemaViolation) {
+			t.Fatalf("Expected ErrSchemaViolation, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "/tags/0") {
+			t.Errorf("Expected violation path in error, got %v", err)
+		}
+		if tw.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", tw.StatusCode)
+		}
+		if logger.LastEntry() == nil {
+			t.Error("Expected violation to be logged")
+		}
+		if strings.Contains(tw.Buffer.String(), "root") {
+			t.Errorf("Expected original payload to be withheld, got %s", tw.Buffer.String())
+		}
+	})
+
+	t.Run("Keywords", func(t *testing.T) {
+		cases := []struct {
+			doc     interface{}
+			message string
+		}{
+			{map[string]interface{}{"name": "ada"}, `missing required property "id"`},
+			{map[string]interface{}{"id": 0, "name": "ada"}, "less than minimum"},
+			{map[string]interface{}{"id": 1, "name": "ada", "extra": true}, "additional property"},
+			{map[string]interface{}{"id": "1", "name": "ada"}, "expected type integer"},
+		}
+		for _, c := range cases {
+			err := reg.Validate("user", c.doc)
+			if err == nil || !strings.Contains(err.Error(), c.message) {
+				t.Errorf("Expected %q, got %v", c.message, err)
+			}
+		}
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		// Push validates the envelope it encodes, such as the compact form.
+		compact := NewSchemaRegistry()
+		if err := compact.Register("compact", []byte(`{"type": "object", "required": ["s", "d"]}`)); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithSchemas(compact).WithSchema("compact").WithSchemaCheck(Yes).WithCompact(Yes).WithWriter(tw)
+		if err := r.Push(tw, Response{Data: map[string]int{"id": 1}}); err != nil {
+			t.Errorf("Expected the compact envelope to conform, got %v", err)
+		}
+	})
+
+	t.Run("ContentTypeAndHiddenErrors", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithSchemas(reg).WithSchema("user").WithSchemaCheck(Yes).
+			WithContentType(ContentTypeXML).WithWriter(tw)
+		_ = r.WithShowError(No)
+		err := r.Raw(schemaUser{Name: "ada"})
+		if !errors.Is(err, ErrSchemaViolation) {
+			t.Fatalf("Expected ErrSchemaViolation, got %v", err)
+		}
+		if tw.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Expected status 500, got %d", tw.StatusCode)
+		}
+		if ct := tw.Headers.Get(HeaderContentType); !strings.HasPrefix(ct, ContentTypeXML) {
+			t.Errorf("Expected the negotiated XML content type, got %q", ct)
+		}
+		if strings.Contains(tw.Buffer.String(), "required") || strings.Contains(tw.Buffer.String(), "schema") {
+			t.Errorf("Expected the violation hidden from clients, got %s", tw.Buffer.String())
+		}
+	})
+
+	t.Run("Switch", func(t *testing.T) {
+		doc := map[string]string{"unexpected": "shape"}
+		base := NewRenderer(settings).WithSchemas(reg).WithSchema("user")
+
+		t.Setenv(SchemaCheckEnv, "")
+		if err := base.WithWriter(&TestWriter{Headers: make(http.Header)}).Raw(doc); err != nil {
+			t.Errorf("Expected no validation by default, got %v", err)
+		}
+		t.Setenv(SchemaCheckEnv, "true")
+		if err := base.WithWriter(&TestWriter{Headers: make(http.Header)}).Raw(doc); !errors.Is(err, ErrSchemaViolation) {
+			t.Errorf("Expected %s to enable validation, got %v", SchemaCheckEnv, err)
+		}
+		if err := base.WithSchemaCheck(No).WithWriter(&TestWriter{Headers: make(http.Header)}).Raw(doc); err != nil {
+			t.Errorf("Expected WithSchemaCheck(No) to override the environment, got %v", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		tw := &TestWriter{Headers: make(http.Header)}
+		r := NewRenderer(settings).WithSchema("user").WithWriter(tw)
+		if err := r.Raw(map[string]string{"unexpected": "shape"}); err != nil {
+			t.Errorf("Expected no validation without a registry, got %v", err)
+		}
+	})
+}
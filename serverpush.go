@@ -0,0 +1,82 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// PushResource is an asset sent with HTTP/2 server push alongside a response.
+type PushResource struct {
+	Target string      // Absolute path or same-origin URL of the asset
+	Header http.Header // Request headers for the pushed request; nil uses the defaults
+}
+
+// ActionResources returns push resources for the linked actions that can be
+// fetched directly: GET actions whose href is an absolute path without URI
+// template variables.
+func ActionResources(actions ...Action) []PushResource {
+	var resources []PushResource
+	for _, a := range actions {
+		if a.Method != Empty && !strings.EqualFold(a.Method, http.MethodGet) {
+			continue
+		}
+		if !strings.HasPrefix(a.Href, "/") || strings.HasPrefix(a.Href, "//") || strings.ContainsAny(a.Href, "{}") {
+			continue
+		}
+		resources = append(resources, PushResource{Target: a.Href})
+	}
+	return resources
+}
+
+// WithPush adds resources that Push and Raw send with HTTP/2 server push
+// before writing the response. Writers that do not support http.Pusher,
+// such as HTTP/1.x connections, are skipped silently.
+// Returns a new Renderer with the resources added.
+func (r *Renderer) WithPush(resources ...PushResource) *Renderer {
+	nr := r.clone()
+	nr.pushes = append(slices.Clone(r.pushes), resources...)
+	return nr
+}
+
+// serverPush initiates the configured pushes when the writer supports them.
+// Clients that disabled push are skipped; other failures are logged as warnings.
+func (r *Renderer) serverPush(w Writer) {
+	if len(r.pushes) == 0 {
+		return
+	}
+	hw := r.httpWriter
+	if rw, ok := w.(http.ResponseWriter); ok {
+		hw = rw
+	}
+	pusher := findPusher(hw)
+	if pusher == nil {
+		return
+	}
+	for _, res := range r.pushes {
+		err := pusher.Push(res.Target, &http.PushOptions{Header: res.Header})
+		if errors.Is(err, http.ErrNotSupported) {
+			return
+		}
+		if err != nil && r.logger != nil {
+			warnTo(r.logger, err, "id", r.id, "target", res.Target)
+		}
+	}
+}
+
+// findPusher returns the http.Pusher behind w, unwrapping middleware writers.
+// Returns nil if none supports server push.
+func findPusher(w http.ResponseWriter) http.Pusher {
+	for w != nil {
+		if p, ok := w.(http.Pusher); ok {
+			return p
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = u.Unwrap()
+	}
+	return nil
+}
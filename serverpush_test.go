@@ -0,0 +1,87 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pushRecorder is a ResponseRecorder supporting http.Pusher.
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+	err    error
+}
+
+func (p *pushRecorder) Push(target string, _ *http.PushOptions) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.pushed = append(p.pushed, target)
+	return nil
+}
+
+// wrappedWriter hides the Pusher behind a middleware-style wrapper.
+type wrappedWriter struct{ http.ResponseWriter }
+
+func (w wrappedWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func TestServerPush(t *testing.T) {
+	resources := ActionResources(
+		Action{Name: "self", Href: "/orders/1"},
+		Action{Name: "style", Method: http.MethodGet, Href: "/static/app.css"},
+		Action{Name: "cancel", Method: http.MethodPost, Href: "/orders/1/cancel"},
+		Action{Name: "item", Href: "/items/{id}"},
+		Action{Name: "docs", Href: "https://example.com/docs"},
+	)
+	if len(resources) != 2 || resources[0].Target != "/orders/1" || resources[1].Target != "/static/app.css" {
+		t.Fatalf("Expected only the fetchable GET actions, got %+v", resources)
+	}
+	r := NewRenderer(settings).WithPush(resources...)
+
+	t.Run("Push", func(t *testing.T) {
+		w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		if err := r.WithWriter(wrappedWriter{w}).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if len(w.pushed) != 2 {
+			t.Errorf("Expected both resources pushed, got %v", w.pushed)
+		}
+	})
+
+	t.Run("Raw", func(t *testing.T) {
+		w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		if err := r.WithWriter(w).Raw(map[string]string{"a": "b"}); err != nil {
+			t.Fatalf("Raw failed: %v", err)
+		}
+		if len(w.pushed) != 2 {
+			t.Errorf("Expected both resources pushed, got %v", w.pushed)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		log := &TestLogger{}
+		w := &pushRecorder{ResponseRecorder: httptest.NewRecorder(), err: http.ErrNotSupported}
+		if err := r.WithLogger(log).WithWriter(w).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if err := r.WithWriter(httptest.NewRecorder()).Data("ok", nil); err != nil {
+			t.Fatalf("Data without Pusher failed: %v", err)
+		}
+		if len(log.Entries) != 0 {
+			t.Errorf("Expected unsupported push skipped silently, got %+v", log.Entries)
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		log := &TestLogger{}
+		w := &pushRecorder{ResponseRecorder: httptest.NewRecorder(), err: errors.New("stream refused")}
+		if err := r.WithLogger(log).WithWriter(w).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if len(log.Entries) != 2 || w.Code != http.StatusOK {
+			t.Errorf("Expected failures logged and the response sent, got %+v", log.Entries)
+		}
+	})
+}
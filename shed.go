@@ -0,0 +1,70 @@
+package beam
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// limiter is a counting semaphore shared by renderers derived from the same
+// WithLoadShedding call.
+type limiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+// acquire reserves a slot, waiting at most l.wait.
+// Returns false if no slot became available in time.
+func (l *limiter) acquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if l.wait <= 0 {
+		return false
+	}
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// release frees a slot reserved by acquire.
+func (l *limiter) release() {
+	<-l.slots
+}
+
+// WithLoadShedding limits how many Handler invocations run concurrently.
+// Requests beyond max wait up to wait for a slot, then are shed with a
+// 503 envelope and Retry-After header, and counted as MetricRequestsShed.
+// Returns a new Renderer with the limiter set; max <= 0 disables shedding.
+func (r *Renderer) WithLoadShedding(max int, wait time.Duration) *Renderer {
+	nr := r.clone()
+	nr.limiter = nil
+	if max > 0 {
+		nr.limiter = &limiter{slots: make(chan struct{}, max), wait: wait}
+	}
+	return nr
+}
+
+// shed writes the overload response for a rejected request.
+func (r *Renderer) shed(w http.ResponseWriter, req *http.Request) {
+	r.count(MetricRequestsShed, 1, "path", req.URL.Path)
+	retry := 1
+	if r.limiter.wait > time.Second {
+		retry = int(r.limiter.wait / time.Second)
+	}
+	nr := r.WithWriter(w).WithRequest(req).
+		WithStatus(http.StatusServiceUnavailable).
+		WithHeader("Retry-After", strconv.Itoa(retry))
+	_ = nr.Push(w, Response{
+		Status:  StatusError,
+		Title:   http.StatusText(http.StatusServiceUnavailable),
+		Message: "server is overloaded, retry later",
+	})
+}
@@ -0,0 +1,62 @@
+package beam
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testCollector records counter totals by metric name.
+type testCollector struct {
+	mu     sync.Mutex
+	counts map[string]float64
+}
+
+func (c *testCollector) Count(name string, delta float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]float64)
+	}
+	c.counts[name] += delta
+}
+
+func (c *testCollector) Observe(name string, value float64, labels ...string) {}
+
+func TestLoadShedding(t *testing.T) {
+	collector := &testCollector{}
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	h := NewRenderer(settings).WithMetrics(collector).WithLoadShedding(1, 0).Handler(func(r *Renderer) error {
+		close(entered)
+		<-unblock
+		return r.Msg("done")
+	})
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/", nil))
+		close(done)
+	}()
+	<-entered
+
+	second := httptest.NewRecorder()
+	h.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/", nil))
+	if second.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", second.Code)
+	}
+	if second.Header().Get("Retry-After") == Empty {
+		t.Error("Expected Retry-After header")
+	}
+	if collector.counts[MetricRequestsShed] != 1 {
+		t.Errorf("Expected 1 shed request, got %v", collector.counts[MetricRequestsShed])
+	}
+
+	close(unblock)
+	<-done
+	if first.Code != http.StatusOK {
+		t.Errorf("Expected first request to succeed, got %d", first.Code)
+	}
+}
@@ -0,0 +1,200 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrShuttingDown is the context cause for streams ended by a graceful shutdown.
+var ErrShuttingDown = errors.New("shutting down")
+
+// Lifecycle coordinates graceful shutdown of renderer-owned work.
+// It tracks active streams and async callbacks and runs registered hooks,
+// so shutdown drains audit and delivery queues instead of dropping them.
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []*shutdownHook
+	closing chan struct{}
+	once    sync.Once
+
+	streams sync.WaitGroup
+	pending sync.WaitGroup
+}
+
+// shutdownHook is a registered hook; pointer identity allows removal.
+type shutdownHook struct {
+	fn func(ctx context.Context)
+}
+
+// NewLifecycle creates an independent Lifecycle.
+// Most programs use the package-level OnShutdown and Shutdown instead.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{closing: make(chan struct{})}
+}
+
+// defaultLifecycle backs OnShutdown, Shutdown, and renderers without WithLifecycle.
+var defaultLifecycle = NewLifecycle()
+
+// OnShutdown registers fn to run when the default Lifecycle shuts down.
+// Hooks run in reverse registration order and receive the shutdown deadline.
+// Returns a function that unregisters the hook.
+func OnShutdown(fn func(ctx context.Context)) func() {
+	return defaultLifecycle.OnShutdown(fn)
+}
+
+// Shutdown gracefully shuts down the default Lifecycle.
+// Returns ctx.Err() if the deadline passes before everything drains.
+func Shutdown(ctx context.Context) error {
+	return defaultLifecycle.Shutdown(ctx)
+}
+
+// OnShutdown registers fn to run when l shuts down.
+// Returns a function that unregisters the hook.
+func (l *Lifecycle) OnShutdown(fn func(ctx context.Context)) func() {
+	h := &shutdownHook{fn: fn}
+	l.mu.Lock()
+	l.hooks = append(l.hooks, h)
+	l.mu.Unlock()
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for i, existing := range l.hooks {
+			if existing == h {
+				l.hooks = append(l.hooks[:i], l.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Done returns a channel closed when shutdown begins.
+func (l *Lifecycle) Done() <-chan struct{} {
+	return l.closing
+}
+
+// Shutdown ends active streams, runs hooks in reverse order, and waits for
+// streams and async callbacks to finish, all within ctx's deadline.
+// Calling it more than once only waits again.
+// Returns ctx.Err() if the deadline passes before everything drains.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	l.once.Do(func() { close(l.closing) })
+	hooks := append([]*shutdownHook(nil), l.hooks...)
+	l.hooks = nil
+	l.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if ctx.Err() != nil {
+				return
+			}
+			hooks[i].fn(ctx)
+		}
+		l.streams.Wait()
+		l.pending.Wait()
+	}()
+
+	select {
+	case <-drained:
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// track registers an active stream and binds a context canceled at shutdown.
+// Returns a function to call when the stream ends.
+func (l *Lifecycle) track(r *Renderer) func() {
+	ctx, cancel := context.WithCancelCause(r.Context())
+	r.ctx = ctx
+	if !l.add(&l.streams) {
+		cancel(ErrShuttingDown)
+		return func() {}
+	}
+	go func() {
+		select {
+		case <-l.closing:
+			cancel(ErrShuttingDown)
+		case <-ctx.Done():
+		}
+	}()
+	return func() {
+		cancel(context.Canceled)
+		l.streams.Done()
+	}
+}
+
+// async wraps cb to run in the background while being tracked for shutdown.
+// Once shutdown has begun, callbacks run synchronously so none are lost.
+func (l *Lifecycle) async(cb func(data CallbackData)) func(data CallbackData) {
+	return func(data CallbackData) {
+		if !l.add(&l.pending) {
+			cb(data)
+			return
+		}
+		go func() {
+			defer l.pending.Done()
+			cb(data)
+		}()
+	}
+}
+
+// add increments wg unless shutdown has begun.
+// Holding mu keeps every Add ordered before Shutdown starts waiting.
+func (l *Lifecycle) add(wg *sync.WaitGroup) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-l.closing:
+		return false
+	default:
+		wg.Add(1)
+		return true
+	}
+}
+
+// WithLifecycle binds the Renderer to l instead of the default Lifecycle.
+// Returns a new Renderer using l for streams and async callbacks.
+func (r *Renderer) WithLifecycle(l *Lifecycle) *Renderer {
+	nr := r.clone()
+	nr.lifecycle = l
+	return nr
+}
+
+// WithAsyncCallback registers callbacks that run in the background.
+// Pending callbacks are flushed by Shutdown, so audit events are not lost.
+// Returns a new Renderer with the callbacks added.
+func (r *Renderer) WithAsyncCallback(cb ...func(data CallbackData)) *Renderer {
+	l := r.life()
+	wrapped := make([]func(data CallbackData), len(cb))
+	for i, fn := range cb {
+		wrapped[i] = l.async(fn)
+	}
+	return r.WithCallback(wrapped...)
+}
+
+// life returns the Renderer's Lifecycle.
+func (r *Renderer) life() *Lifecycle {
+	if r.lifecycle != nil {
+		return r.lifecycle
+	}
+	return defaultLifecycle
+}
+
+// watchShutdown wraps a Stream callback so the stream completes cleanly once shutdown begins.
+func (r *Renderer) watchShutdown(callback func(*Renderer) (interface{}, error)) func(*Renderer) (interface{}, error) {
+	return func(nr *Renderer) (interface{}, error) {
+		if errors.Is(context.Cause(r.ctx), ErrShuttingDown) {
+			return nil, io.EOF
+		}
+		data, err := callback(nr)
+		if err != nil && errors.Is(context.Cause(r.ctx), ErrShuttingDown) {
+			return nil, io.EOF
+		}
+		return data, err
+	}
+}
@@ -0,0 +1,84 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLifecycleShutdown(t *testing.T) {
+	t.Run("HooksReverseOrder", func(t *testing.T) {
+		l := NewLifecycle()
+		var order []int
+		l.OnShutdown(func(context.Context) { order = append(order, 1) })
+		remove := l.OnShutdown(func(context.Context) { order = append(order, 2) })
+		l.OnShutdown(func(context.Context) { order = append(order, 3) })
+		remove()
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+		if len(order) != 2 || order[0] != 3 || order[1] != 1 {
+			t.Errorf("Expected hooks [3 1], got %v", order)
+		}
+	})
+
+	t.Run("Deadline", func(t *testing.T) {
+		l := NewLifecycle()
+		l.OnShutdown(func(context.Context) { time.Sleep(200 * time.Millisecond) })
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := l.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline exceeded, got %v", err)
+		}
+	})
+
+	t.Run("AsyncCallbacksFlushed", func(t *testing.T) {
+		l := NewLifecycle()
+		var delivered atomic.Int32
+		r := NewRenderer(settings).WithLifecycle(l).WithAsyncCallback(func(CallbackData) {
+			time.Sleep(20 * time.Millisecond)
+			delivered.Add(1)
+		})
+		for i := 0; i < 3; i++ {
+			if err := r.Push(httptest.NewRecorder(), Response{Message: "audit"}); err != nil {
+				t.Fatalf("Push failed: %v", err)
+			}
+		}
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+		if delivered.Load() != 3 {
+			t.Errorf("Expected 3 delivered callbacks, got %d", delivered.Load())
+		}
+	})
+
+	t.Run("StreamsEndCleanly", func(t *testing.T) {
+		l := NewLifecycle()
+		w := httptest.NewRecorder()
+		r := NewRenderer(settings).WithLifecycle(l).WithWriter(w).WithContentType(ContentTypeNDJSON)
+		started := make(chan struct{})
+		result := make(chan error, 1)
+		go func() {
+			first := true
+			result <- r.Stream(func(r *Renderer) (interface{}, error) {
+				if first {
+					first = false
+					close(started)
+					return 1, nil
+				}
+				<-r.Context().Done()
+				return nil, r.Context().Err()
+			})
+		}()
+		<-started
+		if err := l.Shutdown(context.Background()); err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+		if err := <-result; err != nil {
+			t.Errorf("Expected clean stream completion, got %v", err)
+		}
+	})
+}
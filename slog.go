@@ -0,0 +1,109 @@
+package beam
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// LevelFatal is the slog level of entries logged through Logger.Fatal.
+const LevelFatal = slog.LevelError + 4
+
+// slogBadKey is the key slog gives a value without a string key.
+const slogBadKey = "!BADKEY"
+
+// SlogLogger adapts l to the Logger interface, so a *slog.Logger can be
+// passed to WithLogger directly. Entries are logged with the error as the
+// message at LevelError, LevelFatal, slog.LevelWarn, or slog.LevelInfo, and
+// the caller's file, line, and function as a slog.SourceKey attribute.
+// A nil l uses slog.Default.
+func SlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+// slogLogger implements Logger, WarnLogger, and InfoLogger on a *slog.Logger.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s *slogLogger) Error(err error, fields ...interface{}) {
+	s.log(slog.LevelError, errMessage(err), fields)
+}
+
+func (s *slogLogger) Fatal(err error, fields ...interface{}) {
+	s.log(LevelFatal, errMessage(err), fields)
+}
+
+func (s *slogLogger) Warn(err error, fields ...interface{}) {
+	s.log(slog.LevelWarn, errMessage(err), fields)
+}
+
+func (s *slogLogger) Info(msg string, fields ...interface{}) {
+	s.log(slog.LevelInfo, msg, fields)
+}
+
+// log emits one entry at level, skipping the conversion when it is disabled.
+func (s *slogLogger) log(level slog.Level, msg string, fields []interface{}) {
+	l := s.l
+	if l == nil {
+		l = slog.Default()
+	}
+	ctx := context.Background()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	l.LogAttrs(ctx, level, msg, slogAttrs(fields)...)
+}
+
+// slogAttrs converts alternating key/value fields to slog attributes. The
+// caller-info fields become a single *slog.Source, and a value without a
+// string key is kept under "!BADKEY", as slog does.
+func slogAttrs(fields []interface{}) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields)/2+1)
+	var src *slog.Source
+	source := func() *slog.Source {
+		if src == nil {
+			src = &slog.Source{}
+		}
+		return src
+	}
+	for i := 0; i < len(fields); i++ {
+		key, ok := fields[i].(string)
+		if !ok || i+1 == len(fields) {
+			if a, isAttr := fields[i].(slog.Attr); isAttr {
+				attrs = append(attrs, a)
+			} else {
+				attrs = append(attrs, slog.Any(slogBadKey, fields[i]))
+			}
+			continue
+		}
+		i++
+		value := fields[i]
+		switch key {
+		case fieldFile:
+			source().File = fmt.Sprint(value)
+		case fieldLine:
+			if line, isInt := value.(int); isInt {
+				source().Line = line
+				continue
+			}
+			attrs = append(attrs, slog.Any(key, value))
+		case fieldFunc:
+			source().Function = fmt.Sprint(value)
+		default:
+			attrs = append(attrs, slog.Any(key, value))
+		}
+	}
+	if src != nil {
+		attrs = append(attrs, slog.Any(slog.SourceKey, src))
+	}
+	return attrs
+}
+
+// errMessage returns err's message, or an empty string for a nil error.
+func errMessage(err error) string {
+	if err == nil {
+		return Empty
+	}
+	return err.Error()
+}
@@ -0,0 +1,81 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	newLogger := func(buf *bytes.Buffer, level slog.Level) Logger {
+		return SlogLogger(slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level})))
+	}
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+		t.Helper()
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Invalid log entry %q: %v", buf.String(), err)
+		}
+		return entry
+	}
+
+	t.Run("Levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := newLogger(&buf, slog.LevelDebug)
+		cases := []struct {
+			log  func()
+			want string
+		}{
+			{func() { l.Error(errors.New("boom")) }, "ERROR"},
+			{func() { l.Fatal(errors.New("boom")) }, "ERROR+4"},
+			{func() { l.(WarnLogger).Warn(errors.New("boom")) }, "WARN"},
+			{func() { l.(InfoLogger).Info("boom") }, "INFO"},
+		}
+		for _, c := range cases {
+			buf.Reset()
+			c.log()
+			entry := decode(t, &buf)
+			if entry["level"] != c.want || entry["msg"] != "boom" {
+				t.Errorf("Expected %s boom, got %v", c.want, entry)
+			}
+		}
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		newLogger(&buf, slog.LevelInfo).Error(errors.New("failed"),
+			fieldFile, "main.go", fieldLine, 42, fieldFunc, "handle", fieldID, "abc", 7)
+		entry := decode(t, &buf)
+		src, _ := entry[slog.SourceKey].(map[string]interface{})
+		if src["file"] != "main.go" || src["line"] != float64(42) || src["function"] != "handle" {
+			t.Errorf("Expected caller info as source, got %v", entry[slog.SourceKey])
+		}
+		if entry[fieldID] != "abc" || entry[slogBadKey] != float64(7) {
+			t.Errorf("Unexpected attributes %v", entry)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		newLogger(&buf, slog.LevelError).(InfoLogger).Info("quiet")
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing logged, got %q", buf.String())
+		}
+	})
+
+	t.Run("Renderer", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := httptest.NewRecorder()
+		_ = NewRenderer(settings).WithLogger(newLogger(&buf, slog.LevelInfo)).WithWriter(w).Fatal(errors.New("db down"))
+		entry := decode(t, &buf)
+		if entry["level"] != "ERROR+4" || entry["msg"] != "db down" {
+			t.Errorf("Expected a fatal entry, got %v", entry)
+		}
+		if _, ok := entry[slog.SourceKey].(map[string]interface{}); !ok {
+			t.Errorf("Expected caller info, got %v", entry)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package beam
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// metaSlow is the meta key flagging responses that exceeded the slow threshold.
+const metaSlow = "slow"
+
+// WarnLogger is an optional Logger extension for warning-level entries.
+// Slow-request annotations use Warn when the Renderer's logger implements it.
+type WarnLogger interface {
+	Warn(err error, fields ...interface{})
+}
+
+// warnTo logs err at warn level, falling back to Error for loggers without Warn.
+func warnTo(l Logger, err error, fields ...interface{}) {
+	if wl, ok := l.(WarnLogger); ok {
+		wl.Warn(err, fields...)
+		return
+	}
+	l.Error(err, fields...)
+}
+
+// WithSlowThreshold flags responses that take longer than d to produce.
+// Slow responses carry a "slow" meta flag, a "slow" Server-Timing marker,
+// a warn-level log entry, and a StatusSlow callback.
+// Returns a new Renderer with the threshold set; zero disables detection.
+func (r *Renderer) WithSlowThreshold(d time.Duration) *Renderer {
+	nr := r.clone()
+	nr.slowThreshold = d
+	return nr
+}
+
+// elapsed returns the time spent on the current request.
+// Measured from WithRequest when a request is bound, otherwise from the output call.
+func (r *Renderer) elapsed() time.Duration {
+	if !r.received.IsZero() {
+		return r.now().Sub(r.received)
+	}
+	return r.now().Sub(r.start)
+}
+
+// isSlow reports whether the current response exceeded the slow threshold.
+func (r *Renderer) isSlow() bool {
+	limit := r.slowLimit()
+	return limit > 0 && r.elapsed() > limit
+}
+
+// markSlow adds the slow meta flag to a response when the threshold was exceeded.
+func (r *Renderer) markSlow(resp *Response) {
+	if !r.isSlow() {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaSlow] = true
+}
+
+// annotateSlow sets the Server-Timing marker, logs a warning, and triggers
+// a StatusSlow callback when the threshold was exceeded.
+func (r *Renderer) annotateSlow() {
+	if !r.isSlow() {
+		return
+	}
+	d := r.elapsed()
+	r.header.Add("Server-Timing", fmt.Sprintf("slow;dur=%.1f", float64(d)/float64(time.Millisecond)))
+	msg := fmt.Sprintf("slow response: %s exceeded %s", d.Round(time.Millisecond), r.slowLimit())
+	if r.logger != nil {
+		fields := []interface{}{"id", r.id, "duration", d, "threshold", r.slowLimit()}
+		if r.request != nil {
+			fields = append(fields, "method", r.request.Method, "path", r.request.URL.Path)
+		}
+		warnTo(r.logger, errors.New(msg), fields...)
+	}
+	r.emit(newCallbackData(r.id, StatusSlow, msg, nil))
+}
@@ -0,0 +1,58 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowThreshold(t *testing.T) {
+	t.Run("Slow", func(t *testing.T) {
+		var statuses []string
+		logger := &TestLogger{}
+		rec := httptest.NewRecorder()
+		r := NewRenderer(settings).
+			WithSlowThreshold(10 * time.Millisecond).
+			WithLogger(logger).
+			WithCallback(func(d CallbackData) { statuses = append(statuses, d.Status) }).
+			WithWriter(rec).
+			WithRequest(httptest.NewRequest(http.MethodGet, "/report", nil))
+
+		time.Sleep(20 * time.Millisecond)
+		if err := r.Msg("done"); err != nil {
+			t.Fatalf("Msg failed: %v", err)
+		}
+
+		var resp Response
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if resp.Meta[metaSlow] != true {
+			t.Errorf("Expected slow meta flag, got %+v", resp.Meta)
+		}
+		if st := rec.Header().Get("Server-Timing"); !strings.HasPrefix(st, "slow;dur=") {
+			t.Errorf("Expected Server-Timing slow marker, got %q", st)
+		}
+		if logger.LastEntry() == nil {
+			t.Error("Expected slow response to be logged")
+		}
+		if len(statuses) == 0 || statuses[0] != StatusSlow {
+			t.Errorf("Expected StatusSlow callback first, got %v", statuses)
+		}
+	})
+
+	t.Run("Fast", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r := NewRenderer(settings).WithSlowThreshold(time.Second).WithWriter(rec).
+			WithRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		if err := r.Msg("done"); err != nil {
+			t.Fatalf("Msg failed: %v", err)
+		}
+		if rec.Header().Get("Server-Timing") != Empty || strings.Contains(rec.Body.String(), `"slow"`) {
+			t.Errorf("Expected no slow annotations, got %v %s", rec.Header(), rec.Body.String())
+		}
+	})
+}
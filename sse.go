@@ -0,0 +1,216 @@
+package beam
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// streamState tracks per-writer state shared by renderers bound to the same writer.
+// Created by WithWriter so that clones derived from it agree on what was already sent.
+type streamState struct {
+	headersSent atomic.Bool
+}
+
+// markHeaders records that headers were written for the bound writer.
+// Returns true if this call was the first to do so and headers should be applied.
+func (s *streamState) markHeaders() bool {
+	if s == nil {
+		return true
+	}
+	return !s.headersSent.Swap(true)
+}
+
+// PushEvent sends a Response as a single enveloped Server-Sent Event.
+// Assembles the Response like Push (tags, actions, meta, system) and writes it
+// as an SSE frame, using the "error" event type for error and fatal statuses.
+// Headers are applied only for the first event written to the bound writer.
+// Returns an error if the writer is unset, the context is canceled, or writing fails.
+func (r *Renderer) PushEvent(resp Response) error {
+	nr := r.clone()
+	if nr.start.IsZero() {
+		nr.start = nr.now()
+	}
+
+	if nr.ctx != nil {
+		select {
+		case <-nr.ctx.Done():
+			nr.triggerCallbacks(nr.id, StatusError, "operation canceled", ErrContextCanceled)
+			return ErrContextCanceled
+		default:
+		}
+	}
+
+	w := nr.writer
+	if err := nr.checkOutput(w); err != nil {
+		return err
+	}
+	nr.ensureID()
+	if err := nr.clientGone(); err != nil {
+		return err
+	}
+	if nr.code == 0 {
+		nr.code = http.StatusOK // Default for streams
+	}
+
+	env := getResponse()
+	defer putResponse(env)
+	nr.assemble(env, resp)
+
+	encoded, err := nr.encoders.Encode(ContentTypeEventStream, Event{Data: *env, Envelope: true})
+	if err != nil {
+		wrapped := errors.Join(errEncodingFailed, err)
+		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+		if nr.finalizer != nil {
+			nr.finalizer(w, wrapped)
+		}
+		return wrapped
+	}
+
+	if nr.stream.markHeaders() {
+		if err := nr.applyCommonHeaders(w, ContentTypeEventStream); err != nil {
+			wrapped := errors.Join(errHeaderWriteFailed, err)
+			nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+			if nr.finalizer != nil {
+				nr.finalizer(w, wrapped)
+			}
+			return wrapped
+		}
+	}
+
+	if _, err := nr.write(w, encoded); err != nil {
+		wrapped := errors.Join(errWriteFailed, err)
+		nr.triggerCallbacks(nr.id, StatusFatal, wrapped.Error(), wrapped)
+		if nr.finalizer != nil {
+			nr.finalizer(w, wrapped)
+		}
+		return wrapped
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	nr.triggerCallbacks(nr.id, env.Status, env.Message, nil)
+	return nil
+}
+
+// EventBuilder constructs Server-Sent Events frame by frame.
+// Supports comment lines, multi-line data blocks, and custom fields, prefixing
+// every line correctly so payloads containing newlines cannot corrupt the stream.
+// The zero value is ready for use; methods return the builder for chaining.
+type EventBuilder struct {
+	buf bytes.Buffer
+	err error
+}
+
+// NewEventBuilder creates an empty EventBuilder.
+// Returns a *EventBuilder ready for chaining.
+func NewEventBuilder() *EventBuilder {
+	return &EventBuilder{}
+}
+
+// ID sets the event ID field.
+// Line breaks are stripped since the id field must be a single line.
+func (b *EventBuilder) ID(id string) *EventBuilder {
+	writeSSEField(&b.buf, "id", sseSingleLine(id))
+	return b
+}
+
+// Type sets the event type field.
+// Line breaks are stripped since the event field must be a single line.
+func (b *EventBuilder) Type(t string) *EventBuilder {
+	writeSSEField(&b.buf, "event", sseSingleLine(t))
+	return b
+}
+
+// Retry sets the client reconnection delay.
+// Ignores non-positive durations.
+func (b *EventBuilder) Retry(d time.Duration) *EventBuilder {
+	if d > 0 {
+		writeSSEField(&b.buf, "retry", strconv.FormatInt(d.Milliseconds(), 10))
+	}
+	return b
+}
+
+// Comment adds a comment frame, ignored by clients but useful as a heartbeat.
+// Multi-line comments are emitted as one ":" line per line.
+func (b *EventBuilder) Comment(text string) *EventBuilder {
+	writeSSEField(&b.buf, "", text)
+	return b
+}
+
+// Data appends a text data block.
+// Each line of text becomes its own "data:" line.
+func (b *EventBuilder) Data(text string) *EventBuilder {
+	writeSSEField(&b.buf, "data", text)
+	return b
+}
+
+// JSON appends v encoded as JSON to the data block.
+// Encoding errors are retained and reported by Build.
+func (b *EventBuilder) JSON(v interface{}) *EventBuilder {
+	data, err := json.Marshal(applyTypeMarshalers(v))
+	if err != nil {
+		b.err = errors.Join(errEncodingFailed, err)
+		return b
+	}
+	writeSSEField(&b.buf, "data", string(data))
+	return b
+}
+
+// Field appends a custom field.
+// Field names containing ":" or line breaks are rejected at Build time.
+func (b *EventBuilder) Field(name, value string) *EventBuilder {
+	if name == Empty || strings.ContainsAny(name, ":\r\n") {
+		b.err = fmt.Errorf("invalid SSE field name %q", name)
+		return b
+	}
+	writeSSEField(&b.buf, name, value)
+	return b
+}
+
+// Build returns the terminated SSE frame.
+// Returns an error if any JSON or Field call failed.
+func (b *EventBuilder) Build() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	out := make([]byte, b.buf.Len()+1)
+	copy(out, b.buf.Bytes())
+	out[len(out)-1] = '\n'
+	return out, nil
+}
+
+// Reset clears the builder for reuse.
+func (b *EventBuilder) Reset() {
+	b.buf.Reset()
+	b.err = nil
+}
+
+// writeSSEField writes a field with one "name: line" per line of value.
+// An empty name writes comment lines prefixed with ":".
+// Normalizes CRLF and CR line endings before splitting.
+func writeSSEField(buf *bytes.Buffer, name, value string) {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	for _, line := range strings.Split(value, "\n") {
+		buf.WriteString(name)
+		buf.WriteString(": ")
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+}
+
+// sseSingleLine strips line breaks from a single-line SSE field value.
+func sseSingleLine(s string) string {
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
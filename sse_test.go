@@ -0,0 +1,84 @@
+package beam
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderer_PushEvent(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := NewRenderer(settings).WithWriter(w).WithTag("feed")
+
+	if err := r.PushEvent(Response{Message: "tick", Data: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.PushEvent(Response{Status: StatusError, Message: "boom", Errors: ErrorList{errors.New("bad")}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ct := w.Header().Get(HeaderContentType); ct != ContentTypeEventStream {
+		t.Errorf("Expected content type %s, got %s", ContentTypeEventStream, ct)
+	}
+	body := w.Body.String()
+	frames := strings.Split(strings.TrimSpace(body), "\n\n")
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 events, got %d: %q", len(frames), body)
+	}
+	if !strings.Contains(frames[0], `"status":"+ok"`) || !strings.Contains(frames[0], `"tags":["feed"]`) {
+		t.Errorf("Expected enveloped success event, got %q", frames[0])
+	}
+	if !strings.HasPrefix(frames[1], "event: error\n") || !strings.Contains(frames[1], `"errors":["bad"]`) {
+		t.Errorf("Expected error event with errors, got %q", frames[1])
+	}
+}
+
+func TestEventEnvelope(t *testing.T) {
+	enc := &EventStreamEncoder{}
+	data, err := enc.Marshal(Event{Type: "update", Data: map[string]int{"n": 1}, Envelope: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "event: update\ndata: {\"status\":\"+ok\",\"data\":{\"n\":1}}\n\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestEventBuilder(t *testing.T) {
+	t.Run("MultilineAndComments", func(t *testing.T) {
+		frame, err := NewEventBuilder().
+			Comment("keepalive").
+			ID("7").
+			Type("log").
+			Data("line one\nline two").
+			Field("x-trace", "abc").
+			Build()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := ": keepalive\nid: 7\nevent: log\ndata: line one\ndata: line two\nx-trace: abc\n\n"
+		if string(frame) != expected {
+			t.Errorf("Expected %q, got %q", expected, string(frame))
+		}
+	})
+
+	t.Run("InvalidField", func(t *testing.T) {
+		if _, err := NewEventBuilder().Field("bad:name", "v").Build(); err == nil {
+			t.Error("Expected error for invalid field name")
+		}
+	})
+
+	t.Run("IndentedEncoderStaysFramed", func(t *testing.T) {
+		enc := &EventStreamEncoder{Indent: "  "}
+		data, err := enc.Marshal(Event{Data: map[string]int{"a": 1}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "data: {\ndata:   \"a\": 1\ndata: }\n\n"
+		if string(data) != expected {
+			t.Errorf("Expected %q, got %q", expected, string(data))
+		}
+	})
+}
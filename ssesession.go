@@ -0,0 +1,257 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HeaderLastEventID is the header a reconnecting SSE client sends with the last event ID it received.
+const HeaderLastEventID = "Last-Event-ID"
+
+// DefaultSSEBuffer is the number of events queued per client when SSEOptions.Buffer is zero.
+const DefaultSSEBuffer = 64
+
+var (
+	// ErrSSEBufferFull is returned by Send when the client's queue is full.
+	ErrSSEBufferFull = errors.New("sse client buffer full")
+	// ErrSSESessionClosed is returned by Send after the session ended.
+	ErrSSESessionClosed = errors.New("sse session closed")
+)
+
+// SSEBacklog keeps the most recent events so reconnecting clients can resume
+// after their Last-Event-ID. Events without an ID are numbered sequentially.
+// Share one SSEBacklog between the sessions of a feed, Append each event once,
+// and Send the returned event to every session. Safe for concurrent use.
+type SSEBacklog struct {
+	mu     sync.Mutex
+	size   int
+	seq    uint64
+	events []Event
+}
+
+// NewSSEBacklog creates a backlog keeping the last size events.
+func NewSSEBacklog(size int) *SSEBacklog {
+	return &SSEBacklog{size: max(size, 1)}
+}
+
+// Append records evt, assigning the next sequence number as ID if it has none.
+// Returns the recorded event.
+func (b *SSEBacklog) Append(evt Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	if evt.ID == Empty {
+		evt.ID = strconv.FormatUint(b.seq, 10)
+	}
+	b.events = append(b.events, evt)
+	if over := len(b.events) - b.size; over > 0 {
+		b.events = append(b.events[:0], b.events[over:]...)
+	}
+	return evt
+}
+
+// Since returns the events recorded after the event with lastID.
+// Returns false if lastID is no longer (or never was) in the backlog.
+func (b *SSEBacklog) Since(lastID string) ([]Event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, evt := range b.events {
+		if evt.ID == lastID {
+			return append([]Event(nil), b.events[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+// SSEOptions configures an SSESession.
+type SSEOptions struct {
+	Heartbeat time.Duration // Interval between heartbeat comments on idle streams; zero disables
+	Buffer    int           // Events queued per client; zero uses DefaultSSEBuffer
+	Retry     time.Duration // Reconnection delay advertised to the client; zero omits it
+	Backlog   *SSEBacklog   // Replay source for Last-Event-ID; nil disables resume
+}
+
+// SSESession serves one Server-Sent Events client.
+// Events passed to Send are queued per client and written by Run, which also
+// replays missed events after Last-Event-ID, emits heartbeat comments, and
+// returns cleanly once the request context is canceled or Close is called.
+type SSESession struct {
+	r      *Renderer
+	opts   SSEOptions
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+	lastID string
+	seen   map[string]struct{} // IDs replayed from the backlog, skipped if queued again
+}
+
+// SSESession creates a session for the bound request and writer.
+// The client's Last-Event-ID header selects where Run resumes.
+func (r *Renderer) SSESession(opts SSEOptions) *SSESession {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultSSEBuffer
+	}
+	s := &SSESession{
+		r:      r.clone(),
+		opts:   opts,
+		events: make(chan Event, opts.Buffer),
+		done:   make(chan struct{}),
+	}
+	if r.request != nil {
+		s.lastID = r.request.Header.Get(HeaderLastEventID)
+	}
+	return s
+}
+
+// LastEventID returns the ID the client resumed from, if any.
+func (s *SSESession) LastEventID() string {
+	return s.lastID
+}
+
+// Send queues evt for the client without blocking.
+// Returns ErrSSEBufferFull if the client is too slow to keep up, or
+// ErrSSESessionClosed after the session ended.
+func (s *SSESession) Send(evt Event) error {
+	if s.closed() {
+		return ErrSSESessionClosed
+	}
+	select {
+	case s.events <- evt:
+		return nil
+	default:
+		return ErrSSEBufferFull
+	}
+}
+
+// Close ends the session; Run writes the queued events and returns.
+func (s *SSESession) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// closed reports whether the session ended.
+func (s *SSESession) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run writes headers and missed events, then streams queued events until the
+// context is canceled or Close is called.
+// Returns nil on a graceful close or an error if writing fails.
+func (s *SSESession) Run() error {
+	defer s.Close()
+	r := s.r
+	w := r.writer
+	if err := r.checkOutput(w); err != nil {
+		return err
+	}
+	if r.code == 0 {
+		r.code = http.StatusOK
+	}
+	r.header.Set("Cache-Control", "no-cache")
+	if r.stream.markHeaders() {
+		if err := r.applyCommonHeaders(w, ContentTypeEventStream); err != nil {
+			return errors.Join(errHeaderWriteFailed, err)
+		}
+		// Let the client see the response before the first event arrives.
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+	if s.opts.Retry > 0 {
+		if err := s.write(NewEventBuilder().Retry(s.opts.Retry)); err != nil {
+			return err
+		}
+	}
+	if err := s.replay(); err != nil {
+		return err
+	}
+
+	var heartbeat <-chan time.Time
+	if s.opts.Heartbeat > 0 {
+		ticker := time.NewTicker(s.opts.Heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return s.drain()
+		case evt := <-s.events:
+			if err := s.send(evt); err != nil {
+				return err
+			}
+		case <-heartbeat:
+			if err := s.write(NewEventBuilder().Comment("heartbeat")); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replay writes the backlog events the client missed since Last-Event-ID.
+// A Last-Event-ID no longer in the backlog cannot be resumed; the client then
+// receives a "reset" event so it can refetch state.
+func (s *SSESession) replay() error {
+	if s.lastID == Empty || s.opts.Backlog == nil {
+		return nil
+	}
+	missed, ok := s.opts.Backlog.Since(s.lastID)
+	if !ok {
+		return s.write(Event{Type: "reset", Data: map[string]string{"last_event_id": s.lastID}})
+	}
+	s.seen = make(map[string]struct{}, len(missed))
+	for _, evt := range missed {
+		if err := s.write(evt); err != nil {
+			return err
+		}
+		s.seen[evt.ID] = struct{}{}
+	}
+	return nil
+}
+
+// drain writes the events still queued when the session was closed.
+func (s *SSESession) drain() error {
+	for {
+		select {
+		case evt := <-s.events:
+			if err := s.send(evt); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// send writes a queued event unless it was already replayed.
+func (s *SSESession) send(evt Event) error {
+	if _, ok := s.seen[evt.ID]; ok && evt.ID != Empty {
+		return nil
+	}
+	return s.write(evt)
+}
+
+// write encodes and flushes one frame.
+func (s *SSESession) write(v interface{}) error {
+	encoded, err := s.r.encoders.Encode(ContentTypeEventStream, v)
+	if err != nil {
+		return errors.Join(errEncodingFailed, err)
+	}
+	if _, err := s.r.write(s.r.writer, encoded); err != nil {
+		return errors.Join(errWriteFailed, err)
+	}
+	if flusher, ok := s.r.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSESession(t *testing.T) {
+	backlog := NewSSEBacklog(2)
+	for _, msg := range []string{"a", "b", "c"} {
+		backlog.Append(Event{Data: msg})
+	}
+
+	t.Run("ResumeAndHeartbeat", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/feed", nil)
+		req.Header.Set(HeaderLastEventID, "2")
+		w := httptest.NewRecorder()
+		s := NewRenderer(settings).WithWriter(w).WithRequest(req).
+			SSESession(SSEOptions{Heartbeat: 5 * time.Millisecond, Retry: 3 * time.Second, Backlog: backlog})
+
+		// Queued before Run: the replayed event 3 must not be sent twice.
+		if err := s.Send(Event{ID: "3", Data: "c"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Send(backlog.Append(Event{Data: "d"})); err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			s.Close()
+		}()
+		if err := s.Run(); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		body := w.Body.String()
+		if w.Header().Get(HeaderContentType) != ContentTypeEventStream || w.Header().Get("Cache-Control") != "no-cache" {
+			t.Errorf("Unexpected headers %v", w.Header())
+		}
+		if !strings.HasPrefix(body, "retry: 3000\n\n") {
+			t.Errorf("Expected retry frame first, got %q", body)
+		}
+		if strings.Count(body, "id: 3\n") != 1 || !strings.Contains(body, "id: 4\n") || strings.Contains(body, "id: 2\n") {
+			t.Errorf("Expected events 3 and 4 once each, got %q", body)
+		}
+		if !strings.Contains(body, ": heartbeat\n") {
+			t.Errorf("Expected heartbeat comment, got %q", body)
+		}
+		if err := s.Send(Event{Data: "late"}); !errors.Is(err, ErrSSESessionClosed) {
+			t.Errorf("Expected ErrSSESessionClosed, got %v", err)
+		}
+	})
+
+	t.Run("ExpiredLastEventID", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := httptest.NewRequest(http.MethodGet, "/feed", nil).WithContext(ctx)
+		req.Header.Set(HeaderLastEventID, "1")
+		w := httptest.NewRecorder()
+		s := NewRenderer(settings).WithWriter(w).WithRequest(req).SSESession(SSEOptions{Backlog: backlog})
+		cancel()
+		if err := s.Run(); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if !strings.Contains(w.Body.String(), "event: reset\n") {
+			t.Errorf("Expected reset event, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("BufferFull", func(t *testing.T) {
+		s := NewRenderer(settings).SSESession(SSEOptions{Buffer: 1})
+		if err := s.Send(Event{Data: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Send(Event{Data: 2}); !errors.Is(err, ErrSSEBufferFull) {
+			t.Errorf("Expected ErrSSEBufferFull, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package beam
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// TagRoute directs the logs and callbacks of renderers carrying a tag.
+// Statuses limits the route to some statuses (e.g., StatusFatal); log calls
+// map to StatusError, StatusFatal, StatusWarning, and StatusSuccessful for Info.
+type TagRoute struct {
+	Logger    Logger                    // Receives routed logs; nil routes callbacks only
+	Callbacks []func(data CallbackData) // Receive routed callbacks
+	Statuses  []string                  // Statuses routed; empty routes every status
+	Exclusive bool                      // Routed logs skip the renderer's own logger
+}
+
+// matches reports whether the route applies to status.
+func (t TagRoute) matches(status string) bool {
+	return len(t.Statuses) == 0 || slices.Contains(t.Statuses, status)
+}
+
+// tagRoute is a registered TagRoute with its callbacks ready to emit.
+type tagRoute struct {
+	TagRoute
+	callbacks *CallbackManager
+}
+
+// TagRoutes maps Renderer tags to routes, configured once and shared by
+// every Renderer via WithTagRoutes so sensitive domains get stricter handling
+// without per-handler wiring. Safe for concurrent use.
+type TagRoutes struct {
+	mu     sync.RWMutex
+	routes map[string][]*tagRoute
+}
+
+// NewTagRoutes creates an empty TagRoutes.
+func NewTagRoutes() *TagRoutes {
+	return &TagRoutes{routes: make(map[string][]*tagRoute)}
+}
+
+// Route adds a route for renderers tagged with tag.
+// Renderers resolve their logger when tags, logger, or routes are attached,
+// so routes should be configured before renderers are derived.
+// Returns the TagRoutes for chaining.
+func (t *TagRoutes) Route(tag string, route TagRoute) *TagRoutes {
+	tr := &tagRoute{TagRoute: route, callbacks: NewCallbackManager()}
+	tr.callbacks.AddCallback(route.Callbacks...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes[tag] = append(t.routes[tag], tr)
+	return t
+}
+
+// match returns the routes registered for any of tags, each once.
+func (t *TagRoutes) match(tags []string) []*tagRoute {
+	if t == nil || len(tags) == 0 {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var matched []*tagRoute
+	for _, tag := range tags {
+		for _, tr := range t.routes[tag] {
+			if !slices.Contains(matched, tr) {
+				matched = append(matched, tr)
+			}
+		}
+	}
+	return matched
+}
+
+// logger returns base wrapped to fan out to the loggers routed for tags.
+// Returns base unchanged when no route with a Logger applies.
+func (t *TagRoutes) logger(base Logger, tags []string) Logger {
+	var routes []*tagRoute
+	for _, tr := range t.match(tags) {
+		if tr.Logger != nil {
+			routes = append(routes, tr)
+		}
+	}
+	if len(routes) == 0 {
+		return base
+	}
+	return &tagLogger{base: base, routes: routes}
+}
+
+// emit passes data to the callbacks routed for tags and its status.
+// Returns the panics of failing callbacks, joined, or nil.
+func (t *TagRoutes) emit(tags []string, data CallbackData) error {
+	var errs []error
+	for _, tr := range t.match(tags) {
+		if tr.matches(data.Status) {
+			errs = append(errs, tr.callbacks.Emit(data))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithTagRoutes routes the Renderer's logs and callbacks by its tags.
+// Returns a new Renderer using routes.
+func (r *Renderer) WithTagRoutes(routes *TagRoutes) *Renderer {
+	nr := r.clone()
+	nr.tagRoutes = routes
+	nr.routeLogger()
+	return nr
+}
+
+// routeLogger resolves the logger for the Renderer's current tags and Runtime.
+func (r *Renderer) routeLogger() {
+	r.logger = r.runtime.logger(r.tagRoutes.logger(r.baseLogger, r.tags))
+}
+
+// emit passes data to the registered and tag-routed callbacks.
+// Panicking callbacks are reported through the logger.
+func (r *Renderer) emit(data CallbackData) {
+	r.reportCallbacks(r.callbacks.Emit(data))
+	r.reportCallbacks(r.tagRoutes.emit(r.tags, data))
+}
+
+// tagLogger fans log calls out to the renderer's logger and routed loggers.
+// Implements WarnLogger and InfoLogger, falling back to Error for loggers that don't.
+type tagLogger struct {
+	base   Logger
+	routes []*tagRoute
+}
+
+func (l *tagLogger) Error(err error, fields ...interface{}) {
+	l.each(StatusError, func(lg Logger) { lg.Error(err, fields...) })
+}
+
+func (l *tagLogger) Fatal(err error, fields ...interface{}) {
+	l.each(StatusFatal, func(lg Logger) { lg.Fatal(err, fields...) })
+}
+
+func (l *tagLogger) Warn(err error, fields ...interface{}) {
+	l.each(StatusWarning, func(lg Logger) { warnTo(lg, err, fields...) })
+}
+
+func (l *tagLogger) Info(msg string, fields ...interface{}) {
+	l.each(StatusSuccessful, func(lg Logger) { infoTo(lg, msg, fields...) })
+}
+
+// each calls fn for the routed loggers matching status and, unless an
+// exclusive route matched, the base logger.
+func (l *tagLogger) each(status string, fn func(Logger)) {
+	exclusive := false
+	for _, tr := range l.routes {
+		if tr.matches(status) {
+			fn(tr.Logger)
+			exclusive = exclusive || tr.Exclusive
+		}
+	}
+	if !exclusive && l.base != nil {
+		fn(l.base)
+	}
+}
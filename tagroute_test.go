@@ -0,0 +1,61 @@
+package beam
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTagRoutes(t *testing.T) {
+	base, billing := &TestLogger{}, &TestLogger{}
+	var routed []CallbackData
+	routes := NewTagRoutes().Route("billing", TagRoute{
+		Logger:    billing,
+		Callbacks: []func(CallbackData){func(d CallbackData) { routed = append(routed, d) }},
+		Statuses:  []string{StatusFatal},
+		Exclusive: true,
+	})
+	r := NewRenderer(settings).WithLogger(base).WithTagRoutes(routes)
+
+	t.Run("Untagged", func(t *testing.T) {
+		if err := r.WithWriter(httptest.NewRecorder()).Fatal(errors.New("boom")); err != nil {
+			t.Fatalf("Fatal failed: %v", err)
+		}
+		if len(base.Entries) != 1 || len(billing.Entries) != 0 || len(routed) != 0 {
+			t.Errorf("Expected only the base logger, got base=%d billing=%d callbacks=%d",
+				len(base.Entries), len(billing.Entries), len(routed))
+		}
+	})
+
+	base.Entries, billing.Entries = nil, nil
+	tagged := r.WithTag("billing")
+
+	t.Run("Fatal", func(t *testing.T) {
+		if err := tagged.WithWriter(httptest.NewRecorder()).Fatal(errors.New("charge failed")); err != nil {
+			t.Fatalf("Fatal failed: %v", err)
+		}
+		if len(billing.Entries) != 1 || billing.Entries[0].Level != "fatal" {
+			t.Errorf("Expected the fatal routed to billing, got %+v", billing.Entries)
+		}
+		if len(base.Entries) != 0 {
+			t.Errorf("Expected the exclusive route to skip the base logger, got %+v", base.Entries)
+		}
+		if len(routed) != 1 || routed[0].Status != StatusFatal {
+			t.Errorf("Expected one routed fatal callback, got %+v", routed)
+		}
+	})
+
+	t.Run("Unrouted", func(t *testing.T) {
+		tagged.Log(errors.New("retrying"))
+		if len(base.Entries) != 1 || len(billing.Entries) != 1 {
+			t.Errorf("Expected errors to stay on the base logger, got base=%d billing=%d",
+				len(base.Entries), len(billing.Entries))
+		}
+		if err := tagged.WithWriter(httptest.NewRecorder()).Msg("ok"); err != nil {
+			t.Fatalf("Msg failed: %v", err)
+		}
+		if len(routed) != 1 {
+			t.Errorf("Expected successful callbacks not routed, got %d", len(routed))
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package beam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// FrameType identifies the kind of a TCP feed frame.
+type FrameType byte
+
+// Frame types used by TCPConn.
+const (
+	FrameData      FrameType = 'D' // Encoded response payload
+	FrameHeartbeat FrameType = 'H' // Keep-alive; carries the last sent sequence number
+	FrameResume    FrameType = 'R' // Sent by a reconnecting client with its last received sequence
+)
+
+// frameHeaderSize is the encoded size of a frame header: type, sequence, and length.
+const frameHeaderSize = 1 + 8 + 4
+
+// maxFramePayload bounds the payload size accepted by ReadFrame.
+const maxFramePayload = 64 << 20
+
+var (
+	// ErrSequenceGap is returned by Resume when the requested frames are no longer buffered.
+	ErrSequenceGap = errors.New("resume sequence no longer buffered")
+	// ErrConnClosed is returned when writing to a closed TCPConn.
+	ErrConnClosed = errors.New("connection closed")
+
+	errFrameTooLarge = errors.New("frame payload too large")
+	// errPartialFrame marks a frame cut short by a failed write; re-sending
+	// the rest would not restore the framing, so such writes are never retried.
+	errPartialFrame = errors.New("frame partially written")
+)
+
+// Frame is a single unit of the TCP feed wire format.
+// Encoded as a 1-byte type, an 8-byte big-endian sequence number,
+// a 4-byte big-endian payload length, and the payload.
+type Frame struct {
+	Type    FrameType
+	Seq     uint64
+	Payload []byte
+}
+
+// WriteFrame encodes f to w.
+// Returns an error if writing fails.
+func WriteFrame(w io.Writer, f Frame) error {
+	_, err := writeFrame(w, f)
+	return err
+}
+
+// writeFrame encodes f to w and returns the bytes written.
+func writeFrame(w io.Writer, f Frame) (int, error) {
+	var header [frameHeaderSize]byte
+	header[0] = byte(f.Type)
+	binary.BigEndian.PutUint64(header[1:9], f.Seq)
+	return writeLengthPrefixed(w, header[:], f.Payload)
+}
+
+// ReadFrame decodes the next frame from r.
+// Returns io.EOF at a clean end of stream or an error if the frame is malformed.
+func ReadFrame(r io.Reader) (Frame, error) {
+	var header [frameHeaderSize]byte
+	payload, err := readLengthPrefixed(r, header[:])
+	if err != nil {
+		return Frame{}, err
+	}
+	return Frame{Type: FrameType(header[0]), Seq: binary.BigEndian.Uint64(header[1:9]), Payload: payload}, nil
+}
+
+// writeLengthPrefixed writes header followed by payload in a single Write,
+// storing the payload length in the last 4 bytes of header, big-endian.
+// Shared by TCP feed frames and FramedProtocol messages.
+// Returns the bytes written, header included.
+func writeLengthPrefixed(w io.Writer, header, payload []byte) (int, error) {
+	buf := make([]byte, len(header)+len(payload))
+	copy(buf, header)
+	binary.BigEndian.PutUint32(buf[len(header)-4:len(header)], uint32(len(payload)))
+	copy(buf[len(header):], payload)
+	return w.Write(buf)
+}
+
+// readLengthPrefixed fills header from r and reads the payload whose length
+// its last 4 bytes hold, bounded by maxFramePayload.
+// Returns a nil payload for empty frames.
+func readLengthPrefixed(r io.Reader, header []byte) ([]byte, error) {
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[len(header)-4:])
+	if size > maxFramePayload {
+		return nil, errFrameTooLarge
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// frameWriteError returns the error of a failed frame write, marked with
+// errPartialFrame when n bytes of the frame already went out.
+func frameWriteError(n int, err error) error {
+	if n > 0 {
+		return errors.Join(errPartialFrame, err)
+	}
+	return err
+}
+
+// TCPOptions configures the lifecycle of a TCPConn.
+type TCPOptions struct {
+	Heartbeat    time.Duration // Idle interval after which a heartbeat frame is sent; zero disables
+	ReadTimeout  time.Duration // Deadline for each ReadFrame call; zero disables
+	WriteTimeout time.Duration // Deadline for each frame write; zero disables
+	Backlog      int           // Number of sent data frames kept for Resume
+	StartSeq     uint64        // Sequence number of the last frame already delivered
+}
+
+// TCPConn frames renderer output over a persistent TCP connection.
+// Every Write becomes one sequenced data frame; idle periods are filled with
+// heartbeats, and a reconnecting client can resume from its last sequence.
+// Use it as the Writer for a Renderer configured with TCPProtocol.
+type TCPConn struct {
+	opts TCPOptions
+
+	mu      sync.Mutex
+	conn    net.Conn
+	seq     uint64
+	backlog []Frame
+	last    time.Time
+	err     error
+
+	done chan struct{}
+	once sync.Once
+}
+
+// NewTCPConn wraps conn and starts the heartbeat loop if configured.
+// Takes the connection and lifecycle options.
+// Returns a TCPConn ready to be used as a Writer.
+func NewTCPConn(conn net.Conn, opts TCPOptions) *TCPConn {
+	c := &TCPConn{
+		opts: opts,
+		conn: conn,
+		seq:  opts.StartSeq,
+		last: time.Now(),
+		done: make(chan struct{}),
+	}
+	if opts.Heartbeat > 0 {
+		go c.heartbeat()
+	}
+	return c
+}
+
+// Write sends p as the next data frame.
+// Returns len(p) on success, or 0 and an error if the connection is closed or
+// the write fails; a frame cut short fails with an error that is never retried.
+func (c *TCPConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return 0, c.err
+	}
+	f := Frame{Type: FrameData, Seq: c.seq + 1, Payload: append([]byte(nil), p...)}
+	if err := c.writeFrame(f); err != nil {
+		return 0, err
+	}
+	c.seq = f.Seq
+	if c.opts.Backlog > 0 {
+		c.backlog = append(c.backlog, f)
+		if over := len(c.backlog) - c.opts.Backlog; over > 0 {
+			c.backlog = append(c.backlog[:0], c.backlog[over:]...)
+		}
+	}
+	return len(p), nil
+}
+
+// ReadFrame reads the next frame sent by the client, applying ReadTimeout.
+// Returns the frame or an error if reading fails or the deadline passes.
+func (c *TCPConn) ReadFrame() (Frame, error) {
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if c.opts.ReadTimeout > 0 {
+		if err := conn.SetReadDeadline(time.Now().Add(c.opts.ReadTimeout)); err != nil {
+			return Frame{}, err
+		}
+	}
+	return ReadFrame(conn)
+}
+
+// Resume switches to a new connection and replays frames after lastSeq.
+// Takes the reconnected client's connection and the last sequence it received.
+// Returns ErrSequenceGap if frames after lastSeq were dropped from the backlog.
+func (c *TCPConn) Resume(conn net.Conn, lastSeq uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	select {
+	case <-c.done:
+		return ErrConnClosed
+	default:
+	}
+	if lastSeq > c.seq {
+		return fmt.Errorf("%w: client at %d, server at %d", ErrSequenceGap, lastSeq, c.seq)
+	}
+	if lastSeq < c.seq && (len(c.backlog) == 0 || c.backlog[0].Seq > lastSeq+1) {
+		return fmt.Errorf("%w: client at %d", ErrSequenceGap, lastSeq)
+	}
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+	c.conn = conn
+	c.err = nil
+	for _, f := range c.backlog {
+		if f.Seq <= lastSeq {
+			continue
+		}
+		if err := c.writeFrame(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Seq returns the sequence number of the last data frame sent.
+func (c *TCPConn) Seq() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.seq
+}
+
+// Close stops the heartbeat loop and closes the underlying connection.
+// Returns an error if closing the connection fails.
+func (c *TCPConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.done)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.err = ErrConnClosed
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// writeFrame writes f with the write deadline applied; c.mu must be held.
+// A failed write is recorded so later writes fail fast until Resume.
+func (c *TCPConn) writeFrame(f Frame) error {
+	if c.opts.WriteTimeout > 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.opts.WriteTimeout)); err != nil {
+			c.err = err
+			return err
+		}
+	}
+	if n, err := writeFrame(c.conn, f); err != nil {
+		c.err = errors.Join(errWriteFailed, frameWriteError(n, err))
+		return c.err
+	}
+	c.last = time.Now()
+	return nil
+}
+
+// heartbeat sends a heartbeat frame whenever the connection has been idle for the interval.
+func (c *TCPConn) heartbeat() {
+	ticker := time.NewTicker(max(c.opts.Heartbeat/2, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case now := <-ticker.C:
+			c.mu.Lock()
+			if c.err == nil && now.Sub(c.last) >= c.opts.Heartbeat {
+				_ = c.writeFrame(Frame{Type: FrameHeartbeat, Seq: c.seq})
+			}
+			c.mu.Unlock()
+		}
+	}
+}
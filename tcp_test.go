@@ -0,0 +1,90 @@
+package beam
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestTCPConn(t *testing.T) {
+	t.Run("PushFramesAndHeartbeat", func(t *testing.T) {
+		server, client := net.Pipe()
+		conn := NewTCPConn(server, TCPOptions{Heartbeat: 20 * time.Millisecond, WriteTimeout: time.Second})
+		defer conn.Close()
+
+		go func() {
+			_ = NewRenderer(settings).WithProtocol(&TCPProtocol{}).Push(conn, Response{Message: "tick"})
+		}()
+		f, err := ReadFrame(client)
+		if err != nil {
+			t.Fatalf("ReadFrame failed: %v", err)
+		}
+		if f.Type != FrameData || f.Seq != 1 || len(f.Payload) == 0 {
+			t.Errorf("Expected data frame 1, got %c %d %q", f.Type, f.Seq, f.Payload)
+		}
+		f, err = ReadFrame(client)
+		if err != nil {
+			t.Fatalf("ReadFrame failed: %v", err)
+		}
+		if f.Type != FrameHeartbeat || f.Seq != 1 {
+			t.Errorf("Expected heartbeat at seq 1, got %c %d", f.Type, f.Seq)
+		}
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		server, client := net.Pipe()
+		conn := NewTCPConn(server, TCPOptions{Backlog: 2, StartSeq: 10})
+		defer conn.Close()
+		go func() {
+			for _, msg := range []string{"a", "b", "c"} {
+				_, _ = conn.Write([]byte(msg))
+			}
+		}()
+		for i := 0; i < 3; i++ {
+			if _, err := ReadFrame(client); err != nil {
+				t.Fatalf("ReadFrame failed: %v", err)
+			}
+		}
+		if conn.Seq() != 13 {
+			t.Fatalf("Expected seq 13, got %d", conn.Seq())
+		}
+
+		server2, client2 := net.Pipe()
+		if err := conn.Resume(server2, 10); !errors.Is(err, ErrSequenceGap) {
+			t.Errorf("Expected ErrSequenceGap for dropped frames, got %v", err)
+		}
+		done := make(chan error, 1)
+		go func() { done <- conn.Resume(server2, 12) }()
+		f, err := ReadFrame(client2)
+		if err != nil {
+			t.Fatalf("ReadFrame failed: %v", err)
+		}
+		if f.Seq != 13 || string(f.Payload) != "c" {
+			t.Errorf("Expected replay of frame 13, got %d %q", f.Seq, f.Payload)
+		}
+		if err := <-done; err != nil {
+			t.Errorf("Resume failed: %v", err)
+		}
+	})
+
+	t.Run("ReadTimeout", func(t *testing.T) {
+		server, client := net.Pipe()
+		defer client.Close()
+		conn := NewTCPConn(server, TCPOptions{ReadTimeout: 10 * time.Millisecond})
+		defer conn.Close()
+		var ne net.Error
+		if _, err := conn.ReadFrame(); !errors.As(err, &ne) || !ne.Timeout() {
+			t.Errorf("Expected timeout error, got %v", err)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		server, _ := net.Pipe()
+		conn := NewTCPConn(server, TCPOptions{})
+		_ = conn.Close()
+		if _, err := conn.Write([]byte("x")); !errors.Is(err, ErrConnClosed) {
+			t.Errorf("Expected ErrConnClosed, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package beam
+
+import "context"
+
+// metaTrace is the meta key carrying the trace and span IDs.
+const metaTrace = "trace"
+
+// Headers carrying the trace and span IDs, prefixed like the other Beam headers.
+const (
+	HeaderNameTraceID = "Trace-ID"
+	HeaderNameSpanID  = "Span-ID"
+)
+
+// Span attributes set by WithTracer.
+const (
+	AttrContentType = "beam.content_type"         // Content type of the output
+	AttrEncodedSize = "beam.encoded_size"         // Bytes written, after encoding and compression
+	AttrStatusCode  = "http.response.status_code" // HTTP status code sent
+)
+
+// Span is the part of a tracing span the Renderer uses.
+// Adapting an OpenTelemetry trace.Span takes a few lines, keeping Beam free
+// of a tracing dependency when tracing is unused.
+type Span interface {
+	// SetAttribute records a key/value attribute on the span.
+	SetAttribute(key string, value interface{})
+
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+
+	// TraceID and SpanID return the hex identifiers of the span.
+	TraceID() string
+	SpanID() string
+}
+
+// Tracer starts the spans covering Push, Raw, Stream, and Binary calls.
+// Start may return the span already active in ctx to annotate it instead of
+// starting a child. Implementations must be safe for concurrent use.
+type Tracer interface {
+	Start(ctx context.Context, name string) Span
+}
+
+// TraceInfo is the meta.trace block of a traced response.
+type TraceInfo struct {
+	TraceID string `json:"trace_id" xml:"trace_id" msgpack:"trace_id"`
+	SpanID  string `json:"span_id" xml:"span_id" msgpack:"span_id"`
+}
+
+// WithTracer traces every Push, Raw, Stream, and Binary call with a span
+// recording the content type, encoded size, status code, and error. The trace
+// and span IDs are sent in headers and, for Push, in meta.trace.
+// Returns a new Renderer with the tracer set; nil disables tracing.
+func (r *Renderer) WithTracer(t Tracer) *Renderer {
+	nr := r.clone()
+	nr.tracer = t
+	return nr
+}
+
+// startSpan starts the span of the current output call, parented on the
+// Renderer's context or the bound request's.
+func (r *Renderer) startSpan() {
+	if r.tracer == nil {
+		return
+	}
+	ctx := r.ctx
+	if ctx == nil && r.request != nil {
+		ctx = r.request.Context()
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r.output.span = r.tracer.Start(ctx, "beam."+r.output.op)
+}
+
+// endSpan records the outcome of the output call on its span and ends it.
+func (r *Renderer) endSpan(contentType string, err error) {
+	s := r.output.span
+	if s == nil {
+		return
+	}
+	s.SetAttribute(AttrContentType, contentType)
+	s.SetAttribute(AttrEncodedSize, r.output.bytes)
+	s.SetAttribute(AttrStatusCode, r.code)
+	if err != nil {
+		s.RecordError(err)
+	}
+	s.End()
+}
+
+// traced returns the span of the current output call, or nil.
+func (r *Renderer) traced() Span {
+	if r.output == nil {
+		return nil
+	}
+	return r.output.span
+}
+
+// traceHeaders sets the trace and span ID headers.
+func (r *Renderer) traceHeaders() {
+	span := r.traced()
+	if span == nil {
+		return
+	}
+	r.header.Set(r.headerName(HeaderNameTraceID), span.TraceID())
+	r.header.Set(r.headerName(HeaderNameSpanID), span.SpanID())
+}
+
+// markTrace adds meta.trace to a traced response.
+func (r *Renderer) markTrace(resp *Response) {
+	span := r.traced()
+	if span == nil {
+		return
+	}
+	if resp.Meta == nil {
+		resp.Meta = make(map[string]interface{})
+	}
+	resp.Meta[metaTrace] = TraceInfo{TraceID: span.TraceID(), SpanID: span.SpanID()}
+}
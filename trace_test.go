@@ -0,0 +1,133 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testSpan records what the Renderer reports on a span.
+type testSpan struct {
+	name  string
+	id    int
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+func (s *testSpan) TraceID() string                            { return "4bf92f3577b34da6a3ce929d0e0e4736" }
+func (s *testSpan) SpanID() string                             { return fmt.Sprintf("%016x", s.id) }
+
+// testTracer collects the spans it starts.
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(_ context.Context, name string) Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &testSpan{name: name, id: len(t.spans) + 1, attrs: make(map[string]interface{})}
+	t.spans = append(t.spans, s)
+	return s
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("Push", func(t *testing.T) {
+		tracer := &testTracer{}
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithTracer(tracer).WithWriter(w).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if len(tracer.spans) != 1 {
+			t.Fatalf("Expected one span, got %d", len(tracer.spans))
+		}
+		span := tracer.spans[0]
+		if span.name != "beam.Push" || !span.ended || span.err != nil {
+			t.Errorf("Unexpected span %+v", span)
+		}
+		if span.attrs[AttrContentType] != ContentTypeJSON || span.attrs[AttrStatusCode] != http.StatusOK {
+			t.Errorf("Unexpected attributes %v", span.attrs)
+		}
+		if span.attrs[AttrEncodedSize] != int64(w.Body.Len()) {
+			t.Errorf("Expected encoded size %d, got %v", w.Body.Len(), span.attrs[AttrEncodedSize])
+		}
+		if w.Header().Get("X-test-Trace-ID") != span.TraceID() || w.Header().Get("X-test-Span-ID") != span.SpanID() {
+			t.Errorf("Expected trace headers, got %v", w.Header())
+		}
+		var resp struct {
+			Meta map[string]TraceInfo `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Meta[metaTrace].SpanID != span.SpanID() {
+			t.Errorf("Expected meta.trace, got %+v", resp.Meta[metaTrace])
+		}
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		tracer := &testTracer{}
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithTracer(tracer).WithWriter(w).Binary("image/png", []byte("png")); err != nil {
+			t.Fatalf("Binary failed: %v", err)
+		}
+		span := tracer.spans[0]
+		if span.name != "beam.Binary" || span.attrs[AttrContentType] != "image/png" || span.attrs[AttrEncodedSize] != int64(3) {
+			t.Errorf("Unexpected span %+v", span)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		tracer := &testTracer{}
+		w := httptest.NewRecorder()
+		n := 0
+		err := NewRenderer(settings).WithTracer(tracer).WithWriter(w).WithContentType(ContentTypeNDJSON).
+			Stream(func(*Renderer) (interface{}, error) {
+				if n++; n > 2 {
+					return nil, io.EOF
+				}
+				return map[string]int{"n": n}, nil
+			})
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		span := tracer.spans[0]
+		if span.name != "beam.Stream" || span.attrs[AttrEncodedSize] != int64(w.Body.Len()) {
+			t.Errorf("Expected the streamed size %d, got %+v", w.Body.Len(), span)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		tracer := &testTracer{}
+		err := NewRenderer(settings).WithTracer(tracer).WithWriter(&TestWriter{
+			Headers:    http.Header{},
+			WriteError: errors.New("broken pipe"),
+		}).Data("ok", nil)
+		if err == nil {
+			t.Fatal("Expected a write error")
+		}
+		if span := tracer.spans[0]; !errors.Is(span.err, err) || !span.ended {
+			t.Errorf("Expected the error recorded, got %+v", span)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		if err := NewRenderer(settings).WithWriter(w).Data("ok", nil); err != nil {
+			t.Fatalf("Data failed: %v", err)
+		}
+		if w.Header().Get("X-test-Trace-ID") != Empty {
+			t.Error("Expected no trace headers without a tracer")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package beam
+
+import (
+	"reflect"
+	"sync"
+)
+
+// typeNames holds the process-wide registry of discriminator names.
+// Consulted when a Renderer has type discriminators enabled.
+var typeNames = struct {
+	mu    sync.RWMutex
+	names map[reflect.Type]string
+}{names: make(map[reflect.Type]string)}
+
+// RegisterTypeName registers the discriminator emitted for values of type T.
+// Pointers to T resolve to the same name and slices or arrays of T to name + "[]",
+// so polymorphic endpoints can tell clients which shape to decode.
+// Registering an empty name removes the entry for T.
+func RegisterTypeName[T any](name string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	typeNames.mu.Lock()
+	defer typeNames.mu.Unlock()
+	if name == Empty {
+		delete(typeNames.names, t)
+		return
+	}
+	typeNames.names[t] = name
+}
+
+// TypeName returns the discriminator registered for v's type.
+// Returns an empty string when v is nil or its type is not registered.
+func TypeName(v interface{}) string {
+	if v == nil {
+		return Empty
+	}
+	return typeNameOf(reflect.TypeOf(v))
+}
+
+// typeNameOf resolves t, following pointers and slice or array elements.
+func typeNameOf(t reflect.Type) string {
+	typeNames.mu.RLock()
+	name, ok := typeNames.names[t]
+	typeNames.mu.RUnlock()
+	if ok {
+		return name
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeNameOf(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if elem := typeNameOf(t.Elem()); elem != Empty {
+			return elem + "[]"
+		}
+	}
+	return Empty
+}
+
+// WithTypeDiscriminator enables or disables info_type/data_type discriminators.
+// Names come from RegisterTypeName; unregistered values get no discriminator,
+// and values set explicitly on the Response are left untouched.
+// Returns a new Renderer with the updated setting.
+func (r *Renderer) WithTypeDiscriminator(enabled State) *Renderer {
+	nr := r.clone()
+	nr.typeNames = enabled
+	return nr
+}
+
+// discriminate fills in resp.InfoType and resp.DataType when enabled.
+func (r *Renderer) discriminate(resp *Response) {
+	if !r.typeNames.Enabled() {
+		return
+	}
+	if resp.InfoType == Empty {
+		resp.InfoType = TypeName(resp.Info)
+	}
+	if resp.DataType == Empty {
+		resp.DataType = TypeName(resp.Data)
+	}
+}
@@ -0,0 +1,59 @@
+package beam
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+type testCat struct{ Name string }
+type testDog struct{ Name string }
+
+func TestTypeDiscriminator(t *testing.T) {
+	RegisterTypeName[testCat]("cat")
+	RegisterTypeName[testDog]("dog")
+	defer RegisterTypeName[testCat]("")
+	defer RegisterTypeName[testDog]("")
+
+	decode := func(t *testing.T, r *Renderer, d Response) map[string]interface{} {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := r.Push(w, d); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		return body
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		body := decode(t, NewRenderer(settings), Response{Data: testCat{"tom"}})
+		if _, ok := body["data_type"]; ok {
+			t.Errorf("Expected no data_type by default, got %v", body["data_type"])
+		}
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		r := NewRenderer(settings).WithTypeDiscriminator(Yes)
+		body := decode(t, r, Response{Info: &testDog{"rex"}, Data: []testCat{{"tom"}}})
+		if body["info_type"] != "dog" {
+			t.Errorf("Expected info_type dog, got %v", body["info_type"])
+		}
+		if body["data_type"] != "cat[]" {
+			t.Errorf("Expected data_type cat[], got %v", body["data_type"])
+		}
+	})
+
+	t.Run("UnregisteredAndExplicit", func(t *testing.T) {
+		r := NewRenderer(settings).WithTypeDiscriminator(Yes)
+		body := decode(t, r, Response{Info: map[string]int{"a": 1}, Data: testCat{"tom"}, DataType: "feline"})
+		if _, ok := body["info_type"]; ok {
+			t.Errorf("Expected no info_type for unregistered type, got %v", body["info_type"])
+		}
+		if body["data_type"] != "feline" {
+			t.Errorf("Expected explicit data_type to win, got %v", body["data_type"])
+		}
+	})
+}
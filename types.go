@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"net/http"
+	"runtime/debug"
+	"slices"
+	"sync/atomic"
 	"time"
 )
 
@@ -118,6 +122,8 @@ type CallbackData struct {
 	Message string   `json:"message,omitempty"`
 	Output  string   `json:"output,omitempty"`
 	Err     error    `json:"-"` // Not marshaled, for internal use
+
+	Progress *StreamProgress `json:"progress,omitempty"` // Set on StatusProgress callbacks
 }
 
 // IsError checks if the callback data represents an error state.
@@ -138,17 +144,27 @@ func (c CallbackData) Error() error {
 // Contains fields for status, message, data, and errors.
 // Used by Renderer to structure response output.
 type Response struct {
-	Status  string                 `json:"status" xml:"status" msgpack:"status"`
-	Title   string                 `json:"title,omitempty" xml:"title,omitempty" msgpack:"title"`
-	Message string                 `json:"message,omitempty" xml:"message,omitempty" msgpack:"message"`
-	Tags    []string               `json:"tags,omitempty" xml:"tags,omitempty" msgpack:"tags"`
-	Info    interface{}            `json:"info,omitempty" xml:"info,omitempty" msgpack:"info"`
-	Data    interface{}            `json:"data,omitempty" xml:"data,omitempty" msgpack:"data"`
+	Status  string      `json:"status" xml:"status" msgpack:"status"`
+	Title   string      `json:"title,omitempty" xml:"title,omitempty" msgpack:"title"`
+	Message string      `json:"message,omitempty" xml:"message,omitempty" msgpack:"message"`
+	Tags    []string    `json:"tags,omitempty" xml:"tags,omitempty" msgpack:"tags"`
+	Info    interface{} `json:"info,omitempty" xml:"info,omitempty" msgpack:"info"`
+	Data    interface{} `json:"data,omitempty" xml:"data,omitempty" msgpack:"data"`
+
+	// InfoType and DataType name the shapes of Info and Data for polymorphic endpoints.
+	// Filled from RegisterTypeName when WithTypeDiscriminator is enabled.
+	InfoType string `json:"info_type,omitempty" xml:"info_type,omitempty" msgpack:"info_type,omitempty"`
+	DataType string `json:"data_type,omitempty" xml:"data_type,omitempty" msgpack:"data_type,omitempty"`
+
 	Meta    map[string]interface{} `json:"meta,omitempty" xml:"meta,omitempty" msgpack:"meta"`
 	Errors  ErrorList              `json:"errors,omitempty" xml:"errors,omitempty" msgpack:"errors"`
 	Actions []Action               `json:"actions,omitempty" xml:"actions,omitempty" msgpack:"actions"`
 }
 
+// StatusHook adjusts a response before it is encoded.
+// Registered per status code with Renderer.OnStatus.
+type StatusHook func(r *Renderer, resp *Response)
+
 // Action represents a possible next step the client can take
 type Action struct {
 	Name        string                 `json:"name"`                  // Unique identifier for the action
@@ -158,6 +174,13 @@ type Action struct {
 	Parameters  map[string]interface{} `json:"parameters,omitempty"`  // Required parameters
 	Headers     map[string]string      `json:"headers,omitempty"`     // Required headers
 	Required    bool                   `json:"required,omitempty"`
+	Feature     string                 `json:"-" xml:"-" msgpack:"-"` // Client capability required to receive the action
+
+	// Kind, Interval, and MaxAttempts describe how a client follows up on
+	// the response, e.g. polling an async operation; see PollAction.
+	Kind        ActionKind `json:"kind,omitempty"`         // Follow-up semantics: poll, retry, or redirect
+	Interval    int        `json:"interval,omitempty"`     // Seconds to wait before each attempt
+	MaxAttempts int        `json:"max_attempts,omitempty"` // Attempts after which the client should give up; zero means unbounded
 }
 
 // ErrorList is a custom type for a list of errors that implements JSON marshalling.
@@ -166,29 +189,41 @@ type Action struct {
 type ErrorList []error
 
 // MarshalJSON implements custom JSON marshaling for ErrorList.
-// Converts each error to its string representation.
-// Returns JSON-encoded error strings or an error if marshaling fails.
+// Errors implementing ErrorCoder become {code, message} objects; others stay
+// plain message strings, so existing clients keep reading uncoded errors.
+// Returns the JSON array or an error if marshaling fails.
 func (el ErrorList) MarshalJSON() ([]byte, error) {
-	errStrings := make([]string, len(el))
-	for i, err := range el {
-		if err != nil {
-			errStrings[i] = err.Error()
+	out := make([]interface{}, len(el))
+	for i, it := range el.items() {
+		if it.Code != Empty {
+			out[i] = it
+		} else {
+			out[i] = it.Message
 		}
 	}
-	return json.Marshal(errStrings)
+	return json.Marshal(out)
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for ErrorList.
-// Converts JSON string array to a slice of errors.
+// Accepts an array of strings or of {code, message} objects.
 // Returns an error if unmarshaling fails.
 func (el *ErrorList) UnmarshalJSON(data []byte) error {
-	var errStrings []string
-	if err := json.Unmarshal(data, &errStrings); err != nil {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
-	*el = make(ErrorList, len(errStrings))
-	for i, s := range errStrings {
-		(*el)[i] = errors.New(s)
+	*el = make(ErrorList, len(raw))
+	for i, r := range raw {
+		var s string
+		if err := json.Unmarshal(r, &s); err == nil {
+			(*el)[i] = errors.New(s)
+			continue
+		}
+		var it ErrorItem
+		if err := json.Unmarshal(r, &it); err != nil {
+			return err
+		}
+		(*el)[i] = it.err()
 	}
 	return nil
 }
@@ -201,7 +236,34 @@ func (el *ErrorList) UnmarshalJSON(data []byte) error {
 // Manages a slice of callback functions for response events.
 // Used by Renderer to notify callbacks of response status.
 type CallbackManager struct {
-	callbacks []func(data CallbackData)
+	callbacks []*callbackEntry
+}
+
+// CallbackHandle identifies callbacks registered by one AddCallback call.
+// Pass it to Remove to deregister them.
+type CallbackHandle uint64
+
+// callbackSeq issues CallbackHandles.
+var callbackSeq atomic.Uint64
+
+// callbackEntry is a registered callback.
+type callbackEntry struct {
+	handle CallbackHandle
+	fn     func(data CallbackData)
+}
+
+// CallbackPanicError reports a callback that panicked.
+// The callback stays registered; remove it with WithoutCallback if its panics
+// are permanent.
+type CallbackPanicError struct {
+	Handle CallbackHandle
+	Value  interface{}
+	Stack  []byte
+}
+
+// Error returns the recovered value formatted as an error message.
+func (e *CallbackPanicError) Error() string {
+	return fmt.Sprintf("panic in callback %d: %v", e.Handle, e.Value)
 }
 
 // NewCallbackManager creates a new CallbackManager.
@@ -216,26 +278,51 @@ func NewCallbackManager() *CallbackManager {
 // Returns a new *CallbackManager with copied callbacks.
 func (cm *CallbackManager) Clone() *CallbackManager {
 	newCM := &CallbackManager{
-		callbacks: append([]func(data CallbackData){}, cm.callbacks...),
+		callbacks: slices.Clone(cm.callbacks),
 	}
 	return newCM
 }
 
 // AddCallback registers one or more callbacks.
 // Takes callback functions that accept CallbackData.
-// Appends callbacks to the manager and returns it for chaining.
-func (cm *CallbackManager) AddCallback(cb ...func(data CallbackData)) *CallbackManager {
-	cm.callbacks = append(cm.callbacks, cb...)
-	return cm
+// Returns a handle that removes them again via Remove.
+func (cm *CallbackManager) AddCallback(cb ...func(data CallbackData)) CallbackHandle {
+	h := CallbackHandle(callbackSeq.Add(1))
+	for _, fn := range cb {
+		if fn != nil {
+			cm.callbacks = append(cm.callbacks, &callbackEntry{handle: h, fn: fn})
+		}
+	}
+	return h
+}
+
+// Remove deregisters the callbacks added under h.
+// Returns true if any callback was removed.
+func (cm *CallbackManager) Remove(h CallbackHandle) bool {
+	n := len(cm.callbacks)
+	cm.callbacks = slices.DeleteFunc(slices.Clone(cm.callbacks), func(e *callbackEntry) bool {
+		return e.handle == h
+	})
+	return len(cm.callbacks) != n
+}
+
+// Len returns the number of registered callbacks.
+func (cm *CallbackManager) Len() int {
+	return len(cm.callbacks)
 }
 
 // Trigger calls all registered callbacks with the provided data.
 // Takes ID, status, message, and optional error for callbacks.
-// Executes each callback with constructed CallbackData.
-func (cm *CallbackManager) Trigger(id, status, msg string, err error) {
+// Returns the panics of failing callbacks, joined, or nil.
+func (cm *CallbackManager) Trigger(id, status, msg string, err error) error {
 	if len(cm.callbacks) == 0 {
-		return
+		return nil
 	}
+	return cm.Emit(newCallbackData(id, status, msg, err))
+}
+
+// newCallbackData builds the CallbackData for Trigger's arguments.
+func newCallbackData(id, status, msg string, err error) CallbackData {
 	data := CallbackData{
 		ID:      id,
 		Status:  status,
@@ -245,9 +332,33 @@ func (cm *CallbackManager) Trigger(id, status, msg string, err error) {
 	if err != nil {
 		data.Output = err.Error()
 	}
-	for _, cb := range cm.callbacks {
-		cb(data)
+	return data
+}
+
+// Emit calls all registered callbacks with data as is.
+// Used for callbacks carrying more than Trigger's fields, such as stream progress.
+// A panicking callback is recovered so the others still run, and is called
+// again on later events.
+// Returns the panics of failing callbacks, joined, or nil.
+func (cm *CallbackManager) Emit(data CallbackData) error {
+	var errs []error
+	for _, e := range cm.callbacks {
+		if err := e.call(data); err != nil {
+			errs = append(errs, err)
+		}
 	}
+	return errors.Join(errs...)
+}
+
+// call runs the callback, converting a panic into a *CallbackPanicError.
+func (e *callbackEntry) call(data CallbackData) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &CallbackPanicError{Handle: e.handle, Value: v, Stack: debug.Stack()}
+		}
+	}()
+	e.fn(data)
+	return nil
 }
 
 type State int
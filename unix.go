@@ -0,0 +1,154 @@
+package beam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// metaPeer is the meta key carrying the Unix socket peer credentials.
+const metaPeer = "peer"
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+var (
+	// ErrPeerCredUnsupported is returned when peer credentials are unavailable on this platform or connection.
+	ErrPeerCredUnsupported = errors.New("peer credentials not supported")
+
+	// ErrSocketInUse is returned by ListenUnix when a server already listens on the socket.
+	ErrSocketInUse = errors.New("unix socket in use")
+
+	errNoSocketActivation = errors.New("no sockets passed by systemd")
+)
+
+// PeerCred identifies the process on the other end of a Unix socket.
+type PeerCred struct {
+	PID int `json:"pid" xml:"pid" msgpack:"pid"`
+	UID int `json:"uid" xml:"uid" msgpack:"uid"`
+	GID int `json:"gid" xml:"gid" msgpack:"gid"`
+}
+
+// peerCredKey is the context key under which PeerCred is stored.
+type peerCredKey struct{}
+
+// ListenUnix listens on a Unix domain socket at path.
+// Removes a stale socket file first and applies mode (0 keeps the umask default).
+// The socket is created in a private directory next to path and linked into
+// place once mode is applied, so it is never reachable with a looser mode.
+// Returns ErrSocketInUse if a server already answers at path, or an error if
+// the socket cannot be created.
+func ListenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%w: %s", ErrSocketInUse, path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	if mode == 0 {
+		return net.Listen("unix", path)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".sock")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "s")
+	l, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmp, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	l.SetUnlinkOnClose(false)
+	// Link fails if path exists, so nothing at path is ever replaced.
+	if err := os.Chmod(tmp, mode); err == nil {
+		err = os.Link(tmp, path)
+	}
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return &unixListener{UnixListener: l, path: path}, nil
+}
+
+// unixListener removes its socket file on Close, as net.Listen's listeners
+// do for the path they were created at.
+type unixListener struct {
+	*net.UnixListener
+	path string
+	once sync.Once
+}
+
+func (l *unixListener) Close() error {
+	err := l.UnixListener.Close()
+	l.once.Do(func() { os.Remove(l.path) })
+	return err
+}
+
+// SystemdListeners returns the sockets passed by systemd socket activation.
+// Reads LISTEN_PID and LISTEN_FDS and unsets them so child processes do not inherit them.
+// Returns an error if the process was not socket-activated.
+func SystemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errNoSocketActivation
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n <= 0 {
+		return nil, errNoSocketActivation
+	}
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, open := range listeners {
+				open.Close()
+			}
+			return nil, fmt.Errorf("systemd fd %d: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// PeerCredContext stores the peer credentials of Unix socket connections in the context.
+// Intended for http.Server.ConnContext; other connection types pass through unchanged.
+func PeerCredContext(ctx context.Context, c net.Conn) context.Context {
+	uc, ok := c.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	cred, err := peerCred(uc)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, peerCredKey{}, cred)
+}
+
+// PeerCredFromContext returns the peer credentials stored by PeerCredContext.
+func PeerCredFromContext(ctx context.Context) (PeerCred, bool) {
+	cred, ok := ctx.Value(peerCredKey{}).(PeerCred)
+	return cred, ok
+}
+
+// UnixServer returns an http.Server for h that records Unix socket peer credentials.
+// Renderers bound with WithRequest surface the credentials as meta "peer".
+// Serve it with a listener from ListenUnix or SystemdListeners.
+func UnixServer(h http.Handler) *http.Server {
+	return &http.Server{Handler: h, ConnContext: PeerCredContext}
+}
@@ -0,0 +1,103 @@
+package beam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "beam.sock")
+	l, err := ListenUnix(path, 0o600)
+	if err != nil {
+		t.Fatalf("ListenUnix failed: %v", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("Expected socket mode 0600, got %v (%v)", fi.Mode().Perm(), err)
+	}
+
+	srv := UnixServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = NewRenderer(settings).WithRequest(req).Push(w, Response{Message: "hi"})
+	}))
+	go srv.Serve(l)
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://unix/")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	var out struct {
+		Meta map[string]PeerCred `json:"meta"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("Invalid JSON: %v (%s)", err, body)
+	}
+	peer, ok := out.Meta["peer"]
+	if runtime.GOOS != "linux" {
+		if ok {
+			t.Errorf("Expected no peer credentials on %s, got %+v", runtime.GOOS, peer)
+		}
+		return
+	}
+	if !ok || peer.PID != os.Getpid() || peer.UID != os.Getuid() {
+		t.Errorf("Expected own credentials, got %+v", peer)
+	}
+
+	t.Run("StaleSocket", func(t *testing.T) {
+		stale := filepath.Join(t.TempDir(), "stale.sock")
+		l1, err := net.Listen("unix", stale)
+		if err != nil {
+			t.Fatalf("Listen failed: %v", err)
+		}
+		l1.(*net.UnixListener).SetUnlinkOnClose(false)
+		l1.Close()
+		l2, err := ListenUnix(stale, 0)
+		if err != nil {
+			t.Fatalf("Expected stale socket to be replaced, got %v", err)
+		}
+		l2.Close()
+	})
+
+	t.Run("InUse", func(t *testing.T) {
+		if _, err := ListenUnix(path, 0o600); !errors.Is(err, ErrSocketInUse) {
+			t.Fatalf("Expected ErrSocketInUse, got %v", err)
+		}
+		if _, err := client.Get("http://unix/"); err != nil {
+			t.Errorf("Expected the running server to keep its socket, got %v", err)
+		}
+	})
+
+	t.Run("CloseRemovesSocket", func(t *testing.T) {
+		sock := filepath.Join(t.TempDir(), "closed.sock")
+		l, err := ListenUnix(sock, 0o600)
+		if err != nil {
+			t.Fatalf("ListenUnix failed: %v", err)
+		}
+		l.Close()
+		if _, err := os.Lstat(sock); !os.IsNotExist(err) {
+			t.Errorf("Expected the socket removed on Close, got %v", err)
+		}
+	})
+
+	t.Run("NotActivated", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", "1")
+		if _, err := SystemdListeners(); err == nil {
+			t.Error("Expected error when LISTEN_PID does not match")
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package beam
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+// EventTypeUploadProgress is the SSE event type of upload progress reports.
+const EventTypeUploadProgress = "upload.progress"
+
+// DefaultUploadRetention is how long finished uploads stay visible to watchers.
+const DefaultUploadRetention = time.Minute
+
+// UploadProgress reports how much of an upload has been received.
+type UploadProgress struct {
+	ID       string  `json:"id" xml:"id" msgpack:"id"`
+	Received int64   `json:"received" xml:"received" msgpack:"received"`
+	Total    int64   `json:"total,omitempty" xml:"total,omitempty" msgpack:"total,omitempty"`       // Declared Content-Length; zero if unknown
+	Percent  float64 `json:"percent,omitempty" xml:"percent,omitempty" msgpack:"percent,omitempty"` // Zero if Total is unknown
+	Done     bool    `json:"done,omitempty" xml:"done,omitempty" msgpack:"done,omitempty"`
+	Error    string  `json:"error,omitempty" xml:"error,omitempty" msgpack:"error,omitempty"`
+}
+
+// UploadTracker relays the progress of uploads, read on one request, to
+// watchers streaming it on another, so browsers can show percent-complete
+// for large uploads. Uploads and watchers meet by an upload ID chosen by the
+// application, e.g. a query parameter of both requests.
+// Safe for concurrent use.
+type UploadTracker struct {
+	Retention time.Duration // How long finished uploads stay visible; zero uses DefaultUploadRetention
+
+	mu      sync.Mutex
+	uploads map[string]*upload
+}
+
+// upload is the progress of one upload and its watchers.
+type upload struct {
+	progress UploadProgress
+	active   bool      // Body being tracked or finished
+	expires  time.Time // When a finished upload may be dropped
+	watchers map[chan UploadProgress]struct{}
+}
+
+// NewUploadTracker creates an empty UploadTracker.
+func NewUploadTracker() *UploadTracker {
+	return &UploadTracker{uploads: make(map[string]*upload)}
+}
+
+// Read parses the body of the request bound to r into v like Renderer.Request,
+// reporting the upload's progress under id until the body is read.
+// Returns hauler.ErrNilRequest without a bound request, or the parse error.
+func (t *UploadTracker) Read(r *Renderer, id string, v interface{}) error {
+	req := r.HTTPRequest()
+	if req == nil {
+		return hauler.ErrNilRequest
+	}
+	t.Track(req, id)
+	err := r.Request(req, v)
+	t.Finish(id, err)
+	return err
+}
+
+// Track wraps req.Body so reads report the upload's progress under id, for
+// handlers consuming the body themselves. Finish must be called once the
+// body was read.
+func (t *UploadTracker) Track(req *http.Request, id string) {
+	total := max(req.ContentLength, 0)
+	t.update(id, func(u *upload) {
+		u.active = true
+		u.progress = UploadProgress{ID: id, Total: total}
+	})
+	if req.Body != nil {
+		req.Body = &uploadBody{ReadCloser: req.Body, t: t, id: id}
+	}
+}
+
+// Finish marks the upload id as done, failed if err is not nil.
+func (t *UploadTracker) Finish(id string, err error) {
+	retention := t.Retention
+	if retention <= 0 {
+		retention = DefaultUploadRetention
+	}
+	t.update(id, func(u *upload) {
+		u.active = true
+		u.expires = time.Now().Add(retention)
+		u.progress.ID = id
+		u.progress.Done = true
+		if err != nil {
+			u.progress.Error = err.Error()
+		} else if u.progress.Total > 0 {
+			u.progress.Percent = 100
+		}
+	})
+	time.AfterFunc(retention, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.drop(id)
+	})
+}
+
+// Progress returns the latest progress of the upload id.
+// Returns false if the upload is unknown or has not started.
+func (t *UploadTracker) Progress(id string) (UploadProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.uploads[id]
+	if !ok || !u.active {
+		return UploadProgress{}, false
+	}
+	return u.progress, true
+}
+
+// Watch streams the progress of the upload id on r until it finishes or the
+// client disconnects: as SSE events of type EventTypeUploadProgress when the
+// client accepts text/event-stream, as NDJSON otherwise. The upload may start
+// after the watcher connects. Reports are coalesced, so a slow client always
+// receives the latest one.
+// Returns an error if streaming fails.
+func (t *UploadTracker) Watch(r *Renderer, id string) error {
+	ch := make(chan UploadProgress, 1)
+	t.update(id, func(u *upload) {
+		u.watchers[ch] = struct{}{}
+		if u.active {
+			ch <- u.progress
+		}
+	})
+	defer t.unwatch(id, ch)
+
+	sse := false
+	if req := r.HTTPRequest(); req != nil {
+		sse = strings.Contains(req.Header.Get("Accept"), ContentTypeEventStream)
+	}
+	contentType := ContentTypeNDJSON
+	if sse {
+		contentType = ContentTypeEventStream
+	}
+	done := false
+	return r.WithContentType(contentType).Stream(func(r *Renderer) (interface{}, error) {
+		if done {
+			return nil, io.EOF
+		}
+		select {
+		case p := <-ch:
+			done = p.Done
+			if sse {
+				return Event{Type: EventTypeUploadProgress, Data: p}, nil
+			}
+			return p, nil
+		case <-r.Context().Done():
+			return nil, io.EOF
+		}
+	})
+}
+
+// update applies fn to the upload id, creating it if needed, and sends the
+// resulting progress to its watchers.
+func (t *UploadTracker) update(id string, fn func(u *upload)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.uploads == nil {
+		t.uploads = make(map[string]*upload)
+	}
+	u, ok := t.uploads[id]
+	if !ok {
+		u = &upload{progress: UploadProgress{ID: id}, watchers: make(map[chan UploadProgress]struct{})}
+		t.uploads[id] = u
+	}
+	before := len(u.watchers)
+	fn(u)
+	if !u.active || len(u.watchers) != before {
+		return
+	}
+	for ch := range u.watchers {
+		// Replace an unread report so watchers only see the latest one.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- u.progress
+	}
+}
+
+// unwatch removes a watcher, dropping uploads nobody reads or watches.
+func (t *UploadTracker) unwatch(id string, ch chan UploadProgress) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if u, ok := t.uploads[id]; ok {
+		delete(u.watchers, ch)
+	}
+	t.drop(id)
+}
+
+// drop removes the upload id once it has no watchers and either never
+// started or finished longer than the retention ago. Requires t.mu held.
+func (t *UploadTracker) drop(id string) {
+	u, ok := t.uploads[id]
+	if !ok || len(u.watchers) > 0 {
+		return
+	}
+	if !u.active || (u.progress.Done && !time.Now().Before(u.expires)) {
+		delete(t.uploads, id)
+	}
+}
+
+// received records n more bytes of the upload id.
+func (t *UploadTracker) received(id string, n int) {
+	t.update(id, func(u *upload) {
+		p := &u.progress
+		p.Received += int64(n)
+		if p.Total > 0 {
+			p.Percent = math.Min(math.Round(float64(p.Received)*10000/float64(p.Total))/100, 100)
+		}
+	})
+}
+
+// uploadBody reports the bytes read from a tracked request body.
+type uploadBody struct {
+	io.ReadCloser
+	t  *UploadTracker
+	id string
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.t.received(b.id, n)
+	}
+	return n, err
+}
@@ -0,0 +1,123 @@
+package beam
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadTracker(t *testing.T) {
+	payload := `{"name":"` + strings.Repeat("x", 64*1024) + `"}`
+	upload := func(t *testing.T, tracker *UploadTracker, id, body string) error {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+		req.Header.Set(HeaderContentType, ContentTypeJSON)
+		var v struct{ Name string }
+		return tracker.Read(NewRenderer(settings).WithRequest(req), id, &v)
+	}
+	watch := func(tracker *UploadTracker, id, accept string) (*httptest.ResponseRecorder, chan error) {
+		req := httptest.NewRequest(http.MethodGet, "/progress", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		done := make(chan error, 1)
+		go func() { done <- tracker.Watch(NewRenderer(settings).WithRequest(req).WithWriter(w), id) }()
+		return w, done
+	}
+	waitWatching := func(t *testing.T, tracker *UploadTracker, id string) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			tracker.mu.Lock()
+			u, ok := tracker.uploads[id]
+			n := 0
+			if ok {
+				n = len(u.watchers)
+			}
+			tracker.mu.Unlock()
+			if n > 0 {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatal("Watcher never subscribed")
+	}
+	lines := func(t *testing.T, body string) []UploadProgress {
+		t.Helper()
+		var out []UploadProgress
+		sc := bufio.NewScanner(strings.NewReader(body))
+		for sc.Scan() {
+			var p UploadProgress
+			if err := json.Unmarshal(sc.Bytes(), &p); err != nil {
+				t.Fatalf("Invalid NDJSON line %q: %v", sc.Text(), err)
+			}
+			out = append(out, p)
+		}
+		return out
+	}
+
+	t.Run("NDJSON", func(t *testing.T) {
+		tracker := NewUploadTracker()
+		w, done := watch(tracker, "u1", "*/*")
+		waitWatching(t, tracker, "u1")
+		if err := upload(t, tracker, "u1", payload); err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("Watch failed: %v", err)
+		}
+		if got := w.Header().Get(HeaderContentType); got != ContentTypeNDJSON {
+			t.Errorf("Expected NDJSON, got %q", got)
+		}
+		reports := lines(t, w.Body.String())
+		last := reports[len(reports)-1]
+		if !last.Done || last.Percent != 100 || last.Received != int64(len(payload)) || last.Total != int64(len(payload)) {
+			t.Errorf("Unexpected final report %+v", last)
+		}
+	})
+
+	t.Run("SSE", func(t *testing.T) {
+		tracker := NewUploadTracker()
+		w, done := watch(tracker, "u2", ContentTypeEventStream)
+		waitWatching(t, tracker, "u2")
+		if err := upload(t, tracker, "u2", payload); err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		if err := <-done; err != nil {
+			t.Fatalf("Watch failed: %v", err)
+		}
+		if !strings.Contains(w.Body.String(), "event: "+EventTypeUploadProgress) || !strings.Contains(w.Body.String(), `"done":true`) {
+			t.Errorf("Expected upload progress events, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("LateWatcher", func(t *testing.T) {
+		tracker := NewUploadTracker()
+		if err := upload(t, tracker, "u3", `{"name":`); err == nil {
+			t.Fatal("Expected a parse error")
+		}
+		p, ok := tracker.Progress("u3")
+		if !ok || !p.Done || p.Error == Empty {
+			t.Errorf("Expected a failed upload, got %+v", p)
+		}
+		w, done := watch(tracker, "u3", "*/*")
+		if err := <-done; err != nil {
+			t.Fatalf("Watch failed: %v", err)
+		}
+		if reports := lines(t, w.Body.String()); len(reports) != 1 || reports[0].Error == Empty {
+			t.Errorf("Expected the final report only, got %+v", reports)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		tracker := &UploadTracker{Retention: time.Millisecond}
+		tracker.Finish("u4", nil)
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := tracker.Progress("u4"); ok {
+			t.Error("Expected the finished upload dropped after retention")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package beam
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+// WithValidator sets a hook Request runs on every decoded value, after the
+// value's own hauler.Validator method. Failures surface as hauler.ErrValidation,
+// which Error and Handler answer with 422 Unprocessable Entity.
+// Returns a new Renderer with the validator set; nil removes it.
+func (r *Renderer) WithValidator(fn func(interface{}) error) *Renderer {
+	nr := r.clone()
+	nr.validator = fn
+	return nr
+}
+
+// requestErrorStatus maps request errors to their HTTP status code:
+// 413 for oversized bodies and 422 for failed validation or invalid GeoJSON.
+// Returns 0 when no error is a request error.
+func requestErrorStatus(errs []error) int {
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, hauler.ErrBodyTooLarge):
+			return http.StatusRequestEntityTooLarge
+		case errors.Is(err, hauler.ErrValidation), errors.Is(err, hauler.ErrInvalidGeoJSON):
+			return http.StatusUnprocessableEntity
+		}
+	}
+	return 0
+}
+
+// requestError answers a request error returned to Handler.
+// Validation failures list each failed field as an error and in Info;
+// other request errors use the status text as the message.
+func (r *Renderer) requestError(err error) error {
+	var ve *hauler.ValidationError
+	if !errors.As(err, &ve) {
+		return r.ErrorMsg(http.StatusText(requestErrorStatus([]error{err})), err)
+	}
+	errs := make([]error, len(ve.Fields))
+	for i := range ve.Fields {
+		errs[i] = &ve.Fields[i]
+	}
+	return r.ErrorInfo(hauler.ErrValidation.Error(), ve.Fields, errs...)
+}
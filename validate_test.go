@@ -0,0 +1,75 @@
+package beam
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/olekukonko/beam/hauler"
+)
+
+type signupRequest struct {
+	Email string `json:"email"`
+}
+
+func (s *signupRequest) Validate() error {
+	if !strings.Contains(s.Email, "@") {
+		return &hauler.FieldError{Field: "email", Message: "must be an email address"}
+	}
+	return nil
+}
+
+func TestRequestValidation(t *testing.T) {
+	h := NewRenderer(settings).Handler(func(r *Renderer) error {
+		var s signupRequest
+		if err := r.Request(r.request, &s); err != nil {
+			return err
+		}
+		return r.Msg("ok")
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", hauler.ContentTypeJSON)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(`{"email":"a@b.c"}`); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+	rec := post(`{"email":"nope"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Info []hauler.FieldError `json:"info"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Info) != 1 || resp.Info[0].Field != "email" {
+		t.Errorf("Expected email field error in info, got %s", rec.Body)
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	r := NewRenderer(settings).WithValidator(func(v interface{}) error {
+		return errors.New("rejected")
+	})
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", hauler.ContentTypeJSON)
+	var data map[string]interface{}
+	if err := r.Request(req, &data); !errors.Is(err, hauler.ErrValidation) {
+		t.Errorf("Expected ErrValidation, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	_ = r.WithWriter(rec).Error(&hauler.FieldError{Field: "x", Message: "bad"})
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected Error to answer 422, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,468 @@
+package beam
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WebSocket message types, equal to the RFC 6455 opcodes.
+const (
+	WebSocketText   = 1
+	WebSocketBinary = 2
+	WebSocketClose  = 8
+	WebSocketPing   = 9
+	WebSocketPong   = 10
+)
+
+// websocketGUID is the RFC 6455 key suffix used to compute Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessage bounds the size of a message accepted by ReadMessage.
+const maxWebSocketMessage = 64 << 20
+
+// maxControlPayload bounds the payload of close, ping, and pong frames (RFC 6455 section 5.5).
+const maxControlPayload = 125
+
+// DefaultWebSocketWriteTimeout bounds each message write of connections
+// returned by UpgradeWebSocket, so a peer that stops reading cannot block
+// the writer forever.
+const DefaultWebSocketWriteTimeout = 10 * time.Second
+
+var (
+	// ErrWebSocketHandshake is returned by UpgradeWebSocket for requests that are not valid upgrades.
+	ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+	// ErrWebSocketClosed is returned when the peer closed the connection or no pong arrived in time.
+	ErrWebSocketClosed = errors.New("websocket closed")
+	// ErrWebSocketOrigin is returned by UpgradeWebSocket for cross-origin requests its CheckOrigin rejects.
+	ErrWebSocketOrigin = errors.New("websocket origin not allowed")
+
+	errWebSocketFrame = errors.New("malformed websocket frame")
+)
+
+// WebSocketConn is a message-oriented websocket connection.
+// Implemented by the connections UpgradeWebSocket returns and, as is, by
+// gorilla/websocket's *Conn, so either can back a WebSocket.
+type WebSocketConn interface {
+	ReadMessage() (messageType int, p []byte, err error)
+	WriteMessage(messageType int, data []byte) error
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+	SetPongHandler(h func(appData string) error)
+	Close() error
+}
+
+// WebSocketOptions configures the lifecycle of a WebSocket.
+type WebSocketOptions struct {
+	PingInterval time.Duration // Interval between keep-alive pings during Stream; zero disables
+	PongWait     time.Duration // Time each ping waits for its pong; zero only checks the ping is sent. Pongs are seen only while the connection is read
+	WriteTimeout time.Duration // Deadline for control frames; zero uses one second
+}
+
+// WebSocket writes renderer output to a websocket connection.
+// Every Write becomes one message. It implements Pinger, so Stream keeps idle
+// connections alive and detects dead peers. Use it with Renderer.WebSocket.
+type WebSocket struct {
+	opts   WebSocketOptions
+	conn   WebSocketConn
+	mu     sync.Mutex // Serializes writes; websocket connections allow one writer
+	pongs  chan struct{}
+	closed atomic.Bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewWebSocket wraps conn and installs its pong handler.
+// Returns a WebSocket ready to be passed to Renderer.WebSocket.
+func NewWebSocket(conn WebSocketConn, opts WebSocketOptions) *WebSocket {
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = time.Second
+	}
+	ws := &WebSocket{opts: opts, conn: conn, pongs: make(chan struct{}, 1), done: make(chan struct{})}
+	conn.SetPongHandler(func(string) error {
+		select {
+		case ws.pongs <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	return ws
+}
+
+// Ping sends a ping frame and, when PongWait is set, waits for the pong.
+// Pongs arrive through ReadMessage, so the connection must be read meanwhile.
+// Returns ErrWebSocketClosed after Close, or an error if the ping cannot be
+// sent or ctx is done before the pong arrives.
+func (ws *WebSocket) Ping(ctx context.Context) error {
+	if ws.closed.Load() {
+		return ErrWebSocketClosed
+	}
+	select {
+	case <-ws.pongs: // Drop a pong answering an earlier ping
+	default:
+	}
+	deadline := time.Now().Add(ws.opts.WriteTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	ws.mu.Lock()
+	err := ws.conn.WriteControl(WebSocketPing, nil, deadline)
+	ws.mu.Unlock()
+	if err != nil {
+		return errors.Join(errWriteFailed, err)
+	}
+	if ws.opts.PongWait <= 0 {
+		return nil
+	}
+	select {
+	case <-ws.pongs:
+		return nil
+	case <-ws.done:
+		return ErrWebSocketClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriteMessage sends data as one message of the given type.
+// Returns ErrWebSocketClosed after Close or an error if the write fails.
+func (ws *WebSocket) WriteMessage(messageType int, data []byte) error {
+	if ws.closed.Load() {
+		return ErrWebSocketClosed
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if err := ws.conn.WriteMessage(messageType, data); err != nil {
+		return errors.Join(errWriteFailed, err)
+	}
+	return nil
+}
+
+// ReadMessage reads the next data message sent by the client.
+// Reading also processes pings, pongs, and close frames.
+// Returns an error if the connection fails or the client closed it.
+func (ws *WebSocket) ReadMessage() (int, []byte, error) {
+	return ws.conn.ReadMessage()
+}
+
+// Close sends a normal-closure frame and closes the connection.
+// The close frame is skipped while a write is in progress: that write may be
+// stuck on a dead peer, and closing the connection is what unblocks it.
+// Returns an error if closing the connection fails.
+func (ws *WebSocket) Close() error {
+	var err error
+	ws.once.Do(func() {
+		ws.closed.Store(true)
+		close(ws.done)
+		if ws.mu.TryLock() {
+			_ = ws.conn.WriteControl(WebSocketClose, closePayload(1000), time.Now().Add(ws.opts.WriteTimeout))
+			ws.mu.Unlock()
+		}
+		err = ws.conn.Close()
+	})
+	return err
+}
+
+// WebSocketProtocol implements the websocket protocol.
+// The upgrade handshake already carried the HTTP headers, so applying
+// headers is a no-op; status and metadata travel in the envelope.
+type WebSocketProtocol struct{}
+
+// ApplyHeaders is a no-op for websocket messages.
+// Returns nil.
+func (p *WebSocketProtocol) ApplyHeaders(w Writer, code int) error {
+	return nil
+}
+
+// WebSocket binds the Renderer to ws so Push, Stream, and the other output
+// methods send each encoded response or chunk as one websocket message.
+// Messages are text for textual content types (JSON, XML, text/*) and binary
+// otherwise (e.g., MsgPack), so set the content type before calling WebSocket.
+// With a PingInterval, ws is also the keepalive Pinger, so a Stream to an
+// unresponsive peer ends with a *PeerGoneError.
+// Returns a new Renderer writing to ws with WebSocketProtocol.
+func (r *Renderer) WebSocket(ws *WebSocket) *Renderer {
+	messageType := WebSocketBinary
+	if textual(r.contentType) {
+		messageType = WebSocketText
+	}
+	nr := r.WithWriter(&webSocketWriter{ws: ws, messageType: messageType}).WithProtocol(&WebSocketProtocol{})
+	if ws.opts.PingInterval > 0 {
+		nr = nr.WithKeepAlive(ws, KeepAlive{Interval: ws.opts.PingInterval, Timeout: ws.opts.PongWait})
+	}
+	return nr
+}
+
+// webSocketWriter adapts a WebSocket to Writer, one message per Write.
+type webSocketWriter struct {
+	ws          *WebSocket
+	messageType int
+}
+
+func (w *webSocketWriter) Write(p []byte) (int, error) {
+	if err := w.ws.WriteMessage(w.messageType, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// textual reports whether contentType is sent as websocket text messages.
+func textual(contentType string) bool {
+	return strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "xml") ||
+		contentType == ContentTypeFormURLEncoded
+}
+
+// UpgradeOptions configures UpgradeWebSocket.
+type UpgradeOptions struct {
+	// CheckOrigin reports whether the request's Origin may open a socket.
+	// Browsers send cookies with cross-site websocket handshakes, so accepting
+	// any origin lets other sites act as the user. Nil uses SameOrigin.
+	CheckOrigin func(req *http.Request) bool
+
+	// WriteTimeout bounds each message write; zero uses DefaultWebSocketWriteTimeout.
+	WriteTimeout time.Duration
+}
+
+// SameOrigin reports whether req carries no Origin header, as non-browser
+// clients do, or one whose host matches the request's Host.
+// It is the default UpgradeOptions.CheckOrigin.
+func SameOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == Empty {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, req.Host)
+}
+
+// UpgradeWebSocket performs the RFC 6455 server handshake on req and takes over the connection.
+// The returned connection implements the subset of the protocol Beam needs:
+// unfragmented writes, fragmented reads, and automatic ping, pong, and close handling.
+// At most one UpgradeOptions is used.
+// Returns ErrWebSocketHandshake for requests that are not websocket upgrades,
+// and ErrWebSocketOrigin with 403 Forbidden for origins CheckOrigin rejects.
+func UpgradeWebSocket(w http.ResponseWriter, req *http.Request, opts ...UpgradeOptions) (WebSocketConn, error) {
+	var o UpgradeOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if req.Method != http.MethodGet ||
+		!headerHasToken(req.Header, "Connection", "upgrade") ||
+		!headerHasToken(req.Header, "Upgrade", "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" || key == Empty {
+		http.Error(w, ErrWebSocketHandshake.Error(), http.StatusBadRequest)
+		return nil, ErrWebSocketHandshake
+	}
+	checkOrigin := o.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = SameOrigin
+	}
+	if !checkOrigin(req) {
+		http.Error(w, ErrWebSocketOrigin.Error(), http.StatusForbidden)
+		return nil, ErrWebSocketOrigin
+	}
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, errors.Join(ErrWebSocketHandshake, err)
+	}
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	_, err = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, errors.Join(ErrWebSocketHandshake, err)
+	}
+	if o.WriteTimeout <= 0 {
+		o.WriteTimeout = DefaultWebSocketWriteTimeout
+	}
+	return &wsConn{conn: conn, br: rw.Reader, writeTimeout: o.WriteTimeout}, nil
+}
+
+// headerHasToken reports whether the comma-separated header contains token, case-insensitively.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsConn is the server side of a websocket connection established by UpgradeWebSocket.
+type wsConn struct {
+	conn         net.Conn
+	br           *bufio.Reader
+	wmu          sync.Mutex // Guards frame writes, including pongs sent while reading
+	onPong       func(string) error
+	writeTimeout time.Duration
+}
+
+func (c *wsConn) WriteMessage(messageType int, data []byte) error {
+	return c.WriteControl(messageType, data, time.Now().Add(c.writeTimeout))
+}
+
+func (c *wsConn) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	defer c.conn.SetWriteDeadline(time.Time{})
+	return c.writeFrame(byte(messageType), data)
+}
+
+func (c *wsConn) SetPongHandler(h func(appData string) error) {
+	c.onPong = h
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+// ReadMessage returns the next text or binary message, reassembling fragments.
+// Pings are answered, pongs passed to the pong handler, and a close frame is
+// echoed before ErrWebSocketClosed is returned.
+func (c *wsConn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch opcode {
+		case WebSocketPing:
+			if err := c.WriteControl(WebSocketPong, payload, time.Now().Add(time.Second)); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case WebSocketPong:
+			if c.onPong != nil {
+				if err := c.onPong(string(payload)); err != nil {
+					return 0, nil, err
+				}
+			}
+			continue
+		case WebSocketClose:
+			_ = c.WriteControl(WebSocketClose, payload, time.Now().Add(time.Second))
+			return 0, nil, ErrWebSocketClosed
+		case 0: // Continuation
+			if messageType == 0 {
+				return 0, nil, errWebSocketFrame
+			}
+		default:
+			if messageType != 0 {
+				return 0, nil, errWebSocketFrame
+			}
+			messageType = int(opcode)
+		}
+		if len(message)+len(payload) > maxWebSocketMessage {
+			return 0, nil, errFrameTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// writeFrame writes one unmasked, final frame; c.wmu must be held.
+func (c *wsConn) writeFrame(opcode byte, data []byte) error {
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch n := len(data); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if _, err := c.conn.Write(append(header, data...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readFrame reads one client frame and unmasks its payload.
+// Client frames must be masked per RFC 6455; frames with reserved bits,
+// unknown opcodes, or invalid control framing are rejected.
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = head[0]&0x80 != 0, head[0]&0x0F
+	// No extensions are negotiated, so reserved bits must be clear.
+	if head[0]&0x70 != 0 || head[1]&0x80 == 0 {
+		return false, 0, nil, errWebSocketFrame
+	}
+	switch opcode {
+	case 0, WebSocketText, WebSocketBinary:
+	case WebSocketClose, WebSocketPing, WebSocketPong:
+		// Control frames cannot be fragmented and carry at most 125 bytes.
+		if !fin || head[1]&0x7F > maxControlPayload {
+			return false, 0, nil, errWebSocketFrame
+		}
+	default:
+		return false, 0, nil, errWebSocketFrame
+	}
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > maxWebSocketMessage {
+		return false, 0, nil, errFrameTooLarge
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// closePayload encodes a close frame body carrying code.
+func closePayload(code uint16) []byte {
+	return binary.BigEndian.AppendUint16(nil, code)
+}
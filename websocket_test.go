@@ -0,0 +1,280 @@
+package beam
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dialWebSocket performs a client handshake against srv and returns the raw connection.
+func dialWebSocket(t *testing.T, srv *httptest.Server) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Unexpected handshake response %d %v", resp.StatusCode, resp.Header)
+	}
+	return conn, br
+}
+
+// readServerFrame reads one unmasked frame sent by the server.
+func readServerFrame(t *testing.T, br *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	size := int(head[1] & 0x7F)
+	if size == 126 {
+		var ext [2]byte
+		io.ReadFull(br, ext[:])
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(br, payload); err != nil {
+		t.Fatal(err)
+	}
+	return head[0] & 0x0F, payload
+}
+
+// writeClientFrame writes one masked frame as a client would.
+func writeClientFrame(t *testing.T, conn net.Conn, opcode byte, payload []byte) {
+	t.Helper()
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := UpgradeWebSocket(w, req)
+		if err != nil {
+			return
+		}
+		ws := NewWebSocket(conn, WebSocketOptions{PingInterval: 20 * time.Millisecond, PongWait: time.Second})
+		defer ws.Close()
+		messages := make(chan string, 1)
+		go func() {
+			for {
+				_, msg, err := ws.ReadMessage()
+				if err != nil {
+					return
+				}
+				messages <- string(msg)
+			}
+		}()
+		r := NewRenderer(settings).WebSocket(ws)
+		if err := r.Push(nil, Response{Message: "hello"}); err != nil {
+			t.Errorf("Push failed: %v", err)
+		}
+		// Keep-alive pings run while Stream waits for the client.
+		err = r.Stream(func(*Renderer) (interface{}, error) {
+			received <- <-messages
+			return nil, io.EOF
+		})
+		if err != nil {
+			t.Errorf("Stream failed: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	conn, br := dialWebSocket(t, srv)
+	op, payload := readServerFrame(t, br)
+	if op != WebSocketText || !strings.Contains(string(payload), `"message":"hello"`) {
+		t.Errorf("Expected text envelope, got opcode %d %q", op, payload)
+	}
+	for {
+		op, _ := readServerFrame(t, br)
+		if op == WebSocketPing {
+			break
+		}
+	}
+	writeClientFrame(t, conn, WebSocketPong, nil)
+	writeClientFrame(t, conn, WebSocketText, []byte("bye"))
+	select {
+	case msg := <-received:
+		if msg != "bye" {
+			t.Errorf("Expected client message, got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for client message")
+	}
+	for {
+		op, _ := readServerFrame(t, br)
+		if op == WebSocketClose {
+			break
+		}
+	}
+}
+
+func TestWebSocketPeerGone(t *testing.T) {
+	result := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := UpgradeWebSocket(w, req)
+		if err != nil {
+			return
+		}
+		ws := NewWebSocket(conn, WebSocketOptions{PingInterval: 10 * time.Millisecond, PongWait: 10 * time.Millisecond})
+		defer ws.Close()
+		go func() {
+			for {
+				if _, _, err := ws.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+		r := NewRenderer(settings).WebSocket(ws)
+		result <- r.Stream(func(nr *Renderer) (interface{}, error) {
+			<-nr.Context().Done()
+			return nil, nr.Context().Err()
+		})
+	}))
+	defer srv.Close()
+
+	dialWebSocket(t, srv) // Never answers pings
+	select {
+	case err := <-result:
+		var pe *PeerGoneError
+		if !errors.As(err, &pe) {
+			t.Errorf("Expected PeerGoneError, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the dead peer to be detected")
+	}
+}
+
+func TestUpgradeWebSocketRejectsPlainRequests(t *testing.T) {
+	w := httptest.NewRecorder()
+	if _, err := UpgradeWebSocket(w, httptest.NewRequest(http.MethodGet, "/", nil)); err != ErrWebSocketHandshake {
+		t.Errorf("Expected ErrWebSocketHandshake, got %v", err)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", w.Code)
+	}
+}
+
+func TestWebSocketBinaryMessages(t *testing.T) {
+	if textual(ContentTypeMsgPack) || !textual(ContentTypeJSON) || !textual("text/plain; charset=utf-8") {
+		t.Error("Unexpected message type selection")
+	}
+}
+
+func TestUpgradeWebSocketOrigin(t *testing.T) {
+	upgrade := func(origin string, opts ...UpgradeOptions) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "http://api.example.com/ws", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		_, err := UpgradeWebSocket(w, req, opts...)
+		return w, err
+	}
+
+	if w, err := upgrade("https://evil.example"); err != ErrWebSocketOrigin || w.Code != http.StatusForbidden {
+		t.Errorf("Expected a cross-site handshake rejected with 403, got %d %v", w.Code, err)
+	}
+	// The recorder cannot be hijacked, so accepted origins fail past the origin check.
+	for _, origin := range []string{"", "https://API.example.com"} {
+		if _, err := upgrade(origin); err == ErrWebSocketOrigin {
+			t.Errorf("Expected origin %q accepted", origin)
+		}
+	}
+	allow := UpgradeOptions{CheckOrigin: func(*http.Request) bool { return true }}
+	if _, err := upgrade("https://evil.example", allow); err == ErrWebSocketOrigin {
+		t.Error("Expected CheckOrigin to override the default")
+	}
+}
+
+func TestWebSocketRejectsInvalidFrames(t *testing.T) {
+	mask := []byte{0, 0, 0, 0}
+	frames := map[string][]byte{
+		"Reserved":          append([]byte{0x80 | 0x40 | WebSocketText, 0x80}, mask...),
+		"UnknownOpcode":     append([]byte{0x80 | 3, 0x80}, mask...),
+		"FragmentedControl": append([]byte{WebSocketPing, 0x80}, mask...),
+		"LargeControl":      append(append([]byte{0x80 | WebSocketPing, 0x80 | 126, 0, 126}, mask...), make([]byte, 126)...),
+	}
+	for name, frame := range frames {
+		t.Run(name, func(t *testing.T) {
+			errs := make(chan error, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				conn, err := UpgradeWebSocket(w, req)
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer conn.Close()
+				_, _, err = conn.ReadMessage()
+				errs <- err
+			}))
+			defer srv.Close()
+
+			conn, _ := dialWebSocket(t, srv)
+			if _, err := conn.Write(frame); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case err := <-errs:
+				if err != errWebSocketFrame {
+					t.Errorf("Expected errWebSocketFrame, got %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("Timed out waiting for the frame to be rejected")
+			}
+		})
+	}
+}
+
+func TestWebSocketWriteTimeout(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	c := &wsConn{conn: server, br: bufio.NewReader(server), writeTimeout: 20 * time.Millisecond}
+	// Nobody reads the pipe, so the write blocks until its deadline.
+	if err := c.WriteMessage(WebSocketText, []byte("stuck")); err == nil {
+		t.Fatal("Expected the write to time out")
+	}
+
+	ws := NewWebSocket(c, WebSocketOptions{})
+	ws.mu.Lock() // A write stuck on the dead peer
+	done := make(chan struct{})
+	go func() {
+		ws.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked behind a pending write")
+	}
+	ws.mu.Unlock()
+}